@@ -0,0 +1,242 @@
+// Copyright 2020 the Drone Authors. All rights reserved.
+// Use of this source code is governed by the Blue Oak Model License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/drone/drone-robot/plugin"
+)
+
+// command runs a CLI subcommand. The plugin arguments read from the
+// environment provide the defaults for the subcommand flags.
+type command func(argv []string, args plugin.Args) error
+
+var commands = map[string]command{
+	"analyze": analyze,
+	"convert": convert,
+	"merge":   merge,
+	"compare": compare,
+	"digest":  digest,
+	"serve":   serve,
+}
+
+// runCommand runs the named subcommand.
+func runCommand(name string, argv []string, args plugin.Args) error {
+	cmd, ok := commands[name]
+	if !ok {
+		var names []string
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command %q, expected one of: %s", name, strings.Join(names, ", "))
+	}
+	return cmd(argv, args)
+}
+
+// analyze computes and logs the statistics of a report directory or
+// file, and validates them against the configured thresholds.
+func analyze(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	output := flags.String("json", "", "write the statistics as JSON to this file")
+	flags.StringVar(&args.ReportFileNamePattern, "pattern", args.ReportFileNamePattern, "report file name pattern")
+	flags.StringVar(&args.ReportFileRegex, "regex", args.ReportFileRegex, "report file regular expression, matched recursively")
+	flags.BoolVar(&args.OnlyCritical, "only-critical", args.OnlyCritical, "only count critical tests")
+	flags.BoolVar(&args.CountSkippedTests, "count-skipped", args.CountSkippedTests, "count skipped tests")
+	flags.BoolVar(&args.MergeReruns, "merge-reruns", args.MergeReruns, "merge rerun outputs in execution order")
+	flags.StringVar(&args.GateExpression, "gate", args.GateExpression, "gate expression the statistics must satisfy")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: robot-stats analyze [flags] <report directory or file>")
+	}
+
+	args.ReportDirectory = positional[0]
+	if info, err := os.Stat(args.ReportDirectory); err == nil && !info.IsDir() {
+		args.ReportDirectory, args.ReportFileNamePattern = filepath.Split(positional[0])
+		args.ReportFileRegex = ""
+	}
+	if args.ReportFileNamePattern == "" {
+		args.ReportFileNamePattern = "output.xml"
+	}
+	if err := plugin.ValidateInputs(args); err != nil {
+		return err
+	}
+
+	stats, err := plugin.Analyze(context.Background(), args)
+	if err != nil {
+		return err
+	}
+	plugin.LogResults(stats, args)
+	gates, gateErr := plugin.EvaluateGates(stats, args)
+	stats.Gates = gates
+	if *output != "" {
+		if err := plugin.WriteJSON(*output, stats); err != nil {
+			return err
+		}
+	}
+	return gateErr
+}
+
+// convert converts a result set to a stats JSON file, written to
+// standard output unless --json is set.
+func convert(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	output := flags.String("json", "", "write the statistics as JSON to this file")
+	flags.StringVar(&args.ReportFileNamePattern, "pattern", args.ReportFileNamePattern, "report file name pattern for result directories")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: robot-stats convert [--json file] <report directory or file>")
+	}
+
+	stats, err := plugin.LoadResultSet(positional[0], args)
+	if err != nil {
+		return err
+	}
+	return writeJSON(*output, stats)
+}
+
+// merge merges the outputs of an original run and its reruns, in the
+// given order, and reports tests that failed and then passed.
+func merge(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	output := flags.String("json", "", "write the merged statistics as JSON to this file")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: robot-stats merge [--json file] <original> <rerun>...")
+	}
+
+	stats, err := plugin.Merge(positional, args)
+	if err != nil {
+		return err
+	}
+	plugin.LogResults(stats, args)
+	if *output != "" {
+		return plugin.WriteJSON(*output, stats)
+	}
+	return nil
+}
+
+// compare diffs two result sets given as stats JSON files, report files
+// or report directories.
+func compare(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	output := flags.String("json", "", "write the comparison report as JSON to this file")
+	flags.StringVar(&args.ReportFileNamePattern, "pattern", args.ReportFileNamePattern, "report file name pattern for result directories")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return fmt.Errorf("usage: robot-stats compare [--json file] [--pattern glob] <before> <after>")
+	}
+
+	diff, err := plugin.Compare(positional[0], positional[1], args)
+	if err != nil {
+		return err
+	}
+	plugin.LogRunDiff(diff, args.SummaryStyle)
+	if *output != "" {
+		return plugin.WriteJSON(*output, diff)
+	}
+	return nil
+}
+
+// digest summarizes the runs of a time window, given as stats JSON files
+// or read from the history.
+func digest(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("digest", flag.ContinueOnError)
+	output := flags.String("out", args.DigestFile, "write the digest to this file, as Markdown when it ends in .md and JSON otherwise")
+	flags.DurationVar(&args.DigestWindow, "window", args.DigestWindow, "time window of the summarized runs (default 24h)")
+	flags.StringVar(&args.HistoryDir, "history", args.HistoryDir, "history location read without stats files")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 && args.HistoryDir == "" {
+		return fmt.Errorf("usage: robot-stats digest [--window 24h] [--out file] [--history location] [stats file pattern...]")
+	}
+
+	d, err := plugin.LoadDigest(context.Background(), positional, args)
+	if err != nil {
+		return err
+	}
+	plugin.LogDigest(d, args.SummaryStyle)
+	if *output != "" {
+		return plugin.WriteDigest(*output, d)
+	}
+	return nil
+}
+
+// serve runs the HTTP results service until interrupted.
+func serve(argv []string, args plugin.Args) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	dataDir := flags.String("data-dir", "", "directory persisting uploaded runs")
+	maxUpload := flags.Int64("max-upload-size", 100, "maximum upload size in MB")
+	flags.BoolVar(&args.OnlyCritical, "only-critical", args.OnlyCritical, "only count critical tests")
+	flags.BoolVar(&args.CountSkippedTests, "count-skipped", args.CountSkippedTests, "count skipped tests")
+	positional, err := parseFlags(flags, argv)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("usage: robot-stats serve [--addr :8080] [--data-dir dir]")
+	}
+
+	server, err := plugin.NewServer(args, plugin.ServerOptions{DataDir: *dataDir, MaxUploadSize: *maxUpload << 20})
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return server.ListenAndServe(ctx, *addr)
+}
+
+// parseFlags parses the flags, allowing them to follow positional
+// arguments, and returns the positional arguments.
+func parseFlags(flags *flag.FlagSet, argv []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(argv); err != nil {
+			return nil, err
+		}
+		argv = flags.Args()
+		if len(argv) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, argv[0])
+		argv = argv[1:]
+	}
+}
+
+// writeJSON writes the value as JSON to the file, or to standard output
+// when the path is empty.
+func writeJSON(path string, v interface{}) error {
+	if path != "" {
+		return plugin.WriteJSON(path, v)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -6,6 +6,7 @@ package main
 
 import (
 	"context"
+	"os"
 
 	"github.com/drone/drone-robot/plugin"
 	"github.com/kelseyhightower/envconfig"
@@ -20,6 +21,25 @@ func main() {
 		logrus.Fatalf("\nFailed to process arguments: %s", err)
 	}
 
+	// Load the configuration file, with environment variables overriding
+	// the file values
+	if args.ConfigFile != "" {
+		path := args.ConfigFile
+		args = plugin.Args{}
+		if err := plugin.LoadConfigFile(path, &args); err != nil {
+			logrus.Fatalf("\nFailed to load configuration file: %s", err)
+		}
+		if err := envconfig.Process("", &args); err != nil {
+			logrus.Fatalf("\nFailed to process arguments: %s", err)
+		}
+	}
+
+	// Expand environment variable references, e.g. ${DRONE_BUILD_NUMBER}
+	plugin.ExpandArgs(&args, os.Getenv)
+
+	// Mask secret values in all log output
+	logrus.AddHook(plugin.NewSecretMasker(args))
+
 	switch args.Level {
 	case "debug":
 		logrus.SetFormatter(textFormatter)
@@ -28,22 +48,53 @@ func main() {
 		logrus.SetFormatter(textFormatter)
 		logrus.SetLevel(logrus.TraceLevel)
 	}
+	// Quiet mode only logs warnings, errors and the summary line
+	if args.Quiet {
+		logrus.SetLevel(logrus.WarnLevel)
+	}
+
+	stopProfiling := startProfiling(args)
+
+	// Run a CLI subcommand when invoked with arguments, e.g.
+	// `robot-stats analyze ./results --json out.json`
+	if len(os.Args) > 1 && os.Args[1] != "plugin" {
+		err := runCommand(os.Args[1], os.Args[2:], args)
+		stopProfiling()
+		if err != nil {
+			writeErrorReport(args, err)
+			logrus.Fatalf("\n%s failed: %s", os.Args[1], err)
+		}
+		return
+	}
 
 	logrus.Info("Starting Robot Framework plugin execution\n")
 
 	// Validate user inputs
 	if err := plugin.ValidateInputs(args); err != nil {
+		stopProfiling()
+		writeErrorReport(args, err)
 		logrus.Fatalf("\nInput validation failed: %s", err)
 	}
 
 	// Execute the plugin logic
-	if err := plugin.Exec(context.Background(), args); err != nil {
+	err := plugin.Exec(context.Background(), args)
+	stopProfiling()
+	if err != nil {
+		writeErrorReport(args, err)
 		logrus.Fatalf("\nPlugin execution failed")
 	}
 
 	logrus.Info("\nPlugin execution completed successfully")
 }
 
+// writeErrorReport writes the error report file, if configured, before
+// the plugin exits with the error.
+func writeErrorReport(args plugin.Args, err error) {
+	if werr := plugin.WriteErrorReport(args.ErrorReportFile, err); werr != nil {
+		logrus.Errorf("Failed to write error report: %s", werr)
+	}
+}
+
 // default formatter that writes logs without including timestamp
 // or level information.
 type formatter struct{}
@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AnalyzerOptions configures how an Analyzer counts tests.
+type AnalyzerOptions struct {
+	// FilePattern is the report file name pattern used by AnalyzeDir.
+	// Defaults to *.xml.
+	FilePattern string
+
+	// FileRegex replaces FilePattern with a regular expression matched
+	// while walking the directory recursively.
+	FileRegex string
+
+	// FileIgnoreCase matches the file pattern or regex case-insensitively,
+	// and SkipSymlinks ignores symbolic links during discovery.
+	FileIgnoreCase bool
+	SkipSymlinks   bool
+
+	// MaxSearchDepth limits the directory levels walked for FileRegex,
+	// zero for unlimited, and SkipHiddenDirs ignores dot directories.
+	MaxSearchDepth int
+	SkipHiddenDirs bool
+
+	OnlyCritical         bool
+	CountSkipped         bool
+	TreatSkippedAsFailed bool
+
+	// FastSummary reads the counts from the statistics block of the
+	// report instead of walking every test.
+	FastSummary bool
+
+	// MergeReruns merges the report files in execution order, counting
+	// tests that failed and then passed as flaky.
+	MergeReruns bool
+
+	// Robot Framework tag patterns and suite and test name patterns
+	// selecting the tests included in the statistics.
+	IncludeTags   []string
+	ExcludeTags   []string
+	IncludeSuites []string
+	ExcludeSuites []string
+	IncludeTests  []string
+	ExcludeTests  []string
+
+	// Per-test duration budgets in milliseconds, by default and by tag.
+	MaxTestDuration      float64
+	MaxTestDurationByTag map[string]float64
+
+	// FailureSort orders the failure details: suite (the default),
+	// duration or file.
+	FailureSort string
+
+	// Parsing limits: the report size in MB (zero for unlimited) and the
+	// element nesting depth (zero for the default).
+	MaxReportSize int
+	MaxXMLDepth   int
+}
+
+// Analyzer computes statistics for Robot Framework output files. It is
+// the programmatic entry point for tools embedding the parser.
+type Analyzer struct {
+	args Args
+
+	// failures streams the failure details to a file, when set.
+	failures *failureStream
+
+	// keepTests keeps the per-test results in Tests.
+	keepTests bool
+}
+
+// NewAnalyzer returns an Analyzer with the given options.
+func NewAnalyzer(opts AnalyzerOptions) *Analyzer {
+	followSymlinks := !opts.SkipSymlinks
+	return newAnalyzer(Args{
+		ReportFileNamePattern: opts.FilePattern,
+		ReportFileRegex:       opts.FileRegex,
+		ReportFileIgnoreCase:  opts.FileIgnoreCase,
+		FollowSymlinks:        &followSymlinks,
+		MaxSearchDepth:        opts.MaxSearchDepth,
+		SkipHiddenDirs:        opts.SkipHiddenDirs,
+		OnlyCritical:          opts.OnlyCritical,
+		CountSkippedTests:     opts.CountSkipped,
+		TreatSkippedAsFailed:  opts.TreatSkippedAsFailed,
+		FastSummary:           opts.FastSummary,
+		MergeReruns:           opts.MergeReruns,
+		IncludeTags:           opts.IncludeTags,
+		ExcludeTags:           opts.ExcludeTags,
+		IncludeSuites:         opts.IncludeSuites,
+		ExcludeSuites:         opts.ExcludeSuites,
+		IncludeTests:          opts.IncludeTests,
+		ExcludeTests:          opts.ExcludeTests,
+		MaxTestDuration:       opts.MaxTestDuration,
+		MaxTestDurationByTag:  opts.MaxTestDurationByTag,
+		MaxReportSize:         opts.MaxReportSize,
+		MaxXMLDepth:           opts.MaxXMLDepth,
+		FailureSort:           opts.FailureSort,
+	})
+}
+
+func newAnalyzer(args Args) *Analyzer {
+	if args.ReportFileNamePattern == "" {
+		args.ReportFileNamePattern = defaultReportFilePattern
+	}
+	return &Analyzer{args: args, keepTests: true}
+}
+
+// AnalyzeDir computes the aggregated statistics of the report files in
+// the directory, including per-test records. With a state manifest, only
+// the files that are new since the previous run are aggregated.
+func (a *Analyzer) AnalyzeDir(ctx context.Context, dir string) (*StatsResult, error) {
+	query, err := newFileQuery(a.args)
+	if err != nil {
+		return nil, err
+	}
+	files, err := locateFiles(dir, query)
+	if err != nil {
+		logrus.Errorf("Error locating files: %v", err)
+		return nil, fmt.Errorf("failed to locate files: %v", err)
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("no Robot Framework Report files found. Check the report file pattern")
+	}
+	if a.args.StateManifest != "" {
+		return a.analyzeNewFiles(ctx, files)
+	}
+	return a.AnalyzeFiles(ctx, files...)
+}
+
+// AnalyzeReader computes the statistics of a single report read from r.
+// The name identifies the report in logs.
+func (a *Analyzer) AnalyzeReader(ctx context.Context, name string, r io.Reader) (_ *StatsResult, err error) {
+	defer recoverParse(name, &err)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	opts, err := newStatsOptions(a.args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statistics options: %v", err)
+	}
+	opts.SkipTestResults = !a.keepTests
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", name, err)
+	}
+	robotOutput, err := parseOutput(data, name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := StatsResult{}
+	if robotOutput != nil {
+		stats = computeStats(*robotOutput, opts)
+	}
+	sortFailureDetails(&stats, a.args.FailureSort)
+	return &stats, nil
+}
+
+// AnalyzeFiles computes the aggregated statistics of the report files.
+func (a *Analyzer) AnalyzeFiles(ctx context.Context, files ...string) (*StatsResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	opts, err := newStatsOptions(a.args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid statistics options: %v", err)
+	}
+	opts.FailureStream = a.failures
+	opts.SkipTestResults = !a.keepTests || a.failures != nil
+
+	var stats StatsResult
+	if a.args.MergeReruns {
+		stats = processMergedFiles(files, opts)
+	} else {
+		stats = processFiles(files, opts)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sortFailureDetails(&stats, a.args.FailureSort)
+	return &stats, nil
+}
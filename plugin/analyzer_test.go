@@ -0,0 +1,37 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+)
+
+// TestAnalyzerAnalyzeDir validates the programmatic API
+func TestAnalyzerAnalyzeDir(t *testing.T) {
+	analyzer := NewAnalyzer(AnalyzerOptions{CountSkipped: true, IncludeTags: []string{"smoke"}})
+	stats, err := analyzer.AnalyzeDir(context.Background(), "../testdata/rf7")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.TotalTests != 1 || stats.PassedTests != 1 {
+		t.Errorf("Expected 1 passed smoke test, got %d tests and %d passed", stats.TotalTests, stats.PassedTests)
+	}
+	if len(stats.Tests) != 1 || stats.Tests[0].Name != "Pay With Card" {
+		t.Errorf("Expected the Pay With Card test record, got %+v", stats.Tests)
+	}
+}
+
+// TestAnalyzerErrors validates errors for missing reports, invalid options and cancelled contexts
+func TestAnalyzerErrors(t *testing.T) {
+	if _, err := NewAnalyzer(AnalyzerOptions{FilePattern: "missing.xml"}).AnalyzeDir(context.Background(), "../testdata"); err == nil {
+		t.Error("Expected an error when no report files match")
+	}
+	if _, err := NewAnalyzer(AnalyzerOptions{IncludeSuites: []string{"regex:("}}).AnalyzeFiles(context.Background(), "../testdata/robot_report.xml"); err == nil {
+		t.Error("Expected an error for an invalid suite pattern")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewAnalyzer(AnalyzerOptions{}).AnalyzeFiles(ctx, "../testdata/robot_report.xml"); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
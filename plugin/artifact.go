@@ -0,0 +1,130 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// artifactKind is the kind of the artifact metadata file read by the
+// Drone and Harness artifact metadata publisher.
+const artifactKind = "fileUpload/v1"
+
+// artifactMetadata is the artifact metadata file linking uploaded files
+// from the Artifacts tab.
+type artifactMetadata struct {
+	Kind string       `json:"kind"`
+	Data artifactData `json:"data"`
+}
+
+type artifactData struct {
+	FileArtifacts []fileArtifact `json:"fileArtifacts"`
+}
+
+type fileArtifact struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// robotArtifacts are the files Robot Framework writes next to its
+// output.xml.
+var robotArtifacts = []string{"log.html", "report.html"}
+
+// generatedReports returns the existing report files: the report files
+// written by the plugin, then the processed output files with their
+// log and report files, or their copies in the publish directory. Paths
+// are listed once.
+func generatedReports(args Args, stats StatsResult) []string {
+	var paths []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		if path == "" || seen[filepath.Clean(path)] {
+			return
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			return
+		}
+		seen[filepath.Clean(path)] = true
+		paths = append(paths, path)
+	}
+	for _, path := range []string{
+		args.HTMLReport,
+		args.MarkdownSummary,
+		args.TrendChart,
+		args.TimelineFile,
+		args.StatsFile,
+		args.CompareReport,
+		args.RDJSONFile,
+		args.FailureDetailsFile,
+		args.SplitManifest,
+	} {
+		add(path)
+	}
+	if args.PublishDir != "" {
+		for _, path := range publishedFiles(args.PublishDir, publishLayout(args.ReportDirectory, stats.Files)) {
+			add(path)
+		}
+		return paths
+	}
+	for _, file := range stats.Files {
+		add(file.File)
+		for _, name := range robotArtifacts {
+			add(filepath.Join(filepath.Dir(file.File), name))
+		}
+	}
+	return paths
+}
+
+// artifactURL returns the URL of the uploaded file: the file path
+// relative to the workspace, appended to the base URL of the upload.
+// Files outside the workspace are expected at the root of the upload.
+func artifactURL(baseURL, workspace, path string) string {
+	rel := filepath.Base(path)
+	if abs, err := filepath.Abs(path); err == nil {
+		if r, err := filepath.Rel(workspace, abs); err == nil && r != ".." && !strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			rel = r
+		}
+	}
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.Join(segments, "/")
+}
+
+// newArtifactMetadata returns the artifact metadata of the files.
+func newArtifactMetadata(baseURL, workspace string, paths []string) artifactMetadata {
+	metadata := artifactMetadata{Kind: artifactKind, Data: artifactData{FileArtifacts: []fileArtifact{}}}
+	for _, path := range paths {
+		metadata.Data.FileArtifacts = append(metadata.Data.FileArtifacts, fileArtifact{
+			Name: filepath.Base(path),
+			URL:  artifactURL(baseURL, workspace, path),
+		})
+	}
+	return metadata
+}
+
+// newArtifactMetadataReporter writes the artifact metadata file listing
+// the generated reports. It runs after the reporters writing them.
+func newArtifactMetadataReporter(args Args) (Reporter, error) {
+	if args.ArtifactFile == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		workspace, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		metadata := newArtifactMetadata(args.ArtifactBaseURL, workspace, generatedReports(args, stats))
+		if err := WriteJSON(args.ArtifactFile, metadata); err != nil {
+			return fmt.Errorf("failed to write artifact metadata: %v", err)
+		}
+		logrus.Infof("Wrote artifact metadata of %d reports to %s\n", len(metadata.Data.FileArtifacts), args.ArtifactFile)
+		return nil
+	}), nil
+}
@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestArtifactMetadataReporter validates the artifact metadata of the
+// generated reports and the Robot Framework files
+func TestArtifactMetadataReporter(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"reports/shard 1/output.xml", "reports/shard 1/log.html", "robot-report.html", "robot-stats.json"} {
+		file = filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args := Args{
+		ArtifactFile:    filepath.Join(dir, ".artifact"),
+		ArtifactBaseURL: "https://bucket.example.com/octo/42/",
+		HTMLReport:      filepath.Join(dir, "robot-report.html"),
+		StatsFile:       filepath.Join(dir, "robot-stats.json"),
+		MarkdownSummary: filepath.Join(dir, "missing.md"),
+	}
+	stats := StatsResult{Files: []FileStats{{File: filepath.Join(dir, "reports/shard 1/output.xml")}}}
+	got := newArtifactMetadata(args.ArtifactBaseURL, dir, generatedReports(args, stats))
+	want := artifactMetadata{Kind: "fileUpload/v1", Data: artifactData{FileArtifacts: []fileArtifact{
+		{Name: "robot-report.html", URL: "https://bucket.example.com/octo/42/robot-report.html"},
+		{Name: "robot-stats.json", URL: "https://bucket.example.com/octo/42/robot-stats.json"},
+		{Name: "output.xml", URL: "https://bucket.example.com/octo/42/reports/shard%201/output.xml"},
+		{Name: "log.html", URL: "https://bucket.example.com/octo/42/reports/shard%201/log.html"},
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Artifact metadata mismatch (-want +got):\n%s", diff)
+	}
+
+	reporter, err := newArtifactMetadataReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(args.ArtifactFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written artifactMetadata
+	if err := json.Unmarshal(data, &written); err != nil || written.Kind != artifactKind || len(written.Data.FileArtifacts) != 4 {
+		t.Errorf("Expected the metadata of 4 reports, got %s", data)
+	}
+}
+
+// TestArtifactURL validates the URLs of reports outside the workspace
+func TestArtifactURL(t *testing.T) {
+	workspace := filepath.Join(t.TempDir(), "workspace")
+	outside := filepath.Join(filepath.Dir(workspace), "other", "report.html")
+	if got := artifactURL("https://example.com/base", workspace, outside); got != "https://example.com/base/report.html" {
+		t.Errorf("Expected the report at the root of the upload, got %s", got)
+	}
+}
@@ -0,0 +1,77 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// firstFailedKeyword returns the innermost keyword of the first failure
+// in the keywords, in execution order. A failed control structure is
+// returned when no keyword inside it failed, e.g. for invalid syntax.
+func firstFailedKeyword(keywords []Keyword) (*Keyword, bool) {
+	for i := range keywords {
+		kw := &keywords[i]
+		if inner, ok := firstFailedKeyword(kw.Keywords); ok {
+			return inner, true
+		}
+		if kw.Status.Status == "FAIL" {
+			return kw, true
+		}
+	}
+	return nil, false
+}
+
+// keywordFailureMessage returns the failure message of a keyword: the
+// status text, or the last FAIL message of reports without one.
+func keywordFailureMessage(kw *Keyword) string {
+	if message := strings.TrimSpace(kw.Status.Text); message != "" {
+		return message
+	}
+	for i := len(kw.Messages) - 1; i >= 0; i-- {
+		if kw.Messages[i].Level == "FAIL" {
+			return strings.TrimSpace(kw.Messages[i].Text)
+		}
+	}
+	return ""
+}
+
+// countAtRiskTest counts a passed test with failed keywords beneath it,
+// adding its details when enabled.
+func countAtRiskTest(test Test, suiteName string, failed int, stats *StatsResult, opts statsOptions) {
+	stats.AtRiskTests++
+	if !opts.AtRiskDetails {
+		return
+	}
+	details := AtRiskTestDetails{
+		Name:           test.Name,
+		Suite:          namespaced(opts.namespace, suiteName),
+		FailedKeywords: failed,
+	}
+	if kw, ok := firstFailedKeyword(test.Keywords); ok {
+		details.Keyword = kw.displayName()
+		details.Message = keywordFailureMessage(kw)
+	}
+	stats.AtRiskTestsDetails = append(stats.AtRiskTestsDetails, details)
+}
+
+// logAtRiskTests logs the passed tests with failed keywords.
+func logAtRiskTests(stats StatsResult) {
+	if len(stats.AtRiskTestsDetails) == 0 {
+		return
+	}
+	logrus.Infof("At-Risk Test Details (passed with failed keywords):\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for i, test := range stats.AtRiskTestsDetails {
+		logrus.Infof("%d. Test Name: %s\n", i+1, test.Name)
+		logrus.Infof("   Suite: %s\n", test.Suite)
+		logrus.Infof("   Failed Keywords: %d\n", test.FailedKeywords)
+		if test.Keyword != "" {
+			logrus.Infof("   First Failed Keyword: %s\n", test.Keyword)
+		}
+		if test.Message != "" {
+			logrus.Infof("   Error Message: %s\n", test.Message)
+		}
+		logrus.Infof("-----------------------------------------------\n")
+	}
+}
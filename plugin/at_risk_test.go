@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestAtRiskTests validates the detection of passed tests with failed
+// keywords
+func TestAtRiskTests(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Ignored">
+<kw name="Run Keyword And Ignore Error">
+<kw name="Click Element"><msg level="FAIL">Element not found</msg><status status="FAIL"/></kw>
+<status status="PASS"/>
+</kw>
+<kw name="Run Keyword And Return Status">
+<kw name="Should Be Equal"><status status="FAIL">1 != 2</status></kw>
+<status status="PASS"/>
+</kw>
+<status status="PASS"/>
+</test>
+<test id="s1-t2" name="Clean">
+<kw name="Log"><status status="PASS"/></kw>
+<status status="PASS"/>
+</test>
+<test id="s1-t3" name="Failed">
+<kw name="Fail"><status status="FAIL">boom</status></kw>
+<status status="FAIL">boom</status>
+</test>
+</suite>
+</robot>`), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	if stats.AtRiskTests != 1 || stats.AtRiskTestsDetails != nil {
+		t.Errorf("Expected 1 at-risk test without details, got %d and %+v", stats.AtRiskTests, stats.AtRiskTestsDetails)
+	}
+
+	opts, err = newStatsOptions(Args{AtRiskTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats = computeStats(*robotOutput, opts)
+	want := []AtRiskTestDetails{
+		{Name: "Ignored", Suite: "Root", FailedKeywords: 2, Keyword: "Click Element", Message: "Element not found"},
+	}
+	if diff := cmp.Diff(want, stats.AtRiskTestsDetails); diff != "" {
+		t.Errorf("At-risk tests mismatch (-want +got):\n%s", diff)
+	}
+
+	opts, err = newStatsOptions(Args{SkipKeywordStats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := computeStats(*robotOutput, opts); stats.AtRiskTests != 0 {
+		t.Errorf("Expected no at-risk tests without keyword statistics, got %d", stats.AtRiskTests)
+	}
+}
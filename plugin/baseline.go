@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// timeRegression describes the execution time change of the whole run
+// or a single suite relative to the baseline run.
+type timeRegression struct {
+	Name     string
+	Baseline float64
+	Current  float64
+	Percent  float64
+}
+
+// loadBaseline computes statistics for the baseline run report, using
+// the same counting rules as the current run.
+func loadBaseline(path string, args Args) (StatsResult, error) {
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	return processFile(path, opts)
+}
+
+// compareExecutionTime returns the execution time change of the whole
+// run followed by every suite present in both runs. Suites without a
+// baseline execution time are ignored.
+func compareExecutionTime(current, baseline StatsResult) []timeRegression {
+	var regressions []timeRegression
+	if baseline.ExecutionTime > 0 {
+		regressions = append(regressions, newTimeRegression("total", baseline.ExecutionTime, current.ExecutionTime))
+	}
+
+	baselineSuites := suiteExecutionTimes(baseline)
+	currentSuites := suiteExecutionTimes(current)
+	for _, suite := range current.Suites {
+		base, ok := baselineSuites[suite.Name]
+		if !ok || base <= 0 {
+			continue
+		}
+		regressions = append(regressions, newTimeRegression(suite.Name, base, currentSuites[suite.Name]))
+		delete(baselineSuites, suite.Name) // report duplicate suite names once
+	}
+	return regressions
+}
+
+// executionTimeRegressionGates returns a gate result for the execution
+// time change of the whole run and of every suite, in percent.
+func executionTimeRegressionGates(current, baseline StatsResult, args Args) []GateResult {
+	warn, fail := args.ExecutionTimeRegressionWarn, args.ExecutionTimeRegressionFail
+	if warn == nil && fail == nil {
+		return nil
+	}
+
+	var gates []GateResult
+	for _, r := range compareExecutionTime(current, baseline) {
+		gate := GateResult{
+			Name:    "execution time regression " + r.Name,
+			Metric:  "execution_time_regression",
+			Limit:   firstLimit(fail, warn),
+			Actual:  r.Percent,
+			Outcome: gatePass,
+		}
+		switch {
+		case fail != nil && r.Percent > *fail:
+			gate.Outcome = gateFail
+			gate.Message = fmt.Sprintf("execution time of %s regressed by %s%% (%s ms -> %s ms), exceeding the fail threshold (%s%%)",
+				r.Name, formatMetric(r.Percent), formatMetric(r.Baseline), formatMetric(r.Current), formatMetric(*fail))
+		case warn != nil && r.Percent > *warn:
+			gate.Outcome, gate.Limit = gateWarn, warn
+			gate.Message = fmt.Sprintf("execution time of %s regressed by %s%% (%s ms -> %s ms), exceeding the warn threshold (%s%%)",
+				r.Name, formatMetric(r.Percent), formatMetric(r.Baseline), formatMetric(r.Current), formatMetric(*warn))
+			logrus.Warnf("Warning: %s", gate.Message)
+		}
+		gates = append(gates, gate)
+	}
+	return gates
+}
+
+func newTimeRegression(name string, baseline, current float64) timeRegression {
+	return timeRegression{
+		Name:     name,
+		Baseline: baseline,
+		Current:  current,
+		Percent:  (current - baseline) / baseline * 100,
+	}
+}
+
+// suiteExecutionTimes returns the execution time of every suite by
+// name, summing suites with the same name across report files.
+func suiteExecutionTimes(stats StatsResult) map[string]float64 {
+	times := map[string]float64{}
+	for _, suite := range stats.Suites {
+		times[suite.Name] += suite.ExecutionTime
+	}
+	return times
+}
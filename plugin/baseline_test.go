@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestExecutionTimeRegressionGates tests execution time regression gating
+func TestExecutionTimeRegressionGates(t *testing.T) {
+	baseline := StatsResult{
+		ExecutionTime: 1000,
+		Suites: []SuiteStats{
+			{Name: "Root", ExecutionTime: 1000},
+			{Name: "Root.Login", ExecutionTime: 400},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		current  StatsResult
+		args     Args
+		outcomes map[string]string
+		errMsg   string
+	}{
+		{
+			name: "No Limits",
+			current: StatsResult{
+				ExecutionTime: 5000,
+				Suites:        []SuiteStats{{Name: "Root", ExecutionTime: 5000}},
+			},
+		},
+		{
+			name: "Within Limits",
+			current: StatsResult{
+				ExecutionTime: 1050,
+				Suites: []SuiteStats{
+					{Name: "Root", ExecutionTime: 1050},
+					{Name: "Root.Login", ExecutionTime: 420},
+				},
+			},
+			args: Args{
+				ExecutionTimeRegressionFail: floatPtr(10),
+			},
+			outcomes: map[string]string{
+				"execution time regression total":      gatePass,
+				"execution time regression Root":       gatePass,
+				"execution time regression Root.Login": gatePass,
+			},
+		},
+		{
+			name: "Suite Regression Exceeds Fail Limit",
+			current: StatsResult{
+				ExecutionTime: 1080,
+				Suites: []SuiteStats{
+					{Name: "Root", ExecutionTime: 1080},
+					{Name: "Root.Login", ExecutionTime: 600},
+					{Name: "Root.New", ExecutionTime: 300},
+				},
+			},
+			args: Args{
+				ExecutionTimeRegressionWarn: floatPtr(5),
+				ExecutionTimeRegressionFail: floatPtr(20),
+			},
+			outcomes: map[string]string{
+				"execution time regression total":      gateWarn,
+				"execution time regression Root":       gateWarn,
+				"execution time regression Root.Login": gateFail,
+			},
+			errMsg: "execution time of Root.Login regressed by 50% (400 ms -> 600 ms)",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gates := executionTimeRegressionGates(tc.current, baseline, tc.args)
+			var outcomes map[string]string
+			for _, gate := range gates {
+				if outcomes == nil {
+					outcomes = map[string]string{}
+				}
+				outcomes[gate.Name] = gate.Outcome
+			}
+			if diff := cmp.Diff(tc.outcomes, outcomes); diff != "" {
+				t.Errorf("Gate outcomes mismatch (-want +got):\n%s", diff)
+			}
+
+			err := gateErrors(gates)
+			if tc.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// TestEvaluateGatesBaseline tests the regression gates of a baseline file
+func TestEvaluateGatesBaseline(t *testing.T) {
+	args := Args{
+		BaselineFile:                "../testdata/robot_report.xml",
+		ExecutionTimeRegressionFail: floatPtr(10),
+	}
+	baseline, err := loadBaseline(args.BaselineFile, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	current := baseline
+	current.ExecutionTime *= 2
+	gates, err := EvaluateGates(current, args)
+	if err == nil || !strings.Contains(err.Error(), "execution time of total regressed by 100%") {
+		t.Errorf("Expected a total regression error, got %v", err)
+	}
+	var total *GateResult
+	for i := range gates {
+		if gates[i].Name == "execution time regression total" {
+			total = &gates[i]
+		}
+	}
+	if total == nil || total.Outcome != gateFail || total.Actual != 100 {
+		t.Errorf("Expected a failed total regression gate, got %+v", gates)
+	}
+
+	args.BaselineFile = "../testdata/missing.xml"
+	if _, err := EvaluateGates(current, args); err == nil {
+		t.Error("Expected an error for a missing baseline file")
+	}
+}
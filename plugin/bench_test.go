@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// benchTests sets the number of tests in the generated benchmark report.
+// About 2300 tests make a megabyte; pass -bench.tests=2300000 to track
+// the throughput on 1GB reports.
+var benchTests = flag.Int("bench.tests", 20000, "number of tests in the benchmark report")
+
+// benchReport generates a Robot Framework 7 report with the number of
+// tests, every tenth of them failing, in suites of 100 tests.
+func benchReport(tests int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<robot generator="Robot 7.0.1 (Python 3.12.1 on linux)" generated="2025-02-09T15:30:00.123456" rpa="false" schemaversion="5">` + "\n")
+	buf.WriteString(`<suite id="s1" name="Bench" source="/work/tests">` + "\n")
+	failed := 0
+	for i := 0; i < tests; i++ {
+		if i%100 == 0 {
+			if i > 0 {
+				buf.WriteString(`<status status="FAIL" start="2025-02-09T15:30:00.800000" elapsed="2.700000"/>` + "\n</suite>\n")
+			}
+			fmt.Fprintf(&buf, `<suite id="s1-s%d" name="Suite %d" source="/work/tests/suite_%d.robot">`+"\n", i/100+1, i/100, i/100)
+		}
+		status, msg := "PASS", "INFO"
+		if i%10 == 0 {
+			status, msg = "FAIL", "FAIL"
+			failed++
+		}
+		fmt.Fprintf(&buf, `<test id="s1-t%d" name="Test %d" line="%d">`+"\n", i+1, i, i+1)
+		fmt.Fprintf(&buf, `<kw name="Should Be Equal" owner="BuiltIn">`+"\n"+`<msg time="2025-02-09T15:30:03.100000" level="%s">value &lt;%d&gt; checked</msg>`+"\n", msg, i)
+		buf.WriteString(`<arg>${value}</arg>` + "\n" + `<doc>Fails if the given objects are unequal.</doc>` + "\n")
+		fmt.Fprintf(&buf, `<status status="%s" start="2025-02-09T15:30:03.000000" elapsed="0.125000"/>`+"\n</kw>\n", status)
+		fmt.Fprintf(&buf, `<tag>smoke</tag>`+"\n"+`<status status="%s" start="2025-02-09T15:30:02.500000" elapsed="0.750500"/>`+"\n</test>\n", status)
+	}
+	buf.WriteString(`<status status="FAIL" start="2025-02-09T15:30:00.800000" elapsed="2.700000"/>` + "\n</suite>\n")
+	buf.WriteString(`<status status="FAIL" start="2025-02-09T15:30:00.500000" elapsed="3.000000"/>` + "\n</suite>\n")
+	fmt.Fprintf(&buf, "<statistics>\n<total>\n<stat pass=\"%d\" fail=\"%d\" skip=\"0\">All Tests</stat>\n</total>\n</statistics>\n", tests-failed, failed)
+	buf.WriteString("<errors>\n</errors>\n</robot>\n")
+	return buf.Bytes()
+}
+
+// benchFile writes the benchmark report to a temporary file.
+func benchFile(b *testing.B, data []byte) string {
+	path := filepath.Join(b.TempDir(), "output.xml")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	return path
+}
+
+// benchOptions returns the statistics options of the default settings.
+func benchOptions(b *testing.B) statsOptions {
+	logrus.SetLevel(logrus.ErrorLevel)
+	b.Cleanup(func() { logrus.SetLevel(logrus.InfoLevel) })
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	return opts
+}
+
+// BenchmarkCheckReport measures the limit scan run before unmarshaling.
+func BenchmarkCheckReport(b *testing.B) {
+	data := benchReport(*benchTests)
+	limits := newXMLLimits(Args{})
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := limits.checkReport(data, "output.xml"); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkParseOutput measures unmarshaling the report content.
+func BenchmarkParseOutput(b *testing.B) {
+	data := benchReport(*benchTests)
+	opts := benchOptions(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := parseOutput(data, "output.xml", opts); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkComputeStats measures computing the statistics of a parsed
+// report.
+func BenchmarkComputeStats(b *testing.B) {
+	data := benchReport(*benchTests)
+	opts := benchOptions(b)
+	robotOutput, err := parseOutput(data, "output.xml", opts)
+	if err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		computeStats(*robotOutput, opts)
+	}
+}
+
+// BenchmarkProcessFile measures the full parse path from the file.
+func BenchmarkProcessFile(b *testing.B) {
+	data := benchReport(*benchTests)
+	path := benchFile(b, data)
+	opts := benchOptions(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := processFile(path, opts); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkProcessFileSummary measures the fast summary path, which
+// skips the suite tree at byte level.
+func BenchmarkProcessFileSummary(b *testing.B) {
+	data := benchReport(*benchTests)
+	path := benchFile(b, data)
+	opts := benchOptions(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stats, err := processFileSummary(path, opts)
+		if err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		if stats.TotalTests != *benchTests {
+			b.Fatalf("Expected %d tests, got %d", *benchTests, stats.TotalTests)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package plugin
+
+// BuildInfo identifies the CI build the statistics were computed in. It
+// is attached to the JSON statistics and to the integration payloads.
+type BuildInfo struct {
+	Repo   string            `json:"repo,omitempty"`
+	Branch string            `json:"branch,omitempty"`
+	Commit string            `json:"commit,omitempty"`
+	Number string            `json:"number,omitempty"`
+	Stage  string            `json:"stage,omitempty"`
+	Step   string            `json:"step,omitempty"`
+	Link   string            `json:"link,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"` // PLUGIN_BUILD_LABELS
+}
+
+// newBuildInfo reads the build metadata from the Drone environment
+// variables, which Harness CI sets as well, and adds the custom labels.
+// It returns nil outside of a CI build without labels.
+func newBuildInfo(labels map[string]string, getenv func(string) string) *BuildInfo {
+	build := &BuildInfo{
+		Repo:   firstEnv(getenv, "DRONE_REPO"),
+		Branch: firstEnv(getenv, "DRONE_BRANCH", "DRONE_COMMIT_BRANCH"),
+		Commit: firstEnv(getenv, "DRONE_COMMIT_SHA", "DRONE_COMMIT"),
+		Number: firstEnv(getenv, "DRONE_BUILD_NUMBER"),
+		Stage:  firstEnv(getenv, "DRONE_STAGE_NAME"),
+		Step:   firstEnv(getenv, "DRONE_STEP_NAME"),
+		Link:   firstEnv(getenv, "DRONE_BUILD_LINK"),
+	}
+	if len(labels) > 0 {
+		build.Labels = labels
+	}
+	if build.Labels == nil && build.Repo == "" && build.Branch == "" && build.Commit == "" &&
+		build.Number == "" && build.Stage == "" && build.Step == "" && build.Link == "" {
+		return nil
+	}
+	return build
+}
+
+// firstEnv returns the first non-empty environment variable.
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if value := getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
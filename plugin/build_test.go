@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestBuildInfo validates the build metadata read from the environment
+func TestBuildInfo(t *testing.T) {
+	env := map[string]string{
+		"DRONE_REPO":          "octocat/hello-world",
+		"DRONE_COMMIT_BRANCH": "main",
+		"DRONE_COMMIT_SHA":    "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		"DRONE_BUILD_NUMBER":  "42",
+		"DRONE_STAGE_NAME":    "test",
+	}
+	got := newBuildInfo(map[string]string{"team": "qa"}, func(name string) string { return env[name] })
+	want := &BuildInfo{
+		Repo:   "octocat/hello-world",
+		Branch: "main",
+		Commit: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d",
+		Number: "42",
+		Stage:  "test",
+		Labels: map[string]string{"team": "qa"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected build info (-want +got):\n%s", diff)
+	}
+
+	if got := newBuildInfo(nil, func(string) string { return "" }); got != nil {
+		t.Errorf("Expected no build info outside of a build, got %+v", got)
+	}
+}
@@ -0,0 +1,197 @@
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+)
+
+// statsCacheVersion is part of every cache key. Increment it when the
+// statistics computed from a report change, to invalidate cached entries.
+const statsCacheVersion = 12
+
+// statsCache stores the statistics of report files in a directory, keyed
+// by the SHA-256 of the file content and the options the statistics were
+// computed with. A nil cache stores nothing.
+type statsCache struct {
+	dir  string
+	args Args
+}
+
+// cacheOptions holds the arguments affecting the statistics of a single
+// report file.
+type cacheOptions struct {
+	Version              int
+	OnlyCritical         bool
+	CountSkipped         bool
+	TreatSkippedAsFailed bool
+	NotRunAs             string
+	StatusMapping        map[string]string
+	ReservedTags         map[string]string
+	AtRiskDetails        bool
+	AllowFailureTag      string
+	FastSummary          bool
+	Salvage              bool
+	SkipKeywords         bool
+	OutputTags           map[string]string
+	TagStatistics        bool
+	Namespace            string
+	SchemaValidation     string
+	FailureContext       int
+	FailureContextLevel  string
+	MaxReportSize        int
+	MaxXMLDepth          int
+	MaxTestDuration      float64
+	MaxTestDurationByTag map[string]float64
+	IncludeTags          []string
+	ExcludeTags          []string
+	IncludeSuites        []string
+	ExcludeSuites        []string
+	IncludeTests         []string
+	ExcludeTests         []string
+	Scoring              *scoringModel
+	SkipTestResults      bool
+}
+
+// newStatsCache returns the cache of the plugin arguments, nil when no
+// cache directory is configured.
+func newStatsCache(args Args) *statsCache {
+	if args.CacheDir == "" {
+		return nil
+	}
+	return &statsCache{dir: args.CacheDir, args: args}
+}
+
+// key returns the cache key of the report file under the options, empty
+// when the file cannot be read.
+func (c *statsCache) key(filename string, opts statsOptions) string {
+	if c == nil {
+		return ""
+	}
+	hash, err := fileSHA256(filename)
+	if err != nil {
+		return ""
+	}
+	return hash + "-" + c.fingerprint(opts)
+}
+
+// fingerprint returns a short hash of the options the statistics are
+// computed with.
+func (c *statsCache) fingerprint(opts statsOptions) string {
+	args := c.args
+	data, _ := json.Marshal(cacheOptions{
+		Version:              statsCacheVersion,
+		OnlyCritical:         opts.OnlyCritical,
+		CountSkipped:         opts.CountSkipped,
+		TreatSkippedAsFailed: opts.TreatSkippedAsFailed,
+		NotRunAs:             opts.NotRunAs,
+		StatusMapping:        opts.StatusMapping,
+		ReservedTags:         opts.ReservedTags,
+		AtRiskDetails:        opts.AtRiskDetails,
+		AllowFailureTag:      args.AllowFailureTag,
+		FastSummary:          opts.FastSummary,
+		Salvage:              opts.Salvage,
+		SkipKeywords:         opts.SkipKeywords,
+		OutputTags:           opts.OutputTags,
+		TagStatistics:        opts.TagStatistics,
+		Namespace:            opts.namespace,
+		SchemaValidation:     opts.SchemaValidation,
+		FailureContext:       opts.FailureContext,
+		FailureContextLevel:  opts.FailureContextLevel,
+		MaxReportSize:        args.MaxReportSize,
+		MaxXMLDepth:          args.MaxXMLDepth,
+		MaxTestDuration:      opts.MaxTestDuration,
+		MaxTestDurationByTag: opts.MaxTestDurationByTag,
+		IncludeTags:          args.IncludeTags,
+		ExcludeTags:          args.ExcludeTags,
+		IncludeSuites:        args.IncludeSuites,
+		ExcludeSuites:        args.ExcludeSuites,
+		IncludeTests:         args.IncludeTests,
+		ExcludeTests:         args.ExcludeTests,
+		Scoring:              opts.Scoring,
+		SkipTestResults:      opts.SkipTestResults,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// load returns the cached statistics of the key.
+func (c *statsCache) load(key string) (StatsResult, bool) {
+	if c == nil || key == "" {
+		return StatsResult{}, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logrus.Warnf("Failed to read cached statistics: %v", err)
+		}
+		return StatsResult{}, false
+	}
+	var stats StatsResult
+	if err := json.Unmarshal(data, &stats); err != nil {
+		logrus.Warnf("Ignoring invalid cached statistics %s: %v", c.path(key), err)
+		return StatsResult{}, false
+	}
+	return stats, true
+}
+
+// store caches the statistics under the key. Failures are logged, the
+// cache never fails the run.
+func (c *statsCache) store(key string, stats StatsResult) {
+	if c == nil || key == "" {
+		return
+	}
+	if err := c.write(key, stats); err != nil {
+		logrus.Warnf("Failed to cache statistics: %v", err)
+	}
+}
+
+// write writes the entry to a temporary file renamed into place, so
+// concurrent runs sharing the cache never read partial entries.
+func (c *statsCache) write(key string, stats StatsResult) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+func (c *statsCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// fileSHA256 returns the hex encoded SHA-256 of the file content.
+func fileSHA256(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
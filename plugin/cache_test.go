@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestStatsCache validates that cached statistics are reused for unchanged files
+func TestStatsCache(t *testing.T) {
+	dir := t.TempDir()
+	report := filepath.Join(dir, "output.xml")
+	content, err := os.ReadFile("../testdata/robot_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(report, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := Args{CacheDir: filepath.Join(dir, "cache")}
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := processFile(report, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(args.CacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("Expected one cache entry, got %d", len(entries))
+	}
+
+	// a cached entry is returned as is
+	key := opts.Cache.key(report, opts)
+	cached := want
+	cached.TotalTests = 999
+	opts.Cache.store(key, cached)
+	got, err := processFile(report, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TotalTests != 999 {
+		t.Errorf("Expected the cached statistics, got %d tests", got.TotalTests)
+	}
+
+	// other counting options use another entry
+	other := opts
+	other.CountSkipped = !opts.CountSkipped
+	if opts.Cache.key(report, other) == key {
+		t.Errorf("Expected the options to change the cache key")
+	}
+
+	// changed content misses the cache
+	if err := os.WriteFile(report, append(content, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err = processFile(report, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected statistics of the changed file (-want +got):\n%s", diff)
+	}
+}
+
+// TestStatsCacheDisabled validates that a nil cache stores nothing
+func TestStatsCacheDisabled(t *testing.T) {
+	var cache *statsCache
+	if cache.key("../testdata/robot_report.xml", statsOptions{}) != "" {
+		t.Errorf("Expected no key without a cache")
+	}
+	if _, ok := cache.load("key"); ok {
+		t.Errorf("Expected no cached statistics without a cache")
+	}
+	cache.store("key", StatsResult{})
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// newReportDecoder returns an XML decoder for report content in UTF-8 or
+// UTF-16, with or without a byte order mark. Reports generated on
+// Windows are sometimes written as UTF-16.
+func newReportDecoder(r io.Reader) *xml.Decoder {
+	return newUTF8Decoder(utf8Reader(r))
+}
+
+// newUTF8Decoder returns an XML decoder for content already transcoded
+// by utf8Reader.
+func newUTF8Decoder(r io.Reader) *xml.Decoder {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-16", "utf-16le", "utf-16be", "utf16":
+			// already transcoded by utf8Reader
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	return decoder
+}
+
+// utf8Reader detects the encoding of the content from its byte order mark
+// or its first characters and returns the content as UTF-8, without the
+// byte order mark.
+func utf8Reader(r io.Reader) io.Reader {
+	return utf8BufferedReader(bufio.NewReader(r))
+}
+
+// utf8BufferedReader is utf8Reader reading from a buffered reader. UTF-8
+// content is read from br directly.
+func utf8BufferedReader(br *bufio.Reader) io.Reader {
+	head, _ := br.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		br.Discard(len(bomUTF8))
+		return br
+	case bytes.HasPrefix(head, bomUTF16LE):
+		br.Discard(len(bomUTF16LE))
+		return &utf16Reader{r: br, order: binary.LittleEndian}
+	case bytes.HasPrefix(head, bomUTF16BE):
+		br.Discard(len(bomUTF16BE))
+		return &utf16Reader{r: br, order: binary.BigEndian}
+	case bytes.HasPrefix(head, []byte{'<', 0, '?', 0}):
+		return &utf16Reader{r: br, order: binary.LittleEndian}
+	case bytes.HasPrefix(head, []byte{0, '<', 0, '?'}):
+		return &utf16Reader{r: br, order: binary.BigEndian}
+	}
+	return br
+}
+
+// utf16Reader transcodes a UTF-16 stream to UTF-8.
+type utf16Reader struct {
+	r     io.Reader
+	order binary.ByteOrder
+	in    []byte // undecoded input: an odd byte or a pending high surrogate
+	out   []byte // decoded output not yet returned
+	err   error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	buf := make([]byte, 4096)
+	for len(u.out) == 0 && u.err == nil {
+		n, err := u.r.Read(buf)
+		u.in = append(u.in, buf[:n]...)
+		u.err = err
+		u.decode()
+	}
+
+	n := copy(p, u.out)
+	u.out = u.out[n:]
+	if len(u.out) > 0 {
+		return n, nil
+	}
+	return n, u.err
+}
+
+// decode converts the complete code units of the pending input, keeping a
+// trailing high surrogate until its pair arrives or the input ends.
+func (u *utf16Reader) decode() {
+	units := make([]uint16, 0, len(u.in)/2)
+	for i := 0; i+1 < len(u.in); i += 2 {
+		units = append(units, u.order.Uint16(u.in[i:]))
+	}
+	consumed := len(units) * 2
+	if last := len(units) - 1; u.err == nil && last >= 0 && units[last] >= 0xD800 && units[last] < 0xDC00 {
+		units = units[:len(units)-1]
+		consumed -= 2
+	}
+
+	for _, r := range utf16.Decode(units) {
+		u.out = utf8.AppendRune(u.out, r)
+	}
+	u.in = u.in[consumed:]
+	if u.err != nil && len(u.in) > 0 {
+		// a truncated code unit at the end of the input
+		u.out = utf8.AppendRune(u.out, utf8.RuneError)
+		u.in = nil
+	}
+}
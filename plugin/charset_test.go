@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	var buf bytes.Buffer
+	if bom {
+		binary.Write(&buf, order, uint16(0xFEFF))
+	}
+	binary.Write(&buf, order, utf16.Encode([]rune(s)))
+	return buf.Bytes()
+}
+
+// TestUTF8Reader validates transcoding of the supported report encodings
+func TestUTF8Reader(t *testing.T) {
+	text := `<?xml version="1.0" encoding="UTF-16"?><robot name="Ünïcode 🤖"/>`
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{"UTF-8", []byte(text)},
+		{"UTF-8 BOM", append([]byte{0xEF, 0xBB, 0xBF}, text...)},
+		{"UTF-16LE BOM", encodeUTF16(text, binary.LittleEndian, true)},
+		{"UTF-16BE BOM", encodeUTF16(text, binary.BigEndian, true)},
+		{"UTF-16LE", encodeUTF16(text, binary.LittleEndian, false)},
+		{"UTF-16BE", encodeUTF16(text, binary.BigEndian, false)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Read one byte at a time to split surrogate pairs across reads
+			got, err := io.ReadAll(utf8Reader(iotest.OneByteReader(bytes.NewReader(tt.content))))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(got) != text {
+				t.Errorf("Expected %q, got %q", text, got)
+			}
+		})
+	}
+}
+
+// TestParseUTF16Report validates parsing of a UTF-16 report declaring its
+// encoding
+func TestParseUTF16Report(t *testing.T) {
+	content, err := os.ReadFile("../testdata/rf7/output.xml")
+	if err != nil {
+		t.Fatalf("Failed to read test data: %v", err)
+	}
+	content = bytes.Replace(content, []byte(`encoding="UTF-8"`), []byte(`encoding="UTF-16"`), 1)
+
+	robotOutput, err := parseOutput(encodeUTF16(string(content), binary.LittleEndian, true), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if robotOutput == nil || robotOutput.Generator == "" {
+		t.Fatalf("Expected a parsed report, got %+v", robotOutput)
+	}
+}
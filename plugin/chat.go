@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+)
+
+// maxDiscordContent is the message length limit of Discord.
+const maxDiscordContent = 2000
+
+// notificationUsername is the sender name of the chat notifications.
+const notificationUsername = "Robot Framework"
+
+// discordMessage is the payload of a Discord webhook.
+type discordMessage struct {
+	Content  string `json:"content"`
+	Username string `json:"username,omitempty"`
+}
+
+// mattermostMessage is the payload of a Mattermost incoming webhook.
+type mattermostMessage struct {
+	Text     string `json:"text"`
+	Username string `json:"username,omitempty"`
+	Channel  string `json:"channel,omitempty"` // overrides the webhook channel
+}
+
+// newDiscordReporter posts the notification message to a Discord
+// webhook.
+func newDiscordReporter(args Args) (Reporter, error) {
+	if args.DiscordWebhook == "" {
+		return nil, nil
+	}
+	tmpl, err := newNotificationTemplate(args)
+	if err != nil {
+		return nil, err
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		message, ok, err := renderNotification(tmpl, args, stats)
+		if err != nil || !ok {
+			return err
+		}
+		payload := discordMessage{Content: truncateMessage(message, maxDiscordContent), Username: notificationUsername}
+		if err := postWebhook(ctx, args.DiscordWebhook, payload); err != nil {
+			return fmt.Errorf("failed to notify Discord: %v", err)
+		}
+		return nil
+	}), nil
+}
+
+// newMattermostReporter posts the notification message to a Mattermost
+// incoming webhook.
+func newMattermostReporter(args Args) (Reporter, error) {
+	if args.MattermostWebhook == "" {
+		return nil, nil
+	}
+	tmpl, err := newNotificationTemplate(args)
+	if err != nil {
+		return nil, err
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		message, ok, err := renderNotification(tmpl, args, stats)
+		if err != nil || !ok {
+			return err
+		}
+		payload := mattermostMessage{Text: message, Username: notificationUsername, Channel: args.MattermostChannel}
+		if err := postWebhook(ctx, args.MattermostWebhook, payload); err != nil {
+			return fmt.Errorf("failed to notify Mattermost: %v", err)
+		}
+		return nil
+	}), nil
+}
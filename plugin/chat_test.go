@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestChatReporters validates the Discord and Mattermost payloads
+func TestChatReporters(t *testing.T) {
+	var payloads []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+	}))
+	defer server.Close()
+
+	stats := StatsResult{TotalTests: 1, FailedTests: 1, FailedTestsDetails: []FailedTestDetails{{Name: "Login", Suite: "Root", ErrorMessage: strings.Repeat("x", 3000)}}}
+	args := Args{DiscordWebhook: server.URL + "/discord", MattermostWebhook: server.URL + "/mattermost", MattermostChannel: "qa"}
+	for _, factory := range []ReporterFactory{newDiscordReporter, newMattermostReporter} {
+		reporter, err := factory(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Report(context.Background(), stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 webhook calls, got %d", len(payloads))
+	}
+	if content := payloads[0]["content"]; utf8.RuneCountInString(content) != maxDiscordContent || !strings.HasPrefix(content, "❌") {
+		t.Errorf("Expected a truncated Discord message, got %d characters", utf8.RuneCountInString(content))
+	}
+	if payloads[1]["channel"] != "qa" || !strings.Contains(payloads[1]["text"], "Root.Login") {
+		t.Errorf("Unexpected Mattermost payload %v", payloads[1])
+	}
+}
+
+// TestChatReporterErrors validates failed webhook calls and disabled
+// reporters
+func TestChatReporterErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid webhook", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	reporter, err := newDiscordReporter(Args{DiscordWebhook: server.URL + "/api/webhooks/1/token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reporter.Report(context.Background(), StatsResult{})
+	if err == nil || !strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "token") {
+		t.Errorf("Expected a 404 error without the webhook URL, got %v", err)
+	}
+
+	if reporter, err := newMattermostReporter(Args{}); reporter != nil || err != nil {
+		t.Errorf("Expected no Mattermost reporter without webhook, got %v and %v", reporter, err)
+	}
+}
@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// unclassifiedCategory is the category of failures matching no rule.
+const unclassifiedCategory = "unclassified"
+
+// ClassificationRule assigns a category to failed tests. The message,
+// test and suite patterns are name patterns (globs, or regular
+// expressions when prefixed with "regex:"); unset patterns match any
+// failure. The first matching rule wins.
+type ClassificationRule struct {
+	Category string `yaml:"category"`
+	Message  string `yaml:"message"`
+	Test     string `yaml:"test"`
+	Suite    string `yaml:"suite"`
+}
+
+type compiledClassificationRule struct {
+	category string
+	message  *regexp.Regexp
+	test     *regexp.Regexp
+	suite    *regexp.Regexp
+}
+
+func compileClassificationRules(rules []ClassificationRule) ([]compiledClassificationRule, error) {
+	var compiled []compiledClassificationRule
+	for _, rule := range rules {
+		if strings.TrimSpace(rule.Category) == "" {
+			return nil, errors.New("classification rules require a category")
+		}
+		c := compiledClassificationRule{category: rule.Category}
+		for _, p := range []struct {
+			pattern string
+			re      **regexp.Regexp
+		}{{rule.Message, &c.message}, {rule.Test, &c.test}, {rule.Suite, &c.suite}} {
+			if p.pattern == "" {
+				continue
+			}
+			re, err := compileNamePattern(p.pattern)
+			if err != nil {
+				return nil, err
+			}
+			*p.re = re
+		}
+		compiled = append(compiled, c)
+	}
+	return compiled, nil
+}
+
+func (r compiledClassificationRule) matches(test FailedTestDetails) bool {
+	return (r.message == nil || r.message.MatchString(test.ErrorMessage)) &&
+		(r.test == nil || r.test.MatchString(test.Name)) &&
+		(r.suite == nil || r.suite.MatchString(test.Suite))
+}
+
+// classifyFailures assigns a category to every failed test and counts
+// the failures per category.
+func classifyFailures(stats *StatsResult, rules []ClassificationRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	compiled, err := compileClassificationRules(rules)
+	if err != nil {
+		return err
+	}
+
+	stats.FailureCategories = map[string]int{}
+	for i := range stats.FailedTestsDetails {
+		test := &stats.FailedTestsDetails[i]
+		test.Category = unclassifiedCategory
+		for _, rule := range compiled {
+			if rule.matches(*test) {
+				test.Category = rule.category
+				break
+			}
+		}
+		stats.FailureCategories[test.Category]++
+	}
+	return nil
+}
+
+// logFailureCategories logs the failure count of every category.
+func logFailureCategories(stats StatsResult) {
+	if len(stats.FailureCategories) == 0 {
+		return
+	}
+	categories := make([]string, 0, len(stats.FailureCategories))
+	for category := range stats.FailureCategories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	logrus.Infof("Failure Categories:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, category := range categories {
+		logrus.Infof("%s: %d\n", category, stats.FailureCategories[category])
+	}
+	logrus.Infof("-----------------------------------------------\n")
+}
@@ -0,0 +1,35 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestClassifyFailures validates failure categories from classification rules
+func TestClassifyFailures(t *testing.T) {
+	stats := StatsResult{FailedTestsDetails: []FailedTestDetails{
+		{Name: "Login", Suite: "Auth", ErrorMessage: "ConnectionError: Connection refused"},
+		{Name: "Checkout", Suite: "Shop", ErrorMessage: "Element not found"},
+		{Name: "Search", Suite: "Shop", ErrorMessage: "1 != 2"},
+	}}
+	rules := []ClassificationRule{
+		{Category: "environment", Message: "*connection refused*"},
+		{Category: "locator", Message: "regex:not found$", Suite: "Shop"},
+	}
+
+	if err := classifyFailures(&stats, rules); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]int{"environment": 1, "locator": 1, "unclassified": 1}
+	if diff := cmp.Diff(expected, stats.FailureCategories); diff != "" {
+		t.Errorf("Failure categories mismatch (-want +got):\n%s", diff)
+	}
+	if stats.FailedTestsDetails[1].Category != "locator" {
+		t.Errorf("Expected locator category, got %q", stats.FailedTestsDetails[1].Category)
+	}
+
+	if err := classifyFailures(&stats, []ClassificationRule{{Message: "*"}}); err == nil {
+		t.Error("Expected an error for a rule without category")
+	}
+}
@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxLoggedDurationDeltas limits the duration deltas logged in the
+// comparison summary.
+const maxLoggedDurationDeltas = 10
+
+// RunDiff describes the differences between two result sets.
+type RunDiff struct {
+	NewlyFailing   []TestChange    `json:"newly_failing"`
+	NewlyPassing   []TestChange    `json:"newly_passing"`
+	Added          []TestResult    `json:"added"`
+	Removed        []TestResult    `json:"removed"`
+	DurationDeltas []DurationDelta `json:"duration_deltas"`
+}
+
+// TestChange describes a test whose status changed between two runs.
+type TestChange struct {
+	Name   string `json:"name"`
+	Suite  string `json:"suite"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// DurationDelta describes the duration change of a test present in both
+// runs, in milliseconds.
+type DurationDelta struct {
+	Name   string  `json:"name"`
+	Suite  string  `json:"suite"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Delta  float64 `json:"delta"`
+}
+
+// Compare loads two result sets and returns their differences. Each
+// result set is a stats JSON file, a report file or a directory of
+// report files matching the report file name pattern.
+func Compare(before, after string, args Args) (RunDiff, error) {
+	beforeStats, err := LoadResultSet(before, args)
+	if err != nil {
+		return RunDiff{}, fmt.Errorf("failed to load %s: %v", before, err)
+	}
+	afterStats, err := LoadResultSet(after, args)
+	if err != nil {
+		return RunDiff{}, fmt.Errorf("failed to load %s: %v", after, err)
+	}
+	return compareRuns(beforeStats, afterStats), nil
+}
+
+// LoadResultSet computes the statistics of a result set, using the same
+// counting rules as the current run.
+func LoadResultSet(path string, args Args) (StatsResult, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return readStatsFile(path)
+	}
+
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	opts.FastSummary = false // per-test results are required
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	if !info.IsDir() {
+		return processFile(path, opts)
+	}
+
+	query, err := newFileQuery(args)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	files, err := locateFiles(path, query)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	if len(files) == 0 {
+		return StatsResult{}, fmt.Errorf("no report files matching %s found in %s", query, path)
+	}
+	return processFiles(files, opts), nil
+}
+
+// compareRuns returns the test level differences between two runs.
+// Tests are matched by suite long name and test name, and by report file
+// when the name is not unique in the run.
+func compareRuns(before, after StatsResult) RunDiff {
+	diff := RunDiff{}
+	beforeKey, afterKey := runTestKeys(before.Tests), runTestKeys(after.Tests)
+	previous := map[string]TestResult{}
+	for _, test := range before.Tests {
+		previous[beforeKey(test)] = test
+	}
+
+	for _, test := range after.Tests {
+		key := afterKey(test)
+		old, ok := previous[key]
+		if !ok {
+			diff.Added = append(diff.Added, test)
+			continue
+		}
+		delete(previous, key)
+
+		change := TestChange{Name: test.Name, Suite: test.Suite, Before: old.Status, After: test.Status}
+		switch {
+		case old.Status != "FAIL" && test.Status == "FAIL":
+			diff.NewlyFailing = append(diff.NewlyFailing, change)
+		case old.Status != "PASS" && test.Status == "PASS":
+			diff.NewlyPassing = append(diff.NewlyPassing, change)
+		}
+
+		if old.Duration != test.Duration {
+			diff.DurationDeltas = append(diff.DurationDeltas, DurationDelta{
+				Name:   test.Name,
+				Suite:  test.Suite,
+				Before: old.Duration,
+				After:  test.Duration,
+				Delta:  test.Duration - old.Duration,
+			})
+		}
+	}
+
+	for _, test := range before.Tests {
+		if _, ok := previous[beforeKey(test)]; ok {
+			diff.Removed = append(diff.Removed, test)
+		}
+	}
+
+	// Largest duration changes first
+	sort.SliceStable(diff.DurationDeltas, func(i, j int) bool {
+		return math.Abs(diff.DurationDeltas[i].Delta) > math.Abs(diff.DurationDeltas[j].Delta)
+	})
+	return diff
+}
+
+func testKey(test TestResult) string {
+	return test.Suite + "." + test.Name
+}
+
+// runTestKeys returns the comparison key of the tests of a run. Tests
+// whose suite long name and test name occur more than once, e.g. the
+// same suite run by several shards, are told apart by their report file
+// relative to the common directory of the run's files, so the keys do
+// not depend on where the run's reports were stored.
+func runTestKeys(tests []TestResult) func(TestResult) string {
+	counts := map[string]int{}
+	common := ""
+	for i, test := range tests {
+		counts[testKey(test)]++
+		dir := filepath.Dir(test.File)
+		if i == 0 {
+			common = dir
+		}
+		for !strings.HasPrefix(dir+string(filepath.Separator), common+string(filepath.Separator)) && common != filepath.Dir(common) {
+			common = filepath.Dir(common)
+		}
+	}
+	return func(test TestResult) string {
+		key := testKey(test)
+		if counts[key] < 2 {
+			return key
+		}
+		file := test.File
+		if rel, err := filepath.Rel(common, test.File); err == nil {
+			file = rel
+		}
+		return key + "@" + filepath.ToSlash(file)
+	}
+}
+
+// LogRunDiff logs the comparison summary in the summary style.
+func LogRunDiff(diff RunDiff, style string) {
+	logSummary("Robot Framework Run Comparison", []summaryRow{
+		{"❌", "Newly Failing Tests", fmt.Sprint(len(diff.NewlyFailing))},
+		{"✅", "Newly Passing Tests", fmt.Sprint(len(diff.NewlyPassing))},
+		{"➕", "Added Tests", fmt.Sprint(len(diff.Added))},
+		{"➖", "Removed Tests", fmt.Sprint(len(diff.Removed))},
+	}, style)
+
+	for _, change := range diff.NewlyFailing {
+		logrus.Infof("Newly failing: %s.%s (%s -> %s)\n", change.Suite, change.Name, change.Before, change.After)
+	}
+	for _, change := range diff.NewlyPassing {
+		logrus.Infof("Newly passing: %s.%s (%s -> %s)\n", change.Suite, change.Name, change.Before, change.After)
+	}
+	for _, test := range diff.Added {
+		logrus.Infof("Added: %s.%s (%s)\n", test.Suite, test.Name, test.Status)
+	}
+	for _, test := range diff.Removed {
+		logrus.Infof("Removed: %s.%s\n", test.Suite, test.Name)
+	}
+
+	for i, delta := range diff.DurationDeltas {
+		if i == maxLoggedDurationDeltas {
+			logrus.Infof("... and %d more duration changes\n", len(diff.DurationDeltas)-i)
+			break
+		}
+		logrus.Infof("Duration: %s.%s %s ms -> %s ms (%+.2f ms)\n",
+			delta.Suite, delta.Name, formatMetric(delta.Before), formatMetric(delta.After), delta.Delta)
+	}
+}
+
+// writeRunDiff sets the comparison counts as output variables.
+func writeRunDiff(out *outputVars, diff RunDiff) {
+	out.set("NEWLY_FAILING_TESTS", strconv.Itoa(len(diff.NewlyFailing)))
+	out.set("NEWLY_PASSING_TESTS", strconv.Itoa(len(diff.NewlyPassing)))
+	out.set("ADDED_TESTS", strconv.Itoa(len(diff.Added)))
+	out.set("REMOVED_TESTS", strconv.Itoa(len(diff.Removed)))
+}
+
+// WriteJSON writes the value as indented JSON to the file.
+func WriteJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// readStatsFile reads statistics written with PLUGIN_STATS_FILE.
+func readStatsFile(path string) (StatsResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	stats, err := parseStatsFile(data)
+	if err != nil {
+		return StatsResult{}, fmt.Errorf("failed to parse stats file %s: %v", path, err)
+	}
+	return stats, nil
+}
+
+// parseStatsFile decodes statistics written with PLUGIN_STATS_FILE.
+func parseStatsFile(data []byte) (StatsResult, error) {
+	var stats StatsResult
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return StatsResult{}, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestCompareRuns validates the test level differences between two runs
+func TestCompareRuns(t *testing.T) {
+	before := StatsResult{Tests: []TestResult{
+		{Name: "Login", Suite: "Root", Status: "PASS", Duration: 100},
+		{Name: "Logout", Suite: "Root", Status: "FAIL", Duration: 50},
+		{Name: "Search", Suite: "Root", Status: "PASS", Duration: 300},
+		{Name: "Legacy", Suite: "Root", Status: "PASS", Duration: 10},
+	}}
+	after := StatsResult{Tests: []TestResult{
+		{Name: "Login", Suite: "Root", Status: "FAIL", Duration: 120},
+		{Name: "Logout", Suite: "Root", Status: "PASS", Duration: 50},
+		{Name: "Search", Suite: "Root", Status: "PASS", Duration: 200},
+		{Name: "Checkout", Suite: "Root", Status: "PASS", Duration: 70},
+	}}
+
+	expected := RunDiff{
+		NewlyFailing: []TestChange{{Name: "Login", Suite: "Root", Before: "PASS", After: "FAIL"}},
+		NewlyPassing: []TestChange{{Name: "Logout", Suite: "Root", Before: "FAIL", After: "PASS"}},
+		Added:        []TestResult{{Name: "Checkout", Suite: "Root", Status: "PASS", Duration: 70}},
+		Removed:      []TestResult{{Name: "Legacy", Suite: "Root", Status: "PASS", Duration: 10}},
+		DurationDeltas: []DurationDelta{
+			{Name: "Search", Suite: "Root", Before: 300, After: 200, Delta: -100},
+			{Name: "Login", Suite: "Root", Before: 100, After: 120, Delta: 20},
+		},
+	}
+	if diff := cmp.Diff(expected, compareRuns(before, after)); diff != "" {
+		t.Errorf("Run diff mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestCompareRunsShards validates matching identically named tests of
+// different shards by their report file, wherever the runs were stored
+func TestCompareRunsShards(t *testing.T) {
+	before := StatsResult{Tests: []TestResult{
+		{Name: "Login", Suite: "Root", Status: "PASS", File: "previous/shard-1/output.xml"},
+		{Name: "Login", Suite: "Root", Status: "FAIL", File: "previous/shard-2/output.xml"},
+		{Name: "Search", Suite: "Root", Status: "PASS", File: "previous/shard-2/output.xml"},
+	}}
+	after := StatsResult{Tests: []TestResult{
+		{Name: "Login", Suite: "Root", Status: "PASS", File: "reports/shard-1/output.xml"},
+		{Name: "Login", Suite: "Root", Status: "PASS", File: "reports/shard-2/output.xml"},
+		{Name: "Search", Suite: "Root", Status: "FAIL", File: "reports/shard-1/output.xml"},
+	}}
+
+	diff := compareRuns(before, after)
+	want := RunDiff{
+		NewlyFailing: []TestChange{{Name: "Search", Suite: "Root", Before: "PASS", After: "FAIL"}},
+		NewlyPassing: []TestChange{{Name: "Login", Suite: "Root", Before: "FAIL", After: "PASS"}},
+	}
+	if d := cmp.Diff(want, diff); d != "" {
+		t.Errorf("Run diff mismatch (-want +got):\n%s", d)
+	}
+}
+
+// TestCompareStatsFile validates comparing a stats JSON with a report file
+func TestCompareStatsFile(t *testing.T) {
+	args := Args{CountSkippedTests: true}
+	stats, err := LoadResultSet("../testdata/rf7/output.xml", args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Record the passing test as failed in the previous run
+	stats.Tests[0].Status = "FAIL"
+	path := filepath.Join(t.TempDir(), "stats.json")
+	if err := WriteJSON(path, stats); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	diff, err := Compare(path, "../testdata/rf7", args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(diff.NewlyPassing) != 1 || diff.NewlyPassing[0].Name != "Pay With Card" {
+		t.Errorf("Expected Pay With Card to be newly passing, got %+v", diff.NewlyPassing)
+	}
+	if len(diff.NewlyFailing)+len(diff.Added)+len(diff.Removed)+len(diff.DurationDeltas) != 0 {
+		t.Errorf("Expected no other differences, got %+v", diff)
+	}
+}
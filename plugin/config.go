@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ReporterConfig holds the configuration file settings of a reporter.
+// The "enabled" setting disables a reporter when set to false.
+type ReporterConfig map[string]interface{}
+
+// Enabled reports whether the reporter is not disabled.
+func (c ReporterConfig) Enabled() bool {
+	enabled, ok := c["enabled"].(bool)
+	return !ok || enabled
+}
+
+// Decode decodes the reporter settings into v, a pointer to a struct
+// with yaml field tags.
+func (c ReporterConfig) Decode(v interface{}) error {
+	data, err := yaml.Marshal(map[string]interface{}(c))
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(data, v)
+}
+
+// LoadConfigFile reads a YAML or JSON configuration file into args. The
+// file uses the setting names of the environment variables, lowercased
+// and without the PLUGIN_ prefix (e.g. pass_threshold), plus the
+// structured settings only available in the configuration file. Process
+// the environment afterwards so environment variables override the file.
+func LoadConfigFile(path string, args *Args) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	var settings map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := settingFields(args)
+	for _, name := range names {
+		field, ok := fields[name]
+		if !ok {
+			return fmt.Errorf("unknown setting %q in config file %s", name, path)
+		}
+		node := settings[name]
+		if err := decodeSetting(&node, field); err != nil {
+			return fmt.Errorf("invalid value for setting %q in config file %s: %v", name, path, err)
+		}
+	}
+	args.ConfigFile = path
+	return nil
+}
+
+// settingFields maps the configuration file setting names to the
+// argument fields.
+func settingFields(args *Args) map[string]reflect.Value {
+	fields := map[string]reflect.Value{}
+	v := reflect.ValueOf(args).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if name, ok := t.Field(i).Tag.Lookup("yaml"); ok {
+			fields[name] = v.Field(i)
+		} else if env, ok := t.Field(i).Tag.Lookup("envconfig"); ok {
+			fields[strings.ToLower(strings.TrimPrefix(env, "PLUGIN_"))] = v.Field(i)
+		}
+	}
+	return fields
+}
+
+// decodeSetting decodes a setting into the field. Lists may also be
+// given as comma separated strings, as in environment variables.
+func decodeSetting(node *yaml.Node, field reflect.Value) error {
+	if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String && node.Kind == yaml.ScalarNode {
+		var values []string
+		for _, value := range strings.Split(node.Value, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+		return nil
+	}
+	return node.Decode(field.Addr().Interface())
+}
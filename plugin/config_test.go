@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/kelseyhightower/envconfig"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// TestLoadConfigFile validates loading YAML settings with environment overrides
+func TestLoadConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "robot.yml", `
+report_directory: ./reports
+pass_threshold: 3
+failure_rate_fail: 10
+include_tags: [smoke, regression]
+exclude_tags: wip, draft
+max_test_duration_by_tag:
+  slow: 5000
+suite_thresholds:
+  - suite: Checkout*
+    failed_tests_fail: 0
+classification_rules:
+  - category: environment
+    message: "*Connection refused*"
+reporters:
+  console:
+    enabled: false
+`)
+
+	t.Setenv("PLUGIN_PASS_THRESHOLD", "5")
+	var args Args
+	if err := LoadConfigFile(path, &args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := envconfig.Process("", &args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	zero, ten := 0.0, 10.0
+	expected := Args{
+		ReportDirectory:      "./reports",
+		PassThreshold:        5,
+		ConfigFile:           path,
+		FailureRateFail:      &ten,
+		IncludeTags:          []string{"smoke", "regression"},
+		ExcludeTags:          []string{"wip", "draft"},
+		MaxTestDurationByTag: map[string]float64{"slow": 5000},
+		SuiteThresholds:      []SuiteThreshold{{Suite: "Checkout*", FailedTestsFail: &zero}},
+		ClassificationRules:  []ClassificationRule{{Category: "environment", Message: "*Connection refused*"}},
+		Reporters:            map[string]ReporterConfig{"console": {"enabled": false}},
+	}
+	if diff := cmp.Diff(expected, args); diff != "" {
+		t.Errorf("Args mismatch (-want +got):\n%s", diff)
+	}
+	if args.Reporters["console"].Enabled() || !args.Reporters["drone_output"].Enabled() {
+		t.Error("Expected only the console reporter to be disabled")
+	}
+}
+
+// TestLoadConfigFileJSON validates loading JSON settings and reporter settings
+func TestLoadConfigFileJSON(t *testing.T) {
+	path := writeConfigFile(t, "robot.json", `{"report_directory": "out", "count_skipped_tests": true, "reporters": {"webhook": {"url": "https://example.com"}}}`)
+
+	var args Args
+	if err := LoadConfigFile(path, &args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if args.ReportDirectory != "out" || !args.CountSkippedTests {
+		t.Errorf("Unexpected args: %+v", args)
+	}
+
+	var webhook struct {
+		URL string `yaml:"url"`
+	}
+	if err := args.Reporters["webhook"].Decode(&webhook); err != nil || webhook.URL != "https://example.com" {
+		t.Errorf("Expected webhook url, got %q (%v)", webhook.URL, err)
+	}
+}
+
+// TestLoadConfigFileErrors validates errors for unknown settings and invalid values
+func TestLoadConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		errMsg  string
+	}{
+		{content: "pass_treshold: 1", errMsg: `unknown setting "pass_treshold"`},
+		{content: "pass_threshold: many", errMsg: `invalid value for setting "pass_threshold"`},
+		{content: "report_directory: [", errMsg: "failed to parse config file"},
+	}
+	for _, tc := range tests {
+		var args Args
+		err := LoadConfigFile(writeConfigFile(t, "robot.yml", tc.content), &args)
+		if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+			t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// confluenceAPITimeout limits each Confluence API call.
+const confluenceAPITimeout = 30 * time.Second
+
+// confluenceColours are the status macro colours of the gate outcomes.
+var confluenceColours = map[string]string{
+	gatePass: "Green",
+	gateWarn: "Yellow",
+	gateFail: "Red",
+}
+
+// confluenceTemplate renders the summary in the Confluence storage
+// format, which is XHTML with Confluence macros.
+var confluenceTemplate = htmltemplate.Must(htmltemplate.New("confluence").Funcs(htmltemplate.FuncMap{
+	"colour":   func(outcome string) string { return confluenceColours[outcome] },
+	"streak":   failingStreak,
+	"duration": formatDuration,
+}).Parse(`
+{{- with .Stats.Build}}<p>{{.Repo}}{{with .Branch}} · {{.}}{{end}}{{with .Commit}} · {{.}}{{end}}{{with .Number}} · build {{if $.Stats.Build.Link}}<a href="{{$.Stats.Build.Link}}">#{{.}}</a>{{else}}#{{.}}{{end}}{{end}}</p>{{end}}
+{{- with .Outcome}}<p>Gates: <ac:structured-macro ac:name="status"><ac:parameter ac:name="colour">{{colour .}}</ac:parameter><ac:parameter ac:name="title">{{.}}</ac:parameter></ac:structured-macro></p>{{end}}
+<h2>Summary</h2>
+<table><tbody>
+{{- range .Rows}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</tbody></table>
+{{- with .Stats.Gates}}
+<h2>Gates</h2>
+<table><tbody>
+<tr><th>Gate</th><th>Actual</th><th>Outcome</th><th>Message</th></tr>
+{{- range .}}
+<tr><td>{{.Name}}</td><td>{{.Actual}}</td><td>{{.Outcome}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</tbody></table>
+{{- end}}
+{{- with .Stats.FailedTestsDetails}}
+<h2>Failed Tests</h2>
+<table><tbody>
+<tr><th>Suite</th><th>Test</th><th>Duration</th><th>Failing</th><th>Message</th></tr>
+{{- range .}}
+<tr><td>{{.Suite}}</td><td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td>{{duration .Duration $.Unit}}</td><td>{{streak .}}</td><td>{{.ErrorMessage}}</td></tr>
+{{- end}}
+</tbody></table>
+{{- if $.Stats.OmittedFailureDetails}}
+<p>... and {{$.Stats.OmittedFailureDetails}} more failed tests</p>
+{{- end}}
+{{- end}}
+{{- with .Stats.FailureLeaderboard}}
+<h2>Most Frequent Failures</h2>
+<table><tbody>
+<tr><th>Suite</th><th>Test</th><th>Failed Runs</th><th>Last Failed Build</th><th>Last Message</th></tr>
+{{- range .}}
+<tr><td>{{.Suite}}</td><td>{{.Name}}</td><td>{{.Failures}} / {{.Runs}}</td><td>{{.LastBuild}}</td><td>{{.LastMessage}}</td></tr>
+{{- end}}
+</tbody></table>
+{{- end}}
+`))
+
+// confluenceTitle returns the page title: the configured title, or one
+// page per repository and branch.
+func confluenceTitle(title string, build *BuildInfo) string {
+	if title != "" {
+		return title
+	}
+	title = "Robot Framework Test Report"
+	if build != nil {
+		for _, part := range []string{build.Repo, build.Branch} {
+			if part != "" {
+				title += " · " + part
+			}
+		}
+	}
+	return title
+}
+
+// confluenceClient calls the Confluence REST API, which Confluence Cloud
+// and Server share. Requests are authenticated with basic authentication
+// when a username is set, e.g. the account email and an API token on
+// Cloud, and with the token as bearer token otherwise, e.g. a personal
+// access token on Server.
+type confluenceClient struct {
+	baseURL  string // e.g. https://example.atlassian.net/wiki
+	username string
+	token    string
+}
+
+// confluencePage is a page of the Confluence REST API.
+type confluencePage struct {
+	ID        string               `json:"id,omitempty"`
+	Type      string               `json:"type"`
+	Title     string               `json:"title"`
+	Space     *confluenceSpace     `json:"space,omitempty"`
+	Ancestors []confluenceAncestor `json:"ancestors,omitempty"`
+	Body      *confluenceBody      `json:"body,omitempty"`
+	Version   *confluenceVersion   `json:"version,omitempty"`
+	Links     *confluencePageLinks `json:"_links,omitempty"`
+}
+
+type confluenceSpace struct {
+	Key string `json:"key"`
+}
+
+type confluenceAncestor struct {
+	ID string `json:"id"`
+}
+
+type confluenceBody struct {
+	Storage confluenceStorage `json:"storage"`
+}
+
+type confluenceStorage struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+type confluenceVersion struct {
+	Number int `json:"number"`
+}
+
+type confluencePageLinks struct {
+	Base  string `json:"base,omitempty"`
+	WebUI string `json:"webui,omitempty"`
+}
+
+// do sends the request with the JSON body, if any, and decodes the JSON
+// response into v, if any.
+func (c *confluenceClient) do(ctx context.Context, method, path string, body, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	ctx, cancel := context.WithTimeout(ctx, confluenceAPITimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s responded with %s", method, redactedURL(req.URL), resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// findPage returns the page with the title in the space, or nil when
+// there is none.
+func (c *confluenceClient) findPage(ctx context.Context, space, title string) (*confluencePage, error) {
+	query := url.Values{"spaceKey": {space}, "title": {title}, "expand": {"version"}}
+	var result struct {
+		Results []confluencePage `json:"results"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/rest/api/content?"+query.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+	return &result.Results[0], nil
+}
+
+// publish creates the page in the space, below the parent page when set,
+// or updates it with a new version when a page with the title exists. It
+// returns the published page.
+func (c *confluenceClient) publish(ctx context.Context, space, parent, title, content string) (confluencePage, error) {
+	existing, err := c.findPage(ctx, space, title)
+	if err != nil {
+		return confluencePage{}, err
+	}
+	page := confluencePage{
+		Type:  "page",
+		Title: title,
+		Space: &confluenceSpace{Key: space},
+		Body:  &confluenceBody{Storage: confluenceStorage{Value: content, Representation: "storage"}},
+	}
+	var published confluencePage
+	if existing == nil {
+		if parent != "" {
+			page.Ancestors = []confluenceAncestor{{ID: parent}}
+		}
+		err = c.do(ctx, http.MethodPost, "/rest/api/content", page, &published)
+	} else {
+		page.ID = existing.ID
+		page.Version = &confluenceVersion{Number: 1}
+		if existing.Version != nil {
+			page.Version.Number = existing.Version.Number + 1
+		}
+		err = c.do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(existing.ID), page, &published)
+	}
+	return published, err
+}
+
+// newConfluenceReporter publishes the summary as a Confluence page,
+// created on the first run and updated by later runs with the same title.
+func newConfluenceReporter(args Args) (Reporter, error) {
+	if args.ConfluenceURL == "" {
+		return nil, nil
+	}
+	client := &confluenceClient{baseURL: args.ConfluenceURL, username: args.ConfluenceUsername, token: args.ConfluenceToken}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		var b bytes.Buffer
+		if err := confluenceTemplate.Execute(&b, newReportData(stats, args.DurationUnit)); err != nil {
+			return err
+		}
+		title := confluenceTitle(args.ConfluenceTitle, stats.Build)
+		page, err := client.publish(ctx, args.ConfluenceSpace, args.ConfluenceParentID, title, b.String())
+		if err != nil {
+			return fmt.Errorf("failed to publish Confluence page %q: %v", title, err)
+		}
+		link := title
+		if page.Links != nil && page.Links.WebUI != "" {
+			link = page.Links.Base + page.Links.WebUI
+		}
+		logrus.Infof("Published the summary to Confluence: %s\n", link)
+		return nil
+	}), nil
+}
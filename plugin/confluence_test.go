@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeConfluence is a Confluence REST API keeping a single space of
+// pages in memory.
+type fakeConfluence struct {
+	pages []confluencePage
+	auth  []string
+}
+
+func (f *fakeConfluence) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/wiki/rest/api/content":
+		var results []confluencePage
+		for _, page := range f.pages {
+			if page.Title == r.URL.Query().Get("title") && page.Space.Key == r.URL.Query().Get("spaceKey") {
+				results = append(results, page)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	case r.Method == http.MethodPost && r.URL.Path == "/wiki/rest/api/content":
+		var page confluencePage
+		json.NewDecoder(r.Body).Decode(&page)
+		page.ID = "100"
+		page.Version = &confluenceVersion{Number: 1}
+		page.Links = &confluencePageLinks{Base: "https://example.atlassian.net/wiki", WebUI: "/spaces/QA/pages/100"}
+		f.pages = append(f.pages, page)
+		json.NewEncoder(w).Encode(page)
+	case r.Method == http.MethodPut && r.URL.Path == "/wiki/rest/api/content/100":
+		var page confluencePage
+		json.NewDecoder(r.Body).Decode(&page)
+		if page.Version == nil || page.Version.Number != f.pages[0].Version.Number+1 {
+			http.Error(w, "version conflict", http.StatusConflict)
+			return
+		}
+		f.pages[0] = page
+		json.NewEncoder(w).Encode(page)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// TestConfluenceReporter validates creating the page on the first run
+// and updating it with a new version afterwards
+func TestConfluenceReporter(t *testing.T) {
+	confluence := &fakeConfluence{}
+	server := httptest.NewServer(confluence)
+	defer server.Close()
+
+	args := Args{ConfluenceURL: server.URL + "/wiki/", ConfluenceSpace: "QA", ConfluenceParentID: "42", ConfluenceUsername: "ci@example.com", ConfluenceToken: "secret"}
+	reporter, err := newConfluenceReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := StatsResult{
+		TotalTests:         2,
+		FailedTests:        1,
+		Build:              &BuildInfo{Repo: "octo/app", Branch: "main"},
+		Gates:              []GateResult{{Name: "failed_tests", Outcome: gateFail}},
+		FailedTestsDetails: []FailedTestDetails{{Name: "Login", Suite: "Root", ErrorMessage: "a < b"}},
+	}
+	for run := 0; run < 2; run++ {
+		if err := reporter.Report(context.Background(), stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(confluence.pages) != 1 {
+		t.Fatalf("Expected a single page, got %d", len(confluence.pages))
+	}
+	page := confluence.pages[0]
+	if page.Title != "Robot Framework Test Report · octo/app · main" || page.Version.Number != 2 {
+		t.Errorf("Expected the second version of the branch page, got %q version %d", page.Title, page.Version.Number)
+	}
+	content := page.Body.Storage.Value
+	for _, want := range []string{`<ac:parameter ac:name="colour">Red</ac:parameter>`, "<td>Login</td>", "a &lt; b"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected the page to contain %q, got:\n%s", want, content)
+		}
+	}
+	if confluence.auth[0] == "" || !strings.HasPrefix(confluence.auth[0], "Basic ") {
+		t.Errorf("Expected basic authentication, got %q", confluence.auth[0])
+	}
+}
+
+// TestConfluenceReporterErrors validates API errors and the bearer token
+func TestConfluenceReporterErrors(t *testing.T) {
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	reporter, err := newConfluenceReporter(Args{ConfluenceURL: server.URL, ConfluenceSpace: "QA", ConfluenceTitle: "Nightly", ConfluenceToken: "pat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reporter.Report(context.Background(), StatsResult{})
+	if err == nil || !strings.Contains(err.Error(), `"Nightly"`) || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a 403 error publishing Nightly, got %v", err)
+	}
+	if auth != "Bearer pat" {
+		t.Errorf("Expected the bearer token, got %q", auth)
+	}
+
+	if err := ValidateInputs(Args{ReportDirectory: ".", ConfluenceURL: "example.com"}); err == nil {
+		t.Errorf("Expected errors for an invalid URL without space and token")
+	}
+}
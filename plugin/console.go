@@ -0,0 +1,184 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Summary styles.
+const (
+	summaryStyleRich  = "rich"  // emoji metric lines, the default
+	summaryStylePlain = "plain" // ASCII only, metrics in an aligned table
+)
+
+// validSummaryStyle reports whether the style is a known summary style.
+func validSummaryStyle(style string) bool {
+	switch strings.ToLower(strings.TrimSpace(style)) {
+	case "", summaryStyleRich, summaryStylePlain:
+		return true
+	}
+	return false
+}
+
+func isPlainStyle(style string) bool {
+	return strings.EqualFold(strings.TrimSpace(style), summaryStylePlain)
+}
+
+// summaryRow is a metric line of a console summary.
+type summaryRow struct {
+	Icon  string
+	Label string
+	Value string
+}
+
+// summaryRows returns the metric lines of the run summary.
+func summaryRows(stats StatsResult, unit string) []summaryRow {
+	rows := []summaryRow{
+		{"📂", "Total Test Suites", fmt.Sprint(stats.TotalSuites)},
+		{"📄", "Total Test Cases", fmt.Sprint(stats.TotalTests)},
+		{"✅", "Passed Tests", fmt.Sprint(stats.PassedTests)},
+		{"❌", "Failed Tests", fmt.Sprint(stats.FailedTests)},
+		{"⏸", "Skipped Tests", fmt.Sprint(stats.SkippedTests)},
+		{"🔥", "Critical Tests", fmt.Sprint(stats.TotalCritical)},
+		{"✅", "Critical Passed", fmt.Sprint(stats.CriticalPassed)},
+		{"❌", "Critical Failed", fmt.Sprint(stats.CriticalFailed)},
+		{"📌", "Total Keywords", fmt.Sprint(stats.TotalKeywords)},
+		{"✅", "Passed Keywords", fmt.Sprint(stats.PassedKeywords)},
+		{"❌", "Failed Keywords", fmt.Sprint(stats.FailedKeywords)},
+		{"⏸", "Skipped Keywords", fmt.Sprint(stats.SkippedKeywords)},
+		{"⏹", "Not Run Tests", fmt.Sprint(stats.NotRunTests)},
+		{"⏹", "Not Run Keywords", fmt.Sprint(stats.NotRunKeywords)},
+		{"🔧", "Setups", fixtureSummary(stats.Setups)},
+		{"🧹", "Teardowns", fixtureSummary(stats.Teardowns)},
+		{"⚠️", "Warnings", fmt.Sprint(stats.TotalWarnings)},
+		{"🩹", "Allowed Failures", fmt.Sprint(stats.AllowedFailures)},
+	}
+	if stats.UnknownStatusTests > 0 {
+		rows = append(rows, summaryRow{"❓", "Unknown Status Tests", fmt.Sprint(stats.UnknownStatusTests)})
+	}
+	if stats.TotalIterations > 0 {
+		rows = append(rows, summaryRow{"🔁", "Loop Iterations", fmt.Sprintf("%d (%d failed)", stats.TotalIterations, stats.FailedIterations)})
+	}
+	if stats.AtRiskTests > 0 {
+		rows = append(rows, summaryRow{"🫣", "At-Risk Tests", fmt.Sprint(stats.AtRiskTests)})
+	}
+	if stats.DuplicateTestIDs > 0 {
+		rows = append(rows, summaryRow{"🧩", "Duplicate Test IDs", fmt.Sprint(stats.DuplicateTestIDs)})
+	}
+	if stats.EmptyTestNames > 0 {
+		rows = append(rows, summaryRow{"🧩", "Empty Test Names", fmt.Sprint(stats.EmptyTestNames)})
+	}
+	if len(stats.Shards) > 0 {
+		rows = append(rows, summaryRow{"⚖️", "Shard Imbalance", formatMetric(stats.ShardImbalance) + "%"})
+	}
+	if stats.QualityScore != nil {
+		rows = append(rows, summaryRow{"🏅", "Quality Score", formatMetric(*stats.QualityScore)})
+	}
+	return append(rows,
+		summaryRow{"🔁", "Flaky Tests", fmt.Sprint(stats.FlakyTests)},
+		summaryRow{"📉", "Failure Rate", fmt.Sprintf("%.2f%%", stats.FailureRate)},
+		summaryRow{"📉", "Skipped Rate", fmt.Sprintf("%.2f%%", stats.SkippedRate)},
+		summaryRow{"⏱️", "Total Execution Time", formatDuration(stats.ExecutionTime, unit)},
+		summaryRow{"⏱️", "Wall-Clock Time", formatDuration(stats.WallClockTime, unit)},
+		summaryRow{"⏱️", "Cumulative Test Time", formatDuration(stats.CumulativeTestTime, unit)},
+		summaryRow{"🤖", "Robot Framework Version", versionSummary(stats)},
+	)
+}
+
+// versionSummary describes the generator and schema versions of the
+// reports.
+func versionSummary(stats StatsResult) string {
+	version := labelOr(stats.RobotVersion, "unknown")
+	if stats.SchemaVersion != "" {
+		version += " (schema " + stats.SchemaVersion + ")"
+	}
+	return version
+}
+
+// logSummary logs the title and the metric lines in the style: emoji
+// lines between banners, or a plain ASCII table.
+func logSummary(title string, rows []summaryRow, style string) {
+	logrus.Infof("\n===============================================\n")
+	logrus.Infof("%s\n", title)
+	if isPlainStyle(style) {
+		table := make([][]string, len(rows))
+		for i, row := range rows {
+			table[i] = []string{row.Label, row.Value}
+		}
+		for _, line := range asciiTable([]string{"Metric", "Value"}, table) {
+			logrus.Infof("%s\n", line)
+		}
+		return
+	}
+	logrus.Infof("===============================================\n")
+	for _, row := range rows {
+		logrus.Infof("%s %s: %s\n", row.Icon, row.Label, row.Value)
+	}
+	logrus.Infof("===============================================\n")
+}
+
+// asciiTable renders the rows as a table with aligned columns, numbers
+// aligned right.
+func asciiTable(headers []string, rows [][]string) []string {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	separator := "+"
+	for _, width := range widths {
+		separator += strings.Repeat("-", width+2) + "+"
+	}
+	line := func(cells []string, alignNumbers bool) string {
+		var b strings.Builder
+		b.WriteString("|")
+		for i, cell := range cells {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			if alignNumbers && i > 0 && startsWithDigit(cell) {
+				b.WriteString(" " + pad + cell + " |")
+			} else {
+				b.WriteString(" " + cell + pad + " |")
+			}
+		}
+		return b.String()
+	}
+
+	lines := []string{separator, line(headers, false), separator}
+	for _, row := range rows {
+		lines = append(lines, line(row, true))
+	}
+	return append(lines, separator)
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && (s[0] >= '0' && s[0] <= '9' || s[0] == '-')
+}
+
+// styledIcon returns the icon followed by a space in the rich style, and
+// nothing in the plain style.
+func styledIcon(icon, style string) string {
+	if isPlainStyle(style) {
+		return ""
+	}
+	return icon + " "
+}
+
+// quietSummary returns the single summary line of the quiet mode.
+func quietSummary(stats StatsResult, unit string) string {
+	line := fmt.Sprintf("Robot Framework: %d tests, %d passed, %d failed, %d skipped, failure rate %.2f%%, %s",
+		stats.TotalTests, stats.PassedTests, stats.FailedTests, stats.SkippedTests, stats.FailureRate, formatDuration(stats.ExecutionTime, unit))
+	if len(stats.Gates) > 0 {
+		line += ", gates " + gateOutcome(stats.Gates)
+	}
+	return line
+}
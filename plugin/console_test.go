@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestASCIITable validates the plain summary table layout
+func TestASCIITable(t *testing.T) {
+	got := asciiTable([]string{"Metric", "Value"}, [][]string{
+		{"Passed Tests", "12"},
+		{"Failure Rate", "7.50%"},
+		{"Total Execution Time", "1500.00 ms"},
+	})
+	want := []string{
+		"+----------------------+------------+",
+		"| Metric               | Value      |",
+		"+----------------------+------------+",
+		"| Passed Tests         |         12 |",
+		"| Failure Rate         |      7.50% |",
+		"| Total Execution Time | 1500.00 ms |",
+		"+----------------------+------------+",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected table (-want +got):\n%s", diff)
+	}
+	for _, line := range got {
+		for _, r := range line {
+			if r > 127 {
+				t.Errorf("Table line %q is not ASCII", line)
+				break
+			}
+		}
+	}
+}
+
+// TestSummaryStyle validates the summary style values
+func TestSummaryStyle(t *testing.T) {
+	for _, style := range []string{"", "rich", "plain", "PLAIN"} {
+		if !validSummaryStyle(style) {
+			t.Errorf("Expected style %q to be valid", style)
+		}
+	}
+	if validSummaryStyle("fancy") {
+		t.Errorf("Expected style fancy to be invalid")
+	}
+	if styledIcon("✅", "plain") != "" || styledIcon("✅", "") != "✅ " {
+		t.Errorf("Unexpected styled icons")
+	}
+}
+
+// TestSummaryRows validates the optional quality score row
+func TestSummaryRows(t *testing.T) {
+	score := 87.5
+	without := summaryRows(StatsResult{}, "")
+	with := summaryRows(StatsResult{QualityScore: &score}, "")
+	if len(with) != len(without)+1 {
+		t.Fatalf("Expected the quality score row, got %d rows", len(with))
+	}
+	for _, row := range with {
+		if row.Label == "Quality Score" && row.Value != formatMetric(score) {
+			t.Errorf("Unexpected quality score value %q", row.Value)
+		}
+	}
+}
+
+// TestQuietSummary validates the quiet mode summary line
+func TestQuietSummary(t *testing.T) {
+	stats := StatsResult{TotalTests: 12, PassedTests: 10, FailedTests: 1, SkippedTests: 1, FailureRate: 8.333, ExecutionTime: 1500}
+	want := "Robot Framework: 12 tests, 10 passed, 1 failed, 1 skipped, failure rate 8.33%, 1500.00 ms"
+	if got := quietSummary(stats, ""); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	stats.Gates = []GateResult{{Name: "failed_tests", Outcome: gateWarn}}
+	if got := quietSummary(stats, ""); got != want+", gates warn" {
+		t.Errorf("Expected the gate outcome, got %q", got)
+	}
+}
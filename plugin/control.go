@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// controlElements maps the control structure elements of RF 4 and later
+// to the keyword type they are decoded as. Branches of IF and TRY keep
+// the type they are written with, e.g. ELSE IF or EXCEPT.
+var controlElements = map[string]string{
+	"for":      "FOR",
+	"iter":     "ITERATION",
+	"while":    "WHILE",
+	"if":       "IF/ELSE ROOT",
+	"try":      "TRY/EXCEPT ROOT",
+	"branch":   "BRANCH",
+	"group":    "GROUP",
+	"return":   "RETURN", // RF 5 and later
+	"break":    "BREAK",
+	"continue": "CONTINUE",
+	"variable": "VAR",   // RF 7 and later
+	"error":    "ERROR", // invalid syntax
+}
+
+// controlParents are the elements control structure parts must be nested
+// in.
+var controlParents = map[string][]string{
+	"iter":   {"for", "while"},
+	"branch": {"if", "try"},
+}
+
+// controlTypes are the keyword types of control structures and
+// statements, including the FOR loops of RF 3 and earlier, which are
+// written as keywords.
+var controlTypes = map[string]bool{
+	"FOR": true, "FORITEM": true, "ITERATION": true, "WHILE": true, "GROUP": true,
+	"IF/ELSE ROOT": true, "IF": true, "ELSE IF": true, "ELSE": true, "BRANCH": true,
+	"TRY/EXCEPT ROOT": true, "TRY": true, "EXCEPT": true, "FINALLY": true,
+	"RETURN": true, "BREAK": true, "CONTINUE": true, "VAR": true, "ERROR": true,
+}
+
+// keywordList is the keywords of a test or keyword in execution order,
+// including control structures such as FOR loops and their iterations.
+type keywordList []Keyword
+
+// UnmarshalXML decodes a keyword or control structure element and skips
+// any other element.
+func (l *keywordList) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	typ, control := controlElements[start.Name.Local]
+	if start.Name.Local != "kw" && !control {
+		return d.Skip()
+	}
+	var kw Keyword
+	if err := d.DecodeElement(&kw, &start); err != nil {
+		return err
+	}
+	if control && kw.Type == "" {
+		kw.Type = typ
+	}
+	*l = append(*l, kw)
+	return nil
+}
+
+// control reports whether the keyword is a control structure rather
+// than a keyword call.
+func (kw Keyword) control() bool {
+	return controlTypes[strings.ToUpper(kw.Type)]
+}
+
+// displayName returns the keyword name, or the type of control
+// structures, which have none.
+func (kw Keyword) displayName() string {
+	if kw.Name == "" {
+		return kw.Type
+	}
+	return kw.Name
+}
@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestControlStructures validates that keywords inside control structures
+// are counted and failures inside them are identified
+func TestControlStructures(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Retry">
+<while condition="$attempts &lt; 2">
+<iter>
+<try>
+<branch type="TRY">
+<kw name="Connect" owner="NetLibrary"><status status="FAIL">Timeout</status></kw>
+<status status="FAIL"/>
+</branch>
+<branch type="EXCEPT" pattern="Timeout">
+<kw name="Log"><msg level="WARN">Retrying</msg><status status="PASS"/></kw>
+<status status="PASS"/>
+</branch>
+<status status="PASS"/>
+</try>
+<variable name="${attempts}"><var>2</var><status status="PASS"/></variable>
+<break><status status="PASS"/></break>
+<status status="PASS"/>
+</iter>
+<status status="PASS"/>
+</while>
+<status status="PASS"/>
+</test>
+<test id="s1-t2" name="Invalid">
+<group name="Checks">
+<error><value>IF</value><status status="FAIL">IF must have a condition.</status></error>
+<status status="FAIL"/>
+</group>
+<status status="FAIL">IF must have a condition.</status>
+</test>
+</suite>
+</robot>`), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keywords := robotOutput.Suite.Tests[0].Keywords
+	if len(keywords) != 1 || keywords[0].Type != "WHILE" || len(keywords[0].Keywords) != 1 || keywords[0].Keywords[0].Type != "ITERATION" {
+		t.Fatalf("Expected a WHILE loop with one iteration, got %+v", keywords)
+	}
+	var types []string
+	for _, kw := range keywords[0].Keywords[0].Keywords {
+		types = append(types, kw.Type)
+	}
+	if diff := cmp.Diff([]string{"TRY/EXCEPT ROOT", "VAR", "BREAK"}, types); diff != "" {
+		t.Errorf("Control structure types mismatch (-want +got):\n%s", diff)
+	}
+
+	opts, err := newStatsOptions(Args{AtRiskTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	if stats.TotalKeywords != 2 || stats.FailedKeywords != 1 || stats.TotalWarnings != 1 {
+		t.Errorf("Expected 2 keywords, 1 failed and 1 warning, got %d, %d and %d", stats.TotalKeywords, stats.FailedKeywords, stats.TotalWarnings)
+	}
+	if diff := cmp.Diff(map[string]int{"NetLibrary": 1}, stats.LibraryFailures); diff != "" {
+		t.Errorf("Library failures mismatch (-want +got):\n%s", diff)
+	}
+	want := []AtRiskTestDetails{{Name: "Retry", Suite: "Root", FailedKeywords: 1, Keyword: "Connect", Message: "Timeout"}}
+	if diff := cmp.Diff(want, stats.AtRiskTestsDetails); diff != "" {
+		t.Errorf("At-risk tests mismatch (-want +got):\n%s", diff)
+	}
+
+	if kw, ok := firstFailedKeyword(robotOutput.Suite.Tests[1].Keywords); !ok || kw.displayName() != "ERROR" || keywordFailureMessage(kw) != "IF must have a condition." {
+		t.Errorf("Expected the invalid syntax as the first failure, got %+v", kw)
+	}
+}
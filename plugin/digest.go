@@ -0,0 +1,297 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDigestWindow is the time window of the runs in the digest.
+const defaultDigestWindow = 24 * time.Hour
+
+// maxDigestTests limits the tests listed in each digest section.
+const maxDigestTests = 10
+
+// Digest summarizes the runs of several builds in a time window, e.g. for
+// a nightly cron pipeline.
+type Digest struct {
+	Start      time.Time     `json:"start"`
+	End        time.Time     `json:"end"`
+	Runs       int           `json:"runs"`
+	FailedRuns int           `json:"failed_runs"` // runs with failed tests
+	PassRate   float64       `json:"pass_rate"`   // of all tests of all runs
+	Trend      []TrendPoint  `json:"trend"`
+	Failures   []TestHistory `json:"failures"` // most frequently failing tests
+	Flaky      []TestHistory `json:"flaky"`    // flakiest tests
+}
+
+// TestHistory summarizes the results of a test across recorded runs.
+type TestHistory struct {
+	Name        string `json:"name"`
+	Suite       string `json:"suite"`
+	Runs        int    `json:"runs"` // runs reporting the test
+	Failures    int    `json:"failures"`
+	Flips       int    `json:"flips,omitempty"`        // status changes between consecutive runs
+	Reruns      int    `json:"reruns,omitempty"`       // runs where it passed on rerun
+	LastMessage string `json:"last_message,omitempty"` // of the last failure
+	LastBuild   string `json:"last_build,omitempty"`   // of the last failure
+}
+
+// digestWindow returns the time window of the digest.
+func digestWindow(args Args) time.Duration {
+	if args.DigestWindow > 0 {
+		return args.DigestWindow
+	}
+	return defaultDigestWindow
+}
+
+// LoadDigest summarizes the runs recorded in the time window ending now:
+// the stats JSON files matching the patterns, or the runs in the history
+// store without patterns.
+func LoadDigest(ctx context.Context, patterns []string, args Args) (Digest, error) {
+	var records []HistoryRecord
+	if len(patterns) > 0 {
+		var err error
+		if records, err = statsFileRecords(patterns); err != nil {
+			return Digest{}, err
+		}
+	} else {
+		history, err := newHistoryStore(args)
+		if err != nil {
+			return Digest{}, err
+		}
+		if history == nil {
+			return Digest{}, fmt.Errorf("no stats files or history to summarize")
+		}
+		if records, err = history.records(ctx); err != nil {
+			return Digest{}, fmt.Errorf("failed to read history from %s: %v", history.backend, err)
+		}
+	}
+	end := time.Now().UTC()
+	return buildDigest(records, end.Add(-digestWindow(args)), end), nil
+}
+
+// statsFileRecords reads the stats JSON files matching the patterns as
+// runs, oldest first. A run is dated by the end of its execution, or by
+// the modification time of files without one.
+func statsFileRecords(patterns []string) ([]HistoryRecord, error) {
+	var records []HistoryRecord
+	for _, pattern := range patterns {
+		files, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stats file pattern %q: %v", pattern, err)
+		}
+		for _, file := range files {
+			stats, err := readStatsFile(file)
+			if err != nil {
+				return nil, err
+			}
+			recorded := stats.EndTime
+			if recorded.IsZero() {
+				info, err := os.Stat(file)
+				if err != nil {
+					return nil, err
+				}
+				recorded = info.ModTime()
+			}
+			records = append(records, HistoryRecord{Recorded: recorded.UTC(), Build: stats.Build, Stats: stats})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Recorded.Before(records[j].Recorded) })
+	return records, nil
+}
+
+// buildDigest summarizes the runs recorded from start to end, oldest
+// first.
+func buildDigest(records []HistoryRecord, start, end time.Time) Digest {
+	digest := Digest{Start: start, End: end, Trend: []TrendPoint{}, Failures: []TestHistory{}, Flaky: []TestHistory{}}
+	var window []HistoryRecord
+	total, passed := 0, 0
+	for _, record := range records {
+		if record.Recorded.Before(start) || record.Recorded.After(end) {
+			continue
+		}
+		window = append(window, record)
+		digest.Runs++
+		if record.Stats.FailedTests > 0 {
+			digest.FailedRuns++
+		}
+		total += record.Stats.TotalTests
+		passed += record.Stats.PassedTests
+		digest.Trend = append(digest.Trend, newTrendPoint(record.Stats, record.Build, record.Recorded))
+	}
+	if total > 0 {
+		digest.PassRate = float64(passed) / float64(total) * 100
+	}
+
+	tests := summarizeTests(window)
+	digest.Failures = append(digest.Failures, failureLeaderboard(tests, maxDigestTests)...)
+	for _, test := range tests {
+		if test.Flips >= 2 || test.Reruns > 0 {
+			digest.Flaky = append(digest.Flaky, test)
+		}
+	}
+	sort.SliceStable(digest.Flaky, func(i, j int) bool {
+		return digest.Flaky[i].Flips+digest.Flaky[i].Reruns > digest.Flaky[j].Flips+digest.Flaky[j].Reruns
+	})
+	if len(digest.Flaky) > maxDigestTests {
+		digest.Flaky = digest.Flaky[:maxDigestTests]
+	}
+	return digest
+}
+
+// summarizeTests summarizes the results of every test across the runs,
+// oldest first, in the order the tests were first reported. Compact
+// records list only the failed tests, so the tests seen before and
+// missing from them are counted as passed.
+func summarizeTests(records []HistoryRecord) []TestHistory {
+	tests := map[string]*TestHistory{}
+	last := map[string]string{}
+	var order []string
+	test := func(name, suite string) *TestHistory {
+		key := suite + "." + name
+		if tests[key] == nil {
+			tests[key] = &TestHistory{Name: name, Suite: suite}
+			order = append(order, key)
+		}
+		return tests[key]
+	}
+	for _, record := range records {
+		reported := map[string]bool{}
+		for _, result := range record.Stats.Tests {
+			key := testKey(result)
+			reported[key] = true
+			t := test(result.Name, result.Suite)
+			t.Runs++
+			if result.Status == "FAIL" {
+				t.Failures++
+				t.LastMessage = result.ErrorMessage
+				t.LastBuild = record.number()
+			}
+			if previous, ok := last[key]; ok && (previous == "FAIL") != (result.Status == "FAIL") && result.Status != "SKIP" && previous != "SKIP" {
+				t.Flips++
+			}
+			last[key] = result.Status
+		}
+		if record.Compact {
+			for _, key := range order {
+				if reported[key] {
+					continue
+				}
+				tests[key].Runs++
+				if last[key] == "FAIL" {
+					tests[key].Flips++
+				}
+				last[key] = "PASS"
+			}
+		}
+		for _, flaky := range record.Stats.FlakyTestsDetails {
+			test(flaky.Name, flaky.Suite).Reruns++
+		}
+	}
+	summaries := make([]TestHistory, 0, len(order))
+	for _, key := range order {
+		summaries = append(summaries, *tests[key])
+	}
+	return summaries
+}
+
+// LogDigest logs the digest in the summary style.
+func LogDigest(digest Digest, style string) {
+	rows := []summaryRow{
+		{"📅", "Window", digest.Start.Format(time.RFC3339) + " – " + digest.End.Format(time.RFC3339)},
+		{"🏗", "Runs", fmt.Sprint(digest.Runs)},
+		{"❌", "Runs With Failures", fmt.Sprint(digest.FailedRuns)},
+		{"📈", "Pass Rate", formatMetric(digest.PassRate) + "%"},
+	}
+	logSummary("Robot Framework Digest", rows, style)
+
+	if len(digest.Failures) > 0 {
+		logrus.Infof("Most Frequent Failures:\n")
+		logrus.Infof("-----------------------------------------------\n")
+		for i, test := range digest.Failures {
+			logrus.Infof("%d. %s.%s: failed %d of %d runs\n", i+1, test.Suite, test.Name, test.Failures, test.Runs)
+			if test.LastMessage != "" {
+				logrus.Infof("   Last Error Message: %s\n", test.LastMessage)
+			}
+		}
+	}
+	if len(digest.Flaky) > 0 {
+		logrus.Infof("Flakiest Tests:\n")
+		logrus.Infof("-----------------------------------------------\n")
+		for i, test := range digest.Flaky {
+			logrus.Infof("%d. %s.%s: %d status changes, passed on rerun %d times in %d runs\n", i+1, test.Suite, test.Name, test.Flips, test.Reruns, test.Runs)
+		}
+	}
+}
+
+// digestTemplate renders the digest as Markdown, e.g. for a chat message
+// or an issue.
+var digestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"cell":   markdownCell,
+	"metric": formatMetric,
+	"date":   func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+}).Parse(`## Robot Framework Digest
+
+{{date .Start}} – {{date .End}} UTC · {{.Runs}} runs · {{.FailedRuns}} with failures · pass rate {{metric .PassRate}}%
+{{with .Trend}}
+| Build | Recorded | Pass Rate |
+| --- | --- | ---: |
+{{- range .}}
+| {{.Build}} | {{date .Recorded}} | {{metric .PassRate}}% |
+{{- end}}
+{{end}}
+{{- with .Failures}}
+### Most Frequent Failures
+
+| Suite | Test | Failed Runs | Last Message |
+| --- | --- | ---: | --- |
+{{- range .}}
+| {{cell .Suite}} | {{cell .Name}} | {{.Failures}} / {{.Runs}} | {{cell .LastMessage}} |
+{{- end}}
+{{end}}
+{{- with .Flaky}}
+### Flakiest Tests
+
+| Suite | Test | Status Changes | Passed On Rerun | Runs |
+| --- | --- | ---: | ---: | ---: |
+{{- range .}}
+| {{cell .Suite}} | {{cell .Name}} | {{.Flips}} | {{.Reruns}} | {{.Runs}} |
+{{- end}}
+{{end}}`))
+
+// WriteDigest writes the digest to the file: Markdown when the extension
+// is .md, JSON otherwise.
+func WriteDigest(path string, digest Digest) error {
+	if !strings.EqualFold(filepath.Ext(path), ".md") {
+		return WriteJSON(path, digest)
+	}
+	var b bytes.Buffer
+	if err := digestTemplate.Execute(&b, digest); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), 0644)
+}
+
+// execDigest runs the digest mode of the plugin.
+func execDigest(ctx context.Context, args Args) error {
+	digest, err := LoadDigest(ctx, args.DigestFiles, args)
+	if err != nil {
+		return classifyError(ErrorClassDiscovery, err)
+	}
+	LogDigest(digest, args.SummaryStyle)
+	if args.DigestFile != "" {
+		if err := WriteDigest(args.DigestFile, digest); err != nil {
+			return classifyError(ErrorClassReporter, fmt.Errorf("failed to write digest: %v", err))
+		}
+	}
+	return nil
+}
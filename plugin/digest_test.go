@@ -0,0 +1,139 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// digestRecord returns a recorded run of the build with the test
+// statuses, keyed by test name.
+func digestRecord(recorded time.Time, build string, statuses map[string]string) HistoryRecord {
+	stats := StatsResult{}
+	for _, name := range []string{"A", "B", "C"} {
+		status, ok := statuses[name]
+		if !ok {
+			continue
+		}
+		stats.TotalTests++
+		result := TestResult{Name: name, Suite: "Root", Status: status}
+		if status == "PASS" {
+			stats.PassedTests++
+		} else {
+			stats.FailedTests++
+			result.ErrorMessage = name + " failed in " + build
+		}
+		stats.Tests = append(stats.Tests, result)
+	}
+	return HistoryRecord{Recorded: recorded, Build: &BuildInfo{Number: build}, Stats: stats}
+}
+
+// TestBuildDigest validates the most frequent failures, the flakiest
+// tests and the time window of the digest
+func TestBuildDigest(t *testing.T) {
+	end := time.Date(2024, 5, 2, 6, 0, 0, 0, time.UTC)
+	records := []HistoryRecord{
+		digestRecord(end.Add(-30*time.Hour), "1", map[string]string{"A": "FAIL", "B": "FAIL", "C": "FAIL"}),
+		digestRecord(end.Add(-20*time.Hour), "2", map[string]string{"A": "FAIL", "B": "PASS", "C": "PASS"}),
+		digestRecord(end.Add(-10*time.Hour), "3", map[string]string{"A": "FAIL", "B": "FAIL", "C": "PASS"}),
+		digestRecord(end.Add(-1*time.Hour), "4", map[string]string{"A": "FAIL", "B": "PASS", "C": "PASS"}),
+	}
+	records[3].Stats.FlakyTestsDetails = []FlakyTestDetails{{Name: "C", Suite: "Root"}}
+
+	digest := buildDigest(records, end.Add(-24*time.Hour), end)
+	if digest.Runs != 3 || digest.FailedRuns != 3 || len(digest.Trend) != 3 {
+		t.Errorf("Expected 3 runs with failures in the window, got %d, %d and %d trend points", digest.Runs, digest.FailedRuns, len(digest.Trend))
+	}
+	if want := 5.0 / 9 * 100; digest.PassRate != want {
+		t.Errorf("Expected a pass rate of %v, got %v", want, digest.PassRate)
+	}
+	failures := []TestHistory{
+		{Name: "A", Suite: "Root", Runs: 3, Failures: 3, LastMessage: "A failed in 4", LastBuild: "4"},
+		{Name: "B", Suite: "Root", Runs: 3, Failures: 1, Flips: 2, LastMessage: "B failed in 3", LastBuild: "3"},
+	}
+	if diff := cmp.Diff(failures, digest.Failures); diff != "" {
+		t.Errorf("Failures mismatch (-want +got):\n%s", diff)
+	}
+	flaky := []TestHistory{
+		{Name: "B", Suite: "Root", Runs: 3, Failures: 1, Flips: 2, LastMessage: "B failed in 3", LastBuild: "3"},
+		{Name: "C", Suite: "Root", Runs: 3, Reruns: 1},
+	}
+	if diff := cmp.Diff(flaky, digest.Flaky); diff != "" {
+		t.Errorf("Flaky tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestSummarizeCompactTests validates that the tests missing from
+// compact records are counted as passed
+func TestSummarizeCompactTests(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var records []HistoryRecord
+	for i, status := range []string{"FAIL", "PASS", "FAIL"} {
+		record := digestRecord(start.Add(time.Duration(i)*time.Hour), string(rune('1'+i)), map[string]string{"A": status, "B": "PASS"})
+		records = append(records, HistoryRecord{Recorded: record.Recorded, Build: record.Build, Compact: true, Stats: compactStats(record.Stats)})
+	}
+	want := []TestHistory{{Name: "A", Suite: "Root", Runs: 3, Failures: 2, Flips: 2, LastMessage: "A failed in 3", LastBuild: "3"}}
+	if diff := cmp.Diff(want, summarizeTests(records)); diff != "" {
+		t.Errorf("Unexpected test summaries (-want +got):\n%s", diff)
+	}
+}
+
+// TestLoadDigestFiles validates the digest of stats files and its
+// Markdown rendering
+func TestLoadDigestFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().UTC()
+	for i, status := range []string{"PASS", "FAIL"} {
+		stats := digestRecord(now.Add(-time.Duration(2-i)*time.Hour), "7", map[string]string{"A": status}).Stats
+		stats.EndTime = now.Add(-time.Duration(2-i) * time.Hour)
+		if err := WriteJSON(filepath.Join(dir, "run"+string(rune('1'+i))+".json"), stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := digestRecord(now, "6", map[string]string{"A": "FAIL"}).Stats
+	old.EndTime = now.Add(-48 * time.Hour)
+	if err := WriteJSON(filepath.Join(dir, "old.json"), old); err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := LoadDigest(context.Background(), []string{filepath.Join(dir, "*.json")}, Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.Runs != 2 || len(digest.Failures) != 1 || digest.Failures[0].Failures != 1 {
+		t.Errorf("Expected 2 runs and 1 failure of A, got %+v", digest)
+	}
+
+	file := filepath.Join(dir, "digest.md")
+	if err := WriteDigest(file, digest); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Robot Framework Digest", "2 runs · 1 with failures · pass rate 50%", "| Root | A | 1 / 2 | A failed in 7 |"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected the digest to contain %q, got:\n%s", want, data)
+		}
+	}
+
+	if _, err := LoadDigest(context.Background(), nil, Args{}); err == nil {
+		t.Errorf("Expected an error without stats files or history")
+	}
+}
+
+// TestDigestValidation validates the digest mode settings
+func TestDigestValidation(t *testing.T) {
+	if err := ValidateInputs(Args{Digest: true, DigestFiles: []string{"stats/*.json"}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := ValidateInputs(Args{Digest: true}); err == nil || !strings.Contains(err.Error(), "PLUGIN_DIGEST") {
+		t.Errorf("Expected an error without stats files or history, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fileQuery selects the report files of a directory, either by glob
+// pattern or by regular expression.
+type fileQuery struct {
+	// Pattern is the glob pattern matched in the directory, falling back
+	// to pabot worker outputs.
+	Pattern string
+
+	// Regex, when set, replaces the glob pattern. The directory is walked
+	// recursively and the expression must match the whole file name or
+	// the whole path relative to the directory, using forward slashes.
+	Regex *regexp.Regexp
+
+	// IgnoreCase matches the pattern or expression case-insensitively.
+	IgnoreCase bool
+
+	// SkipSymlinks ignores symbolic links to files and directories.
+	SkipSymlinks bool
+
+	// MaxDepth limits the directory levels walked for the regular
+	// expression, the report directory being level 1. Zero is unlimited.
+	MaxDepth int
+
+	// SkipHidden does not walk into directories whose name starts with a
+	// dot, such as .git.
+	SkipHidden bool
+}
+
+// defaultReportFilePattern matches the report files when
+// PLUGIN_REPORT_FILE_NAME_PATTERN is not set.
+const defaultReportFilePattern = "*.xml"
+
+// newFileQuery returns the report file query of the plugin arguments.
+func newFileQuery(args Args) (fileQuery, error) {
+	query := fileQuery{
+		Pattern:      args.ReportFileNamePattern,
+		IgnoreCase:   args.ReportFileIgnoreCase,
+		SkipSymlinks: args.FollowSymlinks != nil && !*args.FollowSymlinks,
+		MaxDepth:     args.MaxSearchDepth,
+		SkipHidden:   args.SkipHiddenDirs,
+	}
+	if query.Pattern == "" {
+		query.Pattern = defaultReportFilePattern
+	}
+	if args.ReportFileRegex != "" {
+		re, err := compileFileRegex(args.ReportFileRegex, query.IgnoreCase)
+		if err != nil {
+			return query, err
+		}
+		query.Regex = re
+	}
+	return query, nil
+}
+
+func compileFileRegex(expr string, ignoreCase bool) (*regexp.Regexp, error) {
+	flags := ""
+	if ignoreCase {
+		flags = "(?i)"
+	}
+	re, err := regexp.Compile(flags + "^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid report file regex %q: %v", expr, err)
+	}
+	return re, nil
+}
+
+func (q fileQuery) String() string {
+	if q.Regex != nil {
+		return "regex " + q.Regex.String()
+	}
+	return q.Pattern
+}
+
+// match returns the files of the directory matching the query. The
+// pattern may also be an absolute path, e.g. C:\results\*\output.xml.
+func (q fileQuery) match(directory string) ([]string, error) {
+	directory = normalizePath(directory)
+	if q.Regex != nil {
+		return q.walk(directory)
+	}
+
+	pattern := normalizePath(q.Pattern)
+	absolute := filepath.IsAbs(pattern) || filepath.VolumeName(pattern) != ""
+	if q.IgnoreCase {
+		// drive letters and UNC shares are not globs
+		volume := filepath.VolumeName(pattern)
+		pattern = volume + caseInsensitiveGlob(pattern[len(volume):])
+	}
+	glob := pattern
+	if !absolute {
+		glob = filepath.Join(directory, pattern)
+	}
+	matches, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fall back to pabot worker outputs
+	if len(matches) == 0 && !absolute {
+		for _, workerPattern := range pabotPatterns(directory, pattern) {
+			workerMatches, _ := filepath.Glob(workerPattern)
+			matches = append(matches, workerMatches...)
+		}
+		if len(matches) > 0 {
+			logrus.Infof("Detected pabot worker outputs")
+		}
+	}
+
+	if q.SkipSymlinks {
+		kept := matches[:0]
+		for _, match := range matches {
+			if viaSymlink(directory, match) {
+				logrus.Debugf("Skipping symbolic link: %s", match)
+				continue
+			}
+			kept = append(kept, match)
+		}
+		matches = kept
+	}
+	return matches, nil
+}
+
+// walk recursively finds the files matching the regular expression,
+// within the maximum depth. Symbolic links are followed unless skipped;
+// directories reached through several links are visited once.
+func (q fileQuery) walk(directory string) ([]string, error) {
+	var matches []string
+	visited := map[string]bool{}
+
+	var visit func(dir string, depth int) error
+	visit = func(dir string, depth int) error {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			if visited[real] {
+				return nil
+			}
+			visited[real] = true
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			isDir := entry.IsDir()
+			if entry.Type()&fs.ModeSymlink != 0 {
+				if q.SkipSymlinks {
+					continue
+				}
+				info, err := os.Stat(path)
+				if err != nil {
+					logrus.Warnf("Error accessing %s: %v", path, err)
+					continue
+				}
+				isDir = info.IsDir()
+			}
+
+			if isDir {
+				if q.MaxDepth > 0 && depth >= q.MaxDepth {
+					continue
+				}
+				if q.SkipHidden && strings.HasPrefix(entry.Name(), ".") {
+					logrus.Debugf("Skipping hidden directory: %s", path)
+					continue
+				}
+				if err := visit(path, depth+1); err != nil {
+					logrus.Warnf("Error accessing %s: %v", path, err)
+				}
+				continue
+			}
+			rel, err := filepath.Rel(directory, path)
+			if err != nil {
+				continue
+			}
+			if q.Regex.MatchString(entry.Name()) || q.Regex.MatchString(filepath.ToSlash(rel)) {
+				matches = append(matches, path)
+			}
+		}
+		return nil
+	}
+
+	if err := visit(directory, 1); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// normalizePath rewrites the path with the separators of the OS, so on
+// Windows forward slashes, UNC paths (//server/share) and lowercase drive
+// letters are accepted.
+func normalizePath(path string) string {
+	return normalizePathFor(path, filepath.Separator)
+}
+
+func normalizePathFor(path string, separator byte) string {
+	if separator != '\\' {
+		return path
+	}
+	path = strings.ReplaceAll(path, "/", `\`)
+	if len(path) >= 2 && path[1] == ':' && ('a' <= path[0] && path[0] <= 'z') {
+		path = strings.ToUpper(path[:1]) + path[1:]
+	}
+	return path
+}
+
+// viaSymlink reports whether the path, or one of its parent directories
+// below the report directory, is a symbolic link.
+func viaSymlink(directory, path string) bool {
+	rel, err := filepath.Rel(directory, path)
+	if err != nil {
+		return false
+	}
+	for rel != "." && rel != string(filepath.Separator) {
+		if info, err := os.Lstat(filepath.Join(directory, rel)); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return true
+		}
+		rel = filepath.Dir(rel)
+	}
+	return false
+}
+
+// caseInsensitiveGlob rewrites the letters of a glob pattern as character
+// classes matching both cases, e.g. "out.xml" as "[oO][uU][tT].[xX][mM][lL]".
+// Escaped characters and existing character classes are kept as is.
+func caseInsensitiveGlob(pattern string) string {
+	var b strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && filepath.Separator != '\\' && i+1 < len(runes):
+			b.WriteRune(r)
+			i++
+			b.WriteRune(runes[i])
+		case r == '[':
+			end := i
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			b.WriteString(string(runes[i:min(end+1, len(runes))]))
+			i = end
+		case unicode.ToLower(r) != unicode.ToUpper(r):
+			b.WriteString("[" + string(unicode.ToLower(r)) + string(unicode.ToUpper(r)) + "]")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
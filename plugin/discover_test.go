@@ -0,0 +1,233 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLocateFilesRegex validates recursive report discovery by regular
+// expression
+func TestLocateFilesRegex(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"output_20250101.xml",
+		"nightly/output_20250102.xml",
+		"nightly/output_2025.xml",
+		"output_20250101.xml.bak",
+		"log.html",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("<robot/>"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		regex string
+		want  []string
+	}{
+		{
+			name:  "File Name",
+			regex: `output_\d{8}\.xml`,
+			want:  []string{"nightly/output_20250102.xml", "output_20250101.xml"},
+		},
+		{
+			name:  "Relative Path",
+			regex: `nightly/.*\.xml`,
+			want:  []string{"nightly/output_2025.xml", "nightly/output_20250102.xml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := newFileQuery(Args{ReportFileRegex: tt.regex})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			files, err := locateFiles(dir, query)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got []string
+			for _, file := range files {
+				rel, _ := filepath.Rel(dir, file)
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := newFileQuery(Args{ReportFileRegex: `output_(\d`}); err == nil {
+		t.Error("Expected an error for an invalid regex")
+	}
+}
+
+// TestDefaultReportFilePattern validates that all XML files match by default
+func TestDefaultReportFilePattern(t *testing.T) {
+	query, err := newFileQuery(Args{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if query.Pattern != "*.xml" {
+		t.Errorf("Expected pattern *.xml, got %s", query.Pattern)
+	}
+	if pattern := newAnalyzer(Args{}).args.ReportFileNamePattern; pattern != "*.xml" {
+		t.Errorf("Expected analyzer pattern *.xml, got %s", pattern)
+	}
+}
+
+// TestLocateFilesIgnoreCase validates case-insensitive report discovery
+func TestLocateFilesIgnoreCase(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"Output.XML", "rerun.xml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("<robot/>"), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	for _, args := range []Args{
+		{ReportFileNamePattern: "output.xml", ReportFileIgnoreCase: true},
+		{ReportFileRegex: `output\.xml`, ReportFileIgnoreCase: true},
+	} {
+		query, err := newFileQuery(args)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		files, err := locateFiles(dir, query)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", query, err)
+		}
+		if len(files) != 1 || filepath.Base(files[0]) != "Output.XML" {
+			t.Errorf("Expected Output.XML for %s, got %v", query, files)
+		}
+	}
+
+	if got := caseInsensitiveGlob(`out[0-9]\*.xml`); got != `[oO][uU][tT][0-9]\*.[xX][mM][lL]` {
+		t.Errorf("Unexpected case-insensitive pattern %s", got)
+	}
+}
+
+// TestLocateFilesSymlinks validates following and skipping symbolic links
+func TestLocateFilesSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := t.TempDir()
+	if err := os.WriteFile(filepath.Join(target, "output.xml"), []byte("<robot/>"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink(target, filepath.Join(dir, "artifacts")); err != nil {
+		t.Skipf("Symbolic links not supported: %v", err)
+	}
+	// a link cycle must not be walked forever
+	if err := os.Symlink(dir, filepath.Join(target, "parent")); err != nil {
+		t.Fatalf("Failed to create link: %v", err)
+	}
+
+	for _, skip := range []bool{false, true} {
+		for _, query := range []fileQuery{
+			{Pattern: "artifacts/output.xml", SkipSymlinks: skip},
+			{Regex: regexp.MustCompile(`^.*output\.xml$`), SkipSymlinks: skip},
+		} {
+			files, _ := query.match(dir)
+			if !skip && len(files) != 1 {
+				t.Errorf("Expected the linked report for %s, got %v", query, files)
+			}
+			if skip && len(files) != 0 {
+				t.Errorf("Expected no reports for %s when skipping links, got %v", query, files)
+			}
+		}
+	}
+}
+
+// TestNormalizePath validates Windows path normalization
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{`c:/results/output.xml`, `C:\results\output.xml`},
+		{`D:\results\*\output.xml`, `D:\results\*\output.xml`},
+		{`//server/share/results`, `\\server\share\results`},
+		{`\\server\share\results`, `\\server\share\results`},
+		{`results/nightly`, `results\nightly`},
+	}
+	for _, tt := range tests {
+		if got := normalizePathFor(tt.path, '\\'); got != tt.want {
+			t.Errorf("Expected %s for %s, got %s", tt.want, tt.path, got)
+		}
+	}
+	if got := normalizePathFor(`c:/results`, '/'); got != `c:/results` {
+		t.Errorf("Expected paths to be kept on other systems, got %s", got)
+	}
+}
+
+// TestLocateFilesAbsolutePattern validates absolute report file patterns
+func TestLocateFilesAbsolutePattern(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nightly", "OUTPUT.xml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("<robot/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	query := fileQuery{Pattern: filepath.Join(dir, "*", "output.xml"), IgnoreCase: true}
+	files, err := locateFiles(t.TempDir(), query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{path}, files); diff != "" {
+		t.Errorf("Unexpected files (-want +got):\n%s", diff)
+	}
+}
+
+// TestLocateFilesDepthAndHidden validates the search depth and hidden directory options
+func TestLocateFilesDepthAndHidden(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"output.xml", "a/output.xml", "a/b/output.xml", ".cache/output.xml"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("<robot/>"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args Args
+		want []string
+	}{
+		{Args{}, []string{".cache/output.xml", "a/b/output.xml", "a/output.xml", "output.xml"}},
+		{Args{MaxSearchDepth: 1}, []string{"output.xml"}},
+		{Args{MaxSearchDepth: 2, SkipHiddenDirs: true}, []string{"a/output.xml", "output.xml"}},
+	}
+	for _, tt := range tests {
+		tt.args.ReportFileRegex = `output\.xml`
+		query, err := newFileQuery(tt.args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := locateFiles(dir, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, file := range files {
+			rel, _ := filepath.Rel(dir, file)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		if diff := cmp.Diff(tt.want, got); diff != "" {
+			t.Errorf("Files mismatch for depth %d (-want +got):\n%s", tt.args.MaxSearchDepth, diff)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Duration units of the summary, the reports and the output variables.
+// Durations are always milliseconds in the JSON statistics.
+const (
+	durationUnitMilliseconds = "ms"
+	durationUnitSeconds      = "s"
+	durationUnitHuman        = "human" // e.g. 1h 2m 3s
+)
+
+// validDurationUnit reports whether the duration unit is known.
+func validDurationUnit(unit string) bool {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "", durationUnitMilliseconds, durationUnitSeconds, durationUnitHuman:
+		return true
+	}
+	return false
+}
+
+// durationUnit returns the normalized duration unit, milliseconds by
+// default.
+func durationUnit(unit string) string {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	if unit == "" {
+		return durationUnitMilliseconds
+	}
+	return unit
+}
+
+// formatDuration formats a duration in milliseconds in the unit, with
+// the unit suffix.
+func formatDuration(ms float64, unit string) string {
+	switch durationUnit(unit) {
+	case durationUnitSeconds:
+		return fmt.Sprintf("%.2f s", ms/1000)
+	case durationUnitHuman:
+		return humanDuration(ms)
+	}
+	return fmt.Sprintf("%.2f ms", ms)
+}
+
+// humanDuration formats a duration in milliseconds as hours, minutes and
+// seconds, leaving out leading zero components. Durations under a second
+// are formatted in milliseconds.
+func humanDuration(ms float64) string {
+	if ms < 1000 {
+		return fmt.Sprintf("%dms", int(math.Round(ms)))
+	}
+	seconds := int(math.Round(ms / 1000))
+	h, m, s := seconds/3600, seconds/60%60, seconds%60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh %dm %ds", h, m, s)
+	case m > 0:
+		return fmt.Sprintf("%dm %ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
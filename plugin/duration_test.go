@@ -0,0 +1,45 @@
+package plugin
+
+import "testing"
+
+// TestFormatDuration validates durations in milliseconds, seconds and
+// the human-readable form
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		ms   float64
+		unit string
+		want string
+	}{
+		{1234.5, "", "1234.50 ms"},
+		{1234.5, "ms", "1234.50 ms"},
+		{1234.5, "S", "1.23 s"},
+		{450, "human", "450ms"},
+		{59_600, "human", "1m 0s"},
+		{3_723_000, "human", "1h 2m 3s"},
+		{12_000, "human", "12s"},
+	}
+	for _, tc := range tests {
+		if got := formatDuration(tc.ms, tc.unit); got != tc.want {
+			t.Errorf("formatDuration(%v, %q) = %q, want %q", tc.ms, tc.unit, got, tc.want)
+		}
+	}
+}
+
+// TestDurationOutputs validates the duration unit of output variables
+func TestDurationOutputs(t *testing.T) {
+	stats := StatsResult{WallClockTime: 3_723_000, CumulativeTestTime: 1500}
+	for unit, want := range map[string][2]string{
+		"":      {"3723000.00", "1500.00"},
+		"s":     {"3723.00", "1.50"},
+		"human": {"1h 2m 3s", "2s"},
+	} {
+		out := &outputVars{unit: unit}
+		writeTestStats(out, stats)
+		if got := [2]string{out.values["WALL_CLOCK_TIME"], out.values["CUMULATIVE_TEST_TIME"]}; got != want {
+			t.Errorf("Unit %q: expected %v, got %v", unit, want, got)
+		}
+	}
+	if validDurationUnit("minutes") {
+		t.Error("Expected unknown duration units to be rejected")
+	}
+}
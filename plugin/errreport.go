@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"errors"
+	"time"
+)
+
+// Error classes of the error report.
+const (
+	ErrorClassValidation = "validation"
+	ErrorClassDiscovery  = "discovery"
+	ErrorClassParse      = "parse"
+	ErrorClassThreshold  = "threshold"
+	ErrorClassReporter   = "reporter"
+	ErrorClassExecution  = "execution"
+)
+
+// RunError classifies an error failing the run, with the report file or
+// the gates it concerns.
+type RunError struct {
+	Class string
+	File  string       // offending report file, if any
+	Gates []GateResult // failed gates of threshold errors
+	Err   error
+}
+
+func (e *RunError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RunError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError wraps the error in a RunError of the class, unless it is
+// nil or already classified.
+func classifyError(class string, err error) error {
+	var runErr *RunError
+	if err == nil || errors.As(err, &runErr) {
+		return err
+	}
+	return &RunError{Class: class, Err: err}
+}
+
+// ErrorReport is the structured description of a failed run, written to
+// PLUGIN_ERROR_REPORT_FILE for pipeline failure handlers.
+type ErrorReport struct {
+	Class   string       `json:"class"`
+	Message string       `json:"message"`
+	File    string       `json:"file,omitempty"`
+	Metric  string       `json:"metric,omitempty"` // metric of the first failed gate
+	Gates   []GateResult `json:"gates,omitempty"`  // failed gates
+	Time    time.Time    `json:"time"`
+}
+
+// NewErrorReport describes the error. Unclassified errors are reported as
+// execution errors.
+func NewErrorReport(err error) ErrorReport {
+	report := ErrorReport{Class: ErrorClassExecution, Message: err.Error(), Time: time.Now().UTC()}
+	var runErr *RunError
+	if errors.As(err, &runErr) {
+		report.Class = runErr.Class
+		report.File = runErr.File
+		report.Gates = runErr.Gates
+		for _, gate := range runErr.Gates {
+			if gate.Metric != "" {
+				report.Metric = gate.Metric
+				break
+			}
+		}
+	}
+	return report
+}
+
+// WriteErrorReport writes the error report of the error to the file. It
+// does nothing when the path is empty.
+func WriteErrorReport(path string, err error) error {
+	if path == "" || err == nil {
+		return nil
+	}
+	return WriteJSON(path, NewErrorReport(err))
+}
@@ -0,0 +1,95 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// TestNewErrorReport validates the classification of run errors
+func TestNewErrorReport(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "output.xml")
+	if err := os.WriteFile(broken, []byte("<robot><suite"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, parseErr := parseFile(broken, statsOptions{})
+
+	_, gateErr := EvaluateGates(StatsResult{FailedTests: 3, FailureRate: 30}, Args{
+		PassThreshold:   5,
+		FailureRateFail: floatPtr(20),
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		want ErrorReport
+	}{
+		{
+			name: "validation",
+			err:  ValidateInputs(Args{}),
+			want: ErrorReport{Class: ErrorClassValidation, Message: "PLUGIN_REPORT_DIRECTORY: report directory is required"},
+		},
+		{
+			name: "parse",
+			err:  parseErr,
+			want: ErrorReport{Class: ErrorClassParse, Message: parseErr.Error(), File: broken},
+		},
+		{
+			name: "threshold",
+			err:  gateErr,
+			want: ErrorReport{
+				Class:   ErrorClassThreshold,
+				Message: "failure rate (30) exceeds the fail threshold (20)",
+				Metric:  "failure_rate",
+				Gates: []GateResult{{Name: "failure_rate", Metric: "failure_rate", Limit: floatPtr(20), Actual: 30, Outcome: gateFail,
+					Message: "failure rate (30) exceeds the fail threshold (20)"}},
+			},
+		},
+		{
+			name: "unclassified",
+			err:  errors.New("boom"),
+			want: ErrorReport{Class: ErrorClassExecution, Message: "boom"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err == nil {
+				t.Fatal("Expected an error")
+			}
+			got := NewErrorReport(tc.err)
+			if diff := cmp.Diff(tc.want, got, cmpopts.IgnoreFields(ErrorReport{}, "Time")); diff != "" {
+				t.Errorf("Error report mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestWriteErrorReport validates the error report file
+func TestWriteErrorReport(t *testing.T) {
+	if err := WriteErrorReport("", errors.New("boom")); err != nil {
+		t.Errorf("Expected no error without a path, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "error.json")
+	err := &RunError{Class: ErrorClassDiscovery, Err: errors.New("no files found")}
+	if err := WriteErrorReport(path, err); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		t.Fatalf("Unexpected error: %v", readErr)
+	}
+	var report ErrorReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if report.Class != ErrorClassDiscovery || report.Message != "no files found" || report.Time.IsZero() {
+		t.Errorf("Unexpected error report %+v", report)
+	}
+}
@@ -0,0 +1,36 @@
+package plugin
+
+import (
+	"os"
+	"reflect"
+)
+
+// ExpandArgs expands $VAR and ${VAR} references to environment variables
+// in the string and list arguments tagged with expand:"true", so values
+// varying per build, e.g. ${DRONE_BUILD_NUMBER}, can be configured
+// declaratively. Unset variables expand to the empty string and $$ is a
+// literal $.
+func ExpandArgs(args *Args, getenv func(string) string) {
+	mapping := func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		return getenv(name)
+	}
+
+	v := reflect.ValueOf(args).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("expand") != "true" {
+			continue
+		}
+		switch f := v.Field(i); {
+		case f.Kind() == reflect.String:
+			f.SetString(os.Expand(f.String(), mapping))
+		case f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String:
+			for j := 0; j < f.Len(); j++ {
+				f.Index(j).SetString(os.Expand(f.Index(j).String(), mapping))
+			}
+		}
+	}
+}
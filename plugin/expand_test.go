@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestExpandArgs validates environment variable expansion in tagged arguments
+func TestExpandArgs(t *testing.T) {
+	env := map[string]string{"DRONE_BUILD_NUMBER": "42", "WORKSPACE": "/drone/src"}
+	args := Args{
+		ReportDirectory:   "${WORKSPACE}/results/$DRONE_BUILD_NUMBER",
+		StatsFile:         "stats-${MISSING}.json",
+		SourceURLTemplate: "https://example.com/$$WORKSPACE/{path}",
+		ReportFileRegex:   `output-\d+\.xml$`,
+		GateExpression:    "failed_tests == 0",
+	}
+	ExpandArgs(&args, func(name string) string { return env[name] })
+
+	want := Args{
+		ReportDirectory:   "/drone/src/results/42",
+		StatsFile:         "stats-.json",
+		SourceURLTemplate: "https://example.com/$WORKSPACE/{path}",
+		ReportFileRegex:   `output-\d+\.xml$`,
+		GateExpression:    "failed_tests == 0",
+	}
+	if diff := cmp.Diff(want, args); diff != "" {
+		t.Errorf("Unexpected arguments (-want +got):\n%s", diff)
+	}
+}
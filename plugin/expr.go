@@ -0,0 +1,533 @@
+package plugin
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// exprNode is a compiled node of a gate expression.
+type exprNode interface {
+	eval(vars map[string]float64) (exprValue, error)
+}
+
+// exprValue is the result of evaluating an expression node. It is either
+// a number or a boolean.
+type exprValue struct {
+	num     float64
+	boolean bool
+	isBool  bool
+}
+
+func (v exprValue) String() string {
+	if v.isBool {
+		return strconv.FormatBool(v.boolean)
+	}
+	return strconv.FormatFloat(v.num, 'f', -1, 64)
+}
+
+// compileExpression parses a gate expression such as
+// `failure_rate < 5 && critical_failed == 0`.
+func compileExpression(input string) (exprNode, error) {
+	tokens, err := tokenizeExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return node, nil
+}
+
+// checkGateExpression compiles a gate expression and checks it without
+// evaluating it: every identifier must be one of the metrics and the
+// operand types must match, so the expression evaluates to a boolean.
+func checkGateExpression(input string, metrics map[string]float64) (exprNode, error) {
+	node, err := compileExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range exprIdentifiers(node) {
+		if _, ok := metrics[name]; !ok {
+			return nil, fmt.Errorf("unknown metric %q", name)
+		}
+	}
+	isBool, err := exprType(node)
+	if err != nil {
+		return nil, err
+	}
+	if !isBool {
+		return nil, fmt.Errorf("expression must evaluate to a boolean, got a number")
+	}
+	return node, nil
+}
+
+// evalGateExpression evaluates a boolean gate expression against the
+// given metric values. Division and modulo by zero are errors; guard
+// them with && or ||, which only evaluate their right operand when
+// needed, e.g. `total_tests > 0 && failed_tests / total_tests < 0.1`.
+func evalGateExpression(input string, vars map[string]float64) (bool, error) {
+	node, err := checkGateExpression(input, vars)
+	if err != nil {
+		return false, err
+	}
+	v, err := node.eval(vars)
+	if err != nil {
+		return false, err
+	}
+	return v.boolean, nil
+}
+
+//
+// tokenizer
+//
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var exprOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%"}
+
+func tokenizeExpression(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := rune(input[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokRParen, ")", i})
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(input) && (unicode.IsDigit(rune(input[i])) || input[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokNumber, input[start:i], start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(input) && (unicode.IsLetter(rune(input[i])) || unicode.IsDigit(rune(input[i])) || input[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, input[start:i], start})
+		default:
+			matched := false
+			for _, op := range exprOperators {
+				if strings.HasPrefix(input[i:], op) {
+					tokens = append(tokens, token{tokOp, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{tokEOF, "end of expression", len(input)}), nil
+}
+
+//
+// parser
+//
+
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *exprParser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("||"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&&"); !ok {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">"); ok {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if op, ok := p.acceptOp("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return &literalNode{value: exprValue{num: n}}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true", "false":
+			return &literalNode{value: exprValue{boolean: tok.text == "true", isBool: true}}, nil
+		}
+		return &identNode{name: tok.text}, nil
+	case tokLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' at position %d", closing.pos)
+		}
+		return node, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+//
+// nodes
+//
+
+type literalNode struct {
+	value exprValue
+}
+
+func (n *literalNode) eval(map[string]float64) (exprValue, error) {
+	return n.value, nil
+}
+
+type identNode struct {
+	name string
+}
+
+func (n *identNode) eval(vars map[string]float64) (exprValue, error) {
+	v, ok := vars[n.name]
+	if !ok {
+		return exprValue{}, fmt.Errorf("unknown metric %q", n.name)
+	}
+	return exprValue{num: v}, nil
+}
+
+type unaryNode struct {
+	op      string
+	operand exprNode
+}
+
+func (n *unaryNode) eval(vars map[string]float64) (exprValue, error) {
+	v, err := n.operand.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if n.op == "!" {
+		if !v.isBool {
+			return exprValue{}, fmt.Errorf("operator ! requires a boolean operand")
+		}
+		return exprValue{boolean: !v.boolean, isBool: true}, nil
+	}
+	if v.isBool {
+		return exprValue{}, fmt.Errorf("operator - requires a numeric operand")
+	}
+	return exprValue{num: -v.num}, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right exprNode
+}
+
+// eval short-circuits: the right operand is only evaluated when the left
+// operand does not decide the result.
+func (n *logicalNode) eval(vars map[string]float64) (exprValue, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if !l.isBool {
+		return exprValue{}, fmt.Errorf("operator %s requires boolean operands", n.op)
+	}
+	if l.boolean == (n.op == "||") {
+		return l, nil
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if !r.isBool {
+		return exprValue{}, fmt.Errorf("operator %s requires boolean operands", n.op)
+	}
+	return r, nil
+}
+
+type compareNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *compareNode) eval(vars map[string]float64) (exprValue, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if l.isBool || r.isBool {
+		if l.isBool != r.isBool || (n.op != "==" && n.op != "!=") {
+			return exprValue{}, fmt.Errorf("operator %s cannot compare %s and %s", n.op, l, r)
+		}
+		return exprValue{boolean: (l.boolean == r.boolean) == (n.op == "=="), isBool: true}, nil
+	}
+	var result bool
+	switch n.op {
+	case "==":
+		result = l.num == r.num
+	case "!=":
+		result = l.num != r.num
+	case "<":
+		result = l.num < r.num
+	case "<=":
+		result = l.num <= r.num
+	case ">":
+		result = l.num > r.num
+	case ">=":
+		result = l.num >= r.num
+	}
+	return exprValue{boolean: result, isBool: true}, nil
+}
+
+type arithNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n *arithNode) eval(vars map[string]float64) (exprValue, error) {
+	l, err := n.left.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	r, err := n.right.eval(vars)
+	if err != nil {
+		return exprValue{}, err
+	}
+	if l.isBool || r.isBool {
+		return exprValue{}, fmt.Errorf("operator %s requires numeric operands", n.op)
+	}
+	switch n.op {
+	case "+":
+		return exprValue{num: l.num + r.num}, nil
+	case "-":
+		return exprValue{num: l.num - r.num}, nil
+	case "*":
+		return exprValue{num: l.num * r.num}, nil
+	case "/":
+		if r.num == 0 {
+			return exprValue{}, fmt.Errorf("division by zero")
+		}
+		return exprValue{num: l.num / r.num}, nil
+	}
+	if r.num == 0 {
+		return exprValue{}, fmt.Errorf("modulo by zero")
+	}
+	return exprValue{num: math.Mod(l.num, r.num)}, nil
+}
+
+// exprIdentifiers returns the metric names referenced by the expression.
+func exprIdentifiers(node exprNode) []string {
+	switch n := node.(type) {
+	case *identNode:
+		return []string{n.name}
+	case *unaryNode:
+		return exprIdentifiers(n.operand)
+	case *logicalNode:
+		return append(exprIdentifiers(n.left), exprIdentifiers(n.right)...)
+	case *compareNode:
+		return append(exprIdentifiers(n.left), exprIdentifiers(n.right)...)
+	case *arithNode:
+		return append(exprIdentifiers(n.left), exprIdentifiers(n.right)...)
+	}
+	return nil
+}
+
+// exprType reports whether the expression evaluates to a boolean,
+// checking the operand types of every operator without evaluating it.
+func exprType(node exprNode) (bool, error) {
+	switch n := node.(type) {
+	case *literalNode:
+		return n.value.isBool, nil
+	case *identNode:
+		return false, nil
+	case *unaryNode:
+		isBool, err := exprType(n.operand)
+		if err != nil {
+			return false, err
+		}
+		if n.op == "!" && !isBool {
+			return false, fmt.Errorf("operator ! requires a boolean operand")
+		}
+		if n.op == "-" && isBool {
+			return false, fmt.Errorf("operator - requires a numeric operand")
+		}
+		return isBool, nil
+	}
+
+	var left, right exprNode
+	switch n := node.(type) {
+	case *logicalNode:
+		left, right = n.left, n.right
+	case *compareNode:
+		left, right = n.left, n.right
+	case *arithNode:
+		left, right = n.left, n.right
+	}
+	l, err := exprType(left)
+	if err != nil {
+		return false, err
+	}
+	r, err := exprType(right)
+	if err != nil {
+		return false, err
+	}
+	switch n := node.(type) {
+	case *logicalNode:
+		if !l || !r {
+			return false, fmt.Errorf("operator %s requires boolean operands", n.op)
+		}
+	case *compareNode:
+		if (l || r) && (l != r || (n.op != "==" && n.op != "!=")) {
+			return false, fmt.Errorf("operator %s cannot compare a boolean and a number or order booleans", n.op)
+		}
+	case *arithNode:
+		if l || r {
+			return false, fmt.Errorf("operator %s requires numeric operands", n.op)
+		}
+		return false, nil
+	}
+	return true, nil
+}
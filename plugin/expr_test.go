@@ -0,0 +1,209 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEvalGateExpression validates gate expression parsing and evaluation
+func TestEvalGateExpression(t *testing.T) {
+	vars := statsMetrics(StatsResult{
+		TotalTests:     120,
+		FailedTests:    3,
+		CriticalFailed: 0,
+		FailureRate:    2.5,
+	})
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   bool
+		expectErr  bool
+		errMsg     string
+	}{
+		{
+			name:       "All Conditions Met",
+			expression: "failure_rate < 5 && critical_failed == 0 && total_tests >= 100",
+			expected:   true,
+		},
+		{
+			name:       "Condition Not Met",
+			expression: "failed_tests == 0 || (failure_rate < 1 && !(total_tests < 100))",
+			expected:   false,
+		},
+		{
+			name:       "Arithmetic",
+			expression: "failed_tests * 100 / total_tests == failure_rate",
+			expected:   true,
+		},
+		{
+			name:       "Unknown Metric",
+			expression: "retried_tests == 0",
+			expectErr:  true,
+			errMsg:     `unknown metric "retried_tests"`,
+		},
+		{
+			name:       "Non Boolean Result",
+			expression: "failed_tests + 1",
+			expectErr:  true,
+			errMsg:     "must evaluate to a boolean",
+		},
+		{
+			name:       "Syntax Error",
+			expression: "failure_rate < 5 &&",
+			expectErr:  true,
+			errMsg:     "unexpected",
+		},
+		{
+			name:       "Division By Zero",
+			expression: "total_tests / critical_failed > 1",
+			expectErr:  true,
+			errMsg:     "division by zero",
+		},
+		{
+			name:       "Modulo By Zero",
+			expression: "total_tests % critical_failed == 0",
+			expectErr:  true,
+			errMsg:     "modulo by zero",
+		},
+		{
+			name:       "Guarded Division",
+			expression: "critical_failed == 0 || total_tests / critical_failed > 1",
+			expected:   true,
+		},
+		{
+			name:       "Type Mismatch In Skipped Operand",
+			expression: "critical_failed == 0 || failed_tests + (total_tests > 0) > 1",
+			expectErr:  true,
+			errMsg:     "requires numeric operands",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := evalGateExpression(tc.expression, vars)
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+					t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			} else if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestCheckGateExpression validates the static checks of gate expressions
+func TestCheckGateExpression(t *testing.T) {
+	metrics := statsMetrics(StatsResult{})
+
+	tests := []struct {
+		expression string
+		errMsg     string
+	}{
+		{expression: "failed_tests / total_tests < 0.1"},
+		{expression: "total_tests % 2 == 0 && !(failure_rate > 5)"},
+		{expression: "(failed_tests > 0) == (critical_failed > 0)"},
+		{expression: "retried_tests == 0", errMsg: `unknown metric "retried_tests"`},
+		{expression: "failed_tests + 1", errMsg: "must evaluate to a boolean"},
+		{expression: "!failed_tests", errMsg: "requires a boolean operand"},
+		{expression: "-(failed_tests > 0) < 1", errMsg: "requires a numeric operand"},
+		{expression: "failed_tests && true", errMsg: "requires boolean operands"},
+		{expression: "(failed_tests > 0) == 1", errMsg: "cannot compare"},
+		{expression: "true < false", errMsg: "cannot compare"},
+		{expression: "failed_tests >", errMsg: "unexpected"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := checkGateExpression(tc.expression, metrics)
+			if tc.errMsg == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
+
+// TestGateExpressionPrecedence validates operator precedence and
+// associativity
+func TestGateExpressionPrecedence(t *testing.T) {
+	vars := statsMetrics(StatsResult{TotalTests: 10, FailedTests: 2})
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{expression: "1 + 2 * 3 == 7", expected: true},
+		{expression: "(1 + 2) * 3 == 9", expected: true},
+		{expression: "10 - 4 - 3 == 3", expected: true},
+		{expression: "24 / 4 / 2 == 3", expected: true},
+		{expression: "7 % 4 * 2 == 6", expected: true},
+		{expression: "2 * 7 % 4 == 2", expected: true},
+		{expression: "-2 * 3 == -6", expected: true},
+		{expression: "- -2 == 2", expected: true},
+		{expression: "total_tests - failed_tests * 2 == 6", expected: true},
+		{expression: "failed_tests * 100 / total_tests > 10 + 5", expected: true},
+		{expression: "true || false && false", expected: true},
+		{expression: "(true || false) && false", expected: false},
+		{expression: "false && true || true", expected: true},
+		{expression: "!false && false", expected: false},
+		{expression: "!(false && false)", expected: true},
+		{expression: "!!true", expected: true},
+		{expression: "(failed_tests > 1) == true", expected: true},
+		{expression: "total_tests > 5 && failed_tests < 1 || failed_tests == 2", expected: true},
+		{expression: "total_tests > 5 && (failed_tests < 1 || failed_tests == 3)", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			result, err := evalGateExpression(tc.expression, vars)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestGateExpressionMalformed validates the errors of malformed gate
+// expressions
+func TestGateExpressionMalformed(t *testing.T) {
+	vars := statsMetrics(StatsResult{})
+
+	tests := []struct {
+		expression string
+		errMsg     string
+	}{
+		{expression: "", errMsg: `unexpected "end of expression" at position 0`},
+		{expression: "   ", errMsg: `unexpected "end of expression" at position 3`},
+		{expression: "failed_tests >", errMsg: `unexpected "end of expression" at position 14`},
+		{expression: "(failed_tests > 0", errMsg: "expected ')' at position 17"},
+		{expression: "failed_tests > 0)", errMsg: `unexpected ")" at position 16`},
+		{expression: "()", errMsg: `unexpected ")" at position 1`},
+		{expression: "failed_tests = 0", errMsg: `unexpected character '=' at position 13`},
+		{expression: "$failed_tests > 0", errMsg: `unexpected character '$' at position 0`},
+		{expression: "failed_tests > 1..2", errMsg: `invalid number "1..2" at position 15`},
+		{expression: "failed_tests > 0 total_tests", errMsg: `unexpected "total_tests" at position 17`},
+		{expression: "1 < 2 < 3", errMsg: `unexpected "<" at position 6`},
+		{expression: "&& failed_tests > 0", errMsg: `unexpected "&&" at position 0`},
+		{expression: "failed_tests >> 1", errMsg: `unexpected ">" at position 14`},
+		{expression: "failed_tests > 0 &&", errMsg: `unexpected "end of expression" at position 19`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.expression, func(t *testing.T) {
+			_, err := evalGateExpression(tc.expression, vars)
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import "strings"
+
+// defaultFailureContextLevel is the lowest message level included in the
+// failure context unless configured otherwise.
+const defaultFailureContextLevel = "INFO"
+
+// contextLevels ranks the message levels eligible for the failure
+// context. ERROR and FAIL messages are reported as the error message.
+var contextLevels = map[string]int{
+	"TRACE": 0,
+	"DEBUG": 1,
+	"INFO":  2,
+	"WARN":  3,
+}
+
+// validFailureContextLevel reports whether the failure context level is
+// known.
+func validFailureContextLevel(level string) bool {
+	if level == "" {
+		return true
+	}
+	_, ok := contextLevels[strings.ToUpper(strings.TrimSpace(level))]
+	return ok
+}
+
+// failureContextLevel returns the normalized failure context level.
+func failureContextLevel(level string) string {
+	level = strings.ToUpper(strings.TrimSpace(level))
+	if level == "" {
+		return defaultFailureContextLevel
+	}
+	return level
+}
+
+// failureContext returns the last max keyword messages at or above the
+// level logged before the test failed. Messages of keywords run after
+// the first failing keyword, like teardowns, are not included.
+func failureContext(test Test, max int, level string) []ContextMessage {
+	if max <= 0 {
+		return nil
+	}
+	min := contextLevels[failureContextLevel(level)]
+	var messages []ContextMessage
+
+	// walk collects the messages of the keywords in execution order and
+	// reports whether the failure was reached.
+	var walk func(keywords []Keyword) bool
+	walk = func(keywords []Keyword) bool {
+		for _, kw := range keywords {
+			if walk(kw.Keywords) {
+				return true
+			}
+			for _, msg := range kw.Messages {
+				if msg.Level == "FAIL" {
+					return true
+				}
+				if rank, ok := contextLevels[msg.Level]; ok && rank >= min {
+					messages = append(messages, ContextMessage{
+						Level: msg.Level,
+						Time:  firstNonEmpty(msg.Time, msg.Timestamp),
+						Text:  strings.TrimSpace(msg.Text),
+					})
+				}
+			}
+			if kw.Status.Status == "FAIL" {
+				return true
+			}
+		}
+		return false
+	}
+	walk(test.Keywords)
+
+	if len(messages) > max {
+		messages = messages[len(messages)-max:]
+	}
+	return messages
+}
@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestFailureContext validates the keyword messages collected before a failure
+func TestFailureContext(t *testing.T) {
+	test := Test{
+		Name: "Login",
+		Keywords: []Keyword{
+			{Name: "Open Browser", Status: Status{Status: "PASS"}, Messages: []Msg{
+				{Level: "INFO", Timestamp: "20240101 10:00:00.000", Text: "Opening browser"},
+				{Level: "DEBUG", Timestamp: "20240101 10:00:00.100", Text: "Capabilities"},
+			}},
+			{Name: "Login User", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+				{Name: "Input Text", Status: Status{Status: "PASS"}, Messages: []Msg{
+					{Level: "TRACE", Time: "2024-01-01T10:00:01.000000", Text: "Arguments"},
+					{Level: "INFO", Time: "2024-01-01T10:00:01.100000", Text: "Typing user"},
+				}},
+				{Name: "Click Button", Status: Status{Status: "FAIL"}, Messages: []Msg{
+					{Level: "WARN", Text: " Retrying click "},
+					{Level: "FAIL", Text: "Element not found"},
+					{Level: "INFO", Text: "After failure"},
+				}},
+			}},
+			{Name: "Close Browser", Type: "teardown", Status: Status{Status: "PASS"}, Messages: []Msg{
+				{Level: "INFO", Text: "Closing browser"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		max   int
+		level string
+		want  []ContextMessage
+	}{
+		{
+			name: "Disabled",
+		},
+		{
+			name:  "Info And Above",
+			max:   10,
+			level: "INFO",
+			want: []ContextMessage{
+				{Level: "INFO", Time: "20240101 10:00:00.000", Text: "Opening browser"},
+				{Level: "INFO", Time: "2024-01-01T10:00:01.100000", Text: "Typing user"},
+				{Level: "WARN", Text: "Retrying click"},
+			},
+		},
+		{
+			name:  "Last Messages",
+			max:   2,
+			level: "TRACE",
+			want: []ContextMessage{
+				{Level: "INFO", Time: "2024-01-01T10:00:01.100000", Text: "Typing user"},
+				{Level: "WARN", Text: "Retrying click"},
+			},
+		},
+		{
+			name:  "Warnings Only",
+			max:   5,
+			level: "warn",
+			want:  []ContextMessage{{Level: "WARN", Text: "Retrying click"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := failureContext(test, tc.max, tc.level)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected failure context (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if validFailureContextLevel("ERROR") {
+		t.Error("Expected ERROR to be rejected as failure context level")
+	}
+}
@@ -0,0 +1,44 @@
+package plugin
+
+import "unicode/utf8"
+
+// truncationMarker ends error messages cut to the maximum length.
+const truncationMarker = "..."
+
+// limitFailureDetails keeps the first max failure details, recording the
+// number of omitted details, and truncates error messages longer than
+// maxMessage characters. Zero limits are not enforced.
+func limitFailureDetails(stats *StatsResult, max, maxMessage int) {
+	if max > 0 && len(stats.FailedTestsDetails) > max {
+		stats.OmittedFailureDetails = len(stats.FailedTestsDetails) - max
+		stats.FailedTestsDetails = stats.FailedTestsDetails[:max]
+	}
+	if maxMessage <= 0 {
+		return
+	}
+	for i := range stats.FailedTestsDetails {
+		stats.FailedTestsDetails[i].ErrorMessage = truncateMessage(stats.FailedTestsDetails[i].ErrorMessage, maxMessage)
+		for j := range stats.FailedTestsDetails[i].Context {
+			stats.FailedTestsDetails[i].Context[j].Text = truncateMessage(stats.FailedTestsDetails[i].Context[j].Text, maxMessage)
+		}
+	}
+	for i := range stats.AllowedFailuresDetails {
+		stats.AllowedFailuresDetails[i].ErrorMessage = truncateMessage(stats.AllowedFailuresDetails[i].ErrorMessage, maxMessage)
+	}
+	for i := range stats.Tests {
+		stats.Tests[i].ErrorMessage = truncateMessage(stats.Tests[i].ErrorMessage, maxMessage)
+	}
+}
+
+// truncateMessage cuts the message to at most max characters, including
+// the truncation marker.
+func truncateMessage(message string, max int) string {
+	if utf8.RuneCountInString(message) <= max {
+		return message
+	}
+	runes := []rune(message)
+	if max <= len(truncationMarker) {
+		return string(runes[:max])
+	}
+	return string(runes[:max-len(truncationMarker)]) + truncationMarker
+}
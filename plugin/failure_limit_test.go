@@ -0,0 +1,53 @@
+package plugin
+
+import "testing"
+
+// TestLimitFailureDetails validates the failure detail cap and message truncation
+func TestLimitFailureDetails(t *testing.T) {
+	stats := StatsResult{
+		FailedTestsDetails: []FailedTestDetails{
+			{Name: "A", ErrorMessage: "Element 'login' not visible after 10 seconds"},
+			{Name: "B", ErrorMessage: "short"},
+			{Name: "C", ErrorMessage: "dropped"},
+		},
+		Tests: []TestResult{{Name: "A", ErrorMessage: "Element 'login' not visible after 10 seconds"}},
+	}
+	limitFailureDetails(&stats, 2, 20)
+
+	if len(stats.FailedTestsDetails) != 2 || stats.OmittedFailureDetails != 1 {
+		t.Fatalf("Expected 2 details and 1 omitted, got %d and %d", len(stats.FailedTestsDetails), stats.OmittedFailureDetails)
+	}
+	if got := stats.FailedTestsDetails[0].ErrorMessage; got != "Element 'login' n..." {
+		t.Errorf("Unexpected truncated message %q", got)
+	}
+	if got := stats.FailedTestsDetails[1].ErrorMessage; got != "short" {
+		t.Errorf("Unexpected message %q", got)
+	}
+	if got := stats.Tests[0].ErrorMessage; got != "Element 'login' n..." {
+		t.Errorf("Unexpected truncated test message %q", got)
+	}
+
+	unlimited := StatsResult{FailedTestsDetails: []FailedTestDetails{{Name: "A"}, {Name: "B"}}}
+	limitFailureDetails(&unlimited, 0, 0)
+	if len(unlimited.FailedTestsDetails) != 2 || unlimited.OmittedFailureDetails != 0 {
+		t.Errorf("Expected no limits, got %+v", unlimited)
+	}
+}
+
+// TestTruncateMessage validates truncation by characters
+func TestTruncateMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		max     int
+		want    string
+	}{
+		{"héllo wörld", 11, "héllo wörld"},
+		{"héllo wörld", 8, "héllo..."},
+		{"héllo wörld", 2, "hé"},
+	}
+	for _, tc := range tests {
+		if got := truncateMessage(tc.message, tc.max); got != tc.want {
+			t.Errorf("truncateMessage(%q, %d) = %q, want %q", tc.message, tc.max, got, tc.want)
+		}
+	}
+}
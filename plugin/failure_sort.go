@@ -0,0 +1,51 @@
+package plugin
+
+import (
+	"sort"
+	"strings"
+)
+
+// Failure detail sort keys.
+const (
+	failureSortSuite    = "suite"    // suite, then test name
+	failureSortDuration = "duration" // slowest first
+	failureSortFile     = "file"     // report file, then suite and test name
+)
+
+// validFailureSort reports whether the key is a known sort key.
+func validFailureSort(key string) bool {
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "", failureSortSuite, failureSortDuration, failureSortFile:
+		return true
+	}
+	return false
+}
+
+// sortFailureDetails sorts the failed and allowed failure details by the
+// key, suite and test name by default. Concurrent processing otherwise
+// leaves them in nondeterministic order.
+func sortFailureDetails(stats *StatsResult, key string) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, details := range [][]FailedTestDetails{stats.FailedTestsDetails, stats.AllowedFailuresDetails} {
+		sort.SliceStable(details, func(i, j int) bool {
+			return failureLess(details[i], details[j], key)
+		})
+	}
+}
+
+func failureLess(a, b FailedTestDetails, key string) bool {
+	switch key {
+	case failureSortDuration:
+		if a.Duration != b.Duration {
+			return a.Duration > b.Duration
+		}
+	case failureSortFile:
+		if a.File != b.File {
+			return a.File < b.File
+		}
+	}
+	if a.Suite != b.Suite {
+		return a.Suite < b.Suite
+	}
+	return a.Name < b.Name
+}
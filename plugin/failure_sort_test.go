@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSortFailureDetails validates the failure detail sort keys
+func TestSortFailureDetails(t *testing.T) {
+	details := []FailedTestDetails{
+		{Name: "Logout", Suite: "Auth", Duration: 300, File: "b/output.xml"},
+		{Name: "Pay", Suite: "Checkout", Duration: 900, File: "a/output.xml"},
+		{Name: "Login", Suite: "Auth", Duration: 100, File: "c/output.xml"},
+	}
+	names := func(details []FailedTestDetails) []string {
+		var names []string
+		for _, test := range details {
+			names = append(names, test.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		key  string
+		want []string
+	}{
+		{"", []string{"Login", "Logout", "Pay"}},
+		{"suite", []string{"Login", "Logout", "Pay"}},
+		{"Duration", []string{"Pay", "Logout", "Login"}},
+		{"file", []string{"Pay", "Logout", "Login"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.key, func(t *testing.T) {
+			stats := StatsResult{
+				FailedTestsDetails:     append([]FailedTestDetails(nil), details...),
+				AllowedFailuresDetails: append([]FailedTestDetails(nil), details...),
+			}
+			sortFailureDetails(&stats, tc.key)
+			if diff := cmp.Diff(tc.want, names(stats.FailedTestsDetails)); diff != "" {
+				t.Errorf("Failed tests order mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, names(stats.AllowedFailuresDetails)); diff != "" {
+				t.Errorf("Allowed failures order mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// failureCSVHeader is the header row of CSV failure detail files.
+var failureCSVHeader = []string{"name", "suite", "status", "duration", "error_message", "category", "source", "line", "url", "file"}
+
+// failureStream writes failure details to a file as each report file is
+// processed, so they are never all held in memory. Files ending in .csv
+// are written as CSV, others as JSON Lines. A nil stream writes nothing.
+type failureStream struct {
+	mu    sync.Mutex
+	path  string
+	file  *os.File
+	buf   *bufio.Writer
+	csv   *csv.Writer
+	count int
+	err   error
+
+	// Processing applied to the details before they are written.
+	rules          []ClassificationRule
+	sourceTemplate string
+	sourceRoot     string
+	maxMessage     int
+}
+
+// newFailureStream creates the failure details file of the plugin
+// arguments. It returns nil when no file is configured.
+func newFailureStream(args Args) (*failureStream, error) {
+	if args.FailureDetailsFile == "" {
+		return nil, nil
+	}
+	file, err := os.Create(args.FailureDetailsFile)
+	if err != nil {
+		return nil, err
+	}
+	s := &failureStream{
+		path:           args.FailureDetailsFile,
+		file:           file,
+		buf:            bufio.NewWriter(file),
+		rules:          args.ClassificationRules,
+		sourceTemplate: args.SourceURLTemplate,
+		sourceRoot:     args.SourceRoot,
+		maxMessage:     args.MaxErrorMessageLength,
+	}
+	if strings.EqualFold(filepath.Ext(s.path), ".csv") {
+		s.csv = csv.NewWriter(s.buf)
+		s.err = s.csv.Write(failureCSVHeader)
+	}
+	return s, nil
+}
+
+// drain classifies, links and writes the failure details of the
+// statistics of a report file, then drops them from the statistics.
+// The failure categories are still counted.
+func (s *failureStream) drain(stats *StatsResult) {
+	if s == nil || len(stats.FailedTestsDetails) == 0 {
+		return
+	}
+	// the rules were validated with the plugin arguments
+	_ = classifyFailures(stats, s.rules)
+	linkFailureSources(stats, s.sourceTemplate, s.sourceRoot)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, test := range stats.FailedTestsDetails {
+		if s.maxMessage > 0 {
+			test.ErrorMessage = truncateMessage(test.ErrorMessage, s.maxMessage)
+		}
+		if s.err == nil {
+			s.err = s.write(test)
+		}
+		s.count++
+	}
+	stats.FailedTestsDetails = nil
+}
+
+func (s *failureStream) write(test FailedTestDetails) error {
+	if s.csv != nil {
+		line := ""
+		if test.Line > 0 {
+			line = strconv.Itoa(test.Line)
+		}
+		return s.csv.Write([]string{
+			test.Name, test.Suite, test.Status, formatMetric(test.Duration), test.ErrorMessage,
+			test.Category, test.Source, line, test.URL, test.File,
+		})
+	}
+	data, err := json.Marshal(test)
+	if err != nil {
+		return err
+	}
+	_, err = s.buf.Write(append(data, '\n'))
+	return err
+}
+
+// close flushes and closes the file, returning the first write error.
+func (s *failureStream) close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.csv != nil {
+		s.csv.Flush()
+		if s.err == nil {
+			s.err = s.csv.Error()
+		}
+	}
+	if err := s.buf.Flush(); s.err == nil {
+		s.err = err
+	}
+	if err := s.file.Close(); s.err == nil {
+		s.err = err
+	}
+	return s.err
+}
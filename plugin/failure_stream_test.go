@@ -0,0 +1,92 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFailureStream validates streaming failure details to JSON Lines and CSV files
+func TestFailureStream(t *testing.T) {
+	dir := t.TempDir()
+	args := Args{
+		ReportDirectory:       "../testdata",
+		ReportFileNamePattern: "robot_report.xml",
+		ClassificationRules:   []ClassificationRule{{Category: "all", Message: "*"}},
+	}
+	want, err := Analyze(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want.FailedTestsDetails) == 0 {
+		t.Fatal("Expected failure details in the test report")
+	}
+
+	// JSON Lines
+	args.FailureDetailsFile = filepath.Join(dir, "failures.jsonl")
+	stats, err := Analyze(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.FailedTestsDetails) != 0 || stats.FailedTests != want.FailedTests {
+		t.Errorf("Expected streamed details and unchanged counts, got %d details and %d failed tests",
+			len(stats.FailedTestsDetails), stats.FailedTests)
+	}
+	if stats.FailureCategories["all"] != want.FailureCategories["all"] {
+		t.Errorf("Expected categories to be counted, got %v", stats.FailureCategories)
+	}
+	if stats.Tests != nil || stats.AllowedFailuresDetails != nil || stats.TotalTests != want.TotalTests {
+		t.Errorf("Expected no per-test results while streaming, got %d results and %d allowed failures",
+			len(stats.Tests), len(stats.AllowedFailuresDetails))
+	}
+	f, err := os.Open(args.FailureDetailsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines int
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var test FailedTestDetails
+		if err := json.Unmarshal(scanner.Bytes(), &test); err != nil {
+			t.Fatalf("Invalid JSON line: %v", err)
+		}
+		if test.Category != "all" || test.File == "" {
+			t.Errorf("Expected classified details with their file, got %+v", test)
+		}
+	}
+	if lines != len(want.FailedTestsDetails) {
+		t.Errorf("Expected %d JSON lines, got %d", len(want.FailedTestsDetails), lines)
+	}
+
+	// CSV
+	args.FailureDetailsFile = filepath.Join(dir, "failures.csv")
+	if _, err := Analyze(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.Open(args.FailureDetailsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer data.Close()
+	records, err := csv.NewReader(data).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != len(want.FailedTestsDetails)+1 || records[0][0] != "name" {
+		t.Errorf("Expected a header and %d rows, got %d records", len(want.FailedTestsDetails), len(records))
+	}
+}
+
+// TestFailureStreamConflicts validates that streaming rejects the
+// settings needing the per-test results
+func TestFailureStreamConflicts(t *testing.T) {
+	err := ValidateInputs(Args{ReportDirectory: ".", FailureDetailsFile: "failures.jsonl", SuiteTree: true, TimelineFile: "timeline.json"})
+	if err == nil || !strings.Contains(err.Error(), "PLUGIN_FAILURE_DETAILS_FILE: conflicts with PLUGIN_TIMELINE_FILE, PLUGIN_SUITE_TREE") {
+		t.Errorf("Expected a conflict with the per-test result settings, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regexPrefix marks a name pattern as a regular expression instead of
+// a glob pattern.
+const regexPrefix = "regex:"
+
+// compileNamePattern compiles a suite or test name pattern. Patterns are
+// case-insensitive globs supporting * and ?, unless prefixed with
+// "regex:" in which case the remainder is a regular expression.
+func compileNamePattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, regexPrefix); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %v", pattern, err)
+		}
+		return re, nil
+	}
+	expr := regexp.QuoteMeta(strings.TrimSpace(pattern))
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return regexp.Compile("(?i)^" + expr + "$")
+}
+
+func compileNamePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		re, err := compileNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// nameFilter selects suites and tests by name. Patterns are matched
+// against both the name and the long name (e.g. "Root.Legacy Suite").
+type nameFilter struct {
+	includeSuites []*regexp.Regexp
+	excludeSuites []*regexp.Regexp
+	includeTests  []*regexp.Regexp
+	excludeTests  []*regexp.Regexp
+}
+
+// newNameFilter compiles the suite and test name patterns of the plugin
+// arguments. It returns nil when no patterns are configured.
+func newNameFilter(args Args) (*nameFilter, error) {
+	if len(args.IncludeSuites)+len(args.ExcludeSuites)+len(args.IncludeTests)+len(args.ExcludeTests) == 0 {
+		return nil, nil
+	}
+
+	var err error
+	f := &nameFilter{}
+	if f.includeSuites, err = compileNamePatterns(args.IncludeSuites); err != nil {
+		return nil, err
+	}
+	if f.excludeSuites, err = compileNamePatterns(args.ExcludeSuites); err != nil {
+		return nil, err
+	}
+	if f.includeTests, err = compileNamePatterns(args.IncludeTests); err != nil {
+		return nil, err
+	}
+	if f.excludeTests, err = compileNamePatterns(args.ExcludeTests); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// excludesSuite reports whether the suite and all its children are
+// excluded.
+func (f *nameFilter) excludesSuite(name, longName string) bool {
+	return f != nil && matchesName(f.excludeSuites, name, longName)
+}
+
+// selectsSuite reports whether the suite, and therefore all its
+// children, is explicitly included.
+func (f *nameFilter) selectsSuite(name, longName string) bool {
+	return f == nil || len(f.includeSuites) == 0 || matchesName(f.includeSuites, name, longName)
+}
+
+// allowsTest reports whether the test passes the test name patterns.
+func (f *nameFilter) allowsTest(name, longName string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.includeTests) > 0 && !matchesName(f.includeTests, name, longName) {
+		return false
+	}
+	return !matchesName(f.excludeTests, name, longName)
+}
+
+func matchesName(patterns []*regexp.Regexp, name, longName string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) || re.MatchString(longName) {
+			return true
+		}
+	}
+	return false
+}
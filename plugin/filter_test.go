@@ -0,0 +1,79 @@
+package plugin
+
+import "testing"
+
+// TestNameFilter validates suite and test name filtering before stats computation
+func TestNameFilter(t *testing.T) {
+	robotOutput := RobotOutput{
+		Suite: Suite{
+			Name: "Root",
+			Suites: []Suite{
+				{
+					Name: "Legacy Checkout",
+					Tests: []Test{
+						{Name: "Old Flow", Status: Status{Status: "FAIL"}},
+					},
+				},
+				{
+					Name: "Login",
+					Tests: []Test{
+						{Name: "Valid Login", Status: Status{Status: "PASS"}},
+						{Name: "Invalid Login", Status: Status{Status: "FAIL"}},
+						{Name: "Login Draft 1", Status: Status{Status: "FAIL"}},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		args           Args
+		expectedTotal  int
+		expectedFailed int
+	}{
+		{
+			name:           "No Patterns",
+			expectedTotal:  4,
+			expectedFailed: 3,
+		},
+		{
+			name:           "Exclude Suite Glob",
+			args:           Args{ExcludeSuites: []string{"legacy*"}},
+			expectedTotal:  3,
+			expectedFailed: 2,
+		},
+		{
+			name:           "Include Suite By Long Name",
+			args:           Args{IncludeSuites: []string{"Root.Login"}},
+			expectedTotal:  3,
+			expectedFailed: 2,
+		},
+		{
+			name:           "Exclude Test Regex",
+			args:           Args{ExcludeTests: []string{`regex:Draft \d+$`}},
+			expectedTotal:  3,
+			expectedFailed: 2,
+		},
+		{
+			name:           "Include Test Glob",
+			args:           Args{IncludeTests: []string{"*Valid Login"}},
+			expectedTotal:  2,
+			expectedFailed: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := newStatsOptions(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			stats := computeStats(robotOutput, opts)
+			if stats.TotalTests != tc.expectedTotal || stats.FailedTests != tc.expectedFailed {
+				t.Errorf("Expected %d total and %d failed tests, got %d total and %d failed",
+					tc.expectedTotal, tc.expectedFailed, stats.TotalTests, stats.FailedTests)
+			}
+		})
+	}
+}
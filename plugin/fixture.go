@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Keyword types of setups and teardowns. RF 4 and later write them in
+// upper case.
+const (
+	keywordTypeSetup    = "setup"
+	keywordTypeTeardown = "teardown"
+)
+
+// add counts a setup or teardown keyword with the status.
+func (k *KeywordStats) add(status string) {
+	k.Total++
+	switch status {
+	case "PASS":
+		k.Passed++
+	case "FAIL":
+		k.Failed++
+	case "SKIP":
+		k.Skipped++
+	case "NOT RUN":
+		k.NotRun++
+	}
+}
+
+// merge returns the sum of the keyword counts.
+func (k KeywordStats) merge(other KeywordStats) KeywordStats {
+	return KeywordStats{
+		Total:   k.Total + other.Total,
+		Passed:  k.Passed + other.Passed,
+		Failed:  k.Failed + other.Failed,
+		Skipped: k.Skipped + other.Skipped,
+		NotRun:  k.NotRun + other.NotRun,
+	}
+}
+
+// passRate returns the percentage of passed keywords, zero without
+// keywords.
+func (k KeywordStats) passRate() float64 {
+	if k.Total == 0 {
+		return 0
+	}
+	return float64(k.Passed) / float64(k.Total) * 100
+}
+
+// countFixtures counts the setup and teardown keywords among the
+// keywords of a suite or test.
+func countFixtures(keywords []Keyword, stats *StatsResult) {
+	for _, kw := range keywords {
+		switch strings.ToLower(kw.Type) {
+		case keywordTypeSetup:
+			stats.Setups.add(kw.Status.Status)
+		case keywordTypeTeardown:
+			stats.Teardowns.add(kw.Status.Status)
+		}
+	}
+}
+
+// fixtureSummary describes the setup or teardown counts of the summary.
+func fixtureSummary(k KeywordStats) string {
+	if k.Total == 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d of %d passed (%.2f%%), %d failed", k.Passed, k.Total, k.passRate(), k.Failed)
+}
@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestFixtureStats validates the setup and teardown counts of suites and
+// tests, including the upper-case keyword types of RF 4 and later
+func TestFixtureStats(t *testing.T) {
+	report := `<?xml version="1.0" encoding="UTF-8"?>
+<robot generator="Robot 6.1 (Python 3.11.4 on linux)" schemaversion="4">
+<suite id="s1" name="Cleanup">
+<kw name="Start Server" type="SETUP"><status status="PASS"/></kw>
+<test id="s1-t1" name="Upload">
+<kw name="Create Bucket" type="SETUP"><status status="PASS"/></kw>
+<kw name="Upload File"><status status="PASS"/></kw>
+<kw name="Delete Bucket" type="TEARDOWN"><status status="FAIL">Bucket not empty</status></kw>
+<status status="FAIL">Teardown failed: Bucket not empty</status>
+</test>
+<test id="s1-t2" name="Download">
+<kw name="Create Bucket" type="SETUP"><status status="FAIL">Quota exceeded</status></kw>
+<kw name="Delete Bucket" type="TEARDOWN"><status status="PASS"/></kw>
+<status status="FAIL">Setup failed: Quota exceeded</status>
+</test>
+<kw name="Stop Server" type="TEARDOWN"><status status="PASS"/></kw>
+<status status="FAIL"/>
+</suite>
+</robot>
+`
+	path := filepath.Join(t.TempDir(), "output.xml")
+	if err := os.WriteFile(path, []byte(report), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := processFile(path, statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(KeywordStats{Total: 3, Passed: 2, Failed: 1}, stats.Setups); diff != "" {
+		t.Errorf("Unexpected setup counts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(KeywordStats{Total: 3, Passed: 2, Failed: 1}, stats.Teardowns); diff != "" {
+		t.Errorf("Unexpected teardown counts (-want +got):\n%s", diff)
+	}
+	if got := fixtureSummary(stats.Teardowns); got != "2 of 3 passed (66.67%), 1 failed" {
+		t.Errorf("Unexpected teardown summary %q", got)
+	}
+
+	skipped, err := processFile(path, statsOptions{SkipKeywords: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped.Setups.Total != 0 || fixtureSummary(skipped.Setups) != "none" {
+		t.Errorf("Expected no setup counts when keywords are skipped, got %+v", skipped.Setups)
+	}
+}
@@ -0,0 +1,99 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fuzzSeeds adds the sample reports of the repository to the seed corpus.
+func fuzzSeeds(f *testing.F) {
+	logrus.SetLevel(logrus.PanicLevel)
+	f.Cleanup(func() { logrus.SetLevel(logrus.InfoLevel) })
+
+	for _, pattern := range []string{"../testdata/*.xml", "../testdata/*/output.xml", "../robottest/corpus/*/output.xml"} {
+		files, _ := filepath.Glob(pattern)
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				f.Fatal(err)
+			}
+			f.Add(data)
+		}
+	}
+	f.Add([]byte(`<robot><suite><test><status status="PASS" start="x" elapsed="-1"/></test></suite></robot>`))
+	f.Add([]byte("\xff\xfe<\x00r\x00"))
+}
+
+// FuzzParseOutput validates that malformed reports are rejected with an
+// error, with and without salvaging, and never make the parser panic
+func FuzzParseOutput(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, salvage := range []bool{false, true} {
+			opts, err := newStatsOptions(Args{SalvageTruncated: salvage, FailureContextMessages: 3, SchemaValidation: "warn"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			robotOutput, err := parseOutput(data, "output.xml", opts)
+			if err != nil || robotOutput == nil {
+				continue
+			}
+			stats := computeStats(*robotOutput, opts)
+			evaluateThresholds(stats, Args{})
+		}
+	})
+}
+
+// TestRecoverParse validates that a parser panic fails the file instead
+// of the run
+func TestRecoverParse(t *testing.T) {
+	parse := func() (err error) {
+		defer recoverParse("output.xml", &err)
+		var suites []Suite
+		_ = suites[1]
+		return nil
+	}
+	err := parse()
+	var runErr *RunError
+	if !errors.As(err, &runErr) || runErr.Class != ErrorClassParse || runErr.File != "output.xml" {
+		t.Errorf("Expected a parse error of output.xml, got %v", err)
+	}
+}
+
+// FuzzDecodeStatistics validates the fast summary path on malformed
+// reports
+func FuzzDecodeStatistics(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		robotOutput, err := decodeStatistics(bytes.NewReader(data), "output.xml", newXMLLimits(Args{}))
+		if err != nil {
+			return
+		}
+		opts := statsOptions{CountSkipped: true, profile: detectProfile(robotOutput)}
+		summaryStats(robotOutput.Statistics, opts)
+	})
+}
+
+// FuzzParseStatsFile validates decoding statistics files and comparing
+// runs with them
+func FuzzParseStatsFile(f *testing.F) {
+	f.Add([]byte(`{"total_tests":2,"tests":[{"name":"a","suite":"s","status":"FAIL","duration":1}]}`))
+	f.Add([]byte(`{"files":[{"file":"a.xml","total_tests":-1}],"tags":{"smoke":{"pass":1}}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		stats, err := parseStatsFile(data)
+		if err != nil {
+			return
+		}
+		compareRuns(stats, stats)
+		evaluateThresholds(stats, Args{ThresholdsScope: thresholdsScopeBoth})
+		if _, err := json.Marshal(stats); err != nil {
+			t.Errorf("Failed to encode decoded statistics: %v", err)
+		}
+	})
+}
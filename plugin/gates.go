@@ -0,0 +1,100 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Gate outcomes.
+const (
+	gatePass = "pass"
+	gateWarn = "warn"
+	gateFail = "fail"
+)
+
+// GateResult is the outcome of a single configured gate: a threshold
+// rule, a suite threshold, the duration budget, the gate expression or
+// an execution time regression limit.
+type GateResult struct {
+	Name    string   `json:"name"`
+	Metric  string   `json:"metric,omitempty"`
+	Limit   *float64 `json:"limit,omitempty"` // the breached limit, otherwise the fail limit
+	Actual  float64  `json:"actual"`
+	Outcome string   `json:"outcome"` // pass, warn or fail
+	Message string   `json:"message,omitempty"`
+}
+
+// EvaluateGates evaluates the statistics against the thresholds, the
+// gate expression and the baseline run. It returns the outcome of every
+// configured gate and an error describing the failed gates.
+func EvaluateGates(stats StatsResult, args Args) ([]GateResult, error) {
+	gates := evaluateThresholds(stats, args)
+
+	// Compare against the baseline run
+	if args.BaselineFile != "" {
+		baseline, err := loadBaseline(args.BaselineFile, args)
+		if err != nil {
+			return gates, &RunError{Class: ErrorClassParse, File: args.BaselineFile, Err: fmt.Errorf("failed to load baseline: %v", err)}
+		}
+		gates = append(gates, executionTimeRegressionGates(stats, baseline, args)...)
+	}
+	return gates, gateErrors(gates)
+}
+
+// gateErrors returns a threshold error joining the messages of the
+// failed gates.
+func gateErrors(gates []GateResult) error {
+	var failed []GateResult
+	var errs []error
+	for _, gate := range gates {
+		if gate.Outcome == gateFail {
+			failed = append(failed, gate)
+			errs = append(errs, errors.New(gate.Message))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &RunError{Class: ErrorClassThreshold, Gates: failed, Err: errors.Join(errs...)}
+}
+
+// gateOutcome returns the overall outcome of the gates: fail when any
+// gate failed, warn when any gate warned, pass otherwise.
+func gateOutcome(gates []GateResult) string {
+	outcome := gatePass
+	for _, gate := range gates {
+		switch gate.Outcome {
+		case gateFail:
+			return gateFail
+		case gateWarn:
+			outcome = gateWarn
+		}
+	}
+	return outcome
+}
+
+// writeGateResults sets the outcome, actual value and limit of every
+// gate and the overall outcome as output variables.
+func writeGateResults(out *outputVars, gates []GateResult) {
+	if len(gates) == 0 {
+		return
+	}
+	out.set("GATE_RESULT", gateOutcome(gates))
+	for _, gate := range gates {
+		name := "GATE_" + outputName(gate.Name)
+		out.set(name, gate.Outcome)
+		out.set(name+"_ACTUAL", formatMetric(gate.Actual))
+		if gate.Limit != nil {
+			out.set(name+"_LIMIT", formatMetric(*gate.Limit))
+		}
+	}
+}
+
+// boolMetric returns 1 for true and 0 for false, the actual value of
+// pass/fail gates.
+func boolMetric(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestEvaluateGates validates the outcome of every configured gate
+func TestEvaluateGates(t *testing.T) {
+	stats := StatsResult{
+		TotalTests:  10,
+		PassedTests: 7,
+		FailedTests: 3,
+		FailureRate: 30,
+		SlowTests:   []SlowTestDetails{{Name: "Slow"}},
+		Tests: []TestResult{
+			{Name: "Pay", Suite: "Root.Checkout", Status: "FAIL"},
+			{Name: "Cart", Suite: "Root.Checkout", Status: "PASS"},
+		},
+	}
+	args := Args{
+		PassThreshold:     5,
+		UnstableThreshold: 2,
+		FailureRateFail:   floatPtr(20),
+		MaxTestDuration:   1000,
+		SuiteThresholds:   []SuiteThreshold{{Suite: "Checkout", FailedTestsFail: floatPtr(1)}},
+		GateExpression:    "passed_tests >= 5",
+	}
+
+	gates, err := EvaluateGates(stats, args)
+	if err == nil || err.Error() != "failure rate (30) exceeds the fail threshold (20)" {
+		t.Errorf("Expected the failure rate gate to fail, got %v", err)
+	}
+
+	want := []GateResult{
+		{Name: "failed_tests", Metric: "failed_tests", Limit: floatPtr(2), Actual: 3, Outcome: gateWarn,
+			Message: "failed tests count (3) exceeds the unstable threshold (2)"},
+		{Name: "failure_rate", Metric: "failure_rate", Limit: floatPtr(20), Actual: 30, Outcome: gateFail,
+			Message: "failure rate (30) exceeds the fail threshold (20)"},
+		{Name: "suite Checkout failed_tests", Metric: "failed_tests", Limit: floatPtr(1), Actual: 1, Outcome: gatePass},
+		{Name: "slow_tests", Metric: "slow_tests", Actual: 1, Outcome: gateWarn,
+			Message: "1 tests exceeded their duration budget"},
+		{Name: "expression", Actual: 1, Outcome: gatePass},
+	}
+	if diff := cmp.Diff(want, gates); diff != "" {
+		t.Errorf("Gate results mismatch (-want +got):\n%s", diff)
+	}
+	if got := gateOutcome(gates); got != gateFail {
+		t.Errorf("Expected overall outcome fail, got %s", got)
+	}
+	if got := gateOutcome(gates[:1]); got != gateWarn {
+		t.Errorf("Expected overall outcome warn, got %s", got)
+	}
+}
+
+// TestWriteGateResults validates the gate output variables
+func TestWriteGateResults(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.env")
+
+	out := &outputVars{}
+	writeGateResults(out, []GateResult{
+		{Name: "failure_rate", Metric: "failure_rate", Limit: floatPtr(20), Actual: 12.5, Outcome: gatePass},
+		{Name: "expression", Actual: 0, Outcome: gateFail},
+	})
+	if err := out.writeTo(outputTarget{Path: output, Format: outputFormatDotenv}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := strings.Join([]string{
+		"GATE_RESULT=fail",
+		"GATE_FAILURE_RATE=pass",
+		"GATE_FAILURE_RATE_ACTUAL=12.5",
+		"GATE_FAILURE_RATE_LIMIT=20",
+		"GATE_EXPRESSION=fail",
+		"GATE_EXPRESSION_ACTUAL=0",
+	}, "\n") + "\n"
+	if diff := cmp.Diff(want, string(data)); diff != "" {
+		t.Errorf("Output mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestKeywordFailureGates validates the failed keyword gates of passed
+// tests with failed keywords
+func TestKeywordFailureGates(t *testing.T) {
+	stats := StatsResult{TotalTests: 4, PassedTests: 4, TotalKeywords: 200, FailedKeywords: 3}
+	if rate := statsMetrics(stats)["keyword_failure_rate"]; rate != 1.5 {
+		t.Errorf("Expected a keyword failure rate of 1.5, got %v", rate)
+	}
+
+	_, err := EvaluateGates(stats, Args{MaxFailedKeywords: floatPtr(5), MaxKeywordFailureRate: floatPtr(1.5)})
+	if err != nil {
+		t.Errorf("Expected the keyword gates to pass, got %v", err)
+	}
+	gates, err := EvaluateGates(stats, Args{MaxFailedKeywords: floatPtr(2), MaxKeywordFailureRate: floatPtr(1)})
+	if err == nil {
+		t.Fatal("Expected the keyword gates to fail")
+	}
+	for _, name := range []string{"failed_keywords", "keyword_failure_rate"} {
+		if gate, ok := findGate(gates, name); !ok || gate.Outcome != gateFail {
+			t.Errorf("Expected the %s gate to fail, got %+v", name, gate)
+		}
+	}
+
+	err = ValidateInputs(Args{ReportDirectory: ".", SkipKeywordStats: true, MaxKeywordFailureRate: floatPtr(1)})
+	if err == nil || !strings.Contains(err.Error(), "PLUGIN_MAX_KEYWORD_FAILURE_RATE") {
+		t.Errorf("Expected a conflict with PLUGIN_SKIP_KEYWORD_STATS, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// errNoHarnessOutput is returned when PLUGIN_HARNESS_OUTPUTS is set
+// outside a Harness CI step.
+var errNoHarnessOutput = fmt.Errorf("no Harness output file, set DRONE_OUTPUT or %s", harnessOutputEnv)
+
+// harnessOutputTarget selects the output variable file Harness CI reads
+// the step output variables from: DRONE_OUTPUT of plugin steps, which
+// Harness parses as KEY=value lines, then the output file of run steps.
+// PLUGIN_OUTPUT_FILE and PLUGIN_OUTPUT_FORMAT do not apply, since Harness
+// would not read the variables otherwise.
+func harnessOutputTarget(getenv func(string) string) (outputTarget, error) {
+	switch {
+	case getenv("DRONE_OUTPUT") != "":
+		return outputTarget{Path: getenv("DRONE_OUTPUT"), Format: outputFormatDotenv}, nil
+	case getenv(harnessOutputEnv) != "":
+		return outputTarget{Path: getenv(harnessOutputEnv), Format: outputFormatExport}, nil
+	}
+	return outputTarget{}, errNoHarnessOutput
+}
+
+// writeHarnessOutputs sets the gate outputs even when no gates are
+// configured, so Harness expressions such as
+// <+steps.robot.output.outputVariables.GATE_RESULT> always resolve.
+func writeHarnessOutputs(out *outputVars) {
+	if _, ok := out.values["GATE_RESULT"]; !ok {
+		out.set("GATE_RESULT", gatePass)
+	}
+	if _, ok := out.values["UNSTABLE"]; !ok {
+		writeUnstable(out, false)
+	}
+}
+
+// exportHarnessOutputs writes the output variables to the Harness output
+// file. Failures are logged unless PLUGIN_FAIL_ON_OUTPUT_ERROR is set.
+func exportHarnessOutputs(out *outputVars, args Args) error {
+	writeHarnessOutputs(out)
+	target, err := harnessOutputTarget(os.Getenv)
+	if err == nil {
+		err = out.writeTo(target)
+	}
+	if err == nil || args.FailOnOutputError {
+		return err
+	}
+	logrus.Warnf("Failed to export Harness output variables: %v", err)
+	return nil
+}
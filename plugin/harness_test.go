@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHarnessOutputs validates the step output variables written for
+// Harness CI, ignoring the generic output file settings
+func TestHarnessOutputs(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.env")
+	t.Setenv("DRONE_OUTPUT", output)
+	t.Setenv("HARNESS_OUTPUT_FILE", "")
+
+	args := Args{HarnessOutputs: true, OutputFile: filepath.Join(t.TempDir(), "ignored.env"), OutputFormat: outputFormatExport}
+	reporter, err := newDroneOutputReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), StatsResult{TotalTests: 3, PassedTests: 2, FailedTests: 1}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"FAILED_TESTS=1\n", "TOTAL_TESTS=3\n", "GATE_RESULT=pass\n", "UNSTABLE=false\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in the output file:\n%s", want, data)
+		}
+	}
+	if _, err := os.Stat(args.OutputFile); err == nil {
+		t.Errorf("Expected PLUGIN_OUTPUT_FILE to be ignored")
+	}
+}
+
+// TestHarnessOutputTarget validates the Harness output files and the
+// error outside Harness CI
+func TestHarnessOutputTarget(t *testing.T) {
+	env := map[string]string{harnessOutputEnv: "/harness/output.sh"}
+	target, err := harnessOutputTarget(func(key string) string { return env[key] })
+	if err != nil || target != (outputTarget{Path: "/harness/output.sh", Format: outputFormatExport}) {
+		t.Errorf("Expected the Harness output file, got %+v and %v", target, err)
+	}
+
+	t.Setenv("DRONE_OUTPUT", "")
+	t.Setenv(harnessOutputEnv, "")
+	out := &outputVars{}
+	out.set("TOTAL_TESTS", "1")
+	if err := exportHarnessOutputs(out, Args{}); err != nil {
+		t.Errorf("Expected output errors to be logged only, got %v", err)
+	}
+	if err := exportHarnessOutputs(out, Args{FailOnOutputError: true}); err != errNoHarnessOutput {
+		t.Errorf("Expected %v, got %v", errNoHarnessOutput, err)
+	}
+}
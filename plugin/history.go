@@ -0,0 +1,343 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HistoryRecord is a run recorded in the history store. Runs are
+// recorded compact, see compactStats; records of earlier versions hold
+// the full statistics.
+type HistoryRecord struct {
+	Recorded time.Time   `json:"recorded"`
+	Build    *BuildInfo  `json:"build,omitempty"`
+	Outcome  string      `json:"outcome"`           // gate outcome, pass, warn or fail
+	Compact  bool        `json:"compact,omitempty"` // Stats.Tests lists the failed tests only
+	Stats    StatsResult `json:"stats"`
+
+	name string // record object name, set when read from the store
+}
+
+// branch returns the branch the run was recorded on.
+func (r HistoryRecord) branch() string {
+	if r.Build == nil {
+		return ""
+	}
+	return r.Build.Branch
+}
+
+// number returns the build number of the run.
+func (r HistoryRecord) number() string {
+	if r.Build == nil {
+		return ""
+	}
+	return r.Build.Number
+}
+
+// historyStore keeps the statistics of past runs in a history backend,
+// one JSON object per run. Runs beyond the retention limits are pruned as
+// new runs are recorded.
+type historyStore struct {
+	backend historyBackend
+	maxRuns int
+	maxAge  time.Duration
+}
+
+// newHistoryStore returns the history store of the plugin arguments, or
+// nil when the history is disabled.
+func newHistoryStore(args Args) (*historyStore, error) {
+	if args.HistoryDir == "" {
+		return nil, nil
+	}
+	backend, err := newHistoryBackend(args)
+	if err != nil {
+		return nil, err
+	}
+	return &historyStore{backend: backend, maxRuns: args.HistoryMaxRuns, maxAge: args.HistoryMaxAge}, nil
+}
+
+// records returns the recorded runs, oldest first.
+func (h *historyStore) records(ctx context.Context) ([]HistoryRecord, error) {
+	names, err := h.backend.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var records []HistoryRecord
+	for _, name := range names {
+		if !strings.HasPrefix(name, "run-") || path.Ext(name) != ".json" {
+			continue
+		}
+		data, err := h.backend.Read(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		var record HistoryRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			logrus.Warnf("Ignoring invalid history record %s: %v", name, err)
+			continue
+		}
+		record.name = name
+		records = append(records, record)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Recorded.Before(records[j].Recorded) })
+	return records, nil
+}
+
+// runs returns the recorded runs read by attachHistory, or reads them
+// when the statistics were not annotated with the history.
+func (h *historyStore) runs(ctx context.Context, stats StatsResult) ([]HistoryRecord, error) {
+	if stats.History != nil {
+		return stats.History, nil
+	}
+	return h.records(ctx)
+}
+
+// recordName returns the object name of the record.
+func recordName(record HistoryRecord) string {
+	name := fmt.Sprintf("run-%d", record.Recorded.UnixNano())
+	if number := record.number(); number != "" {
+		name += "-" + number
+	}
+	return name + ".json"
+}
+
+// add records the run.
+func (h *historyStore) add(ctx context.Context, record HistoryRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return h.backend.Write(ctx, recordName(record), data)
+}
+
+// prune removes the recorded runs beyond the retention limits: runs
+// recorded before now minus the maximum age, then the oldest runs beyond
+// the maximum number of runs. The records are oldest first. It returns
+// the number of removed runs.
+func (h *historyStore) prune(ctx context.Context, records []HistoryRecord, now time.Time) (int, error) {
+	expired := 0
+	if h.maxAge > 0 {
+		cutoff := now.Add(-h.maxAge)
+		for expired < len(records) && records[expired].Recorded.Before(cutoff) {
+			expired++
+		}
+	}
+	if h.maxRuns > 0 && len(records)-expired > h.maxRuns {
+		expired = len(records) - h.maxRuns
+	}
+	for _, record := range records[:expired] {
+		if err := h.backend.Delete(ctx, record.name); err != nil {
+			return 0, err
+		}
+	}
+	return expired, nil
+}
+
+// selectBaseline returns the baseline run: the latest run of the pinned
+// build number when set, otherwise the latest run on the branch whose
+// gates did not fail.
+func selectBaseline(records []HistoryRecord, branch, pinned string) (HistoryRecord, bool) {
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if pinned != "" {
+			if record.number() == pinned {
+				return record, true
+			}
+			continue
+		}
+		if record.branch() == branch && record.Outcome != gateFail {
+			return record, true
+		}
+	}
+	return HistoryRecord{}, false
+}
+
+// historyBaseline returns the baseline run of the current build among
+// the recorded runs. It returns false when no run qualifies.
+func historyBaseline(records []HistoryRecord, build *BuildInfo, pinned string) (HistoryRecord, bool, error) {
+	branch := ""
+	if build != nil {
+		branch = build.Branch
+	}
+	record, ok := selectBaseline(records, branch, pinned)
+	if !ok {
+		if pinned != "" {
+			return HistoryRecord{}, false, fmt.Errorf("build %s is not recorded in the history", pinned)
+		}
+		logrus.Infof("No successful run on branch %q in the history, skipping the comparison", branch)
+		return HistoryRecord{}, false, nil
+	}
+	logrus.Infof("Comparing against build %s recorded %s", record.number(), record.Recorded.Format(time.RFC3339))
+	return record, true, nil
+}
+
+// baselineStats returns the statistics of the baseline run the current
+// run is compared against. A compact record lists only its failed tests
+// without durations, so the other tests of the current run are taken as
+// passed in the baseline and no duration changes are reported.
+func baselineStats(record HistoryRecord, current StatsResult) StatsResult {
+	stats := record.Stats
+	if !record.Compact {
+		return stats
+	}
+	failed := map[string]TestResult{}
+	for _, test := range stats.Tests {
+		failed[testKey(test)] = test
+	}
+	stats.Tests = nil
+	for _, test := range current.Tests {
+		key := testKey(test)
+		if previous, ok := failed[key]; ok {
+			test.Status, test.ErrorMessage = previous.Status, previous.ErrorMessage
+			delete(failed, key)
+		} else {
+			test.Status, test.ErrorMessage = "PASS", ""
+		}
+		stats.Tests = append(stats.Tests, test)
+	}
+	for _, test := range record.Stats.Tests {
+		if _, ok := failed[testKey(test)]; ok {
+			stats.Tests = append(stats.Tests, test)
+		}
+	}
+	return stats
+}
+
+// compactStats returns the statistics recorded in the history: the
+// counts and durations, the durations of the leaf suites for the split
+// manifest, the flaky tests and the failed tests, rather than every test
+// result and report detail.
+func compactStats(stats StatsResult) StatsResult {
+	compact := StatsResult{
+		TotalSuites:        stats.TotalSuites,
+		TotalTests:         stats.TotalTests,
+		PassedTests:        stats.PassedTests,
+		FailedTests:        stats.FailedTests,
+		SkippedTests:       stats.SkippedTests,
+		NotRunTests:        stats.NotRunTests,
+		AllowedFailures:    stats.AllowedFailures,
+		FailureRate:        stats.FailureRate,
+		SkippedRate:        stats.SkippedRate,
+		ExecutionTime:      stats.ExecutionTime,
+		WallClockTime:      stats.WallClockTime,
+		CumulativeTestTime: stats.CumulativeTestTime,
+		StartTime:          stats.StartTime,
+		EndTime:            stats.EndTime,
+		FlakyTests:         stats.FlakyTests,
+		FlakyTestsDetails:  stats.FlakyTestsDetails,
+	}
+	durations := leafSuiteDurations(stats.Suites)
+	for _, suite := range stats.Suites {
+		if duration, ok := durations[suite.Name]; ok {
+			compact.Suites = append(compact.Suites, SuiteStats{Name: suite.Name, ExecutionTime: duration})
+			delete(durations, suite.Name)
+		}
+	}
+	for _, test := range stats.Tests {
+		if test.Status == "FAIL" && !test.AllowedFailure {
+			compact.Tests = append(compact.Tests, TestResult{
+				Name:         test.Name,
+				Suite:        test.Suite,
+				Status:       test.Status,
+				ErrorMessage: test.ErrorMessage,
+				File:         test.File,
+			})
+		}
+	}
+	return compact
+}
+
+// branchRecords returns the records of the branch, oldest first.
+func branchRecords(records []HistoryRecord, branch string) []HistoryRecord {
+	var filtered []HistoryRecord
+	for _, record := range records {
+		if record.branch() == branch {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// attachHistory annotates the statistics with the recorded runs on the
+// branch of the current run: the trend rendered by the reports and the
+// failing streaks of the failed tests, the suite durations of the split
+// manifest and the failure leaderboard.
+func attachHistory(ctx context.Context, stats *StatsResult, args Args) error {
+	history, err := newHistoryStore(args)
+	if history == nil || err != nil {
+		return err
+	}
+	records, err := history.records(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read history from %s: %v", history.backend, err)
+	}
+	if records == nil {
+		records = []HistoryRecord{}
+	}
+	// shared with the comparison and history reporters
+	stats.History = records
+	branch := ""
+	if stats.Build != nil {
+		branch = stats.Build.Branch
+	}
+	records = branchRecords(records, branch)
+	if trendEnabled(args) {
+		stats.Trend = trendPoints(records, *stats, trendRuns(args))
+	}
+	if args.SplitManifest != "" {
+		stats.SuiteDurations, stats.SuiteDurationRuns = suiteDurations(records, *stats, splitRuns)
+	}
+	if args.FailureLeaderboard > 0 {
+		stats.FailureLeaderboard = runLeaderboard(records, *stats, leaderboardRuns(args), args.FailureLeaderboard)
+	}
+	annotateFailingStreaks(stats, records)
+	return nil
+}
+
+// newHistoryReporter records the run in the history store.
+func newHistoryReporter(args Args) (Reporter, error) {
+	history, err := newHistoryStore(args)
+	if history == nil || err != nil {
+		return nil, err
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		// the runs recorded before this one, read once per run
+		var records []HistoryRecord
+		pruning := history.maxRuns > 0 || history.maxAge > 0
+		if pruning {
+			var err error
+			if records, err = history.runs(ctx, stats); err != nil {
+				return fmt.Errorf("failed to read history from %s: %v", history.backend, err)
+			}
+		}
+		record := HistoryRecord{
+			Recorded: time.Now().UTC(),
+			Build:    stats.Build,
+			Outcome:  gateOutcome(stats.Gates),
+			Compact:  true,
+			Stats:    compactStats(stats),
+		}
+		if err := history.add(ctx, record); err != nil {
+			return fmt.Errorf("failed to record the run in %s: %v", history.backend, err)
+		}
+		if !pruning {
+			return nil
+		}
+		record.name = recordName(record)
+		pruned, err := history.prune(ctx, append(records[:len(records):len(records)], record), record.Recorded)
+		if err != nil {
+			return fmt.Errorf("failed to prune the history in %s: %v", history.backend, err)
+		}
+		if pruned > 0 {
+			logrus.Infof("Pruned %d runs from the history", pruned)
+		}
+		return nil
+	}), nil
+}
@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyAPITimeout limits each call to a remote history backend.
+const historyAPITimeout = 30 * time.Second
+
+// historyBackend stores the history records as named objects. The
+// history location selects the backend: s3://bucket/prefix for Amazon S3
+// and S3 compatible services, gs://bucket/prefix for Google Cloud Storage
+// and a directory otherwise. String returns the location.
+type historyBackend interface {
+	List(ctx context.Context) ([]string, error)
+	Read(ctx context.Context, name string) ([]byte, error)
+	Write(ctx context.Context, name string, data []byte) error
+	Delete(ctx context.Context, name string) error
+	String() string
+}
+
+// newHistoryBackend returns the backend of the history location.
+func newHistoryBackend(args Args) (historyBackend, error) {
+	location := args.HistoryDir
+	scheme, rest, ok := strings.Cut(location, "://")
+	if !ok {
+		return localHistory{dir: location}, nil
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("missing bucket in history location %s", location)
+	}
+	if prefix = strings.Trim(prefix, "/"); prefix != "" {
+		prefix += "/"
+	}
+	switch strings.ToLower(scheme) {
+	case "s3":
+		return newS3History(args, bucket, prefix)
+	case "gs":
+		return newGCSHistory(args, bucket, prefix)
+	}
+	return nil, fmt.Errorf("unsupported history location %s, expected a directory, s3:// or gs://", location)
+}
+
+// localHistory stores the records as files in a directory, e.g. on a
+// shared volume.
+type localHistory struct {
+	dir string
+}
+
+func (l localHistory) String() string {
+	return l.dir
+}
+
+// List returns the file names. A missing directory holds no records.
+func (l localHistory) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (l localHistory) Read(ctx context.Context, name string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(l.dir, name))
+}
+
+// Write writes a temporary file renamed into place, so concurrent runs
+// never read partial records.
+func (l localHistory) Write(ctx context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(l.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(l.dir, name))
+}
+
+// Delete removes the file. Records already removed, e.g. by a concurrent
+// run, are ignored.
+func (l localHistory) Delete(ctx context.Context, name string) error {
+	if err := os.Remove(filepath.Join(l.dir, name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// historyRequest sends the request with the authenticating client of a
+// remote history backend and returns the response body of a successful
+// response.
+func historyRequest(ctx context.Context, client *http.Client, req *http.Request) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, historyAPITimeout)
+	defer cancel()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &historyStatusError{Method: req.Method, URL: redactedURL(req.URL), StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+	return body, nil
+}
+
+// historyStatusError is the error of an unsuccessful response of a
+// remote history backend.
+type historyStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *historyStatusError) Error() string {
+	return fmt.Sprintf("%s %s responded with %s", e.Method, e.URL, e.Status)
+}
+
+// redactedURL returns the URL without its query, which may hold tokens.
+func redactedURL(u *url.URL) string {
+	redacted := *u
+	redacted.RawQuery = ""
+	return redacted.String()
+}
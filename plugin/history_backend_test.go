@@ -0,0 +1,215 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHistoryBackendLocation validates the backend selection
+func TestHistoryBackendLocation(t *testing.T) {
+	tests := []struct {
+		location, want string
+	}{
+		{"/cache/history", "/cache/history"},
+		{"s3://bucket/robot/history/", "s3://bucket/robot/history/"},
+		{"gs://bucket", "gs://bucket/"},
+	}
+	for _, test := range tests {
+		args := Args{HistoryDir: test.location, HistoryAccessKey: "key", HistorySecretKey: "secret", HistoryToken: "token"}
+		backend, err := newHistoryBackend(args)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.location, err)
+		}
+		if got := backend.String(); got != test.want {
+			t.Errorf("Expected backend %s, got %s", test.want, got)
+		}
+	}
+	for _, location := range []string{"ftp://host/history", "s3:///history"} {
+		if _, err := newHistoryBackend(Args{HistoryDir: location}); err == nil {
+			t.Errorf("Expected an error for %s", location)
+		}
+	}
+}
+
+// objectServer is an in-memory object store behind a fake API.
+type objectServer struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (o *objectServer) keys(prefix string) []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var keys []string
+	for key := range o.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// s3Handler serves path-style S3 requests of the bucket.
+func (o *objectServer) s3Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+			http.Error(w, "unsigned request", http.StatusForbidden)
+			return
+		}
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/bucket"), "/")
+		if r.Method == http.MethodGet && key == "" {
+			var result struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []struct {
+					Key string `xml:"Key"`
+				} `xml:"Contents"`
+			}
+			for _, key := range o.keys(r.URL.Query().Get("prefix")) {
+				result.Contents = append(result.Contents, struct {
+					Key string `xml:"Key"`
+				}{key})
+			}
+			xml.NewEncoder(w).Encode(result)
+			return
+		}
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet:
+			data, ok := o.objects[key]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		case r.Method == http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			o.objects[key] = data
+		case r.Method == http.MethodDelete:
+			delete(o.objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// gcsHandler serves GCS JSON API requests of the bucket.
+func (o *objectServer) gcsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		path := r.URL.Path
+		switch {
+		case r.Method == http.MethodGet && path == "/storage/v1/b/bucket/o":
+			var result gcsListResult
+			for _, key := range o.keys(r.URL.Query().Get("prefix")) {
+				result.Items = append(result.Items, struct {
+					Name string `json:"name"`
+				}{key})
+			}
+			json.NewEncoder(w).Encode(result)
+		case r.Method == http.MethodPost && path == "/upload/storage/v1/b/bucket/o":
+			data, _ := io.ReadAll(r.Body)
+			o.mu.Lock()
+			o.objects[r.URL.Query().Get("name")] = data
+			o.mu.Unlock()
+		case strings.HasPrefix(path, "/storage/v1/b/bucket/o/"):
+			name, _ := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/storage/v1/b/bucket/o/"))
+			o.mu.Lock()
+			defer o.mu.Unlock()
+			if r.Method == http.MethodDelete {
+				if _, ok := o.objects[name]; !ok {
+					http.NotFound(w, r)
+					return
+				}
+				delete(o.objects, name)
+				return
+			}
+			data, ok := o.objects[name]
+			if !ok || r.URL.Query().Get("alt") != "media" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+// TestRemoteHistory validates recording, reading and pruning runs in the
+// remote backends
+func TestRemoteHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		location string
+		handler  func(*objectServer) http.HandlerFunc
+	}{
+		{"s3", "s3://bucket/robot", (*objectServer).s3Handler},
+		{"gcs", "gs://bucket/robot", (*objectServer).gcsHandler},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := &objectServer{objects: map[string][]byte{"robot/nested/run-1.json": []byte("{}")}}
+			server := httptest.NewServer(test.handler(objects))
+			defer server.Close()
+
+			history, err := newHistoryStore(Args{
+				HistoryDir:       test.location,
+				HistoryEndpoint:  server.URL,
+				HistoryAccessKey: "key",
+				HistorySecretKey: "secret",
+				HistoryToken:     "token",
+				HistoryMaxRuns:   2,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			for i, number := range []string{"1", "2", "3"} {
+				record := HistoryRecord{Recorded: start.Add(time.Duration(i) * time.Hour), Build: &BuildInfo{Number: number}}
+				if err := history.add(ctx, record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			records, err := history.records(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := history.prune(ctx, records, start.Add(3*time.Hour)); err != nil {
+				t.Fatal(err)
+			}
+			records, err = history.records(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, record := range records {
+				got = append(got, record.number())
+			}
+			if diff := cmp.Diff([]string{"2", "3"}, got); diff != "" {
+				t.Errorf("Unexpected recorded runs (-want +got):\n%s", diff)
+			}
+
+			// records removed by a concurrent run are ignored
+			if err := history.backend.Delete(ctx, "missing.json"); err != nil {
+				t.Errorf("Unexpected error deleting a missing record: %v", err)
+			}
+		})
+	}
+}
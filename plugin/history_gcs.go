@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// defaultGCSEndpoint is the Google Cloud Storage JSON API endpoint.
+const defaultGCSEndpoint = "https://storage.googleapis.com"
+
+// gcsScope is the OAuth 2.0 scope of the history objects.
+const gcsScope = "https://www.googleapis.com/auth/devstorage.read_write"
+
+// gcsHistory stores the records as objects in a Google Cloud Storage
+// bucket. The client authenticates the requests with refreshed OAuth 2.0
+// access tokens.
+type gcsHistory struct {
+	bucket   string
+	prefix   string
+	endpoint string
+	client   *http.Client
+}
+
+// newGCSHistory returns the GCS backend. Credentials are resolved as
+// Application Default Credentials: GOOGLE_APPLICATION_CREDENTIALS, the
+// gcloud credentials file, then the metadata server of Google Cloud
+// runners, e.g. with workload identity. PLUGIN_HISTORY_TOKEN overrides
+// them with a static access token.
+func newGCSHistory(args Args, bucket, prefix string) (*gcsHistory, error) {
+	var tokens oauth2.TokenSource
+	if args.HistoryToken != "" {
+		tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: args.HistoryToken})
+	} else {
+		var err error
+		if tokens, err = google.DefaultTokenSource(context.Background(), gcsScope); err != nil {
+			return nil, fmt.Errorf("missing GCS credentials: %v", err)
+		}
+	}
+	return &gcsHistory{
+		bucket:   bucket,
+		prefix:   prefix,
+		endpoint: strings.TrimRight(firstNonEmpty(args.HistoryEndpoint, defaultGCSEndpoint), "/"),
+		client:   oauth2.NewClient(context.Background(), tokens),
+	}, nil
+}
+
+func (g *gcsHistory) String() string {
+	return "gs://" + g.bucket + "/" + g.prefix
+}
+
+// objectURL returns the metadata URL of the object.
+func (g *gcsHistory) objectURL(name string) string {
+	return g.endpoint + "/storage/v1/b/" + url.PathEscape(g.bucket) + "/o/" + url.PathEscape(g.prefix+name)
+}
+
+// gcsListResult is the response of an objects list request.
+type gcsListResult struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// List returns the names of the objects directly under the prefix.
+func (g *gcsHistory) List(ctx context.Context) ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		query := url.Values{"prefix": {g.prefix}, "delimiter": {"/"}}
+		if token != "" {
+			query.Set("pageToken", token)
+		}
+		req, err := http.NewRequest(http.MethodGet, g.endpoint+"/storage/v1/b/"+url.PathEscape(g.bucket)+"/o?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		body, err := historyRequest(ctx, g.client, req)
+		if err != nil {
+			return nil, err
+		}
+		var result gcsListResult
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("invalid GCS list response: %v", err)
+		}
+		for _, item := range result.Items {
+			if name := strings.TrimPrefix(item.Name, g.prefix); name != "" {
+				names = append(names, name)
+			}
+		}
+		if result.NextPageToken == "" {
+			return names, nil
+		}
+		token = result.NextPageToken
+	}
+}
+
+func (g *gcsHistory) Read(ctx context.Context, name string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, g.objectURL(name)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	return historyRequest(ctx, g.client, req)
+}
+
+func (g *gcsHistory) Write(ctx context.Context, name string, data []byte) error {
+	query := url.Values{"uploadType": {"media"}, "name": {g.prefix + name}}
+	req, err := http.NewRequest(http.MethodPost, g.endpoint+"/upload/storage/v1/b/"+url.PathEscape(g.bucket)+"/o?"+query.Encode(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = historyRequest(ctx, g.client, req)
+	return err
+}
+
+// Delete removes the object. Records already removed, e.g. by a
+// concurrent run, are ignored.
+func (g *gcsHistory) Delete(ctx context.Context, name string) error {
+	req, err := http.NewRequest(http.MethodDelete, g.objectURL(name), nil)
+	if err != nil {
+		return err
+	}
+	_, err = historyRequest(ctx, g.client, req)
+	var status *historyStatusError
+	if errors.As(err, &status) && status.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
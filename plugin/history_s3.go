@@ -0,0 +1,138 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// defaultS3Region is the region when neither the arguments nor the AWS
+// configuration set one.
+const defaultS3Region = "us-east-1"
+
+// s3History stores the records as objects in an Amazon S3 or S3
+// compatible bucket.
+type s3History struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// newS3History returns the S3 backend. Credentials and region are
+// resolved by the default chain of the AWS SDK: the environment, the
+// shared configuration files, web identity tokens and the container or
+// instance role. PLUGIN_HISTORY_ACCESS_KEY and PLUGIN_HISTORY_SECRET_KEY
+// override the chain with static credentials.
+func newS3History(args Args, bucket, prefix string) (*s3History, error) {
+	var opts []func(*config.LoadOptions) error
+	if args.HistoryRegion != "" {
+		opts = append(opts, config.WithRegion(args.HistoryRegion))
+	}
+	if args.HistoryAccessKey != "" || args.HistorySecretKey != "" {
+		if args.HistoryAccessKey == "" || args.HistorySecretKey == "" {
+			return nil, fmt.Errorf("incomplete S3 credentials, set both PLUGIN_HISTORY_ACCESS_KEY and PLUGIN_HISTORY_SECRET_KEY")
+		}
+		provider := credentials.NewStaticCredentialsProvider(args.HistoryAccessKey, args.HistorySecretKey, "")
+		opts = append(opts, config.WithCredentialsProvider(provider))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), historyAPITimeout)
+	defer cancel()
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the AWS configuration: %v", err)
+	}
+	if cfg.Region == "" {
+		cfg.Region = defaultS3Region
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if args.HistoryEndpoint == "" {
+			return
+		}
+		// S3 compatible services are addressed path-style and often
+		// reject the optional checksums of recent SDK versions
+		o.BaseEndpoint = aws.String(strings.TrimRight(args.HistoryEndpoint, "/"))
+		o.UsePathStyle = true
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	})
+	return &s3History{client: client, bucket: bucket, prefix: prefix}, nil
+}
+
+func (s *s3History) String() string {
+	return "s3://" + s.bucket + "/" + s.prefix
+}
+
+// List returns the names of the objects directly under the prefix.
+func (s *s3History) List(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, historyAPITimeout)
+	defer cancel()
+	var names []string
+	pages := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(aws.ToString(object.Key), s.prefix)
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+func (s *s3History) Read(ctx context.Context, name string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, historyAPITimeout)
+	defer cancel()
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Body.Close()
+	return io.ReadAll(object.Body)
+}
+
+func (s *s3History) Write(ctx context.Context, name string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, historyAPITimeout)
+	defer cancel()
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.prefix + name),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+// Delete removes the object. Records already removed, e.g. by a
+// concurrent run, are ignored.
+func (s *s3History) Delete(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, historyAPITimeout)
+	defer cancel()
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	})
+	var missing *types.NoSuchKey
+	if errors.As(err, &missing) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,192 @@
+package plugin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHistoryBaseline validates the baseline selection from the history
+func TestHistoryBaseline(t *testing.T) {
+	history := &historyStore{backend: localHistory{dir: t.TempDir()}}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	runs := []struct {
+		number, branch, outcome string
+	}{
+		{"1", "main", gatePass},
+		{"2", "feature", gatePass},
+		{"3", "main", gateWarn},
+		{"4", "main", gateFail},
+	}
+	for i, run := range runs {
+		record := HistoryRecord{
+			Recorded: start.Add(time.Duration(i) * time.Hour),
+			Build:    &BuildInfo{Number: run.number, Branch: run.branch},
+			Outcome:  run.outcome,
+			Stats:    StatsResult{TotalTests: i + 1},
+		}
+		if err := history.add(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		branch, pinned string
+		want           int // total tests of the baseline, 0 for none
+	}{
+		{"main", "", 3},
+		{"feature", "", 2},
+		{"release", "", 0},
+		{"main", "1", 1},
+		{"main", "4", 4},
+	}
+	records, err := history.records(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		record, ok, err := historyBaseline(records, &BuildInfo{Branch: test.branch}, test.pinned)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got := 0
+		if ok {
+			got = record.Stats.TotalTests
+		}
+		if got != test.want {
+			t.Errorf("Baseline of branch %q pinned to %q has %d tests, want %d", test.branch, test.pinned, got, test.want)
+		}
+	}
+	if _, _, err := historyBaseline(records, nil, "99"); err == nil {
+		t.Error("Expected an error for a pinned build missing from the history")
+	}
+}
+
+// TestHistoryReporter validates that runs are recorded with their outcome
+func TestHistoryReporter(t *testing.T) {
+	args := Args{HistoryDir: t.TempDir()}
+	reporter, err := newHistoryReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := StatsResult{
+		TotalTests:  3,
+		PassedTests: 1,
+		FailedTests: 1,
+		Build:       &BuildInfo{Number: "7", Branch: "main"},
+		Gates:       []GateResult{{Name: "failed_tests", Outcome: gateWarn}},
+		Suites:      []SuiteStats{{Name: "Root", ExecutionTime: 30}, {Name: "Root.A", ExecutionTime: 20, Metadata: map[string]string{"Owner": "qa"}}},
+		Tags:        map[string]TagStats{"smoke": {Total: 3}},
+		Tests: []TestResult{
+			{Name: "Passes", Suite: "Root.A", Status: "PASS", Duration: 5},
+			{Name: "Fails", Suite: "Root.A", Status: "FAIL", Duration: 5, ErrorMessage: "boom", File: "output.xml"},
+			{Name: "Allowed", Suite: "Root.A", Status: "FAIL", Duration: 5, AllowedFailure: true},
+		},
+	}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+	history, err := newHistoryStore(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := history.records(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	want := StatsResult{
+		TotalTests:  3,
+		PassedTests: 1,
+		FailedTests: 1,
+		Suites:      []SuiteStats{{Name: "Root.A", ExecutionTime: 20}},
+		Tests:       []TestResult{{Name: "Fails", Suite: "Root.A", Status: "FAIL", ErrorMessage: "boom", File: "output.xml"}},
+	}
+	if !records[0].Compact {
+		t.Error("Expected a compact record")
+	}
+	if diff := cmp.Diff(want, records[0].Stats); diff != "" {
+		t.Errorf("Unexpected recorded stats (-want +got):\n%s", diff)
+	}
+	if records[0].Outcome != gateWarn {
+		t.Errorf("Expected outcome %s, got %s", gateWarn, records[0].Outcome)
+	}
+}
+
+// TestHistoryPrune validates the history retention limits
+func TestHistoryPrune(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		maxRuns int
+		maxAge  time.Duration
+		want    []string // remaining build numbers
+	}{
+		{"unlimited", 0, 0, []string{"1", "2", "3", "4"}},
+		{"max runs", 2, 0, []string{"3", "4"}},
+		{"max age", 0, 60 * time.Hour, []string{"2", "3", "4"}},
+		{"both", 1, 60 * time.Hour, []string{"4"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			history := &historyStore{backend: localHistory{dir: t.TempDir()}, maxRuns: test.maxRuns, maxAge: test.maxAge}
+			for i, number := range []string{"1", "2", "3", "4"} {
+				record := HistoryRecord{
+					Recorded: now.Add(time.Duration(i-3) * 24 * time.Hour),
+					Build:    &BuildInfo{Number: number},
+				}
+				if err := history.add(context.Background(), record); err != nil {
+					t.Fatal(err)
+				}
+			}
+			records, err := history.records(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := history.prune(context.Background(), records, now); err != nil {
+				t.Fatal(err)
+			}
+			records, err = history.records(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, record := range records {
+				got = append(got, record.number())
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("Unexpected remaining runs (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestHistoryBaselineStats validates the comparison against a compact
+// baseline record
+func TestHistoryBaselineStats(t *testing.T) {
+	record := HistoryRecord{
+		Compact: true,
+		Stats: StatsResult{Tests: []TestResult{
+			{Name: "Fixed", Suite: "Root", Status: "FAIL"},
+			{Name: "Removed", Suite: "Root", Status: "FAIL"},
+		}},
+	}
+	current := StatsResult{Tests: []TestResult{
+		{Name: "Fixed", Suite: "Root", Status: "PASS", Duration: 10},
+		{Name: "Broken", Suite: "Root", Status: "FAIL", Duration: 20},
+		{Name: "Stable", Suite: "Root", Status: "PASS", Duration: 30},
+	}}
+	diff := compareRuns(baselineStats(record, current), current)
+	want := RunDiff{
+		NewlyFailing: []TestChange{{Name: "Broken", Suite: "Root", Before: "PASS", After: "FAIL"}},
+		NewlyPassing: []TestChange{{Name: "Fixed", Suite: "Root", Before: "FAIL", After: "PASS"}},
+		Removed:      []TestResult{{Name: "Removed", Suite: "Root", Status: "FAIL"}},
+	}
+	if d := cmp.Diff(want, diff); d != "" {
+		t.Errorf("Unexpected comparison (-want +got):\n%s", d)
+	}
+}
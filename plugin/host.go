@@ -0,0 +1,70 @@
+package plugin
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// HostInfo describes the runner the statistics were computed on, so
+// failures can be correlated with runner pools.
+type HostInfo struct {
+	Hostname string            `json:"hostname,omitempty"`
+	OS       string            `json:"os"`
+	Arch     string            `json:"arch"`
+	CPUs     int               `json:"cpus"`
+	Env      map[string]string `json:"env,omitempty"` // PLUGIN_HOST_ENV, secrets masked
+}
+
+// hostInfoEnabled reports whether the runner details are captured.
+func hostInfoEnabled(args Args) bool {
+	return args.HostInfo || len(args.HostEnv) > 0
+}
+
+// newHostInfo returns the details of the current host with the
+// environment variables matching the patterns. Values of variables whose
+// names look secret-bearing are masked.
+func newHostInfo(patterns []string, environ []string) *HostInfo {
+	host := &HostInfo{OS: runtime.GOOS, Arch: runtime.GOARCH, CPUs: runtime.NumCPU()}
+	host.Hostname, _ = os.Hostname()
+
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !matchesEnvPattern(patterns, name) {
+			continue
+		}
+		if isSecretEnvName(name) {
+			value = secretMask
+		}
+		if host.Env == nil {
+			host.Env = map[string]string{}
+		}
+		host.Env[name] = value
+	}
+	return host
+}
+
+// matchesEnvPattern reports whether the variable name matches one of the
+// shell patterns.
+func matchesEnvPattern(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidEnvPatterns returns the invalid shell patterns, sorted.
+func invalidEnvPatterns(patterns []string) []string {
+	var invalid []string
+	for _, pattern := range patterns {
+		if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+			invalid = append(invalid, pattern)
+		}
+	}
+	sort.Strings(invalid)
+	return invalid
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHostInfo validates the runner details and the selected environment
+// variables, with secret values masked
+func TestHostInfo(t *testing.T) {
+	environ := []string{
+		"RUNNER_POOL=linux-large",
+		"RUNNER_TOKEN=abc123456",
+		"NODE_NAME=node-7",
+		"HOME=/root",
+	}
+	host := newHostInfo([]string{"RUNNER_*", " NODE_NAME"}, environ)
+	if host.OS != runtime.GOOS || host.Arch != runtime.GOARCH || host.CPUs < 1 {
+		t.Errorf("Unexpected host details %+v", host)
+	}
+	want := map[string]string{"RUNNER_POOL": "linux-large", "RUNNER_TOKEN": secretMask, "NODE_NAME": "node-7"}
+	if diff := cmp.Diff(want, host.Env); diff != "" {
+		t.Errorf("Unexpected host environment (-want +got):\n%s", diff)
+	}
+
+	if env := newHostInfo(nil, environ).Env; env != nil {
+		t.Errorf("Expected no environment without patterns, got %v", env)
+	}
+	if invalid := invalidEnvPatterns([]string{"RUNNER_*", "[A-"}); !cmp.Equal(invalid, []string{"[A-"}) {
+		t.Errorf("Expected the malformed pattern to be rejected, got %v", invalid)
+	}
+
+	var b strings.Builder
+	if err := htmlReportTemplate.Execute(&b, newReportData(StatsResult{Host: host}, "")); err != nil {
+		t.Fatal(err)
+	}
+	if html := b.String(); !strings.Contains(html, "linux-large") || strings.Contains(html, "abc123456") {
+		t.Errorf("Expected the host environment in the HTML report, got:\n%s", html)
+	}
+}
@@ -0,0 +1,37 @@
+package plugin
+
+import "strings"
+
+// iteration reports whether the keyword is an iteration of a FOR or WHILE
+// loop.
+func (kw Keyword) iteration() bool {
+	typ := strings.ToUpper(kw.Type)
+	return typ == "ITERATION" || typ == "FORITEM"
+}
+
+// iterationName returns the loop variables of an iteration, e.g.
+// "${user} = admin". RF 3 and earlier report them as the keyword name.
+func iterationName(kw Keyword) string {
+	if kw.Name != "" {
+		return kw.Name
+	}
+	vars := make([]string, 0, len(kw.Vars))
+	for _, v := range kw.Vars {
+		vars = append(vars, v.Name+" = "+v.Value)
+	}
+	return strings.Join(vars, ", ")
+}
+
+// failedIterations returns the names of the innermost failed iterations
+// in the keywords, in execution order.
+func failedIterations(keywords []Keyword) []string {
+	var names []string
+	for _, kw := range keywords {
+		inner := failedIterations(kw.Keywords)
+		if len(inner) == 0 && kw.iteration() && kw.Status.Status == "FAIL" {
+			inner = []string{iterationName(kw)}
+		}
+		names = append(names, inner...)
+	}
+	return names
+}
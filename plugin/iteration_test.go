@@ -0,0 +1,119 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// dataDrivenOutput is a report of a data-driven test with a failed
+// iteration in a nested loop.
+const dataDrivenOutput = `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Login With Users">
+<for flavor="IN">
+<var>${user}</var>
+<value>admin</value>
+<value>guest</value>
+<iter>
+<var name="${user}">admin</var>
+<kw name="Login" owner="LoginLibrary"><arg>${user}</arg><status status="PASS"/></kw>
+<status status="PASS"/>
+</iter>
+<iter>
+<var name="${user}">guest</var>
+<for flavor="IN RANGE">
+<var>${i}</var>
+<iter>
+<var name="${i}">0</var>
+<if>
+<branch type="IF" condition="$i == 0">
+<kw name="Login" owner="LoginLibrary"><arg>${user}</arg><status status="FAIL">Access denied</status></kw>
+<status status="FAIL"/>
+</branch>
+<status status="FAIL"/>
+</if>
+<status status="FAIL"/>
+</iter>
+<status status="FAIL"/>
+</for>
+<status status="FAIL"/>
+</iter>
+<status status="FAIL"/>
+</for>
+<doc>Ignored</doc>
+<kw name="Log"><status status="NOT RUN"/></kw>
+<status status="FAIL">Access denied</status>
+</test>
+</suite>
+</robot>`
+
+// TestIterations validates the counting of loop iterations and of the
+// keywords inside control structures
+func TestIterations(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(dataDrivenOutput), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	if stats.TotalKeywords != 3 || stats.PassedKeywords != 1 || stats.FailedKeywords != 1 || stats.NotRunKeywords != 1 {
+		t.Errorf("Expected 3 keywords, 1 passed, 1 failed and 1 not run, got %d, %d, %d and %d",
+			stats.TotalKeywords, stats.PassedKeywords, stats.FailedKeywords, stats.NotRunKeywords)
+	}
+	if stats.TotalIterations != 3 || stats.FailedIterations != 2 {
+		t.Errorf("Expected 3 iterations and 2 failed, got %d and %d", stats.TotalIterations, stats.FailedIterations)
+	}
+	if len(stats.FailedTestsDetails) != 1 {
+		t.Fatalf("Expected 1 failed test, got %+v", stats.FailedTestsDetails)
+	}
+	if diff := cmp.Diff([]string{"${i} = 0"}, stats.FailedTestsDetails[0].FailedIterations); diff != "" {
+		t.Errorf("Failed iterations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestIterationName validates the names of iterations of both output
+// formats
+func TestIterationName(t *testing.T) {
+	tests := []struct {
+		kw   Keyword
+		want string
+	}{
+		{Keyword{Type: "ITERATION", Vars: []Var{{Name: "${a}", Value: "1"}, {Name: "${b}", Value: "x"}}}, "${a} = 1, ${b} = x"},
+		{Keyword{Name: "${a} = 1", Type: "foritem"}, "${a} = 1"},
+	}
+	for _, test := range tests {
+		if got := iterationName(test.kw); got != test.want {
+			t.Errorf("iterationName(%+v) = %q, want %q", test.kw, got, test.want)
+		}
+	}
+}
+
+// TestLegacyForLoop validates that the FOR loops of RF 3 are not counted
+// as keywords
+func TestLegacyForLoop(t *testing.T) {
+	robotOutput := RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{{
+		Name: "Loop",
+		Keywords: []Keyword{{Name: "${x} IN [ @{items} ]", Type: "for", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+			{Name: "${x} = 1", Type: "foritem", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+				{Name: "Should Be Empty", Status: Status{Status: "FAIL"}},
+			}},
+		}}},
+		Status: Status{Status: "FAIL"},
+	}}}}
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(robotOutput, opts)
+	if stats.TotalKeywords != 1 || stats.TotalIterations != 1 || stats.FailedIterations != 1 {
+		t.Errorf("Expected 1 keyword and 1 failed iteration, got %d keywords and %d of %d iterations failed",
+			stats.TotalKeywords, stats.FailedIterations, stats.TotalIterations)
+	}
+	if diff := cmp.Diff([]string{"${x} = 1"}, stats.FailedTestsDetails[0].FailedIterations); diff != "" {
+		t.Errorf("Failed iterations mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultLeaderboardRuns is the number of runs of the failure
+// leaderboard, including the current run.
+const defaultLeaderboardRuns = 20
+
+// leaderboardRuns returns the number of runs of the failure leaderboard.
+func leaderboardRuns(args Args) int {
+	if args.FailureLeaderboardRuns > 0 {
+		return args.FailureLeaderboardRuns
+	}
+	return defaultLeaderboardRuns
+}
+
+// failureLeaderboard returns the tests that failed in the most runs, at
+// most size tests. Tests failing equally often keep their order.
+func failureLeaderboard(tests []TestHistory, size int) []TestHistory {
+	var leaderboard []TestHistory
+	for _, test := range tests {
+		if test.Failures > 0 {
+			leaderboard = append(leaderboard, test)
+		}
+	}
+	sort.SliceStable(leaderboard, func(i, j int) bool { return leaderboard[i].Failures > leaderboard[j].Failures })
+	if len(leaderboard) > size {
+		leaderboard = leaderboard[:size]
+	}
+	return leaderboard
+}
+
+// runLeaderboard returns the failure leaderboard of the last recorded
+// runs followed by the current run, at most runs runs.
+func runLeaderboard(records []HistoryRecord, stats StatsResult, runs, size int) []TestHistory {
+	current := HistoryRecord{Recorded: time.Now().UTC(), Build: stats.Build, Stats: stats}
+	records = append(records[:len(records):len(records)], current)
+	if len(records) > runs {
+		records = records[len(records)-runs:]
+	}
+	return failureLeaderboard(summarizeTests(records), size)
+}
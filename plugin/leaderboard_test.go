@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestRunLeaderboard validates the leaderboard of the last runs including
+// the current run
+func TestRunLeaderboard(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	records := []HistoryRecord{
+		digestRecord(start, "1", map[string]string{"A": "PASS", "B": "PASS", "C": "FAIL"}),
+		digestRecord(start.Add(time.Hour), "2", map[string]string{"A": "FAIL", "B": "PASS", "C": "PASS"}),
+		digestRecord(start.Add(2*time.Hour), "3", map[string]string{"A": "FAIL", "B": "FAIL", "C": "PASS"}),
+	}
+	current := digestRecord(time.Time{}, "4", map[string]string{"A": "FAIL", "B": "PASS"}).Stats
+	current.Build = &BuildInfo{Number: "4"}
+
+	expected := []TestHistory{
+		{Name: "A", Suite: "Root", Runs: 3, Failures: 3, LastMessage: "A failed in 4", LastBuild: "4"},
+		{Name: "B", Suite: "Root", Runs: 3, Failures: 1, Flips: 2, LastMessage: "B failed in 3", LastBuild: "3"},
+	}
+	if diff := cmp.Diff(expected, runLeaderboard(records, current, 3, 5)); diff != "" {
+		t.Errorf("Leaderboard mismatch (-want +got):\n%s", diff)
+	}
+	if got := runLeaderboard(records, current, 10, 1); len(got) != 1 || got[0].Name != "A" {
+		t.Errorf("Expected the leaderboard to be limited to A, got %+v", got)
+	}
+	if got := runLeaderboard(nil, StatsResult{}, 10, 5); got != nil {
+		t.Errorf("Expected no leaderboard without failures, got %+v", got)
+	}
+}
+
+// TestFailureLeaderboardReports validates the leaderboard of the history
+// in the JSON statistics and the Markdown summary
+func TestFailureLeaderboardReports(t *testing.T) {
+	dir := t.TempDir()
+	args := Args{HistoryDir: filepath.Join(dir, "history"), FailureLeaderboard: 5, MarkdownSummary: filepath.Join(dir, "summary.md")}
+	if err := ValidateInputs(Args{ReportDirectory: ".", FailureLeaderboard: 5}); err == nil {
+		t.Errorf("Expected an error without history")
+	}
+
+	history, err := newHistoryStore(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := digestRecord(time.Now().Add(-time.Hour).UTC(), "1", map[string]string{"A": "FAIL"})
+	if err := history.add(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := digestRecord(time.Time{}, "2", map[string]string{"A": "FAIL"}).Stats
+	if err := attachHistory(context.Background(), &stats, args); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.FailureLeaderboard) != 1 || stats.FailureLeaderboard[0].Failures != 2 {
+		t.Fatalf("Expected A to fail in 2 runs, got %+v", stats.FailureLeaderboard)
+	}
+
+	reporter, err := newMarkdownSummaryReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(args.MarkdownSummary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "| Root | A | 2 / 2 |  | A failed in 2 |"; !strings.Contains(string(data), want) {
+		t.Errorf("Expected the summary to contain %q, got:\n%s", want, data)
+	}
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// library returns the library of the keyword, empty for user keywords
+// and control structures. RF 7 reports it as the keyword owner.
+func (kw Keyword) library() string {
+	if kw.Library != "" {
+		return kw.Library
+	}
+	return kw.Owner
+}
+
+// countLibraryFailure counts the failure of a library keyword.
+func countLibraryFailure(kw *Keyword, stats *StatsResult) {
+	library := kw.library()
+	if library == "" || kw.Status.Status != "FAIL" {
+		return
+	}
+	if stats.LibraryFailures == nil {
+		stats.LibraryFailures = map[string]int{}
+	}
+	stats.LibraryFailures[library]++
+}
+
+// logLibraryFailures logs the failed keyword counts by library, most
+// failures first.
+func logLibraryFailures(stats StatsResult) {
+	if len(stats.LibraryFailures) == 0 {
+		return
+	}
+	libraries := make([]string, 0, len(stats.LibraryFailures))
+	for library := range stats.LibraryFailures {
+		libraries = append(libraries, library)
+	}
+	sort.Slice(libraries, func(i, j int) bool {
+		if stats.LibraryFailures[libraries[i]] != stats.LibraryFailures[libraries[j]] {
+			return stats.LibraryFailures[libraries[i]] > stats.LibraryFailures[libraries[j]]
+		}
+		return libraries[i] < libraries[j]
+	})
+
+	logrus.Infof("Failed Keywords by Library:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, library := range libraries {
+		logrus.Infof("%s: %d\n", library, stats.LibraryFailures[library])
+	}
+	logrus.Infof("-----------------------------------------------\n")
+}
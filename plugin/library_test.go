@@ -0,0 +1,43 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLibraryFailures validates the failed keyword counts by library,
+// ignoring user keywords and reading the RF 7 owner attribute
+func TestLibraryFailures(t *testing.T) {
+	robotOutput := RobotOutput{
+		Suite: Suite{
+			Name: "Suite",
+			Tests: []Test{
+				{Name: "Login", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+					{Name: "Login User", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+						{Name: "Input Text", Library: "SeleniumLibrary", Status: Status{Status: "PASS"}},
+						{Name: "Click Button", Library: "SeleniumLibrary", Status: Status{Status: "FAIL"}},
+					}},
+				}},
+				{Name: "Api", Status: Status{Status: "FAIL"}, Keywords: []Keyword{
+					{Name: "GET", Owner: "RequestsLibrary", Status: Status{Status: "FAIL"}},
+					{Name: "Wait Until Element Is Visible", Owner: "SeleniumLibrary", Status: Status{Status: "FAIL"}},
+				}},
+			},
+		},
+	}
+
+	stats := computeStats(robotOutput, statsOptions{})
+	want := map[string]int{"SeleniumLibrary": 2, "RequestsLibrary": 1}
+	if diff := cmp.Diff(want, stats.LibraryFailures); diff != "" {
+		t.Errorf("Unexpected library failures (-want +got):\n%s", diff)
+	}
+
+	total := StatsResult{}
+	aggregateStats(&total, stats)
+	aggregateStats(&total, StatsResult{LibraryFailures: map[string]int{"RequestsLibrary": 2, "CustomLib": 1}})
+	want = map[string]int{"SeleniumLibrary": 2, "RequestsLibrary": 3, "CustomLib": 1}
+	if diff := cmp.Diff(want, total.LibraryFailures); diff != "" {
+		t.Errorf("Unexpected aggregated library failures (-want +got):\n%s", diff)
+	}
+}
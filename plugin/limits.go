@@ -0,0 +1,96 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// defaultMaxXMLDepth is the default element nesting limit. Robot Framework
+// reports rarely nest keywords more than a few dozen levels deep.
+const defaultMaxXMLDepth = 1000
+
+// xmlLimits guards parsing against hostile or pathological reports.
+type xmlLimits struct {
+	// MaxSize is the report size limit in bytes. Zero disables the limit.
+	MaxSize int64
+
+	// MaxDepth is the element nesting limit. Zero disables the limit.
+	MaxDepth int
+}
+
+// newXMLLimits returns the parsing limits for the plugin arguments.
+func newXMLLimits(args Args) xmlLimits {
+	limits := xmlLimits{
+		MaxSize:  int64(args.MaxReportSize) << 20,
+		MaxDepth: args.MaxXMLDepth,
+	}
+	if limits.MaxDepth == 0 {
+		limits.MaxDepth = defaultMaxXMLDepth
+	}
+	return limits
+}
+
+// checkSize rejects reports larger than the size limit.
+func (l xmlLimits) checkSize(size int64, filename string) error {
+	if l.MaxSize > 0 && size > l.MaxSize {
+		return fmt.Errorf("%s is %d bytes, exceeding the report size limit of %d bytes", filename, size, l.MaxSize)
+	}
+	return nil
+}
+
+// checkDepth rejects elements nested deeper than the depth limit.
+func (l xmlLimits) checkDepth(depth int, filename string) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return fmt.Errorf("%s nests elements deeper than the limit of %d levels", filename, l.MaxDepth)
+	}
+	return nil
+}
+
+// checkDirective rejects document type definitions declaring entities or
+// referencing external resources. Entities are never expanded, but such
+// reports are not produced by Robot Framework and are refused outright.
+func checkDirective(directive xml.Directive, filename string) error {
+	text := strings.ToUpper(string(directive))
+	if !strings.HasPrefix(strings.TrimSpace(text), "DOCTYPE") {
+		return nil
+	}
+	for _, keyword := range []string{"<!ENTITY", "SYSTEM", "PUBLIC"} {
+		if strings.Contains(text, keyword) {
+			return fmt.Errorf("%s declares entities or external resources in its document type, which is not allowed", filename)
+		}
+	}
+	return nil
+}
+
+// checkReport scans the report content for limit violations before it is
+// unmarshaled. Syntax errors are left to the parser.
+func (l xmlLimits) checkReport(data []byte, filename string) error {
+	if err := l.checkSize(int64(len(data)), filename); err != nil {
+		return err
+	}
+
+	decoder := newReportDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		tok, err := decoder.RawToken()
+		if err != nil {
+			return nil // end of input or a syntax error
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if err := l.checkDepth(depth, filename); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			depth--
+		case xml.Directive:
+			if err := checkDirective(t, filename); err != nil {
+				return err
+			}
+		}
+	}
+}
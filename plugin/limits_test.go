@@ -0,0 +1,87 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestParsingLimits validates the rejection of reports exceeding the
+// parsing limits
+func TestParsingLimits(t *testing.T) {
+	deep := `<robot generator="Robot 7.0"><suite id="s1" name="Root">` +
+		strings.Repeat("<kw>", 20) + strings.Repeat("</kw>", 20) +
+		`<test id="s1-t1" name="Test"><status status="PASS"/></test></suite></robot>`
+
+	tests := []struct {
+		name    string
+		content string
+		limits  xmlLimits
+		wantErr string
+	}{
+		{
+			name:    "Within Limits",
+			content: deep,
+			limits:  xmlLimits{MaxSize: 1 << 20, MaxDepth: 50},
+		},
+		{
+			name:    "Too Large",
+			content: deep,
+			limits:  xmlLimits{MaxSize: 100},
+			wantErr: "exceeding the report size limit",
+		},
+		{
+			name:    "Too Deep",
+			content: deep,
+			limits:  xmlLimits{MaxDepth: 10},
+			wantErr: "deeper than the limit of 10 levels",
+		},
+		{
+			name:    "Entity Declaration",
+			content: `<!DOCTYPE robot [<!ENTITY lol "lol">]><robot><suite id="s1" name="Root"></suite></robot>`,
+			wantErr: "declares entities",
+		},
+		{
+			name:    "External Entity",
+			content: `<!DOCTYPE robot SYSTEM "file:///etc/passwd"><robot><suite id="s1" name="Root"></suite></robot>`,
+			wantErr: "declares entities",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOutput([]byte(tt.content), "output.xml", statsOptions{Limits: tt.limits})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestParsingLimitsFastSummary validates that the fast summary path
+// enforces the parsing limits
+func TestParsingLimitsFastSummary(t *testing.T) {
+	opts, err := newStatsOptions(Args{FastSummary: true, MaxReportSize: 1, MaxXMLDepth: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := processFile("../testdata/robot_report.xml", opts); err == nil || !strings.Contains(err.Error(), "deeper than the limit") {
+		t.Errorf("Expected a depth limit error, got %v", err)
+	}
+
+	// Reports exceeding the size limit are rejected before being read
+	path := filepath.Join(t.TempDir(), "output.xml")
+	if err := os.WriteFile(path, make([]byte, 2<<20), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+	if _, err := processFile(path, opts); err == nil || !strings.Contains(err.Error(), "size limit") {
+		t.Errorf("Expected a size limit error, got %v", err)
+	}
+}
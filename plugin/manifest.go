@@ -0,0 +1,144 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// stateManifest records the report files already processed, so only new
+// or changed files are aggregated by the next run.
+type stateManifest struct {
+	Files []manifestEntry `json:"files"`
+}
+
+// manifestEntry is a processed report file and its content hash.
+type manifestEntry struct {
+	Path        string    `json:"path"`
+	SHA256      string    `json:"sha256"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+// loadStateManifest reads the manifest file. A missing file is an empty
+// manifest.
+func loadStateManifest(path string) (*stateManifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &stateManifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m stateManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse state manifest %s: %v", path, err)
+	}
+	return &m, nil
+}
+
+// newFiles returns the files not recorded with their current content
+// hash, with the hashes of the returned files.
+func (m *stateManifest) newFiles(files []string) ([]string, map[string]string, error) {
+	recorded := map[string]string{}
+	for _, entry := range m.Files {
+		recorded[entry.Path] = entry.SHA256
+	}
+	var pending []string
+	hashes := map[string]string{}
+	for _, file := range files {
+		hash, err := fileSHA256(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		if recorded[manifestPath(file)] == hash {
+			continue
+		}
+		pending = append(pending, file)
+		hashes[file] = hash
+	}
+	return pending, hashes, nil
+}
+
+// record adds or updates the entries of the processed files.
+func (m *stateManifest) record(hashes map[string]string, now time.Time) {
+	index := map[string]int{}
+	for i, entry := range m.Files {
+		index[entry.Path] = i
+	}
+	for file, hash := range hashes {
+		entry := manifestEntry{Path: manifestPath(file), SHA256: hash, ProcessedAt: now}
+		if i, ok := index[entry.Path]; ok {
+			m.Files[i] = entry
+		} else {
+			m.Files = append(m.Files, entry)
+		}
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+}
+
+// save writes the manifest to a temporary file renamed into place, so an
+// interrupted run never leaves a partial manifest.
+func (m *stateManifest) save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// manifestPath returns the path recorded for the file, absolute so runs
+// from other working directories match.
+func manifestPath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		return abs
+	}
+	return file
+}
+
+// analyzeNewFiles computes the statistics of the files not recorded in
+// the state manifest, and records them once processed.
+func (a *Analyzer) analyzeNewFiles(ctx context.Context, files []string) (*StatsResult, error) {
+	path := a.args.StateManifest
+	manifest, err := loadStateManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	pending, hashes, err := manifest.newFiles(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash report files: %v", err)
+	}
+	logrus.Infof("%d of %d report files are new since the last run", len(pending), len(files))
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("no new report files since the last run recorded in %s", path)
+	}
+
+	stats, err := a.AnalyzeFiles(ctx, pending...)
+	if err != nil {
+		return nil, err
+	}
+	manifest.record(hashes, time.Now())
+	if err := manifest.save(path); err != nil {
+		return nil, fmt.Errorf("failed to save state manifest %s: %v", path, err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestStateManifest validates that only new or changed report files are aggregated
+func TestStateManifest(t *testing.T) {
+	dir := t.TempDir()
+	content, err := os.ReadFile("../testdata/robot_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a-output.xml", content)
+
+	manifest := filepath.Join(t.TempDir(), "manifest.json")
+	analyzer := newAnalyzer(Args{ReportFileNamePattern: "*output.xml", StateManifest: manifest})
+	first, err := analyzer.AnalyzeDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no new files
+	if _, err := analyzer.AnalyzeDir(context.Background(), dir); err == nil {
+		t.Errorf("Expected an error without new report files")
+	}
+
+	// a new file is aggregated alone, a changed file again
+	write("b-output.xml", content)
+	second, err := analyzer.AnalyzeDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.TotalTests != first.TotalTests || len(second.Files) != 1 {
+		t.Errorf("Expected only the new file, got %d tests in %d files", second.TotalTests, len(second.Files))
+	}
+	write("a-output.xml", append(content, '\n'))
+	third, err := analyzer.AnalyzeDir(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(third.Files) != 1 || filepath.Base(third.Files[0].File) != "a-output.xml" {
+		t.Errorf("Expected only the changed file, got %+v", third.Files)
+	}
+
+	m, err := loadStateManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Files) != 2 {
+		t.Errorf("Expected two recorded files, got %d", len(m.Files))
+	}
+}
@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// secretMask is the replacement text for masked secret values.
+const secretMask = "********"
+
+// minSecretLength is the shortest value that will be masked. Shorter
+// values (e.g. "1", "true") would redact unrelated log output.
+const minSecretLength = 4
+
+// secretEnvMarkers are name segments that identify secret-bearing
+// environment variables.
+var secretEnvMarkers = []string{"TOKEN", "PASSWORD", "SECRET", "WEBHOOK", "APIKEY"}
+
+// SecretMasker is a logrus hook that redacts registered secret values
+// from log messages and fields.
+type SecretMasker struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// NewSecretMasker returns a masker pre-registered with the secret-bearing
+// plugin arguments and environment variables.
+func NewSecretMasker(args Args) *SecretMasker {
+	m := &SecretMasker{}
+	m.RegisterArgs(args)
+	m.RegisterEnv(os.Environ())
+	return m
+}
+
+// Add registers secret values to be masked.
+func (m *SecretMasker) Add(values ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength || containsString(m.secrets, value) {
+			continue
+		}
+		m.secrets = append(m.secrets, value)
+	}
+	// mask longer values first so a secret containing another secret
+	// is fully redacted.
+	sort.Slice(m.secrets, func(i, j int) bool {
+		return len(m.secrets[i]) > len(m.secrets[j])
+	})
+}
+
+// RegisterArgs registers every string argument tagged with secret:"true".
+func (m *SecretMasker) RegisterArgs(args Args) {
+	v := reflect.ValueOf(args)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("secret") != "true" {
+			continue
+		}
+		if f := v.Field(i); f.Kind() == reflect.String {
+			m.Add(f.String())
+		}
+	}
+}
+
+// RegisterEnv registers the values of PLUGIN_ and secret environment
+// variables whose names look secret-bearing.
+func (m *SecretMasker) RegisterEnv(environ []string) {
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && isSecretEnvName(name) {
+			m.Add(value)
+		}
+	}
+}
+
+// Mask replaces all registered secrets in s.
+func (m *SecretMasker) Mask(s string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, secret := range m.secrets {
+		s = strings.ReplaceAll(s, secret, secretMask)
+	}
+	return s
+}
+
+// Levels implements logrus.Hook.
+func (m *SecretMasker) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire implements logrus.Hook and masks the entry message and fields.
+func (m *SecretMasker) Fire(entry *logrus.Entry) error {
+	entry.Message = m.Mask(entry.Message)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = m.Mask(v)
+		case error:
+			entry.Data[key] = m.Mask(v.Error())
+		case fmt.Stringer:
+			entry.Data[key] = m.Mask(v.String())
+		}
+	}
+	return nil
+}
+
+// isSecretEnvName reports whether an environment variable name refers
+// to a secret value.
+func isSecretEnvName(name string) bool {
+	name = strings.ToUpper(name)
+	if strings.HasSuffix(name, "_KEY") {
+		return true
+	}
+	for _, segment := range strings.Split(name, "_") {
+		if containsString(secretEnvMarkers, segment) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TestSecretMasker validates secret registration and masking of log entries
+func TestSecretMasker(t *testing.T) {
+	masker := &SecretMasker{}
+	masker.RegisterEnv([]string{
+		"PLUGIN_SLACK_WEBHOOK=https://hooks.example.com/T000/B000/XXXX",
+		"PLUGIN_API_TOKEN=s3cr3t-token",
+		"PLUGIN_ACCESS_KEY=AKIAEXAMPLE",
+		"PLUGIN_REPORT_DIRECTORY=./reports",
+		"PLUGIN_SKIP_KEYWORD_STATS=true",
+	})
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	logger.AddHook(masker)
+
+	logger.WithError(errors.New("auth failed for s3cr3t-token")).
+		Infof("posting to https://hooks.example.com/T000/B000/XXXX with key AKIAEXAMPLE from ./reports")
+
+	out := buf.String()
+	for _, secret := range []string{"s3cr3t-token", "hooks.example.com", "AKIAEXAMPLE"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Expected secret %q to be masked, got: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "./reports") {
+		t.Errorf("Expected non-secret value to be preserved, got: %s", out)
+	}
+}
@@ -0,0 +1,149 @@
+package plugin
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// processMergedFiles merges the report files in execution order, like
+// rebot --merge, and computes statistics for the merged results. The
+// files are ordered by the start time of their root suite. Tests
+// of later runs replace tests with the same long name in earlier runs,
+// and tests that failed and then passed on rerun are counted as flaky.
+func processMergedFiles(files []string, opts statsOptions) StatsResult {
+	var outputs []RobotOutput
+	progress := startProgress(len(files), opts.ProgressInterval)
+	defer progress.finish()
+	for _, file := range files {
+		logrus.Infof("Processing file: %s", file)
+		robotOutput, err := parseFile(file, opts)
+		if robotOutput != nil {
+			progress.fileDone(countSuiteTests(&robotOutput.Suite))
+		} else {
+			progress.fileDone(0)
+		}
+		if err != nil {
+			logrus.Warnf("Failed to process file %s: %v", file, err)
+			continue
+		}
+		if robotOutput != nil {
+			outputs = append(outputs, *robotOutput)
+		}
+	}
+
+	stats := StatsResult{}
+	for _, merged := range mergeOutputs(sortByStartTime(outputs)) {
+		fileStats := computeStats(merged.Output, opts)
+		fileStats.FlakyTests = len(merged.Flaky)
+		fileStats.FlakyTestsDetails = merged.Flaky
+		opts.FailureStream.drain(&fileStats)
+		aggregateStats(&stats, fileStats)
+	}
+	sortAggregated(&stats)
+	return stats
+}
+
+// mergedOutput is the result of merging runs of the same root suite.
+type mergedOutput struct {
+	Output RobotOutput
+	Flaky  []FlakyTestDetails
+}
+
+// mergeOutputs merges runs with the same root suite name in order.
+// Runs of different root suites are kept separate.
+func mergeOutputs(outputs []RobotOutput) []mergedOutput {
+	var merged []mergedOutput
+	index := map[string]int{}
+	for _, output := range outputs {
+		i, ok := index[output.Suite.Name]
+		if !ok {
+			index[output.Suite.Name] = len(merged)
+			merged = append(merged, mergedOutput{Output: output})
+			continue
+		}
+		mergeSuite(&merged[i].Output.Suite, output.Suite, output.Suite.Name, &merged[i].Flaky)
+	}
+	return merged
+}
+
+// mergeSuite merges the tests and child suites of src into dst.
+func mergeSuite(dst *Suite, src Suite, longName string, flaky *[]FlakyTestDetails) {
+	for _, test := range src.Tests {
+		i := findTest(dst.Tests, test.Name)
+		if i < 0 {
+			dst.Tests = append(dst.Tests, test)
+			continue
+		}
+		if dst.Tests[i].Status.Status == "FAIL" && test.Status.Status == "PASS" {
+			*flaky = append(*flaky, FlakyTestDetails{Name: test.Name, Suite: longName})
+		}
+		dst.Tests[i] = test
+	}
+
+	for _, child := range src.Suites {
+		i := findSuite(dst.Suites, child.Name)
+		if i < 0 {
+			dst.Suites = append(dst.Suites, child)
+			continue
+		}
+		mergeSuite(&dst.Suites[i], child, longName+"."+child.Name, flaky)
+	}
+}
+
+func findTest(tests []Test, name string) int {
+	for i := range tests {
+		if tests[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func findSuite(suites []Suite, name string) int {
+	for i := range suites {
+		if suites[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortByStartTime returns the outputs ordered by the start time of their
+// root suite, so original runs precede reruns whatever the file names
+// and modification times. When an output has no start time, the outputs
+// keep the order of the arguments, like rebot --merge.
+func sortByStartTime(outputs []RobotOutput) []RobotOutput {
+	starts := make([]time.Time, len(outputs))
+	for i, output := range outputs {
+		start, ok := statusStart(output.Suite.Status)
+		if !ok {
+			return outputs
+		}
+		starts[i] = start
+	}
+
+	order := make([]int, len(outputs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return starts[order[i]].Before(starts[order[j]]) })
+	sorted := make([]RobotOutput, len(outputs))
+	for i, k := range order {
+		sorted[i] = outputs[k]
+	}
+	return sorted
+}
+
+// Merge merges the report files of an original run and its reruns and
+// computes statistics for the merged results.
+func Merge(files []string, args Args) (StatsResult, error) {
+	args.MergeReruns = true
+	stats, err := newAnalyzer(args).AnalyzeFiles(context.Background(), files...)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	return *stats, nil
+}
@@ -0,0 +1,132 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func robotTest(name, status string) Test {
+	return Test{Name: name, Status: Status{Status: status}}
+}
+
+// TestMergeOutputs validates merging of original and rerun outputs
+func TestMergeOutputs(t *testing.T) {
+	original := RobotOutput{Suite: Suite{Name: "Root", Suites: []Suite{
+		{Name: "Login", Tests: []Test{
+			robotTest("Valid Login", "FAIL"),
+			robotTest("Invalid Login", "PASS"),
+			robotTest("Locked Account", "FAIL"),
+		}},
+	}}}
+	rerun := RobotOutput{Suite: Suite{Name: "Root", Suites: []Suite{
+		{Name: "Login", Tests: []Test{
+			robotTest("Valid Login", "PASS"),
+			robotTest("Locked Account", "FAIL"),
+		}},
+	}}}
+	other := RobotOutput{Suite: Suite{Name: "Other", Tests: []Test{
+		robotTest("Standalone", "PASS"),
+	}}}
+
+	merged := mergeOutputs([]RobotOutput{original, other, rerun})
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 merged outputs, got %d", len(merged))
+	}
+
+	expectedFlaky := []FlakyTestDetails{{Name: "Valid Login", Suite: "Root.Login"}}
+	if diff := cmp.Diff(expectedFlaky, merged[0].Flaky); diff != "" {
+		t.Errorf("Flaky tests mismatch (-want +got):\n%s", diff)
+	}
+
+	stats := computeStats(merged[0].Output, statsOptions{})
+	if stats.TotalTests != 3 || stats.PassedTests != 2 || stats.FailedTests != 1 {
+		t.Errorf("Expected 3 tests with 2 passed and 1 failed, got %d/%d/%d",
+			stats.TotalTests, stats.PassedTests, stats.FailedTests)
+	}
+	if len(merged[1].Flaky) != 0 {
+		t.Errorf("Expected no flaky tests in a single run, got %v", merged[1].Flaky)
+	}
+}
+
+// TestMergeOutputsAddsNewTests validates that tests only present in a rerun are kept
+func TestMergeOutputsAddsNewTests(t *testing.T) {
+	original := RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{robotTest("A", "PASS")}}}
+	rerun := RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{robotTest("B", "FAIL")},
+		Suites: []Suite{{Name: "Child", Tests: []Test{robotTest("C", "PASS")}}}}}
+
+	merged := mergeOutputs([]RobotOutput{original, rerun})
+	stats := computeStats(merged[0].Output, statsOptions{})
+	if stats.TotalTests != 3 {
+		t.Errorf("Expected 3 tests after merge, got %d", stats.TotalTests)
+	}
+}
+
+// TestMergeOrdersByStartTime validates that runs are merged in the order
+// of their start times, even when the modification times contradict it
+func TestMergeOrdersByStartTime(t *testing.T) {
+	const report = `<robot generator="Robot 7.0" schemaversion="5">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Login"><status status="%[1]s" start="%[2]s" elapsed="1.0"/></test>
+<status status="%[1]s" start="%[2]s" elapsed="1.0"/>
+</suite>
+</robot>`
+	dir := t.TempDir()
+	now := time.Now()
+	runs := []struct {
+		name, status, start string
+		modified            time.Time
+	}{
+		{"a-rerun.xml", "PASS", "2024-05-01T11:00:00.000000", now.Add(-time.Hour)},
+		{"b-original.xml", "FAIL", "2024-05-01T10:00:00.000000", now},
+	}
+	var files []string
+	for _, run := range runs {
+		file := filepath.Join(dir, run.name)
+		if err := os.WriteFile(file, []byte(fmt.Sprintf(report, run.status, run.start)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, run.modified, run.modified); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	stats, err := Merge(files, Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.PassedTests != 1 || stats.FailedTests != 0 {
+		t.Errorf("Expected the rerun to pass the test, got %d passed and %d failed", stats.PassedTests, stats.FailedTests)
+	}
+	expectedFlaky := []FlakyTestDetails{{Name: "Login", Suite: "Root"}}
+	if diff := cmp.Diff(expectedFlaky, stats.FlakyTestsDetails); diff != "" {
+		t.Errorf("Flaky tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestSortByStartTime validates the fallback to the argument order
+func TestSortByStartTime(t *testing.T) {
+	output := func(name, start string) RobotOutput {
+		return RobotOutput{Suite: Suite{Name: name, Status: Status{Start: start}}}
+	}
+	names := func(outputs []RobotOutput) []string {
+		var names []string
+		for _, output := range outputs {
+			names = append(names, output.Suite.Name)
+		}
+		return names
+	}
+	timed := []RobotOutput{output("late", "2024-05-01T11:00:00.000000"), output("early", "2024-05-01T10:00:00.000000")}
+	if diff := cmp.Diff([]string{"early", "late"}, names(sortByStartTime(timed))); diff != "" {
+		t.Errorf("Unexpected order (-want +got):\n%s", diff)
+	}
+	untimed := append(timed, output("unknown", ""))
+	if diff := cmp.Diff([]string{"late", "early", "unknown"}, names(sortByStartTime(untimed))); diff != "" {
+		t.Errorf("Unexpected order without start times (-want +got):\n%s", diff)
+	}
+}
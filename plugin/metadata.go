@@ -0,0 +1,52 @@
+package plugin
+
+import "sort"
+
+// metadataMap converts suite metadata entries to a map. It returns nil
+// when there are no entries.
+func metadataMap(entries []Meta) map[string]string {
+	if len(entries) == 0 {
+		return nil
+	}
+	metadata := map[string]string{}
+	for _, entry := range entries {
+		metadata[entry.Name] = entry.Value
+	}
+	return metadata
+}
+
+// mergeSuiteMetadata combines the metadata of the suites. The suites are
+// sorted by long name, so parent suites take precedence over their
+// children.
+func mergeSuiteMetadata(suites []SuiteStats) map[string]string {
+	var metadata map[string]string
+	for _, suite := range suites {
+		metadata = mergeMetadata(metadata, suite.Metadata)
+	}
+	return metadata
+}
+
+// mergeMetadata adds the entries of src missing from dst.
+func mergeMetadata(dst, src map[string]string) map[string]string {
+	for name, value := range src {
+		if dst == nil {
+			dst = map[string]string{}
+		}
+		if _, ok := dst[name]; !ok {
+			dst[name] = value
+		}
+	}
+	return dst
+}
+
+// writeMetadata sets the metadata output variables, METADATA_<NAME>.
+func writeMetadata(out *outputVars, metadata map[string]string) {
+	names := make([]string, 0, len(metadata))
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.set("METADATA_"+outputName(name), metadata[name])
+	}
+}
@@ -0,0 +1,59 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSuiteMetadata validates metadata extraction for both output formats
+func TestSuiteMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+	}{
+		{
+			name: "Robot Framework 7",
+			report: `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<meta name="Environment">staging</meta>
+<suite id="s1-s1" name="Child">
+<meta name="Environment">ignored</meta>
+<meta name="Version">1.2.3</meta>
+<test id="s1-s1-t1" name="Test"><status status="PASS"/></test>
+</suite>
+</suite>
+</robot>`,
+		},
+		{
+			name: "Robot Framework 3",
+			report: `<robot generator="Robot 3.2.2">
+<suite id="s1" name="Root">
+<metadata><item name="Environment">staging</item></metadata>
+<suite id="s1-s1" name="Child">
+<metadata><item name="Environment">ignored</item><item name="Version">1.2.3</item></metadata>
+<test id="s1-s1-t1" name="Test"><status status="PASS" critical="yes"/></test>
+</suite>
+</suite>
+</robot>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robotOutput, err := parseOutput([]byte(tt.report), "output.xml", statsOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			stats := computeStats(*robotOutput, statsOptions{})
+
+			want := map[string]string{"Environment": "staging", "Version": "1.2.3"}
+			if diff := cmp.Diff(want, stats.Metadata); diff != "" {
+				t.Errorf("Metadata mismatch (-want +got):\n%s", diff)
+			}
+			if got := stats.Suites[1].Metadata["Environment"]; got != "ignored" {
+				t.Errorf("Expected child suite metadata to be kept, got %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package plugin
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Suite namespaces, prefixing the suite names of every report file.
+const (
+	suiteNamespaceFile      = "file"      // report file name without extension
+	suiteNamespaceDirectory = "directory" // parent directory name
+)
+
+// validSuiteNamespace reports whether the namespace mode is known.
+func validSuiteNamespace(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", suiteNamespaceFile, suiteNamespaceDirectory:
+		return true
+	}
+	return false
+}
+
+// suiteNamespace returns the namespace of the suites of the report file,
+// or an empty string when suites are not namespaced.
+func suiteNamespace(filename, mode string) string {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case suiteNamespaceFile:
+		base := filepath.Base(filename)
+		return strings.TrimSuffix(base, filepath.Ext(base))
+	case suiteNamespaceDirectory:
+		return filepath.Base(filepath.Dir(filepath.Clean(filename)))
+	}
+	return ""
+}
+
+// namespaced prefixes the suite name with the namespace, if any.
+func namespaced(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSuiteNamespace validates the namespace of each mode
+func TestSuiteNamespace(t *testing.T) {
+	tests := []struct {
+		mode, want string
+	}{
+		{"", ""},
+		{"file", "output-chrome"},
+		{"Directory", "chrome"},
+	}
+	for _, test := range tests {
+		if got := suiteNamespace(filepath.Join("results", "chrome", "output-chrome.xml"), test.mode); got != test.want {
+			t.Errorf("suiteNamespace(%q) = %q, want %q", test.mode, got, test.want)
+		}
+	}
+	if validSuiteNamespace("suite") {
+		t.Error("Expected unknown namespace mode to be invalid")
+	}
+}
+
+// TestNamespacedSuites validates that duplicate root suites of different
+// files are told apart in the suite breakdown and failure details
+func TestNamespacedSuites(t *testing.T) {
+	dir := t.TempDir()
+	report := []byte(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Login"><status status="FAIL">boom</status></test>
+</suite>
+</robot>`)
+	var files []string
+	for _, browser := range []string{"chrome", "firefox"} {
+		if err := os.Mkdir(filepath.Join(dir, browser), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(dir, browser, "output.xml")
+		if err := os.WriteFile(file, report, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+	opts, err := newStatsOptions(Args{SuiteNamespace: "directory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites, failed []string
+	for _, file := range files {
+		stats, err := processFile(file, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, suite := range stats.Suites {
+			suites = append(suites, suite.Name)
+		}
+		for _, details := range stats.FailedTestsDetails {
+			failed = append(failed, details.Suite)
+		}
+	}
+	want := []string{"chrome.Root", "firefox.Root"}
+	if diff := cmp.Diff(want, suites); diff != "" {
+		t.Errorf("Unexpected suite names (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, failed); diff != "" {
+		t.Errorf("Unexpected failure suites (-want +got):\n%s", diff)
+	}
+}
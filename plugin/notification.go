@@ -0,0 +1,148 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// notificationTimeout limits each notification webhook call.
+const notificationTimeout = 10 * time.Second
+
+// maxNotificationFailures limits the failed tests listed in notifications.
+const maxNotificationFailures = 5
+
+// Notification conditions of PLUGIN_NOTIFY_ON.
+const (
+	notifyAlways  = "always"
+	notifyFailure = "failure" // failed or unstable runs
+)
+
+// Run statuses of notifications.
+const (
+	notificationPassed   = "passed"
+	notificationUnstable = "unstable"
+	notificationFailed   = "failed"
+)
+
+// notificationData is the data of the notification templates.
+type notificationData struct {
+	reportData
+	Status       string              // passed, unstable or failed
+	Failures     []FailedTestDetails // the first failed tests
+	MoreFailures int                 // failed tests beyond Failures
+}
+
+// newNotificationData returns the notification template data of the
+// statistics. Without gates, a run with failed tests is failed.
+func newNotificationData(stats StatsResult, unit string) notificationData {
+	data := notificationData{reportData: newReportData(stats, unit), Status: notificationPassed}
+	switch {
+	case data.Outcome == gateFail, data.Outcome == "" && stats.FailedTests > 0:
+		data.Status = notificationFailed
+	case data.Outcome == gateWarn:
+		data.Status = notificationUnstable
+	}
+	data.Failures = stats.FailedTestsDetails
+	if len(data.Failures) > maxNotificationFailures {
+		data.Failures = data.Failures[:maxNotificationFailures]
+	}
+	data.MoreFailures = stats.FailedTests - len(data.Failures)
+	return data
+}
+
+// notificationFuncs are the functions of the notification templates.
+var notificationFuncs = template.FuncMap{
+	"duration": formatDuration,
+	"metric":   formatMetric,
+	"upper":    strings.ToUpper,
+}
+
+// defaultNotificationTemplate is the Markdown message of the chat
+// notifications.
+const defaultNotificationTemplate = `{{if eq .Status "failed"}}❌{{else if eq .Status "unstable"}}⚠️{{else}}✅{{end}} **Robot Framework tests {{.Status}}**
+{{- with .Stats.Build}} · {{.Repo}}{{with .Branch}} · {{.}}{{end}}{{with .Number}} · build #{{.}}{{end}}{{end}}
+{{.Stats.PassedTests}} passed, {{.Stats.FailedTests}} failed, {{.Stats.SkippedTests}} skipped of {{.Stats.TotalTests}} tests in {{duration .Stats.WallClockTime "human"}}
+{{- range .Failures}}
+• {{.Suite}}.{{.Name}}{{with .ErrorMessage}}: {{.}}{{end}}
+{{- end}}
+{{- with .MoreFailures}}
+… and {{.}} more failed tests
+{{- end}}
+{{- with .Stats.Build}}{{with .Link}}
+{{.}}{{end}}{{end}}`
+
+// newNotificationTemplate parses the notification template of the
+// plugin arguments, or the default template.
+func newNotificationTemplate(args Args) (*template.Template, error) {
+	text := args.NotificationTemplate
+	if text == "" {
+		text = defaultNotificationTemplate
+	}
+	return template.New("notification").Funcs(notificationFuncs).Parse(text)
+}
+
+// validNotifyOn reports whether the notification condition is known.
+func validNotifyOn(condition string) bool {
+	switch strings.ToLower(strings.TrimSpace(condition)) {
+	case "", notifyAlways, notifyFailure:
+		return true
+	}
+	return false
+}
+
+// shouldNotify reports whether the run is notified under the condition.
+func shouldNotify(condition, status string) bool {
+	if strings.EqualFold(strings.TrimSpace(condition), notifyFailure) {
+		return status != notificationPassed
+	}
+	return true
+}
+
+// renderNotification renders the notification message of the statistics.
+// It reports false when the run is not notified.
+func renderNotification(tmpl *template.Template, args Args, stats StatsResult) (string, bool, error) {
+	data := newNotificationData(stats, args.DurationUnit)
+	if !shouldNotify(args.NotifyOn, data.Status) {
+		return "", false, nil
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", false, err
+	}
+	return strings.TrimSpace(b.String()), true, nil
+}
+
+// postWebhook posts the payload as JSON to the webhook URL.
+func postWebhook(ctx context.Context, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// the webhook URL holds its token, leave it out
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestRenderNotification validates the default notification message
+func TestRenderNotification(t *testing.T) {
+	stats := StatsResult{
+		TotalTests:    8,
+		PassedTests:   1,
+		FailedTests:   7,
+		WallClockTime: 90000,
+		Build:         &BuildInfo{Repo: "octo/app", Branch: "main", Number: "42", Link: "https://drone.example.com/octo/app/42"},
+	}
+	for i := 1; i <= 7; i++ {
+		stats.FailedTestsDetails = append(stats.FailedTestsDetails, FailedTestDetails{Name: fmt.Sprintf("Test %d", i), Suite: "Root", ErrorMessage: "boom"})
+	}
+	tmpl, err := newNotificationTemplate(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, ok, err := renderNotification(tmpl, Args{}, stats)
+	if err != nil || !ok {
+		t.Fatalf("Expected a notification, got %v and %v", ok, err)
+	}
+	for _, want := range []string{
+		"❌ **Robot Framework tests failed** · octo/app · main · build #42",
+		"1 passed, 7 failed, 0 skipped of 8 tests",
+		"• Root.Test 5: boom",
+		"… and 2 more failed tests",
+		"https://drone.example.com/octo/app/42",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Expected %q in the message:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "Test 6") {
+		t.Errorf("Expected at most %d failures in the message:\n%s", maxNotificationFailures, message)
+	}
+}
+
+// TestNotificationStatus validates the run status and the notification
+// condition
+func TestNotificationStatus(t *testing.T) {
+	tests := []struct {
+		stats  StatsResult
+		status string
+	}{
+		{StatsResult{TotalTests: 1, PassedTests: 1}, notificationPassed},
+		{StatsResult{TotalTests: 1, FailedTests: 1}, notificationFailed},
+		{StatsResult{TotalTests: 1, FailedTests: 1, Gates: []GateResult{{Name: "fail_rate", Outcome: gateWarn}}}, notificationUnstable},
+		{StatsResult{TotalTests: 1, PassedTests: 1, Gates: []GateResult{{Name: "pass_rate", Outcome: gateFail}}}, notificationFailed},
+	}
+	for _, test := range tests {
+		if got := newNotificationData(test.stats, "").Status; got != test.status {
+			t.Errorf("Expected status %s for %+v, got %s", test.status, test.stats, got)
+		}
+	}
+
+	tmpl, err := newNotificationTemplate(Args{NotificationTemplate: "{{upper .Status}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := Args{NotifyOn: "failure"}
+	if _, ok, _ := renderNotification(tmpl, args, tests[0].stats); ok {
+		t.Errorf("Expected no notification of a passed run on failure")
+	}
+	if message, ok, _ := renderNotification(tmpl, args, tests[2].stats); !ok || message != "UNSTABLE" {
+		t.Errorf("Expected an UNSTABLE notification, got %q", message)
+	}
+}
+
+// TestValidateNotificationSettings validates the checks of the
+// notification settings
+func TestValidateNotificationSettings(t *testing.T) {
+	err := ValidateInputs(Args{
+		ReportDirectory:      ".",
+		DiscordWebhook:       "discord.com/api/webhooks/1/x",
+		NotificationTemplate: "{{.Status",
+		NotifyOn:             "sometimes",
+	})
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, setting := range []string{"PLUGIN_DISCORD_WEBHOOK", "PLUGIN_NOTIFICATION_TEMPLATE", "PLUGIN_NOTIFY_ON"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("Expected an error of %s, got %v", setting, err)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Output file formats.
+const (
+	outputFormatDotenv = "dotenv" // KEY=value lines, as read from DRONE_OUTPUT
+	outputFormatExport = "export" // export KEY='value' lines, sourced by a shell
+)
+
+// harnessOutputEnv names the Harness-style output variable file, written
+// with export syntax.
+const harnessOutputEnv = "HARNESS_OUTPUT_FILE"
+
+// outputTarget is the output variable file and its format.
+type outputTarget struct {
+	Path   string
+	Format string
+}
+
+// resolveOutputTarget selects the output variable file: DRONE_OUTPUT,
+// then the Harness output file, then the explicit output file. A
+// configured format overrides the default format of the file. It returns
+// false when no output file is set.
+func resolveOutputTarget(outputFile, format string, getenv func(string) string) (outputTarget, bool) {
+	var target outputTarget
+	switch {
+	case getenv("DRONE_OUTPUT") != "":
+		target = outputTarget{Path: getenv("DRONE_OUTPUT"), Format: outputFormatDotenv}
+	case getenv(harnessOutputEnv) != "":
+		target = outputTarget{Path: getenv(harnessOutputEnv), Format: outputFormatExport}
+	case outputFile != "":
+		target = outputTarget{Path: outputFile, Format: outputFormatDotenv}
+	default:
+		return target, false
+	}
+	if format = strings.ToLower(strings.TrimSpace(format)); format != "" {
+		target.Format = format
+	}
+	return target, true
+}
+
+// validOutputFormat reports whether the format is a known output format.
+func validOutputFormat(format string) bool {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", outputFormatDotenv, outputFormatExport:
+		return true
+	}
+	return false
+}
+
+// line returns the output file line of the variable.
+func (t outputTarget) line(key, value string) string {
+	if t.Format == outputFormatExport {
+		return fmt.Sprintf("export %s=%s\n", key, shellQuote(value))
+	}
+	return key + "=" + encodeEnvValue(value) + "\n"
+}
+
+// shellQuote single-quotes the value for a POSIX shell.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// errNoOutputFile is returned when no output variable file is set.
+var errNoOutputFile = fmt.Errorf("no output file configured, set DRONE_OUTPUT, %s or PLUGIN_OUTPUT_FILE", harnessOutputEnv)
+
+// outputVars collects output variables in the order they are set, so
+// they can be written to the output file at once.
+type outputVars struct {
+	keys   []string
+	values map[string]string
+
+	// precision is the number of decimals of rates and durations, two
+	// when nil.
+	precision *int
+
+	// unit is the unit of durations, milliseconds when empty.
+	unit string
+}
+
+// defaultOutputPrecision is the number of decimals of rates and
+// durations unless PLUGIN_OUTPUT_PRECISION is set.
+const defaultOutputPrecision = 2
+
+// decimal formats a rate or duration with the output precision. The
+// decimal separator is always a dot, regardless of the locale.
+func (o *outputVars) decimal(v float64) string {
+	precision := defaultOutputPrecision
+	if o.precision != nil {
+		precision = *o.precision
+	}
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+// duration formats a duration in milliseconds in the output unit,
+// without unit suffix.
+func (o *outputVars) duration(ms float64) string {
+	switch durationUnit(o.unit) {
+	case durationUnitSeconds:
+		return o.decimal(ms / 1000)
+	case durationUnitHuman:
+		return humanDuration(ms)
+	}
+	return o.decimal(ms)
+}
+
+// set sets the variable, keeping the position of a variable set before.
+func (o *outputVars) set(key, value string) {
+	if o.values == nil {
+		o.values = map[string]string{}
+	}
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// setJSON sets the variable to the value encoded as JSON.
+func (o *outputVars) setJSON(key string, v interface{}) error {
+	value, err := jsonEnvValue(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %v", key, err)
+	}
+	o.set(key, value)
+	return nil
+}
+
+// write appends the variables to the output file of the arguments.
+func (o *outputVars) write(args Args) error {
+	target, ok := resolveOutputTarget(args.OutputFile, args.OutputFormat, os.Getenv)
+	if !ok {
+		return errNoOutputFile
+	}
+	return o.writeTo(target)
+}
+
+// writeTo appends the variables to the output file in a single atomic
+// write: the current content and the variables are written to a
+// temporary file in the same directory, which then replaces the file.
+func (o *outputVars) writeTo(target outputTarget) error {
+	if len(o.keys) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	existing, err := os.ReadFile(target.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	buf.Write(existing)
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	for _, key := range o.keys {
+		buf.WriteString(target.line(key, o.values[key]))
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target.Path), "."+filepath.Base(target.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target.Path)
+}
+
+// WriteEnvToFile writes a key-value pair to the output variable file,
+// quoting values that do not fit on a plain line. Errors are logged.
+func WriteEnvToFile(key, value string) {
+	out := &outputVars{}
+	out.set(key, value)
+	if err := out.write(envOutputArgs()); err != nil {
+		logrus.Warnf("Failed to write output variable %s: %v", key, err)
+	}
+}
+
+// envOutputArgs returns the output file settings of the environment.
+func envOutputArgs() Args {
+	args := Args{OutputFile: os.Getenv("PLUGIN_OUTPUT_FILE"), OutputFormat: os.Getenv("PLUGIN_OUTPUT_FORMAT")}
+	ExpandArgs(&args, os.Getenv)
+	return args
+}
+
+// envValueEscaper escapes a double-quoted output value. Dollar signs are
+// escaped so dotenv readers do not expand them as variables.
+var envValueEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"$", `\$`,
+)
+
+// encodeEnvValue returns the value as written to an output file. Values
+// that would not survive a plain key=value line, e.g. multi-line values
+// or values containing '=', quotes or '#', are double-quoted and escaped.
+func encodeEnvValue(value string) string {
+	if value == "" || (!strings.ContainsAny(value, "\n\r\"'\\=#$`") && strings.TrimSpace(value) == value) {
+		return value
+	}
+	return `"` + envValueEscaper.Replace(value) + `"`
+}
+
+// jsonEnvValue returns the value encoded as compact JSON, for outputs
+// holding lists or objects.
+func jsonEnvValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// WriteJSONEnvToFile writes the value as JSON to the output variable
+// file.
+func WriteJSONEnvToFile(key string, v interface{}) error {
+	out := &outputVars{}
+	if err := out.setJSON(key, v); err != nil {
+		return err
+	}
+	return out.write(envOutputArgs())
+}
+
+// failedTestNames returns the long names of the failed tests.
+func failedTestNames(stats StatsResult) []string {
+	names := make([]string, 0, len(stats.FailedTestsDetails))
+	for _, test := range stats.FailedTestsDetails {
+		names = append(names, test.Suite+"."+test.Name)
+	}
+	return names
+}
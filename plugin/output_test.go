@@ -0,0 +1,194 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEncodeEnvValue validates quoting of output values
+func TestEncodeEnvValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"", ""},
+		{"42", "42"},
+		{"12.50", "12.50"},
+		{"Root.Login Suite", "Root.Login Suite"},
+		{"a=b", `"a=b"`},
+		{"line 1\nline 2", `"line 1\nline 2"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\reports`, `"C:\\reports"`},
+		{"costs $HOME", `"costs \$HOME"`},
+		{" padded ", `" padded "`},
+		{"# comment", `"# comment"`},
+	}
+	for _, tc := range tests {
+		if got := encodeEnvValue(tc.value); got != tc.want {
+			t.Errorf("encodeEnvValue(%q) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+}
+
+// TestWriteJSONEnvToFile validates JSON-valued outputs
+func TestWriteJSONEnvToFile(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.env")
+	t.Setenv("DRONE_OUTPUT", output)
+
+	stats := StatsResult{FailedTestsDetails: []FailedTestDetails{
+		{Name: "Login", Suite: "Root.Auth"},
+		{Name: `Say "Hi"`, Suite: "Root"},
+	}}
+	if err := WriteJSONEnvToFile("FAILED_TEST_NAMES", failedTestNames(stats)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `FAILED_TEST_NAMES="[\"Root.Auth.Login\",\"Root.Say \\\"Hi\\\"\"]"` + "\n"
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+}
+
+// TestResolveOutputTarget validates output file detection
+func TestResolveOutputTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		outputFile string
+		format     string
+		want       outputTarget
+		wantOK     bool
+	}{
+		{
+			name:   "drone",
+			env:    map[string]string{"DRONE_OUTPUT": "/drone/output.env", "HARNESS_OUTPUT_FILE": "/harness/output.sh"},
+			want:   outputTarget{Path: "/drone/output.env", Format: outputFormatDotenv},
+			wantOK: true,
+		},
+		{
+			name:   "harness",
+			env:    map[string]string{"HARNESS_OUTPUT_FILE": "/harness/output.sh"},
+			want:   outputTarget{Path: "/harness/output.sh", Format: outputFormatExport},
+			wantOK: true,
+		},
+		{
+			name:       "explicit file",
+			outputFile: "outputs.sh",
+			format:     "Export",
+			want:       outputTarget{Path: "outputs.sh", Format: outputFormatExport},
+			wantOK:     true,
+		},
+		{
+			name:       "none",
+			outputFile: "",
+			wantOK:     false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := resolveOutputTarget(tc.outputFile, tc.format, func(key string) string { return tc.env[key] })
+			if ok != tc.wantOK || got != tc.want {
+				t.Errorf("Expected %+v (%v), got %+v (%v)", tc.want, tc.wantOK, got, ok)
+			}
+		})
+	}
+}
+
+// TestOutputTargetLine validates the output line formats
+func TestOutputTargetLine(t *testing.T) {
+	dotenv := outputTarget{Format: outputFormatDotenv}
+	if got := dotenv.line("FAILED_TESTS", "3"); got != "FAILED_TESTS=3\n" {
+		t.Errorf("Unexpected dotenv line %q", got)
+	}
+	export := outputTarget{Format: outputFormatExport}
+	if got := export.line("SUITE", "it's\nmulti-line"); got != "export SUITE='it'\\''s\nmulti-line'\n" {
+		t.Errorf("Unexpected export line %q", got)
+	}
+}
+
+// TestOutputVarsWrite validates the single append of all output variables
+func TestOutputVarsWrite(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "output.env")
+	if err := os.WriteFile(output, []byte("EXISTING=1"), 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out := &outputVars{}
+	out.set("TOTAL_TESTS", "10")
+	out.set("FAILED_TESTS", "2")
+	out.set("TOTAL_TESTS", "12")
+	if err := out.write(Args{OutputFile: output}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if want := "EXISTING=1\nTOTAL_TESTS=12\nFAILED_TESTS=2\n"; string(data) != want {
+		t.Errorf("Expected %q, got %q", want, data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected no temporary files, got %d entries", len(entries))
+	}
+}
+
+// TestExportOutputsErrors validates configurable output error handling
+func TestExportOutputsErrors(t *testing.T) {
+	t.Setenv("DRONE_OUTPUT", "")
+	t.Setenv("HARNESS_OUTPUT_FILE", "")
+
+	out := &outputVars{}
+	out.set("TOTAL_TESTS", "1")
+	if err := exportOutputs(out, Args{}); err != nil {
+		t.Errorf("Expected output errors to be logged only, got %v", err)
+	}
+	if err := exportOutputs(out, Args{FailOnOutputError: true}); err != errNoOutputFile {
+		t.Errorf("Expected %v, got %v", errNoOutputFile, err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing", "output.env")
+	if err := exportOutputs(out, Args{OutputFile: missing, FailOnOutputError: true}); err == nil {
+		t.Error("Expected an error writing to a missing directory")
+	}
+}
+
+// TestOutputPrecision validates the configurable decimals of rates and
+// durations, with a dot decimal separator
+func TestOutputPrecision(t *testing.T) {
+	stats := StatsResult{FailureRate: 33.333333, WallClockTime: 1234.5678, CumulativeTestTime: 1000}
+
+	tests := []struct {
+		name      string
+		precision *int
+		rate      string
+		wallClock string
+	}{
+		{"Default", nil, "33.33", "1234.57"},
+		{"No Decimals", intPtr(0), "33", "1235"},
+		{"Four Decimals", intPtr(4), "33.3333", "1234.5678"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &outputVars{precision: tc.precision}
+			writeTestStats(out, stats)
+			if got := out.values["FAILURE_RATE"]; got != tc.rate {
+				t.Errorf("Expected FAILURE_RATE %q, got %q", tc.rate, got)
+			}
+			if got := out.values["WALL_CLOCK_TIME"]; got != tc.wallClock {
+				t.Errorf("Expected WALL_CLOCK_TIME %q, got %q", tc.wallClock, got)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
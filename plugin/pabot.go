@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pabotResultsDir is the directory pabot writes per-worker outputs to,
+// as pabot_results/<worker>/output.xml.
+const pabotResultsDir = "pabot_results"
+
+// pabotPatterns returns the glob patterns matching pabot worker outputs
+// for the report directory, which may be the pabot results directory
+// itself or its parent.
+func pabotPatterns(directory, fileName string) []string {
+	if filepath.Base(filepath.Clean(directory)) == pabotResultsDir {
+		return []string{filepath.Join(directory, "*", fileName)}
+	}
+	return []string{filepath.Join(directory, pabotResultsDir, "*", fileName)}
+}
+
+// pabotWorker returns the pabot worker label of a report file, or an
+// empty string if the file is not a pabot worker output.
+func pabotWorker(file string) string {
+	workerDir := filepath.Dir(file)
+	if filepath.Base(filepath.Dir(workerDir)) != pabotResultsDir {
+		return ""
+	}
+	return filepath.Base(workerDir)
+}
+
+// newFileStats returns the per-file breakdown entry for a report file.
+func newFileStats(file string, stats StatsResult) FileStats {
+	return FileStats{
+		File:          file,
+		Worker:        pabotWorker(file),
+		TotalTests:    stats.TotalTests,
+		PassedTests:   stats.PassedTests,
+		FailedTests:   stats.FailedTests,
+		SkippedTests:  stats.SkippedTests,
+		WallClockTime: stats.WallClockTime,
+		RobotVersion:  stats.RobotVersion,
+		SchemaVersion: stats.SchemaVersion,
+
+		CriticalFailed: stats.CriticalFailed,
+		TotalWarnings:  stats.TotalWarnings,
+		ExecutionTime:  stats.ExecutionTime,
+	}
+}
+
+// findSplitSuites returns the suites whose tests are split across more
+// than one pabot worker. Only suites without child suites in a file are
+// counted, as Robot Framework suites hold either tests or child suites:
+// parent suites, e.g. the root suite, are in every file of their
+// children without being split themselves.
+func findSplitSuites(files []FileStats, suites map[string][]string) []SplitSuite {
+	workers := map[string]map[string]bool{}
+	for _, file := range files {
+		if file.Worker == "" {
+			continue
+		}
+		names := suites[file.File]
+		for _, suite := range names {
+			if hasChildSuite(names, suite) {
+				continue
+			}
+			if workers[suite] == nil {
+				workers[suite] = map[string]bool{}
+			}
+			workers[suite][file.Worker] = true
+		}
+	}
+
+	var split []SplitSuite
+	for suite, set := range workers {
+		if len(set) < 2 {
+			continue
+		}
+		var names []string
+		for worker := range set {
+			names = append(names, worker)
+		}
+		sort.Strings(names)
+		split = append(split, SplitSuite{Name: suite, Workers: names})
+	}
+	sort.Slice(split, func(i, j int) bool { return split[i].Name < split[j].Name })
+	return split
+}
+
+// hasChildSuite reports whether any of the suite long names is a child
+// of the named suite.
+func hasChildSuite(suites []string, name string) bool {
+	for _, suite := range suites {
+		if strings.HasPrefix(suite, name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// logFileResults logs the per-file breakdown when more than one report
+// file was processed.
+func logFileResults(stats StatsResult, unit string) {
+	if len(stats.Files) < 2 {
+		return
+	}
+	logrus.Infof("Per-File Results:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, file := range stats.Files {
+		label := file.File
+		if file.Worker != "" {
+			label = "worker " + file.Worker + " (" + file.File + ")"
+		}
+		logrus.Infof("%s: %d tests, %d passed, %d failed, %d skipped, %s\n",
+			label, file.TotalTests, file.PassedTests, file.FailedTests, file.SkippedTests, formatDuration(file.WallClockTime, unit))
+	}
+	logrus.Infof("-----------------------------------------------\n")
+
+	for _, suite := range stats.SplitSuites {
+		logrus.Warnf("Suite %s is split across pabot workers %s\n", suite.Name, strings.Join(suite.Workers, ", "))
+	}
+}
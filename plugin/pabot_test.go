@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLocatePabotFiles validates detection of pabot worker outputs
+func TestLocatePabotFiles(t *testing.T) {
+	for _, directory := range []string{"../testdata/pabot", "../testdata/pabot/pabot_results"} {
+		files, err := locateFiles(directory, fileQuery{Pattern: "output.xml"})
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", directory, err)
+		}
+		if len(files) != 2 {
+			t.Fatalf("Expected 2 worker files in %s, got %d", directory, len(files))
+		}
+		if worker := pabotWorker(files[1]); worker != "1" {
+			t.Errorf("Expected worker label 1 for %s, got %q", files[1], worker)
+		}
+	}
+}
+
+// TestFindSplitSuites validates detection of suites split across pabot workers
+func TestFindSplitSuites(t *testing.T) {
+	worker0 := filepath.Join("results", pabotResultsDir, "0", "output.xml")
+	worker1 := filepath.Join("results", pabotResultsDir, "1", "output.xml")
+	files := []FileStats{
+		{File: worker0, Worker: "0"},
+		{File: worker1, Worker: "1"},
+	}
+	suites := map[string][]string{
+		worker0: {"Checkout", "Checkout.Payment", "Checkout.Cart"},
+		worker1: {"Checkout", "Checkout.Payment"},
+	}
+
+	expected := []SplitSuite{
+		{Name: "Checkout.Payment", Workers: []string{"0", "1"}},
+	}
+	if diff := cmp.Diff(expected, findSplitSuites(files, suites)); diff != "" {
+		t.Errorf("Split suites mismatch (-want +got):\n%s", diff)
+	}
+	// the root suite of a normal pabot run is in every file
+	suites = map[string][]string{
+		worker0: {"Root", "Root.A"},
+		worker1: {"Root", "Root.B"},
+	}
+	if split := findSplitSuites(files, suites); len(split) != 0 {
+		t.Errorf("Expected no split suites, got %v", split)
+	}
+}
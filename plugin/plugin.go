@@ -1,95 +1,499 @@
 package plugin
 
 import (
+	"bytes"
 	"context"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
 // Args represents the plugin's configurable arguments.
 type Args struct {
-	ReportDirectory       string `envconfig:"PLUGIN_REPORT_DIRECTORY"`
-	ReportFileNamePattern string `envconfig:"PLUGIN_REPORT_FILE_NAME_PATTERN"`
-	PassThreshold         int    `envconfig:"PLUGIN_PASS_THRESHOLD"`
-	UnstableThreshold     int    `envconfig:"PLUGIN_UNSTABLE_THRESHOLD"`
-	CountSkippedTests     bool   `envconfig:"PLUGIN_COUNT_SKIPPED_TESTS"`
-	OnlyCritical          bool   `envconfig:"PLUGIN_ONLY_CRITICAL"`
-	Level                 string `envconfig:"PLUGIN_LOG_LEVEL"`
+	ReportDirectory       string   `envconfig:"PLUGIN_REPORT_DIRECTORY" expand:"true"`
+	ReportFileNamePattern string   `envconfig:"PLUGIN_REPORT_FILE_NAME_PATTERN" expand:"true"`
+	ReportFileRegex       string   `envconfig:"PLUGIN_REPORT_FILE_REGEX"`
+	ReportFileIgnoreCase  bool     `envconfig:"PLUGIN_REPORT_FILE_IGNORE_CASE"`
+	FollowSymlinks        *bool    `envconfig:"PLUGIN_FOLLOW_SYMLINKS"` // defaults to true
+	MaxSearchDepth        int      `envconfig:"PLUGIN_MAX_SEARCH_DEPTH"`
+	SkipHiddenDirs        bool     `envconfig:"PLUGIN_SKIP_HIDDEN_DIRS"`
+	SuiteNamespace        string   `envconfig:"PLUGIN_SUITE_NAMESPACE"`   // file or directory
+	SchemaValidation      string   `envconfig:"PLUGIN_SCHEMA_VALIDATION"` // warn or fail
+	PassThreshold         int      `envconfig:"PLUGIN_PASS_THRESHOLD"`
+	UnstableThreshold     int      `envconfig:"PLUGIN_UNSTABLE_THRESHOLD"`
+	ThresholdsScope       string   `envconfig:"PLUGIN_THRESHOLDS_SCOPE"` // aggregate, per-file or both
+	CountSkippedTests     bool     `envconfig:"PLUGIN_COUNT_SKIPPED_TESTS"`
+	TreatSkippedAsFailed  bool     `envconfig:"PLUGIN_TREAT_SKIPPED_AS_FAILED"`
+	NotRunAs              string   `envconfig:"PLUGIN_NOT_RUN_AS"` // ignore, skip or fail
+	AllowFailureTag       string   `envconfig:"PLUGIN_ALLOW_FAILURE_TAG"`
+	OnlyCritical          bool     `envconfig:"PLUGIN_ONLY_CRITICAL"`
+	FastSummary           bool     `envconfig:"PLUGIN_FAST_SUMMARY"`
+	SalvageTruncated      bool     `envconfig:"PLUGIN_SALVAGE_TRUNCATED"`
+	MaxReportSize         int      `envconfig:"PLUGIN_MAX_REPORT_SIZE"` // in MB
+	MaxXMLDepth           int      `envconfig:"PLUGIN_MAX_XML_DEPTH"`
+	MergeReruns           bool     `envconfig:"PLUGIN_MERGE_RERUNS"`
+	FailFast              bool     `envconfig:"PLUGIN_FAIL_FAST"`
+	FileWorkers           int      `envconfig:"PLUGIN_FILE_WORKERS"` // defaults to the number of CPUs
+	StatsFile             string   `envconfig:"PLUGIN_STATS_FILE" expand:"true"`
+	CompareTo             string   `envconfig:"PLUGIN_COMPARE_TO" expand:"true"`
+	CompareReport         string   `envconfig:"PLUGIN_COMPARE_REPORT" expand:"true"`
+	MaxFlakyTests         *float64 `envconfig:"PLUGIN_MAX_FLAKY_TESTS"`
+	MaxFailedKeywords     *float64 `envconfig:"PLUGIN_MAX_FAILED_KEYWORDS"`
+	MaxKeywordFailureRate *float64 `envconfig:"PLUGIN_MAX_KEYWORD_FAILURE_RATE"`
+	GateExpression        string   `envconfig:"PLUGIN_GATE_EXPRESSION"`
+	SourceURLTemplate     string   `envconfig:"PLUGIN_SOURCE_URL_TEMPLATE" expand:"true"`
+	SourceRoot            string   `envconfig:"PLUGIN_SOURCE_ROOT" expand:"true"`
+	SummaryStyle          string   `envconfig:"PLUGIN_SUMMARY_STYLE"` // rich or plain
+	SuiteTree             bool     `envconfig:"PLUGIN_SUITE_TREE"`
+	SuiteTreeDepth        int      `envconfig:"PLUGIN_SUITE_TREE_DEPTH"` // 0 shows all levels
+	Quiet                 bool     `envconfig:"PLUGIN_QUIET"`            // single summary line, warnings and errors
+	Verbose               bool     `envconfig:"PLUGIN_VERBOSE"`          // a log line per processed test
+	SkipKeywordStats      bool     `envconfig:"PLUGIN_SKIP_KEYWORD_STATS"`
+	MetadataOutputs       bool     `envconfig:"PLUGIN_METADATA_OUTPUTS"`
+	UnstableWebhook       string   `envconfig:"PLUGIN_UNSTABLE_WEBHOOK" expand:"true"`
+	UnstableWebhookToken  string   `envconfig:"PLUGIN_UNSTABLE_WEBHOOK_TOKEN" secret:"true"`
+	ErrorReportFile       string   `envconfig:"PLUGIN_ERROR_REPORT_FILE" expand:"true"`
+	RDJSONFile            string   `envconfig:"PLUGIN_RDJSON_FILE" expand:"true"`
+	TimelineFile          string   `envconfig:"PLUGIN_TIMELINE_FILE" expand:"true"` // HTML when the extension is .html, JSON otherwise
+	FailureSort           string   `envconfig:"PLUGIN_FAILURE_SORT"`                // suite, duration or file
+	MaxFailureDetails     int      `envconfig:"PLUGIN_MAX_FAILURE_DETAILS"`
+	MaxErrorMessageLength int      `envconfig:"PLUGIN_MAX_ERROR_MESSAGE_LENGTH"`
+	OutputFile            string   `envconfig:"PLUGIN_OUTPUT_FILE" expand:"true"`
+	OutputFormat          string   `envconfig:"PLUGIN_OUTPUT_FORMAT"`    // dotenv or export
+	OutputPrecision       *int     `envconfig:"PLUGIN_OUTPUT_PRECISION"` // decimals of rates and durations, defaults to 2
+	DurationUnit          string   `envconfig:"PLUGIN_DURATION_UNIT"`    // ms, s or human
+	FailOnOutputError     bool     `envconfig:"PLUGIN_FAIL_ON_OUTPUT_ERROR"`
+	HarnessOutputs        bool     `envconfig:"PLUGIN_HARNESS_OUTPUTS"` // write to the output file Harness CI reads
+	ConfigFile            string   `envconfig:"PLUGIN_CONFIG_FILE"`
+	Level                 string   `envconfig:"PLUGIN_LOG_LEVEL"`
+
+	// Wait for report files to appear and stop growing before processing.
+	WaitForReports   bool          `envconfig:"PLUGIN_WAIT_FOR_REPORTS"`
+	WaitPollInterval time.Duration `envconfig:"PLUGIN_WAIT_POLL_INTERVAL"`
+	WaitTimeout      time.Duration `envconfig:"PLUGIN_WAIT_TIMEOUT"`
+	LiveTail         bool          `envconfig:"PLUGIN_LIVE_TAIL"`
+
+	// Keyword messages leading up to a failure, included as failure context.
+	FailureContextMessages int    `envconfig:"PLUGIN_FAILURE_CONTEXT_MESSAGES"`
+	FailureContextLevel    string `envconfig:"PLUGIN_FAILURE_CONTEXT_LEVEL"` // TRACE, DEBUG, INFO or WARN
+
+	// Progress reporting while report files are processed.
+	Progress         *bool         `envconfig:"PLUGIN_PROGRESS"`          // defaults to true
+	ProgressInterval time.Duration `envconfig:"PLUGIN_PROGRESS_INTERVAL"` // defaults to 10s
+
+	// Directory caching the statistics of report files by content hash.
+	CacheDir string `envconfig:"PLUGIN_CACHE_DIR" expand:"true"`
+
+	// Manifest of the processed report files, to only aggregate new files.
+	StateManifest string `envconfig:"PLUGIN_STATE_MANIFEST" expand:"true"`
+
+	// File the failure details are streamed to instead of being collected.
+	FailureDetailsFile string `envconfig:"PLUGIN_FAILURE_DETAILS_FILE" expand:"true"`
+
+	// Hidden diagnostics: CPU and heap profiles for performance reports.
+	CPUProfile  string `envconfig:"PLUGIN_CPU_PROFILE"`
+	HeapProfile string `envconfig:"PLUGIN_HEAP_PROFILE"`
+
+	// Robot Framework tag patterns selecting the tests included in the stats.
+	IncludeTags []string `envconfig:"PLUGIN_INCLUDE_TAGS"`
+	ExcludeTags []string `envconfig:"PLUGIN_EXCLUDE_TAGS"`
+
+	// Tags whose test counts are exported as TAG_<NAME>_* output variables.
+	OutputTags []string `envconfig:"PLUGIN_OUTPUT_TAGS"`
+
+	// Export the tag statistics Robot Framework computed, including
+	// combined tags.
+	TagStatistics bool `envconfig:"PLUGIN_TAG_STATISTICS"`
+
+	// Suite and test name patterns (globs, or regular expressions when
+	// prefixed with "regex:") selecting the tests included in the stats.
+	IncludeSuites []string `envconfig:"PLUGIN_INCLUDE_SUITES"`
+	ExcludeSuites []string `envconfig:"PLUGIN_EXCLUDE_SUITES"`
+	IncludeTests  []string `envconfig:"PLUGIN_INCLUDE_TESTS"`
+	ExcludeTests  []string `envconfig:"PLUGIN_EXCLUDE_TESTS"`
+
+	// Per-metric warning and failure limits. Unset limits are not enforced.
+	FailureRateWarn    *float64 `envconfig:"PLUGIN_FAILURE_RATE_WARN"`
+	FailureRateFail    *float64 `envconfig:"PLUGIN_FAILURE_RATE_FAIL"`
+	SkippedRateWarn    *float64 `envconfig:"PLUGIN_SKIPPED_RATE_WARN"`
+	SkippedRateFail    *float64 `envconfig:"PLUGIN_SKIPPED_RATE_FAIL"`
+	MaxSkippedRate     *float64 `envconfig:"PLUGIN_MAX_SKIPPED_RATE"` // shorthand for PLUGIN_SKIPPED_RATE_FAIL
+	CriticalFailedWarn *float64 `envconfig:"PLUGIN_CRITICAL_FAILED_WARN"`
+	CriticalFailedFail *float64 `envconfig:"PLUGIN_CRITICAL_FAILED_FAIL"`
+	ExecutionTimeWarn  *float64 `envconfig:"PLUGIN_EXECUTION_TIME_WARN"`
+	ExecutionTimeFail  *float64 `envconfig:"PLUGIN_EXECUTION_TIME_FAIL"`
+	WarningsWarn       *float64 `envconfig:"PLUGIN_WARNINGS_WARN"`
+	WarningsFail       *float64 `envconfig:"PLUGIN_WARNINGS_FAIL"`
+	ShardImbalanceWarn *float64 `envconfig:"PLUGIN_SHARD_IMBALANCE_WARN"`
+
+	// Weighted quality score, gated by a single minimum score
+	QualityScore        bool               `envconfig:"PLUGIN_QUALITY_SCORE"`
+	MinQualityScore     *float64           `envconfig:"PLUGIN_MIN_QUALITY_SCORE"`
+	CriticalTestWeight  float64            `envconfig:"PLUGIN_CRITICAL_TEST_WEIGHT"`
+	TagWeights          map[string]float64 `envconfig:"PLUGIN_TAG_WEIGHTS"`
+	FailedKeywordWeight float64            `envconfig:"PLUGIN_FAILED_KEYWORD_WEIGHT"`
+
+	// Execution time regression limits, in percent, against a baseline run.
+	BaselineFile                string   `envconfig:"PLUGIN_BASELINE_FILE" expand:"true"`
+	ExecutionTimeRegressionWarn *float64 `envconfig:"PLUGIN_EXECUTION_TIME_REGRESSION_WARN"`
+	ExecutionTimeRegressionFail *float64 `envconfig:"PLUGIN_EXECUTION_TIME_REGRESSION_FAIL"`
+
+	// Per-test duration budget in milliseconds, with per-tag overrides.
+	MaxTestDuration      float64            `envconfig:"PLUGIN_MAX_TEST_DURATION"`
+	MaxTestDurationByTag map[string]float64 `envconfig:"PLUGIN_MAX_TEST_DURATION_BY_TAG"`
+	FailOnSlowTests      bool               `envconfig:"PLUGIN_FAIL_ON_SLOW_TESTS"`
+
+	// Custom test statuses mapped to pass, fail, skip or ignore, and
+	// whether tests with an empty or unmapped status fail the build.
+	StatusMapping       map[string]string `envconfig:"PLUGIN_STATUS_MAPPING"`
+	FailOnUnknownStatus bool              `envconfig:"PLUGIN_FAIL_ON_UNKNOWN_STATUS"`
+
+	// Actions of the robot: reserved tags, keyed by the tag name without
+	// the prefix: exclude, skip, allow-failure or count.
+	ReservedTags map[string]string `envconfig:"PLUGIN_RESERVED_TAGS"`
+
+	// List the passed tests with failed keywords.
+	AtRiskTests bool `envconfig:"PLUGIN_AT_RISK_TESTS"`
+
+	// Fail the build on duplicate test IDs and empty test names.
+	StrictStructure bool `envconfig:"PLUGIN_STRICT_STRUCTURE"`
+
+	// Custom key/value labels attached to the build metadata.
+	BuildLabels map[string]string `envconfig:"PLUGIN_BUILD_LABELS"`
+
+	// Runner details attached to the JSON statistics and the HTML report.
+	HostInfo bool     `envconfig:"PLUGIN_HOST_INFO"`
+	HostEnv  []string `envconfig:"PLUGIN_HOST_ENV"` // variable name patterns
+
+	// Run history, selecting the comparison baseline when PLUGIN_COMPARE_TO
+	// is not set.
+	HistoryDir    string `envconfig:"PLUGIN_HISTORY_DIR" expand:"true"`
+	BaselineBuild string `envconfig:"PLUGIN_BASELINE_BUILD" expand:"true"`
+
+	// Stats artifact of the previous successful build on the branch, found
+	// through the Drone API, compared against when PLUGIN_COMPARE_TO is not
+	// set.
+	PreviousStatsURL    string `envconfig:"PLUGIN_PREVIOUS_STATS_URL" expand:"true"`
+	PreviousBuildServer string `envconfig:"PLUGIN_PREVIOUS_BUILD_SERVER" expand:"true"`
+	PreviousBuildToken  string `envconfig:"PLUGIN_PREVIOUS_BUILD_TOKEN" secret:"true"`
+
+	// HTML report and Markdown summary, and the trend chart of the last
+	// PLUGIN_TREND_RUNS runs in the history.
+	HTMLReport      string `envconfig:"PLUGIN_HTML_REPORT" expand:"true"`
+	MarkdownSummary string `envconfig:"PLUGIN_MARKDOWN_SUMMARY" expand:"true"`
+	TrendChart      string `envconfig:"PLUGIN_TREND_CHART" expand:"true"`
+	TrendRuns       int    `envconfig:"PLUGIN_TREND_RUNS"`
+
+	// Leaderboard of the PLUGIN_FAILURE_LEADERBOARD tests failing most
+	// often in the last PLUGIN_FAILURE_LEADERBOARD_RUNS runs in the history.
+	FailureLeaderboard     int `envconfig:"PLUGIN_FAILURE_LEADERBOARD"`
+	FailureLeaderboardRuns int `envconfig:"PLUGIN_FAILURE_LEADERBOARD_RUNS"`
+
+	// Suggested split of the suites into PLUGIN_SPLIT_GROUPS groups of
+	// about the same duration, for the --suite arguments of later steps.
+	SplitManifest string `envconfig:"PLUGIN_SPLIT_MANIFEST" expand:"true"`
+	SplitGroups   int    `envconfig:"PLUGIN_SPLIT_GROUPS"` // defaults to the number of pabot workers, or 2
+
+	// Directory the processed output files are copied to with their log
+	// and report files, one directory per output file.
+	PublishDir string `envconfig:"PLUGIN_PUBLISH_DIR" expand:"true"`
+
+	// Artifact metadata file linking the generated reports, uploaded to
+	// PLUGIN_ARTIFACT_BASE_URL by a later step, from the Artifacts tab.
+	ArtifactFile    string `envconfig:"PLUGIN_ARTIFACT_FILE" expand:"true"`
+	ArtifactBaseURL string `envconfig:"PLUGIN_ARTIFACT_BASE_URL" expand:"true"`
+
+	// Confluence page publishing the summary, created or updated by title,
+	// e.g. one page per branch or release.
+	ConfluenceURL      string `envconfig:"PLUGIN_CONFLUENCE_URL" expand:"true"`
+	ConfluenceSpace    string `envconfig:"PLUGIN_CONFLUENCE_SPACE" expand:"true"`
+	ConfluenceParentID string `envconfig:"PLUGIN_CONFLUENCE_PARENT_ID" expand:"true"`
+	ConfluenceTitle    string `envconfig:"PLUGIN_CONFLUENCE_TITLE" expand:"true"`
+	ConfluenceUsername string `envconfig:"PLUGIN_CONFLUENCE_USERNAME" expand:"true"`
+	ConfluenceToken    string `envconfig:"PLUGIN_CONFLUENCE_TOKEN" secret:"true"`
+
+	// Chat notifications of the run, rendered from PLUGIN_NOTIFICATION_TEMPLATE
+	// or the default message and sent when PLUGIN_NOTIFY_ON matches.
+	DiscordWebhook       string `envconfig:"PLUGIN_DISCORD_WEBHOOK" secret:"true"`
+	MattermostWebhook    string `envconfig:"PLUGIN_MATTERMOST_WEBHOOK" secret:"true"`
+	MattermostChannel    string `envconfig:"PLUGIN_MATTERMOST_CHANNEL" expand:"true"`
+	NotificationTemplate string `envconfig:"PLUGIN_NOTIFICATION_TEMPLATE"`
+	NotifyOn             string `envconfig:"PLUGIN_NOTIFY_ON"` // always or failure
+
+	// SMS alerts through Twilio when critical tests fail on one of the
+	// PLUGIN_TWILIO_BRANCHES, e.g. release branches.
+	TwilioAccountSID string   `envconfig:"PLUGIN_TWILIO_ACCOUNT_SID"`
+	TwilioAuthToken  string   `envconfig:"PLUGIN_TWILIO_AUTH_TOKEN" secret:"true"`
+	TwilioFrom       string   `envconfig:"PLUGIN_TWILIO_FROM"`
+	TwilioTo         []string `envconfig:"PLUGIN_TWILIO_TO"`
+	TwilioBranches   []string `envconfig:"PLUGIN_TWILIO_BRANCHES"` // shell patterns
+
+	// Remote history settings, for s3:// and gs:// history locations. The
+	// credentials override the default credential chains.
+	HistoryRegion    string `envconfig:"PLUGIN_HISTORY_REGION"`
+	HistoryEndpoint  string `envconfig:"PLUGIN_HISTORY_ENDPOINT" expand:"true"`
+	HistoryAccessKey string `envconfig:"PLUGIN_HISTORY_ACCESS_KEY"`
+	HistorySecretKey string `envconfig:"PLUGIN_HISTORY_SECRET_KEY" secret:"true"`
+	HistoryToken     string `envconfig:"PLUGIN_HISTORY_TOKEN" secret:"true"`
+
+	// History retention, pruning the oldest runs. Zero keeps all runs.
+	HistoryMaxRuns int           `envconfig:"PLUGIN_HISTORY_MAX_RUNS"`
+	HistoryMaxAge  time.Duration `envconfig:"PLUGIN_HISTORY_MAX_AGE"`
+
+	// Digest mode, summarizing the runs of the last PLUGIN_DIGEST_WINDOW
+	// from stats files or the history instead of analyzing reports.
+	Digest       bool          `envconfig:"PLUGIN_DIGEST"`
+	DigestFiles  []string      `envconfig:"PLUGIN_DIGEST_FILES" expand:"true"` // glob patterns
+	DigestWindow time.Duration `envconfig:"PLUGIN_DIGEST_WINDOW"`
+	DigestFile   string        `envconfig:"PLUGIN_DIGEST_FILE" expand:"true"`
+
+	// Structured settings, only available in the configuration file.
+	SuiteThresholds     []SuiteThreshold          `ignored:"true" yaml:"suite_thresholds"`
+	ClassificationRules []ClassificationRule      `ignored:"true" yaml:"classification_rules"`
+	Reporters           map[string]ReporterConfig `ignored:"true" yaml:"reporters"`
 }
 
-// ValidateInputs ensures valid plugin arguments.
-func ValidateInputs(args Args) error {
-	if args.ReportDirectory == "" {
-		return errors.New("report directory is required")
+// Exec processes Robot Framework Report files and extracts statistics.
+func Exec(ctx context.Context, args Args) error {
+	if args.Digest {
+		return execDigest(ctx, args)
+	}
+	stats, err := Analyze(ctx, args)
+	if err != nil {
+		return err
 	}
-	if args.ReportFileNamePattern == "" {
-		args.ReportFileNamePattern = "*.xml"
+
+	// Evaluate the gates first so reporters publish their outcomes
+	gates, gateErr := EvaluateGates(stats, args)
+	stats.Gates = gates
+	stats.Unstable = isUnstable(gates)
+	stats.Build = newBuildInfo(args.BuildLabels, os.Getenv)
+	if hostInfoEnabled(args) {
+		stats.Host = newHostInfo(args.HostEnv, os.Environ())
+	}
+	if err := attachHistory(ctx, &stats, args); err != nil {
+		logrus.Warnf("Skipping the run history: %v", err)
 	}
-	if args.PassThreshold < 0 || args.UnstableThreshold < 0 {
-		return errors.New("threshold values must be non-negative")
+
+	// Publish the statistics through the enabled reporters
+	if err := defaultReporters.report(ctx, args, stats); err != nil {
+		return classifyError(ErrorClassReporter, err)
 	}
-	return nil
+
+	return gateErr
 }
 
-// Exec processes Robot Framework Report files and extracts statistics.
-func Exec(ctx context.Context, args Args) error {
-	files, err := locateFiles(args.ReportDirectory, args.ReportFileNamePattern)
+// Analyze locates the report files of the plugin arguments and computes
+// their statistics.
+func Analyze(ctx context.Context, args Args) (StatsResult, error) {
+	query, err := newFileQuery(args)
 	if err != nil {
-		logrus.Errorf("Error locating files: %v", err)
-		return fmt.Errorf("failed to locate files: %v", err)
+		return StatsResult{}, err
 	}
 
-	if len(files) == 0 {
-		return errors.New("no Robot Framework Report files found. Check the report file pattern")
+	switch {
+	case args.LiveTail:
+		if err := tailReports(ctx, args.ReportDirectory, query, args.WaitPollInterval, args.WaitTimeout); err != nil {
+			return StatsResult{}, classifyError(ErrorClassDiscovery, err)
+		}
+	case args.WaitForReports:
+		if err := waitForReports(ctx, args.ReportDirectory, query, args.WaitPollInterval, args.WaitTimeout); err != nil {
+			return StatsResult{}, classifyError(ErrorClassDiscovery, err)
+		}
 	}
 
-	var wg sync.WaitGroup
+	stream, err := newFailureStream(args)
+	if err != nil {
+		return StatsResult{}, classifyError(ErrorClassReporter, fmt.Errorf("failed to create failure details file: %v", err))
+	}
+	analyzer := newAnalyzer(args)
+	analyzer.failures = stream
+	analyzer.keepTests = needsTestResults(args)
+	stats, err := analyzer.AnalyzeDir(ctx, args.ReportDirectory)
+	if cerr := stream.close(); err == nil && cerr != nil {
+		return StatsResult{}, classifyError(ErrorClassReporter, fmt.Errorf("failed to write failure details file: %v", cerr))
+	}
+	if err != nil {
+		return StatsResult{}, classifyError(ErrorClassDiscovery, err)
+	}
+	if stream != nil {
+		// classified and linked as they were streamed
+		logrus.Infof("Wrote %d failure details to %s", stream.count, stream.path)
+	} else {
+		if err := classifyFailures(stats, args.ClassificationRules); err != nil {
+			return StatsResult{}, err
+		}
+		linkFailureSources(stats, args.SourceURLTemplate, args.SourceRoot)
+	}
+	limitFailureDetails(stats, args.MaxFailureDetails, args.MaxErrorMessageLength)
+	return *stats, nil
+}
+
+// LogResults logs the aggregated and per-file statistics in the summary
+// style of the plugin arguments, and the suite tree when enabled. In
+// quiet mode it writes a single summary line instead.
+func LogResults(stats StatsResult, args Args) {
+	if args.Quiet {
+		// quiet mode lowers the log level, write the line directly
+		fmt.Fprintln(logrus.StandardLogger().Out, quietSummary(stats, args.DurationUnit))
+		return
+	}
+	logAggregatedResults(stats, args.SummaryStyle, args.DurationUnit)
+	if args.SuiteTree {
+		logSuiteTree(stats, args.SuiteTreeDepth, args.SummaryStyle, args.DurationUnit)
+	}
+	logFileResults(stats, args.DurationUnit)
+	logShardBalance(stats, args.DurationUnit)
+}
+
+// ValidateResults validates the statistics against the thresholds, the
+// gate expression and the baseline run.
+func ValidateResults(stats StatsResult, args Args) error {
+	_, err := EvaluateGates(stats, args)
+	return err
+}
+
+// processFiles processes the report files concurrently and aggregates
+// their statistics.
+func processFiles(files []string, opts statsOptions) StatsResult {
 	var mu sync.Mutex
+	progress := startProgress(len(files), opts.ProgressInterval)
+	defer progress.finish()
+
+	// Files are processed by a pool of workers. The results are
+	// aggregated in file order as soon as every earlier file is done, so
+	// the statistics do not depend on the completion order and only the
+	// results of files completed out of order are held.
+	results := make([]*StatsResult, len(files))
+	done := make([]bool, len(files))
+	next := 0
 	stats := StatsResult{}
-
-	for _, file := range files {
+	fileSuites := map[string][]string{}
+	complete := func(i int, fileStats *StatsResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i], done[i] = fileStats, true
+		for ; next < len(files) && done[next]; next++ {
+			if results[next] == nil {
+				continue
+			}
+			aggregateStats(&stats, *results[next])
+			for _, suite := range results[next].Suites {
+				fileSuites[files[next]] = append(fileSuites[files[next]], suite.Name)
+			}
+			results[next] = nil
+		}
+	}
+	failed, skipped := 0, 0
+	workers := opts.FileWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(files)); w++ {
 		wg.Add(1)
-		go func(f string) {
+		go func() {
 			defer wg.Done()
-			fileStats, err := processFile(f, args.CountSkippedTests, args.OnlyCritical)
-			if err != nil {
-				logrus.Warnf("Failed to process file %s: %v", f, err)
-				return
+			for i := range jobs {
+				// In fail-fast mode the remaining files are skipped once
+				// the pass threshold is exceeded.
+				if opts.FailFastLimit != nil {
+					mu.Lock()
+					breached := failed > *opts.FailFastLimit
+					if breached {
+						skipped++
+					}
+					mu.Unlock()
+					if breached {
+						progress.fileDone(0)
+						complete(i, nil)
+						continue
+					}
+				}
+				fileStats, ok := processReportFile(files[i], opts)
+				progress.fileDone(fileStats.TotalTests)
+				if !ok {
+					complete(i, nil)
+					continue
+				}
+				mu.Lock()
+				failed += fileStats.FailedTests
+				mu.Unlock()
+				complete(i, &fileStats)
 			}
-			mu.Lock()
-			aggregateStats(&stats, fileStats)
-			mu.Unlock()
-		}(file)
+		}()
+	}
+	for i := range files {
+		jobs <- i
 	}
+	close(jobs)
 	wg.Wait()
 
-	logAggregatedResults(stats)
-	writeTestStats(stats)
+	stats.SkippedFiles += skipped
+	if stats.SkippedFiles > 0 {
+		logrus.Warnf("Fail-fast: %d failed tests exceed the pass threshold (%d), skipped %d of %d report files",
+			stats.FailedTests, *opts.FailFastLimit, stats.SkippedFiles, len(files))
+	}
 
-	// Validate against thresholds
-	if err := validateThresholds(stats, args); err != nil {
-		return err
+	sortAggregated(&stats)
+	sort.Slice(stats.Files, func(i, j int) bool { return stats.Files[i].File < stats.Files[j].File })
+	stats.SplitSuites = findSplitSuites(stats.Files, fileSuites)
+	stats.Shards, stats.ShardImbalance = shardBalance(stats.Files)
+	return stats
+}
+
+// processReportFile computes the statistics of a report file, labeling
+// its per-file results and failures with the file. It reports false when
+// the file could not be processed.
+func processReportFile(f string, opts statsOptions) (StatsResult, bool) {
+	fileStats, err := processFile(f, opts)
+	if err != nil {
+		logrus.Warnf("Failed to process file %s: %v", f, err)
+		return fileStats, false
 	}
+	fileStats.Files = []FileStats{newFileStats(f, fileStats)}
+	for i := range fileStats.FailedTestsDetails {
+		fileStats.FailedTestsDetails[i].File = f
+	}
+	for i := range fileStats.AllowedFailuresDetails {
+		fileStats.AllowedFailuresDetails[i].File = f
+	}
+	for i := range fileStats.Tests {
+		fileStats.Tests[i].File = f
+	}
+	opts.FailureStream.drain(&fileStats)
+	return fileStats, true
+}
 
-	return nil
+// recoverParse turns a panic while processing a report file, e.g. on a
+// corrupted artifact, into a parse error of the file, so one bad report
+// cannot crash the run.
+func recoverParse(filename string, err *error) {
+	if r := recover(); r != nil {
+		logrus.Errorf("Recovered from a panic while processing %s: %v", filename, r)
+		*err = &RunError{Class: ErrorClassParse, File: filename, Err: fmt.Errorf("failed to process %s: %v", filename, r)}
+	}
 }
 
-// locateFiles finds output.xml files matching the given pattern.
-func locateFiles(directory, fileName string) ([]string, error) {
-	matches, err := filepath.Glob(filepath.Join(directory, fileName))
+// locateFiles finds output.xml files matching the given query.
+func locateFiles(directory string, query fileQuery) ([]string, error) {
+	matches, err := query.match(directory)
 	if err != nil {
-		logrus.WithError(err).WithField("Pattern", fileName).Error("Error occurred while searching for files")
+		logrus.WithError(err).WithField("Pattern", query).Error("Error occurred while searching for files")
 		return nil, fmt.Errorf("failed to search for files: %v", err)
 	}
 
-	logrus.Infof("Found %d files matching the pattern: %s", len(matches), fileName)
+	logrus.Infof("Found %d files matching the pattern: %s", len(matches), query)
 
 	if len(matches) == 0 {
 		return nil, errors.New("no files found matching the report filename pattern")
@@ -117,46 +521,178 @@ func locateFiles(directory, fileName string) ([]string, error) {
 	return validFiles, nil
 }
 
-func processFile(filename string, countSkipped, onlyCritical bool) (StatsResult, error) {
+func processFile(filename string, opts statsOptions) (_ StatsResult, err error) {
+	defer recoverParse(filename, &err)
 	logrus.Infof("Processing file: %s", filename)
+	opts.namespace = suiteNamespace(filename, opts.SuiteNamespace)
 
-	fileContent, err := os.ReadFile(filename)
+	key := opts.Cache.key(filename, opts)
+	if stats, ok := opts.Cache.load(key); ok {
+		logrus.Infof("Using cached statistics of %s", filename)
+		return stats, nil
+	}
+	stats, err := computeFileStats(filename, opts)
+	if err == nil {
+		opts.Cache.store(key, stats)
+	}
+	return stats, err
+}
+
+// computeFileStats computes the statistics of a report file.
+func computeFileStats(filename string, opts statsOptions) (StatsResult, error) {
+	if opts.FastSummary {
+		stats, err := processFileSummary(filename, opts)
+		switch {
+		case errors.Is(err, errNoStatistics):
+			logrus.Warnf("No statistics block in %s, falling back to full parsing", filename)
+		case err != nil && opts.Salvage:
+			logrus.Warnf("Failed to read summary of %s, falling back to salvage parsing", filename)
+		default:
+			return stats, err
+		}
+	}
+
+	robotOutput, err := parseFile(filename, opts)
+	if err != nil || robotOutput == nil {
+		return StatsResult{}, err
+	}
+	return computeStats(*robotOutput, opts), nil
+}
+
+// parseFile reads and unmarshals a report file. It returns a nil output
+// for empty files and reports without tests.
+func parseFile(filename string, opts statsOptions) (*RobotOutput, error) {
+	var size int64
+	if info, err := os.Stat(filename); err == nil {
+		if err := opts.Limits.checkSize(info.Size(), filename); err != nil {
+			return nil, &RunError{Class: ErrorClassParse, File: filename, Err: err}
+		}
+		size = info.Size()
+	}
+
+	// the decoded output copies its strings, so the buffer is reused
+	// once parsing is done
+	buf, err := readFile(filename, size)
 	if err != nil {
 		logrus.Errorf("Error opening file: %s. Error: %v", filename, err)
-		return StatsResult{}, fmt.Errorf("error opening file: %s. Error: %v", filename, err)
+		return nil, &RunError{Class: ErrorClassParse, File: filename, Err: fmt.Errorf("error opening file: %s. Error: %v", filename, err)}
 	}
+	defer putBuffer(buf)
+	robotOutput, err := parseOutput(buf.Bytes(), filename, opts)
+	if err != nil {
+		return nil, &RunError{Class: ErrorClassParse, File: filename, Err: err}
+	}
+	return robotOutput, nil
+}
 
+// parseOutput unmarshals report content. It returns a nil output for
+// empty content and reports without tests. Content exceeding the parsing
+// limits is rejected; with salvage enabled, the complete tests of
+// malformed content are recovered.
+func parseOutput(fileContent []byte, filename string, opts statsOptions) (*RobotOutput, error) {
 	// ✅ Handle empty files properly
 	if len(fileContent) == 0 {
 		logrus.Warnf("Skipping empty file: %s", filename)
-		return StatsResult{}, nil
+		return nil, nil
+	}
+
+	if err := opts.Limits.checkReport(fileContent, filename); err != nil {
+		logrus.Errorf("Rejected report: %v", err)
+		return nil, err
+	}
+	if err := checkSchema(fileContent, filename, opts.SchemaValidation); err != nil {
+		logrus.Errorf("Rejected report %s: %v", filename, err)
+		return nil, err
 	}
 
 	var robotOutput RobotOutput
-	err = xml.Unmarshal(fileContent, &robotOutput)
+	err := newReportDecoder(bytes.NewReader(fileContent)).Decode(&robotOutput)
+	if err != nil && opts.Salvage {
+		robotOutput, err = salvageOutput(fileContent, filename, err)
+	}
 	if err != nil {
 		logrus.Errorf("Failed to parse XML: %v", err)
-		return StatsResult{}, fmt.Errorf("failed to parse output.xml: %v", err)
+		return nil, fmt.Errorf("failed to parse output.xml: %v", err)
 	}
 
 	// ✅ Prevent empty suites from being counted
 	if len(robotOutput.Suite.Tests) == 0 && len(robotOutput.Suite.Suites) == 0 {
 		logrus.Warnf("Skipping suite with no tests: %s", filename)
-		return StatsResult{}, nil
+		return nil, nil
 	}
 
-	return computeStats(robotOutput, onlyCritical, countSkipped), nil
+	return &robotOutput, nil
 }
 
 // validateThresholds checks test results against configured thresholds.
 func validateThresholds(stats StatsResult, args Args) error {
-	if stats.FailedTests > args.PassThreshold {
-		return fmt.Errorf("failed tests count (%d) exceeds the pass threshold (%d)", stats.FailedTests, args.PassThreshold)
+	return gateErrors(evaluateThresholds(stats, args))
+}
+
+// evaluateThresholds evaluates the threshold rules, the suite thresholds,
+// the duration budgets and the gate expression.
+func evaluateThresholds(stats StatsResult, args Args) []GateResult {
+	var gates []GateResult
+	scope := thresholdsScope(args.ThresholdsScope)
+	if scope != thresholdsScopePerFile {
+		gates = thresholdGates("", thresholdRules(args), stats)
+	}
+	if scope != thresholdsScopeAggregate {
+		gates = append(gates, fileThresholdGates(stats, args)...)
+	}
+	gates = append(gates, suiteThresholdGates(args.SuiteThresholds, stats, args)...)
+
+	if args.MaxTestDuration > 0 || len(args.MaxTestDurationByTag) > 0 {
+		n := len(stats.SlowTests)
+		gate := GateResult{Name: "slow_tests", Metric: "slow_tests", Actual: float64(n), Outcome: gatePass}
+		if n > 0 {
+			gate.Message = fmt.Sprintf("%d tests exceeded their duration budget", n)
+			if args.FailOnSlowTests {
+				gate.Outcome = gateFail
+			} else {
+				gate.Outcome = gateWarn
+				logrus.Warnf("Warning: %s", gate.Message)
+			}
+		}
+		gates = append(gates, gate)
 	}
-	if stats.FailedTests > args.UnstableThreshold {
-		logrus.Warnf("Warning: failed tests count (%d) exceeds the unstable threshold (%d)", stats.FailedTests, args.UnstableThreshold)
+
+	if n := stats.UnknownStatusTests; n > 0 || args.FailOnUnknownStatus {
+		gate := GateResult{Name: "unknown_status_tests", Metric: "unknown_status_tests", Actual: float64(n), Outcome: gatePass}
+		if n > 0 {
+			gate.Message = fmt.Sprintf("%d tests have an empty or unknown status", n)
+			if args.FailOnUnknownStatus {
+				gate.Outcome = gateFail
+			} else {
+				gate.Outcome = gateWarn
+				logrus.Warnf("Warning: %s", gate.Message)
+			}
+		}
+		gates = append(gates, gate)
+	}
+
+	if gate, ok := structureGate(stats, args.StrictStructure); ok {
+		if gate.Outcome == gateWarn {
+			logrus.Warnf("Warning: %s", gate.Message)
+		}
+		gates = append(gates, gate)
+	}
+
+	if args.GateExpression != "" {
+		gate := GateResult{Name: "expression", Outcome: gatePass}
+		ok, err := evalGateExpression(args.GateExpression, statsMetrics(stats))
+		switch {
+		case err != nil:
+			gate.Outcome = gateFail
+			gate.Message = fmt.Sprintf("failed to evaluate gate expression: %v", err)
+		case !ok:
+			gate.Outcome = gateFail
+			gate.Message = fmt.Sprintf("gate expression not satisfied: %s", args.GateExpression)
+		}
+		gate.Actual = boolMetric(ok)
+		gates = append(gates, gate)
 	}
-	return nil
+	return gates
 }
 
 // aggregateStats merges statistics from multiple files.
@@ -167,21 +703,84 @@ func aggregateStats(stats *StatsResult, fileStats StatsResult) {
 	stats.PassedTests += fileStats.PassedTests
 	stats.FailedTests += fileStats.FailedTests
 	stats.SkippedTests += fileStats.SkippedTests
+	stats.NotRunTests += fileStats.NotRunTests
+	stats.UnknownStatusTests += fileStats.UnknownStatusTests
+	stats.AtRiskTests += fileStats.AtRiskTests
+	stats.DuplicateTestIDs += fileStats.DuplicateTestIDs
+	stats.EmptyTestNames += fileStats.EmptyTestNames
 	stats.TotalKeywords += fileStats.TotalKeywords
 	stats.PassedKeywords += fileStats.PassedKeywords
 	stats.FailedKeywords += fileStats.FailedKeywords
 	stats.SkippedKeywords += fileStats.SkippedKeywords
+	stats.NotRunKeywords += fileStats.NotRunKeywords
+	stats.TotalIterations += fileStats.TotalIterations
+	stats.FailedIterations += fileStats.FailedIterations
+	stats.Setups = stats.Setups.merge(fileStats.Setups)
+	stats.Teardowns = stats.Teardowns.merge(fileStats.Teardowns)
+	stats.TotalWarnings += fileStats.TotalWarnings
+	stats.AllowedFailures += fileStats.AllowedFailures
+	stats.RobotVersion = mergeVersions(stats.RobotVersion, fileStats.RobotVersion)
+	stats.SchemaVersion = mergeVersions(stats.SchemaVersion, fileStats.SchemaVersion)
+	stats.AllowedFailuresDetails = append(stats.AllowedFailuresDetails, fileStats.AllowedFailuresDetails...)
+	stats.AtRiskTestsDetails = append(stats.AtRiskTestsDetails, fileStats.AtRiskTestsDetails...)
+	if fileStats.QualityWeights != nil {
+		stats.QualityWeights = stats.QualityWeights.add(*fileStats.QualityWeights)
+		score := stats.QualityWeights.Score()
+		stats.QualityScore = &score
+	}
 
 	// Aggregate critical test counts
 	stats.TotalCritical += fileStats.TotalCritical
 	stats.CriticalPassed += fileStats.CriticalPassed
 	stats.CriticalFailed += fileStats.CriticalFailed
 
-	// Merge failed test details
+	// Merge failed test details and suite breakdown
 	stats.FailedTestsDetails = append(stats.FailedTestsDetails, fileStats.FailedTestsDetails...)
+	stats.Suites = append(stats.Suites, fileStats.Suites...)
+	stats.Metadata = mergeMetadata(stats.Metadata, fileStats.Metadata)
+	for category, count := range fileStats.FailureCategories {
+		if stats.FailureCategories == nil {
+			stats.FailureCategories = map[string]int{}
+		}
+		stats.FailureCategories[category] += count
+	}
+	for name, tagStats := range fileStats.Tags {
+		if stats.Tags == nil {
+			stats.Tags = map[string]TagStats{}
+		}
+		total := stats.Tags[name]
+		total.Total += tagStats.Total
+		total.Passed += tagStats.Passed
+		total.Failed += tagStats.Failed
+		total.Skipped += tagStats.Skipped
+		stats.Tags[name] = total
+	}
+	stats.TagStatistics = mergeTagStatistics(stats.TagStatistics, fileStats.TagStatistics)
+	for reason, count := range fileStats.SkipReasons {
+		if stats.SkipReasons == nil {
+			stats.SkipReasons = map[string]int{}
+		}
+		stats.SkipReasons[reason] += count
+	}
+	for library, count := range fileStats.LibraryFailures {
+		if stats.LibraryFailures == nil {
+			stats.LibraryFailures = map[string]int{}
+		}
+		stats.LibraryFailures[library] += count
+	}
+	stats.SlowTests = append(stats.SlowTests, fileStats.SlowTests...)
+	stats.Files = append(stats.Files, fileStats.Files...)
+	stats.Incomplete = stats.Incomplete || fileStats.Incomplete
+	stats.RecoveredTests += fileStats.RecoveredTests
+	stats.SkippedFiles += fileStats.SkippedFiles
+	stats.FlakyTests += fileStats.FlakyTests
+	stats.FlakyTestsDetails = append(stats.FlakyTestsDetails, fileStats.FlakyTestsDetails...)
+	stats.Tests = append(stats.Tests, fileStats.Tests...)
 
 	// Aggregate execution time
 	stats.ExecutionTime += fileStats.ExecutionTime
+	stats.CumulativeTestTime += fileStats.CumulativeTestTime
+	aggregateWallClockTime(stats, fileStats)
 
 	// Compute failure and skipped rates safely (avoid division by zero)
 	if stats.TotalTests > 0 {
@@ -193,27 +792,41 @@ func aggregateStats(stats *StatsResult, fileStats StatsResult) {
 	}
 }
 
-// logAggregatedResults logs a detailed summary of the test execution.
-func logAggregatedResults(stats StatsResult) {
-	logrus.Infof("\n===============================================\n")
-	logrus.Infof("Robot Framework Test Report Summary\n")
-	logrus.Infof("===============================================\n")
-	logrus.Infof("📂 Total Test Suites: %d\n", stats.TotalSuites)
-	logrus.Infof("📄 Total Test Cases: %d\n", stats.TotalTests)
-	logrus.Infof("✅ Passed Tests: %d\n", stats.PassedTests)
-	logrus.Infof("❌ Failed Tests: %d\n", stats.FailedTests)
-	logrus.Infof("⏸ Skipped Tests: %d\n", stats.SkippedTests)
-	logrus.Infof("🔥 Critical Tests: %d\n", stats.TotalCritical)
-	logrus.Infof("✅ Critical Passed: %d\n", stats.CriticalPassed)
-	logrus.Infof("❌ Critical Failed: %d\n", stats.CriticalFailed)
-	logrus.Infof("📌 Total Keywords: %d\n", stats.TotalKeywords)
-	logrus.Infof("✅ Passed Keywords: %d\n", stats.PassedKeywords)
-	logrus.Infof("❌ Failed Keywords: %d\n", stats.FailedKeywords)
-	logrus.Infof("⏸ Skipped Keywords: %d\n", stats.SkippedKeywords)
-	logrus.Infof("📉 Failure Rate: %.2f%%\n", stats.FailureRate)
-	logrus.Infof("📉 Skipped Rate: %.2f%%\n", stats.SkippedRate)
-	logrus.Infof("⏱️ Total Execution Time: %.2f ms\n", stats.ExecutionTime)
-	logrus.Infof("===============================================\n")
+// sortAggregated sorts the lists merged by aggregateStats, once all
+// files are aggregated.
+func sortAggregated(stats *StatsResult) {
+	sortSlowTests(stats.SlowTests)
+	sortTestResults(stats.Tests)
+}
+
+// aggregateWallClockTime merges the wall-clock time of a file. Runs with
+// known start times contribute the span they extend, so parallel runs
+// are not double counted; other runs contribute their full duration.
+func aggregateWallClockTime(stats *StatsResult, fileStats StatsResult) {
+	if fileStats.StartTime.IsZero() {
+		stats.WallClockTime += fileStats.WallClockTime
+		return
+	}
+
+	oldSpan := stats.EndTime.Sub(stats.StartTime)
+	if stats.StartTime.IsZero() || fileStats.StartTime.Before(stats.StartTime) {
+		stats.StartTime = fileStats.StartTime
+	}
+	if stats.EndTime.IsZero() || fileStats.EndTime.After(stats.EndTime) {
+		stats.EndTime = fileStats.EndTime
+	}
+	newSpan := stats.EndTime.Sub(stats.StartTime)
+	stats.WallClockTime += float64((newSpan - oldSpan).Microseconds()) / 1000
+}
+
+// logAggregatedResults logs a detailed summary of the test execution in
+// the summary style and duration unit.
+func logAggregatedResults(stats StatsResult, style, unit string) {
+	logSummary("Robot Framework Test Report Summary", summaryRows(stats, unit), style)
+
+	if stats.Incomplete {
+		logrus.Warnf("%sIncomplete run: %d complete tests recovered from truncated reports\n", styledIcon("⚠️", style), stats.RecoveredTests)
+	}
 
 	// Log failed test details if any
 	if len(stats.FailedTestsDetails) > 0 {
@@ -224,37 +837,143 @@ func logAggregatedResults(stats StatsResult) {
 			logrus.Infof("   Suite: %s\n", test.Suite)
 			logrus.Infof("   Status: %s\n", test.Status)
 			logrus.Infof("   Error Message: %s\n", test.ErrorMessage)
+			if test.Category != "" {
+				logrus.Infof("   Category: %s\n", test.Category)
+			}
+			if streak := failingStreak(test); streak != "" {
+				logrus.Infof("   Failing: %s\n", streak)
+			}
+			if len(test.FailedIterations) > 0 {
+				logrus.Infof("   Failed Iterations: %s\n", strings.Join(test.FailedIterations, "; "))
+			}
+			if test.Source != "" {
+				logrus.Infof("   Source: %s\n", sourceLocation(test.Source, test.Line))
+			}
+			if test.URL != "" {
+				logrus.Infof("   Link: %s\n", test.URL)
+			}
+			logrus.Infof("-----------------------------------------------\n")
+		}
+		if stats.OmittedFailureDetails > 0 {
+			logrus.Infof("... and %d more failed tests\n", stats.OmittedFailureDetails)
+		}
+	}
+	logFailureCategories(stats)
+
+	// Log failures of allow-failure tests if any
+	if len(stats.AllowedFailuresDetails) > 0 {
+		logrus.Infof("Allowed Failure Details:\n")
+		logrus.Infof("-----------------------------------------------\n")
+		for i, test := range stats.AllowedFailuresDetails {
+			logrus.Infof("%d. Test Name: %s\n", i+1, test.Name)
+			logrus.Infof("   Suite: %s\n", test.Suite)
+			logrus.Infof("   Error Message: %s\n", test.ErrorMessage)
+			logrus.Infof("-----------------------------------------------\n")
+		}
+	}
+	logAtRiskTests(stats)
+	logSkipReasons(stats)
+	logLibraryFailures(stats)
+
+	// Log tests that failed and passed on rerun if any
+	if len(stats.FlakyTestsDetails) > 0 {
+		logrus.Infof("Flaky Test Details:\n")
+		logrus.Infof("-----------------------------------------------\n")
+		for i, test := range stats.FlakyTestsDetails {
+			logrus.Infof("%d. Test Name: %s\n", i+1, test.Name)
+			logrus.Infof("   Suite: %s\n", test.Suite)
+			logrus.Infof("-----------------------------------------------\n")
+		}
+	}
+
+	// Log tests exceeding their duration budget if any
+	if len(stats.SlowTests) > 0 {
+		logrus.Infof("Slow Test Details:\n")
+		logrus.Infof("-----------------------------------------------\n")
+		for i, test := range stats.SlowTests {
+			logrus.Infof("%d. Test Name: %s\n", i+1, test.Name)
+			logrus.Infof("   Suite: %s\n", test.Suite)
+			logrus.Infof("   Status: %s\n", test.Status)
+			logrus.Infof("   Duration: %s (budget %s)\n", formatDuration(test.Duration, unit), formatDuration(test.Budget, unit))
 			logrus.Infof("-----------------------------------------------\n")
 		}
 	}
 }
 
-// writeTestStats writes test statistics to DRONE_OUTPUT.
-func writeTestStats(stats StatsResult) {
+// writeTestStats sets the test statistics as output variables.
+func writeTestStats(out *outputVars, stats StatsResult) {
 	statsMap := map[string]string{
-		"TOTAL_TESTS":      strconv.Itoa(stats.TotalTests),
-		"PASSED_TESTS":     strconv.Itoa(stats.PassedTests),
-		"FAILED_TESTS":     strconv.Itoa(stats.FailedTests),
-		"SKIPPED_TESTS":    strconv.Itoa(stats.SkippedTests),
-		"TOTAL_KEYWORDS":   strconv.Itoa(stats.TotalKeywords),
-		"PASSED_KEYWORDS":  strconv.Itoa(stats.PassedKeywords),
-		"FAILED_KEYWORDS":  strconv.Itoa(stats.FailedKeywords),
-		"SKIPPED_KEYWORDS": strconv.Itoa(stats.SkippedKeywords),
-		"TOTAL_CRITICAL":   strconv.Itoa(stats.TotalCritical),
-		"CRITICAL_PASSED":  strconv.Itoa(stats.CriticalPassed),
-		"CRITICAL_FAILED":  strconv.Itoa(stats.CriticalFailed),
-		"FAILURE_RATE":     fmt.Sprintf("%.2f", stats.FailureRate),
-		"SKIPPED_RATE":     fmt.Sprintf("%.2f", stats.SkippedRate),
-	}
-
-	for key, value := range statsMap {
-		WriteEnvToFile(key, value)
+		"TOTAL_TESTS":          strconv.Itoa(stats.TotalTests),
+		"PASSED_TESTS":         strconv.Itoa(stats.PassedTests),
+		"FAILED_TESTS":         strconv.Itoa(stats.FailedTests),
+		"SKIPPED_TESTS":        strconv.Itoa(stats.SkippedTests),
+		"TOTAL_KEYWORDS":       strconv.Itoa(stats.TotalKeywords),
+		"PASSED_KEYWORDS":      strconv.Itoa(stats.PassedKeywords),
+		"FAILED_KEYWORDS":      strconv.Itoa(stats.FailedKeywords),
+		"SKIPPED_KEYWORDS":     strconv.Itoa(stats.SkippedKeywords),
+		"NOT_RUN_TESTS":        strconv.Itoa(stats.NotRunTests),
+		"UNKNOWN_STATUS_TESTS": strconv.Itoa(stats.UnknownStatusTests),
+		"AT_RISK_TESTS":        strconv.Itoa(stats.AtRiskTests),
+		"DUPLICATE_TEST_IDS":   strconv.Itoa(stats.DuplicateTestIDs),
+		"EMPTY_TEST_NAMES":     strconv.Itoa(stats.EmptyTestNames),
+		"NOT_RUN_KEYWORDS":     strconv.Itoa(stats.NotRunKeywords),
+		"TOTAL_ITERATIONS":     strconv.Itoa(stats.TotalIterations),
+		"FAILED_ITERATIONS":    strconv.Itoa(stats.FailedIterations),
+		"TOTAL_SETUPS":         strconv.Itoa(stats.Setups.Total),
+		"FAILED_SETUPS":        strconv.Itoa(stats.Setups.Failed),
+		"TOTAL_TEARDOWNS":      strconv.Itoa(stats.Teardowns.Total),
+		"FAILED_TEARDOWNS":     strconv.Itoa(stats.Teardowns.Failed),
+		"TOTAL_WARNINGS":       strconv.Itoa(stats.TotalWarnings),
+		"ALLOWED_FAILURES":     strconv.Itoa(stats.AllowedFailures),
+		"TOTAL_CRITICAL":       strconv.Itoa(stats.TotalCritical),
+		"CRITICAL_PASSED":      strconv.Itoa(stats.CriticalPassed),
+		"CRITICAL_FAILED":      strconv.Itoa(stats.CriticalFailed),
+		"FAILURE_RATE":         out.decimal(stats.FailureRate),
+		"SKIPPED_RATE":         out.decimal(stats.SkippedRate),
+		"WALL_CLOCK_TIME":      out.duration(stats.WallClockTime),
+		"CUMULATIVE_TEST_TIME": out.duration(stats.CumulativeTestTime),
+		"FLAKY_TESTS":          strconv.Itoa(stats.FlakyTests),
+		"RUN_INCOMPLETE":       strconv.FormatBool(stats.Incomplete),
+	}
+
+	if stats.QualityScore != nil {
+		statsMap["QUALITY_SCORE"] = out.decimal(*stats.QualityScore)
+	}
+
+	if len(stats.Shards) > 0 {
+		statsMap["SHARD_IMBALANCE"] = out.decimal(stats.ShardImbalance)
+	}
+
+	if stats.RobotVersion != "" {
+		statsMap["ROBOT_VERSION"] = stats.RobotVersion
+	}
+	if stats.SchemaVersion != "" {
+		statsMap["ROBOT_SCHEMA_VERSION"] = stats.SchemaVersion
+	}
+
+	for category, count := range stats.FailureCategories {
+		statsMap["FAILURE_CATEGORY_"+outputName(category)] = strconv.Itoa(count)
+	}
+
+	if len(stats.FailedTestsDetails) > 0 {
+		if names, err := jsonEnvValue(failedTestNames(stats)); err == nil {
+			statsMap["FAILED_TEST_NAMES"] = names
+		}
+	}
+
+	keys := make([]string, 0, len(statsMap))
+	for key := range statsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		out.set(key, statsMap[key])
 	}
 }
 
-// WriteEnvToFile writes a key-value pair to DRONE_OUTPUT.
-func WriteEnvToFile(key, value string) {
-	outputFile, _ := os.OpenFile(os.Getenv("DRONE_OUTPUT"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	defer outputFile.Close()
-	outputFile.WriteString(key + "=" + value + "\n")
+// outputName converts a name to an output variable name segment.
+func outputName(name string) string {
+	return strings.ToUpper(nonAlphanumeric.ReplaceAllString(strings.TrimSpace(name), "_"))
 }
+
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
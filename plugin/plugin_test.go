@@ -2,10 +2,14 @@ package plugin
 
 import (
 	"context"
+	"fmt"
 	"math"
+	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -23,8 +27,8 @@ func TestValidateInputs(t *testing.T) {
 			args: Args{
 				ReportDirectory:       "./testdata",
 				ReportFileNamePattern: "robot_report.xml",
-				PassThreshold:         5,
-				UnstableThreshold:     10,
+				PassThreshold:         10,
+				UnstableThreshold:     5,
 			},
 			expectErr: false,
 		},
@@ -46,6 +50,25 @@ func TestValidateInputs(t *testing.T) {
 			expectErr: true,
 			errMsg:    "threshold values must be non-negative",
 		},
+		{
+			name: "Unstable Threshold Above Pass Threshold",
+			args: Args{
+				ReportDirectory:   "./testdata",
+				PassThreshold:     5,
+				UnstableThreshold: 10,
+			},
+			expectErr: true,
+			errMsg:    "PLUGIN_UNSTABLE_THRESHOLD: unstable threshold (10) must not exceed PLUGIN_PASS_THRESHOLD (5)",
+		},
+		{
+			name: "Slow Test Failure Without Budget",
+			args: Args{
+				ReportDirectory: "./testdata",
+				FailOnSlowTests: true,
+			},
+			expectErr: true,
+			errMsg:    "PLUGIN_FAIL_ON_SLOW_TESTS: requires PLUGIN_MAX_TEST_DURATION",
+		},
 	}
 
 	for _, tc := range tests {
@@ -103,7 +126,7 @@ func TestLocateFiles(t *testing.T) {
 			}
 			t.Logf("Checking directory: %s", absPath)
 
-			files, err := locateFiles(tc.directory, tc.outputFile)
+			files, err := locateFiles(tc.directory, fileQuery{Pattern: tc.outputFile})
 			if tc.expectedErr {
 				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
 					t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
@@ -129,27 +152,58 @@ func TestProcessFile(t *testing.T) {
 			name:     "Valid Robot Framework XML Report",
 			filePath: "../testdata/robot_report.xml",
 			expected: StatsResult{
-				TotalSuites:     1,
-				TotalTests:      2,
-				PassedTests:     1,
-				FailedTests:     1,
-				SkippedTests:    0,
-				TotalKeywords:   2,
-				PassedKeywords:  1,
-				FailedKeywords:  1,
-				SkippedKeywords: 0,
-				TotalCritical:   2,
-				CriticalPassed:  1,
-				CriticalFailed:  1,
-				FailureRate:     50.00,
-				SkippedRate:     0.00,
-				ExecutionTime:   10606,
+				RobotVersion:       "6.0",
+				TotalSuites:        1,
+				TotalTests:         2,
+				PassedTests:        1,
+				FailedTests:        1,
+				SkippedTests:       0,
+				TotalKeywords:      2,
+				PassedKeywords:     1,
+				FailedKeywords:     1,
+				SkippedKeywords:    0,
+				Setups:             KeywordStats{Total: 1, Passed: 1},
+				Teardowns:          KeywordStats{Total: 1, Passed: 1},
+				LibraryFailures:    map[string]int{"BuiltIn": 1},
+				TotalCritical:      2,
+				CriticalPassed:     1,
+				CriticalFailed:     1,
+				FailureRate:        50.00,
+				SkippedRate:        0.00,
+				ExecutionTime:      10606,
+				WallClockTime:      10400,
+				CumulativeTestTime: 206,
+				StartTime:          time.Date(2025, 2, 9, 15, 30, 0, 300000000, time.UTC),
+				EndTime:            time.Date(2025, 2, 9, 15, 30, 10, 700000000, time.UTC),
 				FailedTestsDetails: []FailedTestDetails{
 					{
 						Name:         "Test Case 2 - Critical Fail",
 						Suite:        "Advanced Test Suite",
 						Status:       "FAIL",
+						Duration:     202,
+						LongSuite:    "Advanced Test Suite",
+						ErrorMessage: "Critical test failed: Major issue detected",
+						Source:       `C:\Users\JohnDoe\Documents\RobotFW\advanced_suite.robot`,
+					},
+				},
+				Suites: []SuiteStats{
+					{Name: "Advanced Test Suite", ExecutionTime: 10400},
+				},
+				Tests: []TestResult{
+					{
+						Name:     "Test Case 1 - Critical Pass",
+						Suite:    "Advanced Test Suite",
+						Status:   "PASS",
+						Duration: 4,
+						Start:    timePtr(time.Date(2025, 2, 9, 15, 30, 4, 999000000, time.UTC)),
+					},
+					{
+						Name:         "Test Case 2 - Critical Fail",
+						Suite:        "Advanced Test Suite",
+						Status:       "FAIL",
+						Duration:     202,
 						ErrorMessage: "Critical test failed: Major issue detected",
+						Start:        timePtr(time.Date(2025, 2, 9, 15, 30, 6, 0, time.UTC)),
 					},
 				},
 			},
@@ -163,7 +217,7 @@ func TestProcessFile(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := processFile(tc.filePath, true, true)
+			result, err := processFile(tc.filePath, statsOptions{CountSkipped: true, OnlyCritical: true})
 			if tc.expectErr {
 				if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
 					t.Errorf("Expected error '%s', but got %v", tc.errMsg, err)
@@ -251,6 +305,48 @@ func TestValidateThresholds(t *testing.T) {
 			expectErr: true,
 			errMsg:    "failed tests count (6) exceeds the pass threshold (5)",
 		},
+		{
+			name: "Failure Rate Exceeds Warn Limit Only",
+			results: StatsResult{
+				TotalTests:  10,
+				FailedTests: 1,
+				FailureRate: 10,
+			},
+			args: Args{
+				PassThreshold:   5,
+				FailureRateWarn: floatPtr(5),
+				FailureRateFail: floatPtr(20),
+			},
+			expectErr: false,
+		},
+		{
+			name: "Critical Failures Exceed Fail Limit",
+			results: StatsResult{
+				TotalTests:     10,
+				FailedTests:    2,
+				CriticalFailed: 2,
+			},
+			args: Args{
+				PassThreshold:      5,
+				CriticalFailedFail: floatPtr(0),
+			},
+			expectErr: true,
+			errMsg:    "critical failed tests count (2) exceeds the fail threshold (0)",
+		},
+		{
+			name: "Skipped Rate Exceeds Max Skipped Rate",
+			results: StatsResult{
+				TotalTests:   10,
+				SkippedTests: 4,
+				SkippedRate:  40,
+			},
+			args: Args{
+				PassThreshold:  5,
+				MaxSkippedRate: floatPtr(25),
+			},
+			expectErr: true,
+			errMsg:    "skipped rate (40) exceeds the fail threshold (25)",
+		},
 	}
 
 	for _, tc := range tests {
@@ -379,7 +475,7 @@ func TestComputeStats(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			stats := computeStats(tc.robotOutput, tc.onlyCritical, tc.countSkipped)
+			stats := computeStats(tc.robotOutput, statsOptions{OnlyCritical: tc.onlyCritical, CountSkipped: tc.countSkipped})
 
 			// Validate results
 			if stats.TotalTests != tc.expectedStats.TotalTests {
@@ -419,3 +515,308 @@ func TestComputeStats(t *testing.T) {
 func almostEqual(a, b, epsilon float64) bool {
 	return math.Abs(a-b) <= epsilon
 }
+
+// Helper function to create float pointers for optional thresholds
+func timePtr(v time.Time) *time.Time {
+	return &v
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// TestTestDurationBudget validates detection of tests exceeding their duration budget
+func TestTestDurationBudget(t *testing.T) {
+	robotOutput := RobotOutput{
+		Suite: Suite{
+			Name: "Suite",
+			Tests: []Test{
+				{Name: "Fast", Status: Status{Status: "PASS", StartTime: "20250209 15:30:00.000", EndTime: "20250209 15:30:00.500"}},
+				{Name: "Slow", Status: Status{Status: "PASS", StartTime: "20250209 15:30:00.000", EndTime: "20250209 15:30:02.000"}},
+				{Name: "Slow But Tagged", TagList: []string{"Long Running"}, Status: Status{Status: "FAIL", StartTime: "20250209 15:30:00.000", EndTime: "20250209 15:30:03.000"}},
+				{Name: "Too Slow Even If Tagged", Tags: []string{"long_running"}, Status: Status{Status: "PASS", StartTime: "20250209 15:30:00.000", EndTime: "20250209 15:30:06.000"}},
+			},
+		},
+	}
+
+	opts, err := newStatsOptions(Args{
+		MaxTestDuration:      1000,
+		MaxTestDurationByTag: map[string]float64{"longrunning": 5000},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(robotOutput, opts)
+
+	expected := []SlowTestDetails{
+		{Name: "Too Slow Even If Tagged", Suite: "Suite", Status: "PASS", Duration: 6000, Budget: 5000},
+		{Name: "Slow", Suite: "Suite", Status: "PASS", Duration: 2000, Budget: 1000},
+	}
+	if diff := cmp.Diff(expected, stats.SlowTests); diff != "" {
+		t.Errorf("Slow tests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestTreatSkippedAsFailed validates that skipped tests can be counted as failures
+func TestTreatSkippedAsFailed(t *testing.T) {
+	robotOutput := RobotOutput{
+		Suite: Suite{
+			Name: "Suite",
+			Tests: []Test{
+				{Name: "Test 1", Status: Status{Status: "PASS"}},
+				{Name: "Test 2", Status: Status{Status: "SKIP"}},
+			},
+		},
+	}
+
+	opts, err := newStatsOptions(Args{TreatSkippedAsFailed: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(robotOutput, opts)
+	if stats.FailedTests != 1 || stats.SkippedTests != 0 {
+		t.Errorf("Expected 1 failed and 0 skipped tests, got %d failed and %d skipped", stats.FailedTests, stats.SkippedTests)
+	}
+	if len(stats.FailedTestsDetails) != 1 || stats.FailedTestsDetails[0].Status != "SKIP" {
+		t.Errorf("Expected skipped test in failure details, got %+v", stats.FailedTestsDetails)
+	}
+}
+
+// TestProcessFileRobot7 validates parsing of RF 7 outputs with ISO-8601 timestamps and elapsed times
+func TestProcessFileRobot7(t *testing.T) {
+	result, err := processFile("../testdata/rf7/output.xml", statsOptions{CountSkipped: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := StatsResult{
+		RobotVersion:       "7.0",
+		SchemaVersion:      "5",
+		TotalSuites:        2,
+		TotalTests:         2,
+		PassedTests:        1,
+		FailedTests:        1,
+		TotalKeywords:      2,
+		PassedKeywords:     1,
+		FailedKeywords:     1,
+		LibraryFailures:    map[string]int{"BuiltIn": 1},
+		TotalCritical:      2,
+		CriticalPassed:     1,
+		CriticalFailed:     1,
+		FailureRate:        50,
+		ExecutionTime:      7950.5,
+		WallClockTime:      3000,
+		CumulativeTestTime: 2250.5,
+		StartTime:          time.Date(2025, 2, 9, 15, 30, 0, 500000000, time.UTC),
+		EndTime:            time.Date(2025, 2, 9, 15, 30, 3, 500000000, time.UTC),
+		FailedTestsDetails: []FailedTestDetails{
+			{Name: "Pay With Expired Card", Suite: "Payment", Status: "FAIL", Duration: 750.5, LongSuite: "Checkout.Payment", ErrorMessage: "expired != valid", Source: "/work/tests/checkout/payment.robot", Line: 10},
+		},
+		Suites: []SuiteStats{
+			{Name: "Checkout", ExecutionTime: 3000},
+			{Name: "Checkout.Payment", ExecutionTime: 2700},
+		},
+		Tests: []TestResult{
+			{
+				Name:     "Pay With Card",
+				Suite:    "Checkout.Payment",
+				Status:   "PASS",
+				Duration: 1500,
+				Start:    timePtr(time.Date(2025, 2, 9, 15, 30, 0, 900000000, time.UTC)),
+			},
+			{
+				Name:         "Pay With Expired Card",
+				Suite:        "Checkout.Payment",
+				Status:       "FAIL",
+				Duration:     750.5,
+				ErrorMessage: "expired != valid",
+				Start:        timePtr(time.Date(2025, 2, 9, 15, 30, 2, 500000000, time.UTC)),
+			},
+		},
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Results mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestParseRobotTime validates parsing of legacy and ISO-8601 timestamps
+func TestParseRobotTime(t *testing.T) {
+	for _, timestamp := range []string{"20250209 15:30:00.123", "2025-02-09T15:30:00.123000", "2025-02-09T15:30:00.123"} {
+		got, err := parseRobotTime(timestamp)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", timestamp, err)
+			continue
+		}
+		if got.Nanosecond() != 123000000 || got.Second() != 0 || got.Minute() != 30 {
+			t.Errorf("Unexpected time for %q: %v", timestamp, got)
+		}
+	}
+}
+
+// TestAggregateWallClockTime validates that parallel runs are not double counted
+func TestAggregateWallClockTime(t *testing.T) {
+	start := time.Date(2025, 2, 9, 15, 30, 0, 0, time.UTC)
+	stats := StatsResult{}
+	aggregateStats(&stats, StatsResult{WallClockTime: 60000, StartTime: start, EndTime: start.Add(time.Minute)})
+	aggregateStats(&stats, StatsResult{WallClockTime: 90000, StartTime: start.Add(30 * time.Second), EndTime: start.Add(2 * time.Minute)})
+	aggregateStats(&stats, StatsResult{WallClockTime: 1000})
+
+	if stats.WallClockTime != 121000 {
+		t.Errorf("Expected WallClockTime 121000, got %.2f", stats.WallClockTime)
+	}
+}
+
+// TestNotRunTests validates counting of NOT RUN tests and keywords
+func TestNotRunTests(t *testing.T) {
+	report := `<robot generator="Robot 7.0">
+<suite id="s1" name="Dry Run">
+<test id="s1-t1" name="Passed"><kw name="Log"><status status="PASS"/></kw><status status="PASS"/></test>
+<test id="s1-t2" name="Not Run"><kw name="Log"><status status="NOT RUN"/></kw><status status="NOT RUN"/></test>
+</suite>
+</robot>`
+	robotOutput, err := parseOutput([]byte(report), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		notRunAs                string
+		passed, failed, skipped int
+	}{
+		{notRunAs: "", passed: 1},
+		{notRunAs: "skip", passed: 1, skipped: 1},
+		{notRunAs: "fail", passed: 1, failed: 1},
+	}
+	for _, tt := range tests {
+		opts, err := newStatsOptions(Args{NotRunAs: tt.notRunAs, CountSkippedTests: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		stats := computeStats(*robotOutput, opts)
+		if stats.NotRunTests != 1 || stats.NotRunKeywords != 1 {
+			t.Errorf("%q: expected 1 not run test and keyword, got %d and %d", tt.notRunAs, stats.NotRunTests, stats.NotRunKeywords)
+		}
+		if stats.TotalTests != 2 || stats.PassedTests != tt.passed || stats.FailedTests != tt.failed || stats.SkippedTests != tt.skipped {
+			t.Errorf("%q: unexpected counts total=%d passed=%d failed=%d skipped=%d",
+				tt.notRunAs, stats.TotalTests, stats.PassedTests, stats.FailedTests, stats.SkippedTests)
+		}
+	}
+}
+
+// TestAllowFailureTag validates that failures of allow-failure tests are
+// reported without counting toward thresholds
+func TestAllowFailureTag(t *testing.T) {
+	report := `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Stable"><status status="PASS"/></test>
+<test id="s1-t2" name="Known Broken"><tag>allow-failure</tag><status status="FAIL">Not implemented yet</status></test>
+<test id="s1-t3" name="Broken"><status status="FAIL">Assertion failed</status></test>
+</suite>
+</robot>`
+	robotOutput, err := parseOutput([]byte(report), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	args := Args{AllowFailureTag: "allow-failure", PassThreshold: 1, UnstableThreshold: 1}
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(*robotOutput, opts)
+
+	if stats.TotalTests != 3 || stats.PassedTests != 1 || stats.FailedTests != 1 || stats.AllowedFailures != 1 {
+		t.Errorf("Unexpected counts total=%d passed=%d failed=%d allowed=%d",
+			stats.TotalTests, stats.PassedTests, stats.FailedTests, stats.AllowedFailures)
+	}
+	if len(stats.AllowedFailuresDetails) != 1 || stats.AllowedFailuresDetails[0].Name != "Known Broken" {
+		t.Errorf("Expected Known Broken in allowed failure details, got %+v", stats.AllowedFailuresDetails)
+	}
+	if err := validateThresholds(stats, args); err != nil {
+		t.Errorf("Expected allowed failures not to count toward thresholds, got %v", err)
+	}
+}
+
+// TestFailFast validates that the remaining report files are skipped once
+// the failed tests exceed the pass threshold
+func TestFailFast(t *testing.T) {
+	report, err := os.ReadFile("../testdata/robot_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var files []string
+	for i := 0; i < runtime.NumCPU()+3; i++ {
+		file := filepath.Join(dir, fmt.Sprintf("output-%d.xml", i))
+		if err := os.WriteFile(file, report, 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, file)
+	}
+
+	opts, err := newStatsOptions(Args{FailFast: true, PassThreshold: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := processFiles(files, opts)
+	if stats.SkippedFiles != 3 || len(stats.Files) != runtime.NumCPU() {
+		t.Errorf("Expected 3 skipped files and %d processed files, got %d skipped and %d processed", runtime.NumCPU(), stats.SkippedFiles, len(stats.Files))
+	}
+
+	opts, _ = newStatsOptions(Args{FailFast: true, PassThreshold: 1, FileWorkers: 1})
+	if stats := processFiles(files, opts); stats.SkippedFiles != len(files)-1 {
+		t.Errorf("Expected every file after the first to be skipped with one worker, got %d skipped", stats.SkippedFiles)
+	}
+
+	opts, _ = newStatsOptions(Args{})
+	if stats := processFiles(files, opts); stats.SkippedFiles != 0 || len(stats.Files) != len(files) {
+		t.Errorf("Expected every file to be processed without fail-fast, got %d skipped", stats.SkippedFiles)
+	}
+}
+
+// TestFileWorkers validates that the statistics do not depend on the
+// number of file workers
+func TestFileWorkers(t *testing.T) {
+	files := []string{"../testdata/robot_report.xml", "../testdata/rf7/output.xml", "../testdata/robot_report.xml"}
+	opts, err := newStatsOptions(Args{FileWorkers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sequential := processFiles(files, opts)
+
+	opts.FileWorkers = len(files)
+	for i := 0; i < 5; i++ {
+		if diff := cmp.Diff(sequential, processFiles(files, opts)); diff != "" {
+			t.Fatalf("Results mismatch between 1 and %d workers (-want +got):\n%s", len(files), diff)
+		}
+	}
+
+	var failed []string
+	for _, details := range sequential.FailedTestsDetails {
+		failed = append(failed, details.File)
+	}
+	want := []string{files[0], files[0], files[1], files[2], files[2]}
+	if diff := cmp.Diff(want, failed); diff != "" {
+		t.Errorf("Expected failures in file order (-want +got):\n%s", diff)
+	}
+}
+
+// TestAnalyzeTestResults validates that the per-test results are only
+// kept when a setting uses them
+func TestAnalyzeTestResults(t *testing.T) {
+	args := Args{ReportDirectory: "../testdata", ReportFileNamePattern: "robot_report.xml"}
+	stats, err := Analyze(context.Background(), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Tests != nil || stats.TotalTests == 0 {
+		t.Errorf("Expected counts without per-test results, got %d results of %d tests", len(stats.Tests), stats.TotalTests)
+	}
+
+	args.StatsFile = filepath.Join(t.TempDir(), "stats.json")
+	if stats, err = Analyze(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Tests) != stats.TotalTests {
+		t.Errorf("Expected a result of every test for the stats file, got %d of %d", len(stats.Tests), stats.TotalTests)
+	}
+}
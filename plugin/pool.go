@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// readerBufferSize is the buffer size of the pooled report readers.
+	readerBufferSize = 64 << 10
+
+	// maxPooledBuffer is the capacity above which a read buffer is
+	// released instead of pooled, so one large report does not stay in
+	// memory for the rest of the run.
+	maxPooledBuffer = 64 << 20
+)
+
+// readerPool holds buffered readers reused across streamed reports.
+var readerPool = sync.Pool{
+	New: func() any { return bufio.NewReaderSize(nil, readerBufferSize) },
+}
+
+// bufferPool holds the buffers reused to read whole reports.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getReader returns a pooled buffered reader reading from r.
+func getReader(r io.Reader) *bufio.Reader {
+	br := readerPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return br
+}
+
+// putReader returns the reader to the pool.
+func putReader(br *bufio.Reader) {
+	br.Reset(nil)
+	readerPool.Put(br)
+}
+
+// readFile reads the file into a pooled buffer sized from the file size.
+// The caller returns the buffer with putBuffer once the content is no
+// longer referenced.
+func readFile(filename string, size int64) (*bytes.Buffer, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if size > 0 {
+		buf.Grow(int(size) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(file); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// putBuffer returns the buffer to the pool unless it grew too large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
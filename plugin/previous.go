@@ -0,0 +1,178 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// previousBuildTimeout limits each call to the CI API and the artifact
+// download.
+const previousBuildTimeout = 30 * time.Second
+
+// apiBuild is a build of the Drone builds list API.
+type apiBuild struct {
+	Number int64  `json:"number"`
+	Status string `json:"status"`
+	Event  string `json:"event"`
+	Target string `json:"target"` // target branch
+}
+
+// previousBuildServer returns the CI API server: the setting, or the
+// Drone server the build runs on.
+func previousBuildServer(args Args, getenv func(string) string) string {
+	if args.PreviousBuildServer != "" {
+		return strings.TrimRight(args.PreviousBuildServer, "/")
+	}
+	if host := getenv("DRONE_SYSTEM_HOST"); host != "" {
+		return firstNonEmpty(getenv("DRONE_SYSTEM_PROTO"), "https") + "://" + host
+	}
+	return ""
+}
+
+// previousBuild returns the number of the last successful build on the
+// branch before the current build.
+func previousBuild(ctx context.Context, server, token, repo, branch string, current int64) (int64, error) {
+	for page := 1; page <= 5; page++ {
+		u := fmt.Sprintf("%s/api/repos/%s/builds?page=%d", server, repo, page)
+		var builds []apiBuild
+		if err := getJSON(ctx, u, token, &builds); err != nil {
+			return 0, err
+		}
+		if len(builds) == 0 {
+			break
+		}
+		for _, build := range builds {
+			if build.Status == "success" && build.Target == branch && build.Event != "pull_request" &&
+				(current == 0 || build.Number < current) {
+				return build.Number, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no successful build on branch %s found", branch)
+}
+
+// fetchPreviousStats downloads the stats JSON artifact of the last
+// successful build on the branch. The artifact URL template placeholders
+// {repo}, {branch} and {build} are replaced with the build values.
+func fetchPreviousStats(ctx context.Context, args Args, getenv func(string) string) (StatsResult, error) {
+	server := previousBuildServer(args, getenv)
+	if server == "" {
+		return StatsResult{}, fmt.Errorf("unknown CI server, set PLUGIN_PREVIOUS_BUILD_SERVER")
+	}
+	build := newBuildInfo(nil, getenv)
+	if build == nil || build.Repo == "" {
+		return StatsResult{}, fmt.Errorf("unknown repository, DRONE_REPO is not set")
+	}
+	current, _ := strconv.ParseInt(build.Number, 10, 64)
+	number, err := previousBuild(ctx, server, args.PreviousBuildToken, build.Repo, build.Branch, current)
+	if err != nil {
+		return StatsResult{}, err
+	}
+
+	artifact := strings.NewReplacer(
+		"{repo}", build.Repo,
+		"{branch}", url.PathEscape(build.Branch),
+		"{build}", strconv.FormatInt(number, 10),
+	).Replace(args.PreviousStatsURL)
+	// only send the API token to the CI server
+	token := ""
+	if sameHost(artifact, server) {
+		token = args.PreviousBuildToken
+	}
+	logrus.Infof("Comparing against build %d", number)
+	var stats StatsResult
+	if err := getJSON(ctx, artifact, token, &stats); err != nil {
+		return StatsResult{}, fmt.Errorf("failed to download the stats of build %d: %v", number, err)
+	}
+	return stats, nil
+}
+
+// getJSON decodes the JSON response of the URL, authenticated with the
+// bearer token when set.
+func getJSON(ctx context.Context, u, token string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, previousBuildTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("GET %s responded with %s", redactedURL(req.URL), resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("invalid response of %s: %v", redactedURL(req.URL), err)
+	}
+	return nil
+}
+
+// sameHost reports whether both URLs have the same host.
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host)
+}
+
+// loadPreviousStats returns the statistics the run is compared against:
+// the PLUGIN_COMPARE_TO result set, the artifact of the previous build or
+// the baseline run of the history. It returns false when there is none.
+func loadPreviousStats(ctx context.Context, args Args, history *historyStore, stats StatsResult) (StatsResult, bool, error) {
+	switch {
+	case args.CompareTo != "":
+		previous, err := LoadResultSet(args.CompareTo, args)
+		if err != nil {
+			return StatsResult{}, false, fmt.Errorf("failed to load comparison results: %v", err)
+		}
+		return previous, true, nil
+	case args.PreviousStatsURL != "":
+		previous, err := fetchPreviousStats(ctx, args, os.Getenv)
+		if err != nil {
+			return StatsResult{}, false, fmt.Errorf("failed to fetch the previous build results: %v", err)
+		}
+		return previous, true, nil
+	}
+	records, err := history.runs(ctx, stats)
+	if err != nil {
+		return StatsResult{}, false, fmt.Errorf("failed to read history: %v", err)
+	}
+	record, ok, err := historyBaseline(records, stats.Build, args.BaselineBuild)
+	if err != nil || !ok {
+		return StatsResult{}, false, err
+	}
+	return baselineStats(record, stats), true, nil
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
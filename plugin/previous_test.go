@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestFetchPreviousStats validates finding the previous build through the
+// Drone API and downloading its stats artifact
+func TestFetchPreviousStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer api-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/repos/octocat/hello-world/builds":
+			builds := []apiBuild{
+				{Number: 42, Status: "running", Target: "main", Event: "push"},
+				{Number: 41, Status: "success", Target: "main", Event: "pull_request"},
+				{Number: 40, Status: "failure", Target: "main", Event: "push"},
+				{Number: 39, Status: "success", Target: "feature", Event: "push"},
+				{Number: 38, Status: "success", Target: "main", Event: "push"},
+			}
+			if r.URL.Query().Get("page") != "1" {
+				builds = nil
+			}
+			json.NewEncoder(w).Encode(builds)
+		case "/artifacts/octocat/hello-world/main/38/robot-stats.json":
+			json.NewEncoder(w).Encode(StatsResult{TotalTests: 38})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	env := map[string]string{
+		"DRONE_REPO":         "octocat/hello-world",
+		"DRONE_BRANCH":       "main",
+		"DRONE_BUILD_NUMBER": "42",
+		"DRONE_SYSTEM_PROTO": "http",
+		"DRONE_SYSTEM_HOST":  strings.TrimPrefix(server.URL, "http://"),
+	}
+	args := Args{
+		PreviousStatsURL:   server.URL + "/artifacts/{repo}/{branch}/{build}/robot-stats.json",
+		PreviousBuildToken: "api-token",
+	}
+	stats, err := fetchPreviousStats(context.Background(), args, func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.TotalTests != 38 {
+		t.Errorf("Expected the stats of build 38, got %d tests", stats.TotalTests)
+	}
+
+	env["DRONE_BRANCH"] = "release"
+	if _, err := fetchPreviousStats(context.Background(), args, func(name string) string { return env[name] }); err == nil {
+		t.Error("Expected an error without a successful build on the branch")
+	}
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultProgressInterval is how often progress is logged while report
+// files are processed.
+const defaultProgressInterval = 10 * time.Second
+
+// progressInterval returns the progress interval of the plugin arguments,
+// zero when progress reporting is disabled.
+func progressInterval(args Args) time.Duration {
+	if args.Progress != nil && !*args.Progress {
+		return 0
+	}
+	if args.ProgressInterval <= 0 {
+		return defaultProgressInterval
+	}
+	return args.ProgressInterval
+}
+
+// progressTracker periodically logs the processed files and tests and
+// the estimated remaining time. A nil tracker reports nothing.
+type progressTracker struct {
+	mu    sync.Mutex
+	total int
+	done  int
+	tests int
+	start time.Time
+	stop  chan struct{}
+}
+
+// startProgress starts logging the progress of processing the files
+// every interval. It returns nil when the interval is zero or there is
+// only one file.
+func startProgress(total int, interval time.Duration) *progressTracker {
+	if interval <= 0 || total < 2 {
+		return nil
+	}
+	p := &progressTracker{total: total, start: time.Now(), stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				logrus.Infof("%s\n", p.message(now))
+			}
+		}
+	}()
+	return p
+}
+
+// fileDone records a processed file and its tests.
+func (p *progressTracker) fileDone(tests int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done++
+	p.tests += tests
+	p.mu.Unlock()
+}
+
+// finish stops logging the progress.
+func (p *progressTracker) finish() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+}
+
+// message returns the progress at the time, estimating the remaining
+// time from the average time per processed file.
+func (p *progressTracker) message(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	eta := "unknown"
+	if p.done > 0 {
+		elapsed := now.Sub(p.start)
+		remaining := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+		eta = remaining.Round(time.Second).String()
+	}
+	return fmt.Sprintf("Progress: %d/%d files, %d tests, ETA %s", p.done, p.total, p.tests, eta)
+}
+
+// countSuiteTests returns the number of tests in the suite tree.
+func countSuiteTests(suite *Suite) int {
+	count := len(suite.Tests)
+	for i := range suite.Suites {
+		count += countSuiteTests(&suite.Suites[i])
+	}
+	return count
+}
@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+// TestProgressMessage validates the progress counts and remaining time estimate
+func TestProgressMessage(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := &progressTracker{total: 10, start: start}
+	if got, want := p.message(start.Add(5*time.Second)), "Progress: 0/10 files, 0 tests, ETA unknown"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	p.fileDone(40)
+	p.fileDone(60)
+	if got, want := p.message(start.Add(20*time.Second)), "Progress: 2/10 files, 100 tests, ETA 1m20s"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestProgressInterval validates the progress defaults and the disable option
+func TestProgressInterval(t *testing.T) {
+	off := false
+	tests := []struct {
+		args Args
+		want time.Duration
+	}{
+		{Args{}, defaultProgressInterval},
+		{Args{ProgressInterval: time.Minute}, time.Minute},
+		{Args{Progress: &off, ProgressInterval: time.Minute}, 0},
+	}
+	for _, tt := range tests {
+		if got := progressInterval(tt.args); got != tt.want {
+			t.Errorf("Expected interval %s, got %s", tt.want, got)
+		}
+	}
+
+	if p := startProgress(1, time.Second); p != nil {
+		t.Errorf("Expected no progress for a single file")
+	}
+	var p *progressTracker
+	p.fileDone(1) // nil trackers report nothing
+	p.finish()
+}
@@ -0,0 +1,126 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultOutputStem is the file name stem of the Robot Framework output
+// file, left out of the publish directory names.
+const defaultOutputStem = "output"
+
+// publishedRun is a directory of the publish directory holding an
+// output file with the log and report files next to it.
+type publishedRun struct {
+	Name  string   // directory name in the publish directory
+	Files []string // source files
+}
+
+// publishName returns the publish directory name of the output file:
+// its directory relative to the report directory and its file name stem
+// unless it is output, joined with dashes, e.g. pabot_results/2/output.xml
+// is published as 2 and chrome/output-smoke.xml as chrome-output-smoke.
+func publishName(reportDir, file string) string {
+	rel, err := filepath.Rel(reportDir, file)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(file)
+	}
+	var parts []string
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+		if part != "." && part != "" && part != pabotResultsDir {
+			parts = append(parts, part)
+		}
+	}
+	if stem := strings.TrimSuffix(filepath.Base(rel), filepath.Ext(rel)); stem != defaultOutputStem || len(parts) == 0 {
+		parts = append(parts, stem)
+	}
+	return strings.Trim(nonAlphanumeric.ReplaceAllString(strings.Join(parts, "-"), "-"), "-")
+}
+
+// publishLayout returns the published runs of the processed output
+// files, in their order. Names shared by several output files are made
+// unique with a counter.
+func publishLayout(reportDir string, files []FileStats) []publishedRun {
+	var runs []publishedRun
+	used := map[string]bool{}
+	for _, file := range files {
+		base := publishName(reportDir, file.File)
+		name := base
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[name] = true
+
+		run := publishedRun{Name: name, Files: []string{file.File}}
+		for _, artifact := range robotArtifacts {
+			path := filepath.Join(filepath.Dir(file.File), artifact)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				run.Files = append(run.Files, path)
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// publishedFiles returns the paths of the files in the publish
+// directory.
+func publishedFiles(publishDir string, runs []publishedRun) []string {
+	var paths []string
+	for _, run := range runs {
+		for _, file := range run.Files {
+			paths = append(paths, filepath.Join(publishDir, run.Name, filepath.Base(file)))
+		}
+	}
+	return paths
+}
+
+// copyFile copies the file, creating the directory of the copy.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// newPublishDirReporter copies the processed output files with their log
+// and report files into one directory per output file of the publish
+// directory, for a later upload or pages publishing step.
+func newPublishDirReporter(args Args) (Reporter, error) {
+	if args.PublishDir == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		runs := publishLayout(args.ReportDirectory, stats.Files)
+		copied := 0
+		for _, run := range runs {
+			for _, file := range run.Files {
+				if err := copyFile(file, filepath.Join(args.PublishDir, run.Name, filepath.Base(file))); err != nil {
+					return fmt.Errorf("failed to publish %s: %v", file, err)
+				}
+				copied++
+			}
+		}
+		logrus.Infof("Published %d files of %d report directories to %s\n", copied, len(runs), args.PublishDir)
+		return nil
+	}), nil
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestPublishName validates the normalized directory names of output
+// files
+func TestPublishName(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"reports/output.xml", "output"},
+		{"reports/pabot_results/2/output.xml", "2"},
+		{"reports/chrome/output-smoke.xml", "chrome-output-smoke"},
+		{"reports/shard 1/output.xml", "shard-1"},
+		{"elsewhere/output.xml", "output"},
+	}
+	for _, test := range tests {
+		if got := publishName("reports", test.file); got != test.want {
+			t.Errorf("Expected %s to be published as %s, got %s", test.file, test.want, got)
+		}
+	}
+}
+
+// TestPublishDirReporter validates copying the output files with their
+// log and report files
+func TestPublishDirReporter(t *testing.T) {
+	dir := t.TempDir()
+	reports := filepath.Join(dir, "reports")
+	for _, file := range []string{"a/output.xml", "a/log.html", "a/report.html", "b/a/output.xml"} {
+		file = filepath.Join(reports, file)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(file), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// b/a normalizes to b-a, a second a/output.xml gets a counter
+	files := []FileStats{{File: filepath.Join(reports, "a/output.xml")}, {File: filepath.Join(reports, "b/a/output.xml")}, {File: filepath.Join(reports, "a/output.xml")}}
+
+	publish := filepath.Join(dir, "publish")
+	args := Args{ReportDirectory: reports, PublishDir: publish}
+	reporter, err := newPublishDirReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), StatsResult{Files: files}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	filepath.WalkDir(publish, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(publish, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	want := []string{"a/log.html", "a/output.xml", "a/report.html", "a-2/log.html", "a-2/output.xml", "a-2/report.html", "b-a/output.xml"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Published files mismatch (-want +got):\n%s", diff)
+	}
+	if data, _ := os.ReadFile(filepath.Join(publish, "b-a", "output.xml")); string(data) != filepath.Join(reports, "b/a/output.xml") {
+		t.Errorf("Unexpected content of the published file: %s", data)
+	}
+
+	args.ArtifactBaseURL = "https://example.com"
+	if paths := generatedReports(args, StatsResult{Files: files[:1]}); len(paths) != 3 || filepath.Dir(paths[0]) != filepath.Join(publish, "a") {
+		t.Errorf("Expected the artifact metadata to list the published files, got %v", paths)
+	}
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// rdjsonResult is a reviewdog diagnostic result (rdjson), see
+// https://github.com/reviewdog/reviewdog/tree/master/proto/rdf
+type rdjsonResult struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdjsonDiagnostic struct {
+	Message  string         `json:"message"`
+	Location rdjsonLocation `json:"location"`
+	Severity string         `json:"severity"`
+	Code     *rdjsonCode    `json:"code,omitempty"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition `json:"start"`
+}
+
+type rdjsonPosition struct {
+	Line int `json:"line"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// rdjsonDiagnostics returns the failed tests as reviewdog diagnostics,
+// errors for counted failures and warnings for allowed failures. Tests
+// without a source inside the root cannot be placed and are left out.
+func rdjsonDiagnostics(stats StatsResult, root string) []rdjsonDiagnostic {
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	diagnostics := []rdjsonDiagnostic{}
+	unplaced := 0
+	for _, group := range []struct {
+		details  []FailedTestDetails
+		severity string
+	}{
+		{stats.FailedTestsDetails, "ERROR"},
+		{stats.AllowedFailuresDetails, "WARNING"},
+	} {
+		for _, test := range group.details {
+			path, ok := sourcePath(test.Source, root)
+			if !ok {
+				unplaced++
+				continue
+			}
+			diagnostic := rdjsonDiagnostic{
+				Message:  "Test '" + test.Name + "' failed",
+				Location: rdjsonLocation{Path: path},
+				Severity: group.severity,
+				Code:     &rdjsonCode{Value: test.Suite + "." + test.Name, URL: test.URL},
+			}
+			if test.ErrorMessage != "" {
+				diagnostic.Message += ": " + test.ErrorMessage
+			}
+			if test.Line > 0 {
+				diagnostic.Location.Range = &rdjsonRange{Start: rdjsonPosition{Line: test.Line}}
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	if unplaced > 0 {
+		logrus.Debugf("Left %d failed tests without a source out of the rdjson report", unplaced)
+	}
+	return diagnostics
+}
+
+// newRDJSONReporter writes the failed tests in reviewdog's rdjson format.
+func newRDJSONReporter(args Args) (Reporter, error) {
+	if args.RDJSONFile == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		return WriteJSON(args.RDJSONFile, rdjsonResult{
+			Source:      rdjsonSource{Name: "robot", URL: "https://robotframework.org"},
+			Diagnostics: rdjsonDiagnostics(stats, args.SourceRoot),
+		})
+	}), nil
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestRDJSONReporter validates the reviewdog diagnostics of failed and
+// allowed-failure tests
+func TestRDJSONReporter(t *testing.T) {
+	root := t.TempDir()
+	stats := StatsResult{
+		FailedTestsDetails: []FailedTestDetails{
+			{Name: "Login", Suite: "Web.Auth", ErrorMessage: "Element not found", Source: filepath.Join(root, "tests", "auth.robot"), Line: 12},
+			{Name: "Orphan", Suite: "Web", Source: filepath.Join(t.TempDir(), "other.robot")},
+		},
+		AllowedFailuresDetails: []FailedTestDetails{
+			{Name: "Search", Suite: "Web.Search", Source: "tests/search.robot"},
+		},
+	}
+
+	file := filepath.Join(t.TempDir(), "robot.rdjson")
+	reporter, err := newRDJSONReporter(Args{RDJSONFile: file, SourceRoot: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got rdjsonResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []rdjsonDiagnostic{
+		{
+			Message:  "Test 'Login' failed: Element not found",
+			Location: rdjsonLocation{Path: "tests/auth.robot", Range: &rdjsonRange{Start: rdjsonPosition{Line: 12}}},
+			Severity: "ERROR",
+			Code:     &rdjsonCode{Value: "Web.Auth.Login"},
+		},
+		{
+			Message:  "Test 'Search' failed",
+			Location: rdjsonLocation{Path: "tests/search.robot"},
+			Severity: "WARNING",
+			Code:     &rdjsonCode{Value: "Web.Search.Search"},
+		},
+	}
+	if diff := cmp.Diff(want, got.Diagnostics); diff != "" {
+		t.Errorf("Unexpected diagnostics (-want +got):\n%s", diff)
+	}
+	if got.Source.Name != "robot" {
+		t.Errorf("Expected the robot source, got %q", got.Source.Name)
+	}
+}
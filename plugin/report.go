@@ -0,0 +1,261 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// reportData is the data of the HTML report and Markdown summary
+// templates.
+type reportData struct {
+	Stats   StatsResult
+	Rows    []summaryRow
+	Outcome string
+	Chart   string // inline SVG of the HTML report, image URL of the Markdown summary
+	Unit    string // duration unit
+}
+
+// newReportData returns the template data of the statistics.
+func newReportData(stats StatsResult, unit string) reportData {
+	data := reportData{Stats: stats, Rows: summaryRows(stats, unit), Unit: unit}
+	if len(stats.Gates) > 0 {
+		data.Outcome = gateOutcome(stats.Gates)
+	}
+	return data
+}
+
+var htmlReportTemplate = htmltemplate.Must(htmltemplate.New("report").Funcs(htmltemplate.FuncMap{
+	// the chart is rendered from numbers and escaped build numbers
+	"svg":      func(s string) htmltemplate.HTML { return htmltemplate.HTML(s) },
+	"streak":   failingStreak,
+	"duration": formatDuration,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Robot Framework Test Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.pass { color: #2da44e; } .warn { color: #bf8700; } .fail { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Robot Framework Test Report</h1>
+{{- with .Stats.Build}}
+<p>{{.Repo}}{{with .Branch}} · {{.}}{{end}}{{with .Commit}} · {{.}}{{end}}{{with .Number}} · build {{if $.Stats.Build.Link}}<a href="{{$.Stats.Build.Link}}">#{{.}}</a>{{else}}#{{.}}{{end}}{{end}}</p>
+{{- end}}
+{{- with .Stats.Host}}
+<p>Host: {{with .Hostname}}{{.}} · {{end}}{{.OS}}/{{.Arch}} · {{.CPUs}} CPUs</p>
+{{- with .Env}}
+<table>
+{{- range $name, $value := .}}
+<tr><th>{{$name}}</th><td>{{$value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+{{- with .Outcome}}
+<p>Gates: <strong class="{{.}}">{{.}}</strong></p>
+{{- end}}
+{{- with .Chart}}
+<h2>Trend</h2>
+{{svg .}}
+{{- end}}
+<h2>Summary</h2>
+<table>
+{{- range .Rows}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- with .Stats.Gates}}
+<h2>Gates</h2>
+<table>
+<tr><th>Gate</th><th>Actual</th><th>Outcome</th><th>Message</th></tr>
+{{- range .}}
+<tr><td>{{.Name}}</td><td>{{.Actual}}</td><td class="{{.Outcome}}">{{.Outcome}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Stats.TagStatistics}}
+<h2>Tag Statistics</h2>
+<table>
+<tr><th>Tag</th><th>Total</th><th>Passed</th><th>Failed</th><th>Skipped</th><th>Documentation</th></tr>
+{{- range .}}
+<tr><td>{{.Name}}{{with .Combined}} ({{.}}){{end}}</td><td>{{.Total}}</td><td class="pass">{{.Passed}}</td><td class="fail">{{.Failed}}</td><td>{{.Skipped}}</td><td>{{.Doc}}{{range .Links}} <a href="{{.URL}}">{{.Title}}</a>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Stats.FailedTestsDetails}}
+<h2>Failed Tests</h2>
+<table>
+<tr><th>Suite</th><th>Test</th><th>Status</th><th>Duration</th><th>Failing</th><th>Message</th></tr>
+{{- range .}}
+<tr><td>{{.Suite}}</td><td>{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</td><td class="fail">{{.Status}}</td><td>{{duration .Duration $.Unit}}</td><td>{{streak .}}</td><td><pre>{{.ErrorMessage}}</pre>
+{{- with .Context}}<details><summary>Context</summary><pre>
+{{- range .}}
+{{.Time}} {{.Level}} {{.Text}}
+{{- end}}</pre></details>{{end}}
+{{- with .FailedIterations}}<details><summary>Failed Iterations</summary><pre>
+{{- range .}}
+{{.}}
+{{- end}}</pre></details>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- if $.Stats.OmittedFailureDetails}}
+<p>... and {{$.Stats.OmittedFailureDetails}} more failed tests</p>
+{{- end}}
+{{- end}}
+{{- with .Stats.AtRiskTestsDetails}}
+<h2>At-Risk Tests</h2>
+<p>Passed tests with failed keywords, e.g. run with Run Keyword And Ignore Error.</p>
+<table>
+<tr><th>Suite</th><th>Test</th><th>Failed Keywords</th><th>First Failed Keyword</th><th>Message</th></tr>
+{{- range .}}
+<tr><td>{{.Suite}}</td><td>{{.Name}}</td><td>{{.FailedKeywords}}</td><td>{{.Keyword}}</td><td><pre>{{.Message}}</pre></td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- with .Stats.FailureLeaderboard}}
+<h2>Most Frequent Failures</h2>
+<table>
+<tr><th>Suite</th><th>Test</th><th>Failed Runs</th><th>Last Failed Build</th><th>Last Message</th></tr>
+{{- range .}}
+<tr><td>{{.Suite}}</td><td>{{.Name}}</td><td class="fail">{{.Failures}} / {{.Runs}}</td><td>{{.LastBuild}}</td><td><pre>{{.LastMessage}}</pre></td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+var markdownSummaryTemplate = template.Must(template.New("summary").Funcs(template.FuncMap{
+	"cell":     markdownCell,
+	"streak":   failingStreak,
+	"duration": formatDuration,
+}).Parse(`## Robot Framework Test Report
+{{with .Stats.Build}}
+{{.Repo}}{{with .Branch}} · {{.}}{{end}}{{with .Commit}} · {{.}}{{end}}{{with .Number}} · build #{{.}}{{end}}
+{{end}}
+{{- with .Outcome}}
+**Gates: {{.}}**
+{{end}}
+{{- with .Chart}}
+![Pass rate and duration trend]({{.}})
+{{end}}
+| Metric | Value |
+| --- | ---: |
+{{- range .Rows}}
+| {{.Label}} | {{.Value}} |
+{{- end}}
+{{with .Stats.TagStatistics}}
+### Tag Statistics
+
+| Tag | Total | Passed | Failed | Skipped | Documentation |
+| --- | ---: | ---: | ---: | ---: | --- |
+{{- range .}}
+| {{cell .Name}}{{with .Combined}} ({{cell .}}){{end}} | {{.Total}} | {{.Passed}} | {{.Failed}} | {{.Skipped}} | {{cell .Doc}}{{range .Links}} [{{cell .Title}}]({{.URL}}){{end}} |
+{{- end}}
+{{end}}
+{{- with .Stats.FailedTestsDetails}}
+### Failed Tests
+
+| Suite | Test | Status | Duration | Failing | Message |
+| --- | --- | --- | ---: | --- | --- |
+{{- range .}}
+| {{cell .Suite}} | {{if .URL}}[{{cell .Name}}]({{.URL}}){{else}}{{cell .Name}}{{end}} | {{.Status}} | {{duration .Duration $.Unit}} | {{streak .}} | {{cell .ErrorMessage}} |
+{{- end}}
+{{- if $.Stats.OmittedFailureDetails}}
+
+... and {{$.Stats.OmittedFailureDetails}} more failed tests
+{{- end}}
+{{end}}
+{{- with .Stats.AtRiskTestsDetails}}
+### At-Risk Tests
+
+Passed tests with failed keywords, e.g. run with Run Keyword And Ignore Error.
+
+| Suite | Test | Failed Keywords | First Failed Keyword | Message |
+| --- | --- | ---: | --- | --- |
+{{- range .}}
+| {{cell .Suite}} | {{cell .Name}} | {{.FailedKeywords}} | {{cell .Keyword}} | {{cell .Message}} |
+{{- end}}
+{{end}}
+{{- with .Stats.FailureLeaderboard}}
+### Most Frequent Failures
+
+| Suite | Test | Failed Runs | Last Failed Build | Last Message |
+| --- | --- | ---: | --- | --- |
+{{- range .}}
+| {{cell .Suite}} | {{cell .Name}} | {{.Failures}} / {{.Runs}} | {{.LastBuild}} | {{cell .LastMessage}} |
+{{- end}}
+{{end}}`))
+
+// markdownCell escapes the text for a Markdown table cell, which
+// renders inline HTML.
+func markdownCell(s string) string {
+	s = strings.NewReplacer("|", `\|`, "<", "&lt;").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// newHTMLReportReporter writes the HTML report.
+func newHTMLReportReporter(args Args) (Reporter, error) {
+	if args.HTMLReport == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		data := newReportData(stats, args.DurationUnit)
+		if len(stats.Trend) > 0 {
+			data.Chart = string(trendSVG(stats.Trend))
+		}
+		var b bytes.Buffer
+		if err := htmlReportTemplate.Execute(&b, data); err != nil {
+			return err
+		}
+		return os.WriteFile(args.HTMLReport, b.Bytes(), 0644)
+	}), nil
+}
+
+// newMarkdownSummaryReporter writes the Markdown summary, e.g. for pull
+// request comments. The trend chart links to PLUGIN_TREND_CHART when set
+// and is embedded as data URI otherwise.
+func newMarkdownSummaryReporter(args Args) (Reporter, error) {
+	if args.MarkdownSummary == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		data := newReportData(stats, args.DurationUnit)
+		if len(stats.Trend) > 0 {
+			data.Chart = markdownChart(args)
+			if data.Chart == "" {
+				data.Chart = "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString(trendSVG(stats.Trend))
+			}
+		}
+		var b bytes.Buffer
+		if err := markdownSummaryTemplate.Execute(&b, data); err != nil {
+			return err
+		}
+		return os.WriteFile(args.MarkdownSummary, b.Bytes(), 0644)
+	}), nil
+}
+
+// markdownChart returns the path of the trend chart relative to the
+// Markdown summary, or an empty string when no chart is written.
+func markdownChart(args Args) string {
+	if args.TrendChart == "" {
+		return ""
+	}
+	rel, err := filepath.Rel(filepath.Dir(args.MarkdownSummary), args.TrendChart)
+	if err != nil {
+		return filepath.ToSlash(args.TrendChart)
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,97 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reportStats returns statistics with a failure and a trend.
+func reportStats() StatsResult {
+	return StatsResult{
+		TotalTests:  2,
+		PassedTests: 1,
+		FailedTests: 1,
+		FailedTestsDetails: []FailedTestDetails{
+			{Name: "Login <admin>", Suite: "Root.Auth", Status: "FAIL", Duration: 12.5, ErrorMessage: "expected | got\nnothing", FailingStreak: 3, FirstFailedBuild: "40"},
+		},
+		Gates: []GateResult{{Name: "failed_tests", Actual: 1, Outcome: gateFail}},
+		Build: &BuildInfo{Repo: "octocat/hello-world", Branch: "main", Number: "42"},
+		Trend: []TrendPoint{{Build: "41", PassRate: 100, Duration: 900}, {Build: "42", PassRate: 50, Duration: 1000}},
+		TagStatistics: []RobotTagStats{
+			{Name: "smoke", Doc: "Quick checks", Links: []TagLink{{Title: "Wiki", URL: "https://wiki.example.com"}}, TagStats: TagStats{Total: 2, Passed: 1, Failed: 1}},
+		},
+	}
+}
+
+// TestHTMLReport validates the HTML report content and escaping
+func TestHTMLReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	reporter, err := newHTMLReportReporter(Args{HTMLReport: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), reportStats()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"octocat/hello-world · main · build #42",
+		`<strong class="fail">fail</strong>`,
+		"<svg",
+		"<tr><th>Failed Tests</th><td>1</td></tr>",
+		"Login &lt;admin&gt;",
+		`<td>Quick checks <a href="https://wiki.example.com">Wiki</a></td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("Expected HTML report to contain %q:\n%s", want, html)
+		}
+	}
+}
+
+// TestMarkdownSummary validates the Markdown summary and its trend chart
+func TestMarkdownSummary(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		chart string
+		want  string
+	}{
+		{"embedded", "", "![Pass rate and duration trend](data:image/svg+xml;base64,"},
+		{"linked", filepath.Join(dir, "charts", "trend.svg"), "![Pass rate and duration trend](charts/trend.svg)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, "summary.md")
+			reporter, err := newMarkdownSummaryReporter(Args{MarkdownSummary: path, TrendChart: test.chart})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := reporter.Report(context.Background(), reportStats()); err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			summary := string(data)
+			for _, want := range []string{
+				test.want,
+				"**Gates: fail**",
+				"| Failed Tests | 1 |",
+				"| smoke | 2 | 1 | 1 | 0 | Quick checks [Wiki](https://wiki.example.com) |",
+				`| Root.Auth | Login &lt;admin> | FAIL | 12.50 ms | 3 runs, since build #40 | expected \| got nothing |`,
+			} {
+				if !strings.Contains(summary, want) {
+					t.Errorf("Expected Markdown summary to contain %q:\n%s", want, summary)
+				}
+			}
+		})
+	}
+}
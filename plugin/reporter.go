@@ -0,0 +1,221 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reporter publishes the statistics of a run, e.g. to the console, to
+// DRONE_OUTPUT or to an external service.
+type Reporter interface {
+	Report(ctx context.Context, stats StatsResult) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(ctx context.Context, stats StatsResult) error
+
+// Report calls f(ctx, stats).
+func (f ReporterFunc) Report(ctx context.Context, stats StatsResult) error {
+	return f(ctx, stats)
+}
+
+// ReporterFactory creates a reporter for the plugin arguments. It returns
+// a nil Reporter when the reporter is not enabled by the arguments.
+type ReporterFactory func(args Args) (Reporter, error)
+
+// reporterRegistry holds the reporter factories in registration order.
+type reporterRegistry struct {
+	mu        sync.Mutex
+	names     []string
+	factories map[string]ReporterFactory
+}
+
+func (r *reporterRegistry) register(name string, factory ReporterFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if factory == nil {
+		panic("plugin: RegisterReporter factory is nil")
+	}
+	if _, dup := r.factories[name]; dup {
+		panic("plugin: RegisterReporter called twice for reporter " + name)
+	}
+	if r.factories == nil {
+		r.factories = map[string]ReporterFactory{}
+	}
+	r.names = append(r.names, name)
+	r.factories[name] = factory
+}
+
+// namedReporter is an enabled reporter and its registration name.
+type namedReporter struct {
+	Name string
+	Reporter
+}
+
+// reporters returns the reporters enabled by the arguments, in
+// registration order.
+func (r *reporterRegistry) reporters(args Args) ([]namedReporter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var enabled []namedReporter
+	for _, name := range r.names {
+		if !args.Reporters[name].Enabled() {
+			continue
+		}
+		reporter, err := r.factories[name](args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s reporter configuration: %v", name, err)
+		}
+		if reporter != nil {
+			enabled = append(enabled, namedReporter{Name: name, Reporter: reporter})
+		}
+	}
+	return enabled, nil
+}
+
+// requiredReporters are the reporters whose failure fails the run: later
+// steps read their outputs. Every other reporter, e.g. a notification or
+// an external service, is best-effort and its failure is logged.
+var requiredReporters = map[string]bool{
+	"drone_output": true,
+	"stats_file":   true,
+}
+
+// report runs all reporters enabled by the arguments. It returns the
+// first failure of a required reporter after the remaining reporters ran.
+func (r *reporterRegistry) report(ctx context.Context, args Args, stats StatsResult) error {
+	reporters, err := r.reporters(args)
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, reporter := range reporters {
+		err := reporter.Report(ctx, stats)
+		switch {
+		case err == nil:
+		case requiredReporters[reporter.Name]:
+			if failed == nil {
+				failed = fmt.Errorf("%s reporter failed: %v", reporter.Name, err)
+			}
+		default:
+			logrus.Warnf("%s reporter failed: %v", reporter.Name, err)
+		}
+	}
+	return failed
+}
+
+var defaultReporters = &reporterRegistry{}
+
+// RegisterReporter registers a reporter factory under the name. Enabled
+// reporters run in registration order after the statistics are computed.
+// It panics if the name is already registered.
+func RegisterReporter(name string, factory ReporterFactory) {
+	defaultReporters.register(name, factory)
+}
+
+// The built-in reporters are registered here, in the order they run.
+func init() {
+	RegisterReporter("console", newConsoleReporter)
+	RegisterReporter("drone_output", newDroneOutputReporter)
+	RegisterReporter("stats_file", newStatsFileReporter)
+	RegisterReporter("comparison", newComparisonReporter)
+	RegisterReporter("unstable_webhook", newUnstableWebhookReporter)
+	RegisterReporter("trend_chart", newTrendChartReporter)
+	RegisterReporter("html_report", newHTMLReportReporter)
+	RegisterReporter("markdown_summary", newMarkdownSummaryReporter)
+	RegisterReporter("rdjson", newRDJSONReporter)
+	RegisterReporter("timeline", newTimelineReporter)
+	RegisterReporter("split_manifest", newSplitManifestReporter)
+	RegisterReporter("confluence", newConfluenceReporter)
+	RegisterReporter("discord", newDiscordReporter)
+	RegisterReporter("mattermost", newMattermostReporter)
+	RegisterReporter("twilio", newTwilioReporter)
+	RegisterReporter("history", newHistoryReporter)
+	RegisterReporter("publish_dir", newPublishDirReporter)
+	RegisterReporter("artifact_metadata", newArtifactMetadataReporter)
+}
+
+// newConsoleReporter logs the aggregated and per-file statistics.
+func newConsoleReporter(args Args) (Reporter, error) {
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		LogResults(stats, args)
+		return nil
+	}), nil
+}
+
+// newDroneOutputReporter writes the statistics to DRONE_OUTPUT.
+func newDroneOutputReporter(args Args) (Reporter, error) {
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		out := &outputVars{precision: args.OutputPrecision, unit: args.DurationUnit}
+		writeTestStats(out, stats)
+		writeTagStats(out, stats)
+		writeGateResults(out, stats.Gates)
+		if len(stats.Gates) > 0 {
+			writeUnstable(out, stats.Unstable)
+		}
+		if args.MetadataOutputs {
+			writeMetadata(out, stats.Metadata)
+		}
+		if args.HarnessOutputs {
+			return exportHarnessOutputs(out, args)
+		}
+		return exportOutputs(out, args)
+	}), nil
+}
+
+// exportOutputs writes the output variables. Failures are logged unless
+// PLUGIN_FAIL_ON_OUTPUT_ERROR is set.
+func exportOutputs(out *outputVars, args Args) error {
+	err := out.write(args)
+	if err == nil || args.FailOnOutputError {
+		return err
+	}
+	logrus.Warnf("Failed to export output variables: %v", err)
+	return nil
+}
+
+// newStatsFileReporter writes the statistics as JSON to the stats file.
+func newStatsFileReporter(args Args) (Reporter, error) {
+	if args.StatsFile == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		return WriteJSON(args.StatsFile, stats)
+	}), nil
+}
+
+// newComparisonReporter compares the run against the previous result set,
+// the stats artifact of the previous build or the baseline run selected
+// from the history store.
+func newComparisonReporter(args Args) (Reporter, error) {
+	history, err := newHistoryStore(args)
+	if err != nil {
+		return nil, err
+	}
+	if args.CompareTo == "" && args.PreviousStatsURL == "" && history == nil {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		previous, ok, err := loadPreviousStats(ctx, args, history, stats)
+		if err != nil || !ok {
+			return err
+		}
+		diff := compareRuns(previous, stats)
+		LogRunDiff(diff, args.SummaryStyle)
+		out := &outputVars{}
+		writeRunDiff(out, diff)
+		if err := exportOutputs(out, args); err != nil {
+			return err
+		}
+		if args.CompareReport != "" {
+			if err := WriteJSON(args.CompareReport, diff); err != nil {
+				return fmt.Errorf("failed to write comparison report: %v", err)
+			}
+		}
+		return nil
+	}), nil
+}
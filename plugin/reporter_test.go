@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestReporterRegistry validates that enabled reporters run in registration order
+func TestReporterRegistry(t *testing.T) {
+	var calls []string
+	recorder := func(name string) ReporterFactory {
+		return func(args Args) (Reporter, error) {
+			return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+				calls = append(calls, name)
+				return nil
+			}), nil
+		}
+	}
+
+	registry := &reporterRegistry{}
+	registry.register("first", recorder("first"))
+	registry.register("disabled", func(args Args) (Reporter, error) { return nil, nil })
+	registry.register("second", recorder("second"))
+
+	if err := registry.report(context.Background(), Args{}, StatsResult{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, calls); diff != "" {
+		t.Errorf("Reporter calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestReporterRegistryErrors validates reporter configuration and report errors
+func TestReporterRegistryErrors(t *testing.T) {
+	var calls []string
+	failing := func(name string) ReporterFactory {
+		return func(args Args) (Reporter, error) {
+			return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+				calls = append(calls, name)
+				return errors.New("sink unavailable")
+			}), nil
+		}
+	}
+	registry := &reporterRegistry{}
+	registry.register("broken", failing("broken"))
+	registry.register("stats_file", failing("stats_file"))
+	registry.register("later", failing("later"))
+	err := registry.report(context.Background(), Args{}, StatsResult{})
+	if err == nil || err.Error() != "stats_file reporter failed: sink unavailable" {
+		t.Errorf("Expected the required reporter failure, got %v", err)
+	}
+	if diff := cmp.Diff([]string{"broken", "stats_file", "later"}, calls); diff != "" {
+		t.Errorf("Expected every reporter to run after failures (-want +got):\n%s", diff)
+	}
+
+	registry = &reporterRegistry{}
+	registry.register("broken", failing("broken"))
+	if err := registry.report(context.Background(), Args{}, StatsResult{}); err != nil {
+		t.Errorf("Expected a best-effort reporter failure to be logged only, got %v", err)
+	}
+
+	registry = &reporterRegistry{}
+	registry.register("webhook", func(args Args) (Reporter, error) {
+		return nil, errors.New("missing url")
+	})
+	err = registry.report(context.Background(), Args{}, StatsResult{})
+	if err == nil || err.Error() != "invalid webhook reporter configuration: missing url" {
+		t.Errorf("Expected configuration error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a duplicate reporter name")
+		}
+	}()
+	registry.register("webhook", func(args Args) (Reporter, error) { return nil, nil })
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import "strings"
+
+// reservedTagPrefix is the prefix of the tags Robot Framework reserves.
+const reservedTagPrefix = "robot:"
+
+// Actions of reserved tags, from the strongest to the weakest.
+const (
+	reservedExclude      = "exclude"       // not counted
+	reservedSkip         = "skip"          // counted as skipped
+	reservedAllowFailure = "allow-failure" // failures reported but not counted
+	reservedCount        = "count"         // counted by status
+)
+
+// reservedActions ranks the reserved tag actions. The strongest action of
+// the tags of a test applies.
+var reservedActions = map[string]int{
+	reservedExclude:      3,
+	reservedSkip:         2,
+	reservedAllowFailure: 1,
+	reservedCount:        0,
+}
+
+// defaultReservedTags are the actions of the reserved tags, keyed by the
+// tag name without the robot: prefix.
+var defaultReservedTags = map[string]string{
+	"exclude": reservedExclude,
+	"skip":    reservedSkip,
+	"flaky":   reservedAllowFailure,
+}
+
+// validReservedAction reports whether the reserved tag action is known.
+func validReservedAction(action string) bool {
+	_, ok := reservedActions[strings.ToLower(strings.TrimSpace(action))]
+	return ok
+}
+
+// reservedTagName returns the normalized name of a reserved tag without
+// the robot: prefix, which is optional in the setting.
+func reservedTagName(tag string) string {
+	return strings.TrimPrefix(normalizeTag(tag), reservedTagPrefix)
+}
+
+// newReservedTags returns the actions of the reserved tags: the defaults
+// overridden by the setting.
+func newReservedTags(setting map[string]string) map[string]string {
+	actions := make(map[string]string, len(defaultReservedTags)+len(setting))
+	for name, action := range defaultReservedTags {
+		actions[name] = action
+	}
+	for tag, action := range setting {
+		actions[reservedTagName(tag)] = strings.ToLower(strings.TrimSpace(action))
+	}
+	return actions
+}
+
+// reservedAction returns the strongest action of the reserved tags of a
+// test, or count without reserved tags.
+func reservedAction(tags []string, actions map[string]string) string {
+	action := reservedCount
+	for _, tag := range tags {
+		normalized := normalizeTag(tag)
+		if !strings.HasPrefix(normalized, reservedTagPrefix) {
+			continue
+		}
+		if a, ok := actions[strings.TrimPrefix(normalized, reservedTagPrefix)]; ok && reservedActions[a] > reservedActions[action] {
+			action = a
+		}
+	}
+	return action
+}
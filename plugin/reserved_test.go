@@ -0,0 +1,77 @@
+package plugin
+
+import "testing"
+
+// reservedTagsOutput returns a report with tests tagged with reserved
+// tags.
+func reservedTagsOutput() RobotOutput {
+	return RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{
+		{Name: "A", Status: Status{Status: "PASS"}},
+		{Name: "B", TagList: []string{"robot:exclude"}, Status: Status{Status: "FAIL"}},
+		{Name: "C", TagList: []string{"ROBOT:SKIP"}, Status: Status{Status: "FAIL"}},
+		{Name: "D", TagList: []string{"robot:flaky"}, Status: Status{Status: "FAIL"}},
+		{Name: "E", Tags: []string{"robot:flaky", "robot:exclude"}, Status: Status{Status: "PASS"}},
+		{Name: "F", TagList: []string{"robot:quarantine"}, Status: Status{Status: "FAIL"}},
+	}}}
+}
+
+// TestReservedTags validates the default actions of reserved tags
+func TestReservedTags(t *testing.T) {
+	opts, err := newStatsOptions(Args{CountSkippedTests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(reservedTagsOutput(), opts)
+	if stats.TotalTests != 4 || stats.PassedTests != 1 || stats.SkippedTests != 1 || stats.FailedTests != 1 || stats.AllowedFailures != 1 {
+		t.Errorf("Expected 4 tests, 1 passed, 1 skipped, 1 failed and 1 allowed failure, got %d, %d, %d, %d and %d",
+			stats.TotalTests, stats.PassedTests, stats.SkippedTests, stats.FailedTests, stats.AllowedFailures)
+	}
+	if len(stats.FailedTestsDetails) != 1 || stats.FailedTestsDetails[0].Name != "F" {
+		t.Errorf("Expected the failure details of F, got %+v", stats.FailedTestsDetails)
+	}
+}
+
+// TestReservedTagsSetting validates overriding and adding reserved tag
+// actions
+func TestReservedTagsSetting(t *testing.T) {
+	args := Args{ReportDirectory: ".", ReservedTags: map[string]string{"flaky": "count", "robot:Quarantine": "Allow-Failure"}}
+	if err := ValidateInputs(args); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(reservedTagsOutput(), opts)
+	if stats.TotalTests != 4 || stats.FailedTests != 1 || stats.AllowedFailures != 1 {
+		t.Errorf("Expected 4 tests, 1 failed and 1 allowed failure, got %d, %d and %d",
+			stats.TotalTests, stats.FailedTests, stats.AllowedFailures)
+	}
+	if len(stats.AllowedFailuresDetails) != 1 || stats.AllowedFailuresDetails[0].Name != "F" {
+		t.Errorf("Expected the allowed failure of F, got %+v", stats.AllowedFailuresDetails)
+	}
+
+	if err := ValidateInputs(Args{ReportDirectory: ".", ReservedTags: map[string]string{"flaky": "ignore"}}); err == nil {
+		t.Errorf("Expected an error for an unknown reserved tag action")
+	}
+}
+
+// TestReservedAction validates that the strongest action applies
+func TestReservedAction(t *testing.T) {
+	actions := newReservedTags(nil)
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, reservedCount},
+		{[]string{"smoke", "robot:no-dry-run"}, reservedCount},
+		{[]string{"robot:flaky", "robot: skip"}, reservedSkip},
+		{[]string{"robot:skip", "robot:EXCLUDE"}, reservedExclude},
+		{[]string{"flaky"}, reservedCount},
+	}
+	for _, test := range tests {
+		if got := reservedAction(test.tags, actions); got != test.want {
+			t.Errorf("reservedAction(%v) = %q, want %q", test.tags, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"github.com/sirupsen/logrus"
+)
+
+// salvageOutput recovers the complete <test> elements of a truncated or
+// malformed report. Suites are rebuilt from the elements read before the
+// error; anything after it is dropped. parseErr is returned when no test
+// could be recovered.
+func salvageOutput(data []byte, filename string, parseErr error) (RobotOutput, error) {
+	var robotOutput RobotOutput
+	var stack []*Suite
+	recovered := 0
+
+	decoder := newReportDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+loop:
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "robot":
+				robotOutput.Generator = attrValue(t, "generator")
+				robotOutput.Schema = attrValue(t, "schemaversion")
+			case t.Name.Local == "suite":
+				stack = append(stack, &Suite{
+					ID:     attrValue(t, "id"),
+					Name:   attrValue(t, "name"),
+					Source: attrValue(t, "source"),
+				})
+			case len(stack) == 0:
+				// statistics and errors follow the root suite
+				if err := decoder.Skip(); err != nil {
+					break loop
+				}
+			case t.Name.Local == "test":
+				var test Test
+				if err := decoder.DecodeElement(&test, &t); err != nil {
+					break loop
+				}
+				suite := stack[len(stack)-1]
+				suite.Tests = append(suite.Tests, test)
+				recovered++
+			case t.Name.Local == "meta":
+				suite := stack[len(stack)-1]
+				var meta Meta
+				if err := decoder.DecodeElement(&meta, &t); err != nil {
+					break loop
+				}
+				suite.Metadata = append(suite.Metadata, meta)
+			case t.Name.Local == "status":
+				suite := stack[len(stack)-1]
+				if err := decoder.DecodeElement(&suite.Status, &t); err != nil {
+					break loop
+				}
+			default:
+				// suite keywords, documentation and metadata
+				if err := decoder.Skip(); err != nil {
+					break loop
+				}
+			}
+		case xml.EndElement:
+			if t.Name.Local == "suite" && len(stack) > 0 {
+				stack = closeSuite(stack, &robotOutput)
+			}
+		}
+	}
+
+	if recovered == 0 {
+		return RobotOutput{}, parseErr
+	}
+
+	// attach the suites left open by the truncation
+	for len(stack) > 0 {
+		stack = closeSuite(stack, &robotOutput)
+	}
+
+	robotOutput.Incomplete = true
+	robotOutput.RecoveredTests = recovered
+	logrus.Warnf("Recovered %d complete tests from malformed report %s: %v", recovered, filename, parseErr)
+	return robotOutput, nil
+}
+
+// closeSuite pops the innermost open suite and attaches it to its parent,
+// or makes it the root suite of the output.
+func closeSuite(stack []*Suite, robotOutput *RobotOutput) []*Suite {
+	suite := stack[len(stack)-1]
+	stack = stack[:len(stack)-1]
+	if len(stack) == 0 {
+		robotOutput.Suite = *suite
+	} else {
+		parent := stack[len(stack)-1]
+		parent.Suites = append(parent.Suites, *suite)
+	}
+	return stack
+}
@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSalvageTruncatedReport validates recovery of the complete tests of
+// a report cut off in the middle of a test
+func TestSalvageTruncatedReport(t *testing.T) {
+	content, err := os.ReadFile("../testdata/robot_report.xml")
+	if err != nil {
+		t.Fatalf("Failed to read test data: %v", err)
+	}
+	// Cut the report inside the third test
+	cut := strings.Index(string(content), `<test id="s1-t3"`) + 40
+	path := filepath.Join(t.TempDir(), "output.xml")
+	if err := os.WriteFile(path, content[:cut], 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := processFile(path, opts); err == nil {
+		t.Fatal("Expected a parse error without salvage mode")
+	}
+
+	opts.Salvage = true
+	stats, err := processFile(path, opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !stats.Incomplete || stats.RecoveredTests != 2 {
+		t.Errorf("Expected an incomplete run with 2 recovered tests, got incomplete=%v recovered=%d", stats.Incomplete, stats.RecoveredTests)
+	}
+	if stats.TotalTests != 2 || stats.PassedTests != 1 || stats.FailedTests != 1 {
+		t.Errorf("Expected 2 tests with 1 passed and 1 failed, got %d/%d/%d", stats.TotalTests, stats.PassedTests, stats.FailedTests)
+	}
+}
+
+// TestSalvageNothingRecovered validates that the parse error is kept when
+// no complete test exists
+func TestSalvageNothingRecovered(t *testing.T) {
+	content := []byte(`<robot generator="Robot 7.0"><suite id="s1" name="Root"><test id="s1-t1" name="Cut`)
+	if _, err := parseOutput(content, "output.xml", statsOptions{Salvage: true}); err == nil {
+		t.Error("Expected a parse error when no test could be recovered")
+	}
+}
@@ -0,0 +1,147 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+)
+
+// elementScanner skips UTF-8 report content at byte level, without
+// tokenizing it, while tracking the element depth for the parsing
+// limits. Text content is skipped with a single search for the next '<'.
+type elementScanner struct {
+	r        *bufio.Reader
+	depth    int
+	limits   xmlLimits
+	filename string
+}
+
+// skipTo advances the reader past the '<' of the next start tag of the
+// element at the depth. Markup is escaped in text and attribute values,
+// so every '<' outside comments and CDATA sections starts a tag. It
+// reports whether the element was found before the end of the input.
+func (s *elementScanner) skipTo(name string, depth int) (bool, error) {
+	for {
+		if err := s.skipPast('<'); err != nil {
+			return false, eofFalse(err)
+		}
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return false, eofFalse(err)
+		}
+		switch c {
+		case '/':
+			s.depth--
+			err = s.skipPast('>')
+		case '?':
+			err = s.skipUntil("?>")
+		case '!':
+			err = s.skipDeclaration()
+		default:
+			s.r.UnreadByte()
+			if s.depth == depth && s.atName(name) {
+				return true, nil
+			}
+			s.depth++
+			if err := s.limits.checkDepth(s.depth, s.filename); err != nil {
+				return false, err
+			}
+			var empty bool
+			if empty, err = s.skipTag(); empty {
+				s.depth--
+			}
+		}
+		if err != nil {
+			return false, eofFalse(err)
+		}
+	}
+}
+
+// atName reports whether the unread input starts with the element name.
+func (s *elementScanner) atName(name string) bool {
+	next, _ := s.r.Peek(len(name) + 1)
+	if len(next) <= len(name) || string(next[:len(name)]) != name {
+		return false
+	}
+	switch next[len(name)] {
+	case '>', '/', ' ', '\t', '\n', '\r':
+		return true
+	}
+	return false
+}
+
+// skipPast advances the reader past the next occurrence of the byte.
+func (s *elementScanner) skipPast(delim byte) error {
+	for {
+		_, err := s.r.ReadSlice(delim)
+		if err != bufio.ErrBufferFull {
+			return err
+		}
+	}
+}
+
+// skipUntil advances the reader past the next occurrence of the
+// terminator, which is at most 8 bytes long.
+func (s *elementScanner) skipUntil(term string) error {
+	var window [8]byte
+	n := len(term)
+	for seen := 0; ; seen++ {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		copy(window[:n-1], window[1:n])
+		window[n-1] = c
+		if seen >= n-1 && string(window[:n]) == term {
+			return nil
+		}
+	}
+}
+
+// skipDeclaration skips a comment, a CDATA section or a directive after
+// "<!". Directives are checked like in the other parsing paths.
+func (s *elementScanner) skipDeclaration() error {
+	if next, _ := s.r.Peek(2); string(next) == "--" {
+		return s.skipUntil("-->")
+	}
+	if next, _ := s.r.Peek(7); string(next) == "[CDATA[" {
+		return s.skipUntil("]]>")
+	}
+	directive, err := s.r.ReadBytes('>')
+	if err != nil {
+		return err
+	}
+	return checkDirective(xml.Directive(directive[:len(directive)-1]), s.filename)
+}
+
+// skipTag advances the reader past the end of the current start tag and
+// reports whether the element is empty, like <kw/>. Quoted attribute
+// values may contain '>'.
+func (s *elementScanner) skipTag() (bool, error) {
+	var quote, last byte
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return last == '/', nil
+		}
+		last = c
+	}
+}
+
+// eofFalse maps the end of the input to a nil error.
+func eofFalse(err error) error {
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+// TestElementScanner validates skipping report content at byte level
+func TestElementScanner(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		found   bool
+		rest    string
+		wantErr string
+	}{
+		{
+			name:    "element after the suite tree",
+			content: `<suite name="a"><test name="b"><kw/></test></suite><statistics><total/></statistics>`,
+			found:   true,
+			rest:    "statistics><total/>",
+		},
+		{
+			name:    "nested element is not matched",
+			content: `<suite><statistics/></suite><statistics>`,
+			found:   true,
+			rest:    "statistics>",
+		},
+		{
+			name:    "comments, CDATA and quoted markup",
+			content: `<!-- <statistics> --><msg><![CDATA[<statistics>]]></msg><kw a="x>y" b='/>'/><statistics >`,
+			found:   true,
+			rest:    "statistics >",
+		},
+		{
+			name:    "longer element name",
+			content: `<statistics2/><statistics/>`,
+			found:   true,
+			rest:    "statistics/>",
+		},
+		{
+			name:    "missing element",
+			content: `<suite><test/></suite>`,
+		},
+		{
+			name:    "depth limit",
+			content: `<suite><suite><suite><test/></suite></suite></suite><statistics>`,
+			wantErr: "deeper than the limit",
+		},
+		{
+			name:    "entity declaration",
+			content: `<!DOCTYPE robot [<!ENTITY a "b">]><statistics>`,
+			wantErr: "declares entities",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(strings.NewReader(tt.content))
+			scanner := &elementScanner{r: r, depth: 1, limits: xmlLimits{MaxDepth: 4}, filename: "output.xml"}
+			found, err := scanner.skipTo("statistics", 1)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if found != tt.found {
+				t.Fatalf("Expected found %v, got %v", tt.found, found)
+			}
+			if rest, _ := r.Peek(len(tt.rest)); found && string(rest) != tt.rest {
+				t.Errorf("Expected the reader at %q, got %q", tt.rest, rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Schema validation modes.
+const (
+	schemaValidationWarn = "warn" // log the structural problems
+	schemaValidationFail = "fail" // reject reports with structural problems
+)
+
+// maxSchemaProblems limits the problems reported per file.
+const maxSchemaProblems = 20
+
+// validSchemaValidation reports whether the schema validation mode is
+// known.
+func validSchemaValidation(mode string) bool {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "", schemaValidationWarn, schemaValidationFail:
+		return true
+	}
+	return false
+}
+
+// outputSchema describes a version of the Robot Framework output schema,
+// the schemaversion attribute of the <robot> element. Reports of Robot
+// Framework 3 have no schema version.
+type outputSchema struct {
+	Version  int
+	Robot    string   // Robot Framework versions using the schema
+	Statuses []string // valid status values
+	ISOTimes bool     // start and elapsed instead of starttime and endtime
+}
+
+// outputSchemas are the known output schema versions.
+var outputSchemas = []outputSchema{
+	{Version: 0, Robot: "3.x", Statuses: []string{"PASS", "FAIL", "NOT_RUN"}},
+	{Version: 2, Robot: "4.x", Statuses: []string{"PASS", "FAIL", "SKIP", "NOT RUN"}},
+	{Version: 3, Robot: "5.x", Statuses: []string{"PASS", "FAIL", "SKIP", "NOT RUN"}},
+	{Version: 4, Robot: "6.x", Statuses: []string{"PASS", "FAIL", "SKIP", "NOT RUN"}},
+	{Version: 5, Robot: "7.x", Statuses: []string{"PASS", "FAIL", "SKIP", "NOT RUN"}, ISOTimes: true},
+}
+
+func (s outputSchema) String() string {
+	if s.Version == 0 {
+		return "legacy schema (Robot Framework " + s.Robot + ")"
+	}
+	return fmt.Sprintf("schema version %d (Robot Framework %s)", s.Version, s.Robot)
+}
+
+// schemaProblem is a structural problem of a report.
+type schemaProblem struct {
+	Line    int
+	Message string
+}
+
+func (p schemaProblem) String() string {
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+// schemaReport is the result of validating a report against the output
+// schema it declares.
+type schemaReport struct {
+	Schema   outputSchema
+	Problems []schemaProblem
+	Omitted  int // problems beyond maxSchemaProblems
+}
+
+// msgLevels are the valid message levels.
+var msgLevels = []string{"TRACE", "DEBUG", "INFO", "HTML", "WARN", "ERROR", "FAIL", "SKIP"}
+
+// schemaElement tracks an open element during validation.
+type schemaElement struct {
+	Name     string
+	Line     int
+	Statuses int // direct <status> children
+}
+
+// validateSchema checks the structure of the report against the rules of
+// the output schema declared by its schemaversion attribute: the element
+// nesting, the required attributes, the status values and the time
+// attributes of the schema version.
+func validateSchema(data []byte) (schemaReport, error) {
+	var report schemaReport
+	problem := func(line int, format string, a ...interface{}) {
+		if len(report.Problems) == maxSchemaProblems {
+			report.Omitted++
+			return
+		}
+		report.Problems = append(report.Problems, schemaProblem{Line: line, Message: fmt.Sprintf(format, a...)})
+	}
+
+	decoder := newReportDecoder(bytes.NewReader(data))
+	var stack []*schemaElement
+	rootSuites := 0
+	statistics := 0 // depth inside <statistics>, holding <suite> entries
+	for {
+		tok, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		line, _ := decoder.InputPos()
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1].Name
+			}
+			if statistics > 0 || name == "statistics" {
+				statistics++
+				stack = append(stack, &schemaElement{Name: name, Line: line})
+				continue
+			}
+			switch {
+			case parent == "" && name != "robot":
+				return report, fmt.Errorf("root element is <%s>, expected <robot>", name)
+			case parent == "":
+				schema, declared, err := detectSchema(attrValue(t, "schemaversion"), attrValue(t, "generator"))
+				if err != nil {
+					return report, err
+				}
+				report.Schema = schema
+				if attrValue(t, "generator") == "" {
+					problem(line, "<robot> is missing the generator attribute")
+				}
+				if !declared && schema.Version > 0 {
+					problem(line, "<robot> is missing the schemaversion attribute of Robot Framework %s", schema.Robot)
+				}
+			case parent == "robot" && name == "suite":
+				rootSuites++
+			case parent == "robot" && name != "statistics" && name != "errors":
+				problem(line, "unexpected <%s> in <robot>", name)
+			case name == "suite" && parent != "suite":
+				problem(line, "<suite> in <%s>, expected in <robot> or <suite>", parent)
+			case name == "test" && parent != "suite":
+				problem(line, "<test> in <%s>, expected in <suite>", parent)
+			}
+			if name == "suite" || name == "test" {
+				if attrValue(t, "name") == "" {
+					problem(line, "<%s> is missing the name attribute", name)
+				}
+			}
+			if name == "kw" && attrValue(t, "name") == "" {
+				problem(line, "<kw> is missing the name attribute")
+			}
+			if parents, ok := controlParents[name]; ok && !containsString(parents, parent) {
+				problem(line, "<%s> in <%s>, expected in <%s>", name, parent, strings.Join(parents, "> or <"))
+			}
+			if name == "status" {
+				if len(stack) > 0 {
+					stack[len(stack)-1].Statuses++
+				}
+				checkStatus(t, line, report.Schema, problem)
+			}
+			if name == "msg" {
+				if level := attrValue(t, "level"); level != "" && !containsString(msgLevels, level) {
+					problem(line, "invalid message level %q", level)
+				}
+			}
+			stack = append(stack, &schemaElement{Name: name, Line: line})
+		case xml.EndElement:
+			if len(stack) == 0 {
+				continue
+			}
+			element := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if statistics > 0 {
+				statistics--
+				continue
+			}
+			if _, control := controlElements[element.Name]; control || element.Name == "suite" || element.Name == "test" || element.Name == "kw" {
+				if element.Statuses != 1 {
+					problem(element.Line, "<%s> has %d <status> elements, expected 1", element.Name, element.Statuses)
+				}
+			}
+		}
+	}
+	if report.Schema.Robot == "" {
+		return report, fmt.Errorf("no <robot> element")
+	}
+	if rootSuites != 1 {
+		problem(1, "<robot> has %d root <suite> elements, expected 1", rootSuites)
+	}
+	return report, nil
+}
+
+// detectSchema returns the output schema of the schemaversion attribute,
+// or of the generator version when the attribute is missing. It reports
+// whether the schema version was declared.
+func detectSchema(version, generator string) (outputSchema, bool, error) {
+	number := 0
+	switch v, ok := parseGenerator(generator); {
+	case version != "":
+		var err error
+		if number, err = strconv.Atoi(version); err != nil {
+			return outputSchema{}, true, fmt.Errorf("invalid schema version %q", version)
+		}
+	case ok && v.Major >= 4 && v.Major <= maxSupportedMajor:
+		// RF 4 introduced schema version 2
+		number = v.Major - 2
+	}
+	for _, schema := range outputSchemas {
+		if schema.Version == number {
+			return schema, version != "", nil
+		}
+	}
+	return outputSchema{}, true, fmt.Errorf("unknown schema version %s", version)
+}
+
+// checkStatus validates the status value and time attributes.
+func checkStatus(t xml.StartElement, line int, schema outputSchema, problem func(int, string, ...interface{})) {
+	status := attrValue(t, "status")
+	if !containsString(schema.Statuses, status) {
+		problem(line, "invalid status %q in %s", status, schema)
+	}
+	for _, a := range t.Attr {
+		switch a.Name.Local {
+		case "starttime", "endtime":
+			if schema.ISOTimes {
+				problem(line, "attribute %s is not defined in %s", a.Name.Local, schema)
+			} else if _, err := time.Parse("20060102 15:04:05.000", a.Value); err != nil && a.Value != "N/A" {
+				problem(line, "invalid %s %q", a.Name.Local, a.Value)
+			}
+		case "start":
+			if !schema.ISOTimes {
+				problem(line, "attribute start is not defined in %s", schema)
+			} else if _, err := time.Parse("2006-01-02T15:04:05.999999", a.Value); err != nil {
+				problem(line, "invalid start %q", a.Value)
+			}
+		case "elapsed":
+			if !schema.ISOTimes {
+				problem(line, "attribute elapsed is not defined in %s", schema)
+			} else if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+				problem(line, "invalid elapsed %q", a.Value)
+			}
+		}
+	}
+}
+
+// checkSchema validates the report when schema validation is enabled,
+// logging the matched schema and the structural problems. In fail mode
+// reports with problems are rejected.
+func checkSchema(data []byte, filename, mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return nil
+	}
+	report, err := validateSchema(data)
+	if err != nil {
+		return fmt.Errorf("schema validation failed: %v", err)
+	}
+	if len(report.Problems) == 0 {
+		logrus.Infof("%s matches the %s", filename, report.Schema)
+		return nil
+	}
+	logrus.Warnf("Schema validation of %s against the %s found %d problems:", filename, report.Schema, len(report.Problems)+report.Omitted)
+	for _, problem := range report.Problems {
+		logrus.Warnf("  %s", problem)
+	}
+	if report.Omitted > 0 {
+		logrus.Warnf("  ... and %d more", report.Omitted)
+	}
+	if mode == schemaValidationFail {
+		return fmt.Errorf("report does not match the %s: %s", report.Schema, report.Problems[0])
+	}
+	return nil
+}
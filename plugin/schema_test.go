@@ -0,0 +1,115 @@
+package plugin
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestValidateSchema validates the detected schema and the structural
+// problems of reports
+func TestValidateSchema(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   string
+		schema   int
+		problems []string
+	}{
+		{
+			name:   "valid RF 7",
+			report: `<robot generator="Robot 7.0" schemaversion="5"><suite id="s1" name="Root"><test id="s1-t1" name="A"><kw name="Log"><msg level="INFO">hi</msg><status status="PASS" start="2024-01-01T12:00:00.000001" elapsed="0.1"/></kw><status status="PASS" start="2024-01-01T12:00:00.000001" elapsed="0.1"/></test><status status="PASS" elapsed="0.2"/></suite><statistics><suite><stat pass="1" fail="0" skip="0">Root</stat></suite></statistics></robot>`,
+			schema: 5,
+		},
+		{
+			name:   "legacy",
+			report: `<robot generator="Robot 3.2.2"><suite id="s1" name="Root"><test id="s1-t1" name="A"><status status="SKIP" starttime="20240101 12:00:00.000" endtime="N/A"/></test><status status="FAIL"/></suite></robot>`,
+			schema: 0,
+			problems: []string{
+				`line 1: invalid status "SKIP" in legacy schema (Robot Framework 3.x)`,
+			},
+		},
+		{
+			name: "broken RF 6",
+			report: `<robot generator="Robot 6.1" schemaversion="4">
+<suite id="s1">
+<test id="s1-t1" name="A">
+<status status="PASSED" start="2024-01-01T12:00:00"/>
+<status status="PASS"/>
+</test>
+<kw name="Setup"></kw>
+<status status="PASS"/>
+</suite>
+<test id="t2" name="B"><status status="PASS"/></test>
+</robot>`,
+			schema: 4,
+			problems: []string{
+				"line 2: <suite> is missing the name attribute",
+				`line 4: invalid status "PASSED" in schema version 4 (Robot Framework 6.x)`,
+				"line 4: attribute start is not defined in schema version 4 (Robot Framework 6.x)",
+				"line 3: <test> has 2 <status> elements, expected 1",
+				"line 7: <kw> has 0 <status> elements, expected 1",
+				"line 10: unexpected <test> in <robot>",
+			},
+		},
+		{
+			name: "control structures",
+			report: `<robot generator="Robot 7.0" schemaversion="5">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="A">
+<for flavor="IN"><var>${x}</var><value>1</value>
+<iter><var name="${x}">1</var><kw name="Log"><status status="PASS"/></kw><status status="PASS"/></iter>
+<status status="PASS"/>
+</for>
+<if><branch type="IF" condition="True"><status status="PASS"/></branch></if>
+<iter><status status="PASS"/></iter>
+<status status="PASS"/>
+</test>
+<status status="PASS"/>
+</suite>
+</robot>`,
+			schema: 5,
+			problems: []string{
+				"line 8: <if> has 0 <status> elements, expected 1",
+				"line 9: <iter> in <test>, expected in <for> or <while>",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := validateSchema([]byte(test.report))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if report.Schema.Version != test.schema {
+				t.Errorf("Expected schema version %d, got %d", test.schema, report.Schema.Version)
+			}
+			var problems []string
+			for _, problem := range report.Problems {
+				problems = append(problems, problem.String())
+			}
+			if diff := cmp.Diff(test.problems, problems); diff != "" {
+				t.Errorf("Unexpected problems (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// TestSchemaValidationModes validates warn and fail modes while parsing
+func TestSchemaValidationModes(t *testing.T) {
+	data, err := os.ReadFile("../testdata/robot_report.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseOutput(data, "robot_report.xml", statsOptions{SchemaValidation: "warn"}); err != nil {
+		t.Errorf("Expected warn mode to accept the report, got %v", err)
+	}
+	_, err = parseOutput(data, "robot_report.xml", statsOptions{SchemaValidation: "fail"})
+	if err == nil || !strings.Contains(err.Error(), "missing the schemaversion attribute") {
+		t.Errorf("Expected fail mode to reject the report, got %v", err)
+	}
+	if _, err := validateSchema([]byte(`<testsuite name="junit"/>`)); err == nil {
+		t.Error("Expected an error for a non Robot Framework report")
+	}
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import "math"
+
+// scoringModel weighs tests and keyword failures into a single quality
+// score between 0 and 100.
+type scoringModel struct {
+	// CriticalWeight is the weight of critical tests. Other tests weigh 1.
+	CriticalWeight float64
+
+	// TagWeights multiplies the weight of tests with the given
+	// (normalized) tags. The highest matching tag weight applies.
+	TagWeights map[string]float64
+
+	// FailedKeywordWeight is the number of points every failed keyword
+	// deducts from the score.
+	FailedKeywordWeight float64
+}
+
+// newScoringModel returns the scoring model of the plugin arguments, or
+// nil when quality scoring is disabled.
+func newScoringModel(args Args) *scoringModel {
+	if !args.QualityScore && args.MinQualityScore == nil {
+		return nil
+	}
+	model := &scoringModel{
+		CriticalWeight:      args.CriticalTestWeight,
+		FailedKeywordWeight: args.FailedKeywordWeight,
+	}
+	if model.CriticalWeight == 0 {
+		model.CriticalWeight = 1
+	}
+	if len(args.TagWeights) > 0 {
+		model.TagWeights = map[string]float64{}
+		for tag, weight := range args.TagWeights {
+			model.TagWeights[normalizeTag(tag)] = weight
+		}
+	}
+	return model
+}
+
+// testWeight returns the weight of a test in the quality score.
+func (m *scoringModel) testWeight(test Test, critical bool) float64 {
+	weight := 1.0
+	if critical {
+		weight = m.CriticalWeight
+	}
+	tagWeight, matched := 0.0, false
+	for _, tag := range test.AllTags() {
+		if w, ok := m.TagWeights[normalizeTag(tag)]; ok && (!matched || w > tagWeight) {
+			tagWeight, matched = w, true
+		}
+	}
+	if matched {
+		weight *= tagWeight
+	}
+	return weight
+}
+
+// add returns the sum of the weights. A nil receiver counts as zero.
+func (w *QualityWeights) add(other QualityWeights) *QualityWeights {
+	sum := other
+	if w != nil {
+		sum.Passed += w.Passed
+		sum.Total += w.Total
+		sum.Penalty += w.Penalty
+	}
+	return &sum
+}
+
+// qualityScore returns the quality score metric, zero when scoring is
+// disabled.
+func qualityScore(stats StatsResult) float64 {
+	if stats.QualityScore == nil {
+		return 0
+	}
+	return *stats.QualityScore
+}
+
+// Score returns the quality score: the weighted share of passed tests in
+// percent, less the failed keyword penalty, between 0 and 100.
+func (w QualityWeights) Score() float64 {
+	score := 100.0
+	if w.Total > 0 {
+		score = w.Passed / w.Total * 100
+	}
+	return math.Max(0, math.Min(100, score-w.Penalty))
+}
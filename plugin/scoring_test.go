@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestQualityScore validates the weighted quality score and its gate
+func TestQualityScore(t *testing.T) {
+	report := `<robot generator="Robot 3.2.2">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Checkout">
+<kw name="Pay"><status status="FAIL"/></kw>
+<tags><tag>P1</tag></tags>
+<status status="FAIL" critical="yes"/>
+</test>
+<test id="s1-t2" name="Login">
+<status status="PASS" critical="yes"/>
+</test>
+<test id="s1-t3" name="Footer">
+<tags><tag>cosmetic</tag></tags>
+<status status="PASS" critical="no"/>
+</test>
+<test id="s1-t4" name="Pending">
+<status status="SKIP" critical="no"/>
+</test>
+</suite>
+</robot>`
+	args := Args{
+		QualityScore:        true,
+		CriticalTestWeight:  2,
+		TagWeights:          map[string]float64{"p 1": 3, "Cosmetic": 0.5},
+		FailedKeywordWeight: 1.5,
+	}
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	robotOutput, err := parseOutput([]byte(report), "output.xml", opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(*robotOutput, opts)
+
+	// Checkout 2*3 failed, Login 2 passed, Footer 1*0.5 passed
+	want := QualityWeights{Passed: 2.5, Total: 8.5, Penalty: 1.5}
+	if stats.QualityWeights == nil || *stats.QualityWeights != want {
+		t.Fatalf("Expected weights %+v, got %+v", want, stats.QualityWeights)
+	}
+	if stats.QualityScore == nil || math.Abs(*stats.QualityScore-27.91) > 0.01 {
+		t.Fatalf("Expected quality score 27.91, got %v", qualityScore(stats))
+	}
+
+	min := 50.0
+	err = validateThresholds(stats, Args{PassThreshold: 10, UnstableThreshold: 5, MinQualityScore: &min})
+	if err == nil || !strings.Contains(err.Error(), "undercuts the minimum quality score") {
+		t.Errorf("Expected the quality score gate to fail, got %v", err)
+	}
+	min = 25
+	if err := validateThresholds(stats, Args{PassThreshold: 10, UnstableThreshold: 5, MinQualityScore: &min}); err != nil {
+		t.Errorf("Expected the quality score gate to pass, got %v", err)
+	}
+}
+
+// TestQualityScoreBounds validates clamping and aggregation of the score
+func TestQualityScoreBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights QualityWeights
+		want    float64
+	}{
+		{"no tests", QualityWeights{}, 100},
+		{"all passed", QualityWeights{Passed: 4, Total: 4}, 100},
+		{"penalty below zero", QualityWeights{Passed: 1, Total: 4, Penalty: 30}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.weights.Score(); got != tc.want {
+				t.Errorf("Expected score %v, got %v", tc.want, got)
+			}
+		})
+	}
+
+	var sum *QualityWeights
+	sum = sum.add(QualityWeights{Passed: 1, Total: 2})
+	sum = sum.add(QualityWeights{Passed: 3, Total: 4, Penalty: 5})
+	if want := (QualityWeights{Passed: 4, Total: 6, Penalty: 5}); *sum != want {
+		t.Errorf("Expected weights %+v, got %+v", want, *sum)
+	}
+}
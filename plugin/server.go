@@ -0,0 +1,292 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultMaxUploadSize limits the size of uploaded reports.
+const defaultMaxUploadSize = 100 << 20
+
+// runIDPattern restricts run IDs to names safe for file names.
+var runIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// ServerOptions configures a Server.
+type ServerOptions struct {
+	// DataDir persists uploaded runs as stats JSON files. Runs are only
+	// kept in memory when empty.
+	DataDir string
+
+	// MaxUploadSize limits the size of uploaded reports in bytes.
+	MaxUploadSize int64
+}
+
+// ServerRun is a report uploaded to the server.
+type ServerRun struct {
+	ID       string      `json:"id"`
+	Received time.Time   `json:"received"`
+	Stats    StatsResult `json:"stats"`
+}
+
+// runSummary is the run listing entry.
+type runSummary struct {
+	ID           string    `json:"id"`
+	Received     time.Time `json:"received"`
+	TotalTests   int       `json:"total_tests"`
+	PassedTests  int       `json:"passed_tests"`
+	FailedTests  int       `json:"failed_tests"`
+	SkippedTests int       `json:"skipped_tests"`
+	FailureRate  float64   `json:"failure_rate"`
+}
+
+// Server is an HTTP results service backed by the statistics engine.
+// Reports are uploaded to POST /api/v1/runs and queried with
+// GET /api/v1/runs, GET /api/v1/runs/{id} and GET /api/v1/stats.
+type Server struct {
+	analyzer *Analyzer
+	opts     ServerOptions
+	mux      *http.ServeMux
+
+	mu   sync.RWMutex
+	runs []ServerRun
+}
+
+// NewServer returns a server counting tests with the plugin arguments,
+// loading the runs persisted in the data directory.
+func NewServer(args Args, opts ServerOptions) (*Server, error) {
+	if _, err := newStatsOptions(args); err != nil {
+		return nil, fmt.Errorf("invalid statistics options: %v", err)
+	}
+	if opts.MaxUploadSize <= 0 {
+		opts.MaxUploadSize = defaultMaxUploadSize
+	}
+
+	s := &Server{analyzer: newAnalyzer(args), opts: opts, mux: http.NewServeMux()}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	s.mux.HandleFunc("POST /api/v1/runs", s.handleUpload)
+	s.mux.HandleFunc("GET /api/v1/runs", s.handleListRuns)
+	s.mux.HandleFunc("GET /api/v1/runs/{id}", s.handleGetRun)
+	s.mux.HandleFunc("GET /api/v1/stats", s.handleStats)
+	return s, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleUpload parses the uploaded output.xml and stores it as a run.
+// The run ID is taken from the id query parameter, or generated.
+func (s *Server) handleUpload(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		writeError(w, status, err)
+		return
+	}
+	stats, err := s.analyzer.AnalyzeReader(r.Context(), "upload", bytes.NewReader(data))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		id = s.nextRunID()
+	}
+	if !runIDPattern.MatchString(id) {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid run id %q", id))
+		return
+	}
+	if s.find(id) >= 0 {
+		s.mu.Unlock()
+		writeError(w, http.StatusConflict, fmt.Errorf("run %s already exists", id))
+		return
+	}
+	run := ServerRun{ID: id, Received: time.Now().UTC(), Stats: *stats}
+	if err := s.persist(run); err != nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	s.runs = append(s.runs, run)
+	s.mu.Unlock()
+
+	logrus.Infof("Stored run %s: %d tests, %d failed\n", id, stats.TotalTests, stats.FailedTests)
+	writeJSONResponse(w, http.StatusCreated, summarizeRun(run))
+}
+
+// handleListRuns lists the stored runs, oldest first.
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summaries := []runSummary{}
+	for _, run := range s.runs {
+		summaries = append(summaries, summarizeRun(run))
+	}
+	writeJSONResponse(w, http.StatusOK, summaries)
+}
+
+// handleGetRun returns the statistics of a single run.
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i := s.find(r.PathValue("id"))
+	if i < 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, s.runs[i])
+}
+
+// handleStats returns the statistics aggregated over all runs, or over
+// the most recent runs when the last query parameter is set.
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := s.runs
+	if last := r.URL.Query().Get("last"); last != "" {
+		n, err := strconv.Atoi(last)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid last parameter %q", last))
+			return
+		}
+		if n < len(runs) {
+			runs = runs[len(runs)-n:]
+		}
+	}
+
+	stats := StatsResult{}
+	for _, run := range runs {
+		aggregateStats(&stats, run.Stats)
+	}
+	sortAggregated(&stats)
+	writeJSONResponse(w, http.StatusOK, stats)
+}
+
+// nextRunID returns a generated run ID: the number following the largest
+// numeric run ID, skipping IDs already taken.
+func (s *Server) nextRunID() string {
+	next := len(s.runs) + 1
+	for _, run := range s.runs {
+		if n, err := strconv.Atoi(run.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	for s.find(strconv.Itoa(next)) >= 0 {
+		next++
+	}
+	return strconv.Itoa(next)
+}
+
+func (s *Server) find(id string) int {
+	for i := range s.runs {
+		if s.runs[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// load reads the runs persisted in the data directory.
+func (s *Server) load() error {
+	if s.opts.DataDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.opts.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(s.opts.DataDir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var run ServerRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return fmt.Errorf("failed to parse run %s: %v", file, err)
+		}
+		s.runs = append(s.runs, run)
+	}
+	sort.SliceStable(s.runs, func(i, j int) bool {
+		return s.runs[i].Received.Before(s.runs[j].Received)
+	})
+	logrus.Infof("Loaded %d runs from %s\n", len(s.runs), s.opts.DataDir)
+	return nil
+}
+
+// persist writes the run to the data directory.
+func (s *Server) persist(run ServerRun) error {
+	if s.opts.DataDir == "" {
+		return nil
+	}
+	return WriteJSON(filepath.Join(s.opts.DataDir, run.ID+".json"), run)
+}
+
+// ListenAndServe serves the API on the address until the context is
+// cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	srv := &http.Server{Addr: addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+
+	logrus.Infof("Serving Robot Framework stats on %s\n", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func summarizeRun(run ServerRun) runSummary {
+	return runSummary{
+		ID:           run.ID,
+		Received:     run.Received,
+		TotalTests:   run.Stats.TotalTests,
+		PassedTests:  run.Stats.PassedTests,
+		FailedTests:  run.Stats.FailedTests,
+		SkippedTests: run.Stats.SkippedTests,
+		FailureRate:  run.Stats.FailureRate,
+	}
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSONResponse(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,123 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func uploadReport(t *testing.T, handler http.Handler, path, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/runs"+query, strings.NewReader(string(data))))
+	return rec
+}
+
+// TestServer validates uploading reports and querying run and aggregated stats
+func TestServer(t *testing.T) {
+	dataDir := t.TempDir()
+	server, err := NewServer(Args{CountSkippedTests: true}, ServerOptions{DataDir: dataDir})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if rec := uploadReport(t, server, "../testdata/rf7/output.xml", "?id=nightly-1"); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := uploadReport(t, server, "../testdata/robot_report.xml", ""); rec.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := uploadReport(t, server, "../testdata/robot_report.xml", "?id=nightly-1"); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for a duplicate run, got %d", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/runs/nightly-1", nil))
+	var run ServerRun
+	if err := json.NewDecoder(rec.Body).Decode(&run); err != nil || run.Stats.TotalTests != 2 {
+		t.Errorf("Expected run nightly-1 with 2 tests, got %+v (%v)", run.Stats, err)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/stats", nil))
+	var stats StatsResult
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil || stats.TotalTests != 6 {
+		t.Errorf("Expected 6 tests across runs, got %d (%v)", stats.TotalTests, err)
+	}
+
+	// Runs are reloaded from the data directory
+	reloaded, err := NewServer(Args{}, ServerOptions{DataDir: dataDir})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rec = httptest.NewRecorder()
+	reloaded.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/runs", nil))
+	var summaries []runSummary
+	if err := json.NewDecoder(rec.Body).Decode(&summaries); err != nil || len(summaries) != 2 {
+		t.Errorf("Expected 2 persisted runs, got %+v (%v)", summaries, err)
+	}
+}
+
+// TestServerRejectsInvalidUploads validates upload errors
+func TestServerRejectsInvalidUploads(t *testing.T) {
+	server, err := NewServer(Args{}, ServerOptions{MaxUploadSize: 64})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rec := uploadReport(t, server, "../testdata/robot_report.xml", ""); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized report, got %d", rec.Code)
+	}
+
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/runs?id=../x", strings.NewReader("<robot")))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for malformed XML, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/runs", iotest.ErrReader(errors.New("connection reset"))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a failed upload, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/runs/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown run, got %d", rec.Code)
+	}
+}
+
+// TestServerRunIDs validates that generated run IDs follow the largest
+// numeric run ID and never collide with stored runs
+func TestServerRunIDs(t *testing.T) {
+	server, err := NewServer(Args{}, ServerOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var ids []string
+	for _, query := range []string{"?id=2", "", "?id=7", "?id=nightly", ""} {
+		rec := uploadReport(t, server, "../testdata/robot_report.xml", query)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201 for %q, got %d: %s", query, rec.Code, rec.Body)
+		}
+		var summary runSummary
+		if err := json.NewDecoder(rec.Body).Decode(&summary); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, summary.ID)
+	}
+	if diff := cmp.Diff([]string{"2", "3", "7", "nightly", "8"}, ids); diff != "" {
+		t.Errorf("Unexpected run IDs (-want +got):\n%s", diff)
+	}
+}
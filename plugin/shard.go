@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ShardStats stores the tests and the duration of a pabot worker.
+// Duration is the wall clock time of the worker outputs in milliseconds.
+type ShardStats struct {
+	Worker     string  `json:"worker"`
+	TotalTests int     `json:"total_tests"`
+	Duration   float64 `json:"duration"`
+}
+
+// shardBalance returns the pabot shards of the per-file results, sorted
+// by worker, and their imbalance: the percentage the slowest shard took
+// longer than the mean shard duration. It returns no shards for fewer
+// than two workers.
+func shardBalance(files []FileStats) ([]ShardStats, float64) {
+	byWorker := map[string]*ShardStats{}
+	for _, file := range files {
+		if file.Worker == "" {
+			continue
+		}
+		shard, ok := byWorker[file.Worker]
+		if !ok {
+			shard = &ShardStats{Worker: file.Worker}
+			byWorker[file.Worker] = shard
+		}
+		shard.TotalTests += file.TotalTests
+		duration := file.WallClockTime
+		if duration == 0 {
+			duration = file.ExecutionTime
+		}
+		shard.Duration += duration
+	}
+	if len(byWorker) < 2 {
+		return nil, 0
+	}
+
+	shards := make([]ShardStats, 0, len(byWorker))
+	var total, slowest float64
+	for _, shard := range byWorker {
+		shards = append(shards, *shard)
+		total += shard.Duration
+		if shard.Duration > slowest {
+			slowest = shard.Duration
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Worker < shards[j].Worker })
+
+	mean := total / float64(len(shards))
+	if mean == 0 {
+		return shards, 0
+	}
+	return shards, (slowest - mean) / mean * 100
+}
+
+// logShardBalance logs the duration and test count of each pabot shard
+// and the shard imbalance.
+func logShardBalance(stats StatsResult, unit string) {
+	if len(stats.Shards) == 0 {
+		return
+	}
+	logrus.Infof("Shard Balance:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, shard := range stats.Shards {
+		logrus.Infof("worker %s: %d tests, %s\n", shard.Worker, shard.TotalTests, formatDuration(shard.Duration, unit))
+	}
+	logrus.Infof("Imbalance: %.2f%% (slowest shard over the mean)\n", stats.ShardImbalance)
+	logrus.Infof("-----------------------------------------------\n")
+}
@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestShardBalance validates the per-worker totals and the imbalance of
+// pabot shards
+func TestShardBalance(t *testing.T) {
+	files := []FileStats{
+		{File: "a.xml", Worker: "1", TotalTests: 4, WallClockTime: 3000},
+		{File: "b.xml", Worker: "0", TotalTests: 2, WallClockTime: 1000},
+		{File: "c.xml", Worker: "0", TotalTests: 1, ExecutionTime: 1000},
+		{File: "output.xml", TotalTests: 10, WallClockTime: 9000},
+	}
+	shards, imbalance := shardBalance(files)
+
+	expected := []ShardStats{
+		{Worker: "0", TotalTests: 3, Duration: 2000},
+		{Worker: "1", TotalTests: 4, Duration: 3000},
+	}
+	if diff := cmp.Diff(expected, shards); diff != "" {
+		t.Errorf("Shards mismatch (-want +got):\n%s", diff)
+	}
+	if math.Abs(imbalance-20) > 1e-9 {
+		t.Errorf("Expected an imbalance of 20%%, got %v", imbalance)
+	}
+
+	if shards, imbalance := shardBalance(files[:1]); shards != nil || imbalance != 0 {
+		t.Errorf("Expected no shards for a single worker, got %v and %v", shards, imbalance)
+	}
+}
+
+// TestShardBalancePabot validates the shards of the pabot test data and
+// the imbalance warning
+func TestShardBalancePabot(t *testing.T) {
+	files, err := locateFiles("../testdata/pabot", fileQuery{Pattern: "output.xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := processFiles(files, opts)
+	if len(stats.Shards) != 2 {
+		t.Fatalf("Expected 2 shards, got %+v", stats.Shards)
+	}
+
+	limit := 0.0
+	if stats.ShardImbalance > limit {
+		gate, _ := findGate(evaluateThresholds(stats, Args{ShardImbalanceWarn: &limit}), "shard_imbalance")
+		if gate.Outcome != gateWarn {
+			t.Errorf("Expected a shard imbalance warning, got %s", gate.Outcome)
+		}
+	}
+	limit = stats.ShardImbalance + 1
+	gate, _ := findGate(evaluateThresholds(stats, Args{ShardImbalanceWarn: &limit}), "shard_imbalance")
+	if gate.Outcome != gatePass {
+		t.Errorf("Expected a passed shard imbalance gate, got %s", gate.Outcome)
+	}
+}
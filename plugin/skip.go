@@ -0,0 +1,50 @@
+package plugin
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// unspecifiedSkipReason groups skipped tests without a skip message.
+const unspecifiedSkipReason = "unspecified"
+
+// skipReason returns the skip message of a skipped test: the status text
+// in RF 4 and later, or the last status message in earlier versions.
+func skipReason(test Test) string {
+	if reason := strings.TrimSpace(test.Status.Text); reason != "" {
+		return reason
+	}
+	for i := len(test.Status.Messages) - 1; i >= 0; i-- {
+		if reason := strings.TrimSpace(test.Status.Messages[i].Text); reason != "" {
+			return reason
+		}
+	}
+	return unspecifiedSkipReason
+}
+
+// logSkipReasons logs the skipped test counts by reason, most frequent
+// first.
+func logSkipReasons(stats StatsResult) {
+	if len(stats.SkipReasons) == 0 {
+		return
+	}
+	reasons := make([]string, 0, len(stats.SkipReasons))
+	for reason := range stats.SkipReasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if stats.SkipReasons[reasons[i]] != stats.SkipReasons[reasons[j]] {
+			return stats.SkipReasons[reasons[i]] > stats.SkipReasons[reasons[j]]
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	logrus.Infof("Skip Reasons:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, reason := range reasons {
+		logrus.Infof("%s: %d\n", reason, stats.SkipReasons[reason])
+	}
+	logrus.Infof("-----------------------------------------------\n")
+}
@@ -0,0 +1,64 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSkipReasons validates the skip reason breakdown
+func TestSkipReasons(t *testing.T) {
+	report := `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="One"><status status="SKIP">Environment unavailable</status></test>
+<test id="s1-t2" name="Two"><status status="SKIP">
+Environment unavailable
+</status></test>
+<test id="s1-t3" name="Three"><status status="SKIP">Feature flag disabled</status></test>
+<test id="s1-t4" name="Four"><status status="SKIP"/></test>
+<test id="s1-t5" name="Five"><status status="PASS"/></test>
+</suite>
+</robot>`
+	robotOutput, err := parseOutput([]byte(report), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(*robotOutput, statsOptions{})
+
+	want := map[string]int{
+		"Environment unavailable": 2,
+		"Feature flag disabled":   1,
+		unspecifiedSkipReason:     1,
+	}
+	if diff := cmp.Diff(want, stats.SkipReasons); diff != "" {
+		t.Errorf("Skip reasons mismatch (-want +got):\n%s", diff)
+	}
+
+	// RF 3 reports the reason as a status message
+	legacy := Test{Status: Status{Status: "SKIP", Messages: []Msg{{Level: "WARN", Text: "Skipped due to known issue"}}}}
+	if got := skipReason(legacy); got != "Skipped due to known issue" {
+		t.Errorf("Expected the status message as skip reason, got %q", got)
+	}
+}
+
+// TestErrorMessage validates the failure message extraction from the status
+// text and the ERROR status messages of older reports
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		status Status
+		want   string
+	}{
+		{"Status Text", Status{Status: "FAIL", Text: "\n  expired != valid\n"}, "expired != valid"},
+		{"Status Text Over Messages", Status{Status: "FAIL", Text: "1 != 2", Messages: []Msg{{Level: "ERROR", Text: "old"}}}, "1 != 2"},
+		{"Last Error Message", Status{Status: "FAIL", Messages: []Msg{{Level: "ERROR", Text: "first"}, {Level: "INFO", Text: "info"}, {Level: "ERROR", Text: "last"}}}, "last"},
+		{"No Message", Status{Status: "FAIL"}, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := testErrorMessage(Test{Status: tc.status}); got != tc.want {
+				t.Errorf("Expected error message %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
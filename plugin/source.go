@@ -0,0 +1,61 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// linkFailureSources sets the link of every failed test with a source
+// from the URL template. The {path} placeholder is replaced with the
+// suite source relative to the source root, using forward slashes, and
+// {line} with the test line number. Sources outside the source root, which
+// defaults to the working directory, are not linked.
+func linkFailureSources(stats *StatsResult, template, root string) {
+	if template == "" {
+		return
+	}
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	for i, test := range stats.FailedTestsDetails {
+		path, ok := sourcePath(test.Source, root)
+		if !ok {
+			continue
+		}
+		line := ""
+		if test.Line > 0 {
+			line = strconv.Itoa(test.Line)
+		}
+		stats.FailedTestsDetails[i].URL = strings.NewReplacer(
+			"{path}", path,
+			"{line}", line,
+		).Replace(template)
+	}
+}
+
+// sourcePath returns the source relative to the root, using forward
+// slashes. Relative sources are kept; it reports false for sources
+// outside the root.
+func sourcePath(source, root string) (string, bool) {
+	if source == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(source) {
+		return filepath.ToSlash(filepath.Clean(source)), true
+	}
+	rel, err := filepath.Rel(root, source)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// sourceLocation formats a source and line number as "path:line".
+func sourceLocation(source string, line int) string {
+	if line <= 0 {
+		return source
+	}
+	return source + ":" + strconv.Itoa(line)
+}
@@ -0,0 +1,33 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLinkFailureSources validates links to the source of failed tests
+func TestLinkFailureSources(t *testing.T) {
+	stats := StatsResult{FailedTestsDetails: []FailedTestDetails{
+		{Name: "Pay With Expired Card", Source: "/work/tests/checkout/payment.robot", Line: 10},
+		{Name: "Legacy", Source: "/work/tests/legacy.robot"},
+		{Name: "Elsewhere", Source: "/opt/shared/common.robot", Line: 3},
+		{Name: "No Source"},
+	}}
+
+	linkFailureSources(&stats, "https://github.com/acme/shop/blob/main/{path}#L{line}", "/work")
+
+	want := []string{
+		"https://github.com/acme/shop/blob/main/tests/checkout/payment.robot#L10",
+		"https://github.com/acme/shop/blob/main/tests/legacy.robot#L",
+		"",
+		"",
+	}
+	var got []string
+	for _, test := range stats.FailedTestsDetails {
+		got = append(got, test.URL)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Links mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,150 @@
+package plugin
+
+import (
+	"context"
+	"sort"
+)
+
+// defaultSplitGroups is the number of groups of the split manifest
+// without pabot workers in the run.
+const defaultSplitGroups = 2
+
+// splitRuns is the number of recorded runs, including the current run,
+// the suite durations of the split manifest are averaged over.
+const splitRuns = 10
+
+// SplitManifest is a suggested split of the suites into groups of about
+// the same duration. Durations are in milliseconds.
+type SplitManifest struct {
+	Groups   []SplitGroup `json:"groups"`
+	Duration float64      `json:"duration"` // sum of the suite durations
+	Runs     int          `json:"runs"`     // runs the durations are averaged over
+}
+
+// SplitGroup is a group of suites of the split manifest. Arguments holds
+// the robot and pabot --suite arguments selecting the suites.
+type SplitGroup struct {
+	Suites    []string `json:"suites"`
+	Duration  float64  `json:"duration"`
+	Arguments []string `json:"arguments"`
+}
+
+// splitGroups returns the number of groups of the split manifest: the
+// configured number, or the number of pabot workers of the run.
+func splitGroups(args Args, stats StatsResult) int {
+	switch {
+	case args.SplitGroups > 0:
+		return args.SplitGroups
+	case len(stats.Shards) > 0:
+		return len(stats.Shards)
+	}
+	return defaultSplitGroups
+}
+
+// leafSuiteDurations returns the durations of the suites without child
+// suites. The durations of a suite reported by several files, e.g. split
+// across pabot workers, add up.
+func leafSuiteDurations(suites []SuiteStats) map[string]float64 {
+	parents := map[string]bool{}
+	for _, suite := range suites {
+		for i, c := range suite.Name {
+			if c == '.' {
+				parents[suite.Name[:i]] = true
+			}
+		}
+	}
+
+	durations := map[string]float64{}
+	for _, suite := range suites {
+		if !parents[suite.Name] {
+			durations[suite.Name] += suite.ExecutionTime
+		}
+	}
+	return durations
+}
+
+// suiteDurations returns the durations of the leaf suites of the current
+// run, averaged over the runs of the records they were reported in. The
+// records are oldest first; only the last runs-1 records are used.
+func suiteDurations(records []HistoryRecord, stats StatsResult, runs int) (map[string]float64, int) {
+	if len(records) > runs-1 {
+		records = records[len(records)-(runs-1):]
+	}
+	durations := leafSuiteDurations(stats.Suites)
+	counts := make(map[string]int, len(durations))
+	for name := range durations {
+		counts[name] = 1
+	}
+	for _, record := range records {
+		for name, duration := range leafSuiteDurations(record.Stats.Suites) {
+			if _, ok := durations[name]; ok {
+				durations[name] += duration
+				counts[name]++
+			}
+		}
+	}
+	for name := range durations {
+		durations[name] /= float64(counts[name])
+	}
+	return durations, len(records) + 1
+}
+
+// buildSplitManifest splits the suites into at most n groups, assigning
+// the longest suites first to the group with the shortest duration.
+// Groups without suites are left out.
+func buildSplitManifest(durations map[string]float64, n int) SplitManifest {
+	names := make([]string, 0, len(durations))
+	for name := range durations {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if durations[names[i]] != durations[names[j]] {
+			return durations[names[i]] > durations[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	var manifest SplitManifest
+	groups := make([]SplitGroup, n)
+	for _, name := range names {
+		shortest := 0
+		for i := range groups {
+			if groups[i].Duration < groups[shortest].Duration {
+				shortest = i
+			}
+		}
+		groups[shortest].Suites = append(groups[shortest].Suites, name)
+		groups[shortest].Duration += durations[name]
+		manifest.Duration += durations[name]
+	}
+
+	for _, group := range groups {
+		if len(group.Suites) == 0 {
+			continue
+		}
+		sort.Strings(group.Suites)
+		for _, suite := range group.Suites {
+			group.Arguments = append(group.Arguments, "--suite", suite)
+		}
+		manifest.Groups = append(manifest.Groups, group)
+	}
+	return manifest
+}
+
+// newSplitManifestReporter writes the split manifest, balanced by the
+// suite durations in the history when PLUGIN_HISTORY_DIR is set, and by
+// the durations of the current run otherwise.
+func newSplitManifestReporter(args Args) (Reporter, error) {
+	if args.SplitManifest == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		durations, runs := stats.SuiteDurations, stats.SuiteDurationRuns
+		if durations == nil {
+			durations, runs = suiteDurations(nil, stats, splitRuns)
+		}
+		manifest := buildSplitManifest(durations, splitGroups(args, stats))
+		manifest.Runs = runs
+		return WriteJSON(args.SplitManifest, manifest)
+	}), nil
+}
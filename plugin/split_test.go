@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestLeafSuiteDurations validates that parent suites are left out and
+// suites reported by several files add up
+func TestLeafSuiteDurations(t *testing.T) {
+	suites := []SuiteStats{
+		{Name: "Root", ExecutionTime: 900},
+		{Name: "Root.Web", ExecutionTime: 500},
+		{Name: "Root.Web.Login", ExecutionTime: 300},
+		{Name: "Root.Web Admin", ExecutionTime: 200},
+		{Name: "Root.Api", ExecutionTime: 100},
+		{Name: "Root.Api", ExecutionTime: 50},
+	}
+	expected := map[string]float64{
+		"Root.Web.Login": 300,
+		"Root.Web Admin": 200,
+		"Root.Api":       150,
+	}
+	if diff := cmp.Diff(expected, leafSuiteDurations(suites)); diff != "" {
+		t.Errorf("Leaf suite durations mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestSuiteDurations validates averaging over the last recorded runs
+func TestSuiteDurations(t *testing.T) {
+	record := func(d float64) HistoryRecord {
+		return HistoryRecord{Stats: StatsResult{Suites: []SuiteStats{{Name: "A", ExecutionTime: d}, {Name: "Removed", ExecutionTime: d}}}}
+	}
+	records := []HistoryRecord{record(1000), record(200), record(400)}
+	stats := StatsResult{Suites: []SuiteStats{{Name: "A", ExecutionTime: 600}, {Name: "New", ExecutionTime: 50}}}
+
+	durations, runs := suiteDurations(records, stats, 3)
+	expected := map[string]float64{"A": 400, "New": 50}
+	if diff := cmp.Diff(expected, durations); diff != "" {
+		t.Errorf("Suite durations mismatch (-want +got):\n%s", diff)
+	}
+	if runs != 3 {
+		t.Errorf("Expected 3 runs, got %d", runs)
+	}
+}
+
+// TestBuildSplitManifest validates the balancing of the suites
+func TestBuildSplitManifest(t *testing.T) {
+	durations := map[string]float64{"A": 700, "B": 500, "C": 300, "D": 200, "E": 200}
+	expected := SplitManifest{
+		Duration: 1900,
+		Groups: []SplitGroup{
+			{Suites: []string{"A", "D"}, Duration: 900, Arguments: []string{"--suite", "A", "--suite", "D"}},
+			{Suites: []string{"B", "C", "E"}, Duration: 1000, Arguments: []string{"--suite", "B", "--suite", "C", "--suite", "E"}},
+		},
+	}
+	if diff := cmp.Diff(expected, buildSplitManifest(durations, 2)); diff != "" {
+		t.Errorf("Split manifest mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := buildSplitManifest(map[string]float64{"A": 1}, 4); len(got.Groups) != 1 {
+		t.Errorf("Expected empty groups to be left out, got %+v", got.Groups)
+	}
+}
+
+// TestSplitManifestReporter validates the manifest file and the default
+// number of groups
+func TestSplitManifestReporter(t *testing.T) {
+	stats := StatsResult{
+		Suites: []SuiteStats{{Name: "A", ExecutionTime: 300}, {Name: "B", ExecutionTime: 200}, {Name: "C", ExecutionTime: 100}},
+		Shards: []ShardStats{{Worker: "0"}, {Worker: "1"}, {Worker: "2"}},
+	}
+	file := filepath.Join(t.TempDir(), "split.json")
+	reporter, err := newSplitManifestReporter(Args{SplitManifest: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got SplitManifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Groups) != 3 || got.Runs != 1 {
+		t.Errorf("Expected a group per pabot worker from a single run, got %+v", got)
+	}
+}
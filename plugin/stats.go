@@ -1,17 +1,290 @@
 package plugin
 
 import (
-	"sync"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/sirupsen/logrus"
 )
 
+// statsOptions controls how statistics are computed from a report.
+type statsOptions struct {
+	OnlyCritical bool
+	CountSkipped bool
+
+	// TreatSkippedAsFailed counts skipped tests as failed tests.
+	TreatSkippedAsFailed bool
+
+	// NotRunAs is the status NOT RUN tests are counted as, SKIP or FAIL.
+	// Empty counts them only as not run.
+	NotRunAs string
+
+	// StatusMapping maps custom test statuses to the status they are
+	// counted as, empty for ignored statuses.
+	StatusMapping map[string]string
+
+	// MaxTestDuration is the per-test duration budget in milliseconds.
+	// Zero disables the budget.
+	MaxTestDuration float64
+
+	// MaxTestDurationByTag overrides the duration budget for tests with
+	// the given (normalized) tags.
+	MaxTestDurationByTag map[string]float64
+
+	// TagFilter selects the tests included in the statistics. A nil
+	// filter includes all tests.
+	TagFilter *tagFilter
+
+	// AllowFailure matches the tags of tests whose failures are reported
+	// but not counted as failures. A nil pattern allows no failures.
+	AllowFailure tagPattern
+
+	// Scoring weighs tests and keyword failures into a quality score. A
+	// nil model disables quality scoring.
+	Scoring *scoringModel
+
+	// NameFilter selects the suites and tests included in the statistics
+	// by name. A nil filter includes all suites and tests.
+	NameFilter *nameFilter
+
+	// ProgressInterval is how often progress is logged while report
+	// files are processed. Zero disables progress reporting.
+	ProgressInterval time.Duration
+
+	// Cache stores the statistics of report files by content hash. A nil
+	// cache disables caching.
+	Cache *statsCache
+
+	// SkipKeywords skips the keyword traversal. Keyword counts are not
+	// computed and warnings are counted from the <errors> block.
+	SkipKeywords bool
+
+	// FailureStream receives the failure details of every report file
+	// instead of the aggregated statistics. A nil stream keeps them.
+	FailureStream *failureStream
+
+	// SkipTestResults skips the per-test results and the details of
+	// allowed failures, so memory does not grow with the number of tests,
+	// e.g. while streaming the failure details.
+	SkipTestResults bool
+
+	// OutputTags holds the tags counted separately, by normalized tag.
+	OutputTags map[string]string
+
+	// TagStatistics keeps the tag statistics of the <statistics> block.
+	TagStatistics bool
+
+	// AtRiskDetails keeps the details of passed tests with failed
+	// keywords.
+	AtRiskDetails bool
+
+	// ReservedTags holds the actions of the robot: reserved tags, by
+	// normalized tag name without the prefix.
+	ReservedTags map[string]string
+
+	// Verbose logs a line for every processed test.
+	Verbose bool
+
+	// FastSummary computes statistics from the report's <statistics>
+	// block instead of walking every test.
+	FastSummary bool
+
+	// Salvage recovers the complete tests of truncated or malformed
+	// reports instead of failing.
+	Salvage bool
+
+	// Limits guards parsing against hostile or pathological reports.
+	Limits xmlLimits
+
+	// profile holds the parsing rules of the report format version.
+	profile formatProfile
+
+	// FileWorkers is the number of report files processed in parallel,
+	// one per CPU when zero.
+	FileWorkers int
+
+	// FailFastLimit stops processing the remaining report files once
+	// more tests failed. Nil processes every file.
+	FailFastLimit *int
+
+	// FailureContext is the number of keyword messages logged before a
+	// failure included in its details, at or above FailureContextLevel.
+	FailureContext      int
+	FailureContextLevel string
+
+	// SchemaValidation validates the reports against the output schema
+	// before parsing: warn logs the problems, fail rejects the report.
+	SchemaValidation string
+
+	// SuiteNamespace prefixes the suite names of every report file with
+	// the file or directory name. Empty keeps the suite names.
+	SuiteNamespace string
+
+	// namespace is the suite name prefix of the report file.
+	namespace string
+
+	// suiteSelected is set while traversing a suite selected by the
+	// suite name include patterns.
+	suiteSelected bool
+
+	// suiteLongName is the long name of the suite being traversed.
+	suiteLongName string
+
+	// suiteSource is the source of the innermost suite with a source.
+	suiteSource string
+}
+
+// newStatsOptions returns the statistics options for the plugin arguments.
+func newStatsOptions(args Args) (statsOptions, error) {
+	opts := statsOptions{
+		OnlyCritical:         args.OnlyCritical,
+		CountSkipped:         args.CountSkippedTests,
+		TreatSkippedAsFailed: args.TreatSkippedAsFailed,
+		NotRunAs:             notRunStatus(args.NotRunAs),
+		StatusMapping:        newStatusMapping(args.StatusMapping),
+		MaxTestDuration:      args.MaxTestDuration,
+		FastSummary:          useFastSummary(args),
+		Salvage:              args.SalvageTruncated,
+		Limits:               newXMLLimits(args),
+		Scoring:              newScoringModel(args),
+		Verbose:              args.Verbose,
+		SkipKeywords:         args.SkipKeywordStats,
+		OutputTags:           newOutputTags(args.OutputTags),
+		TagStatistics:        args.TagStatistics,
+		ReservedTags:         newReservedTags(args.ReservedTags),
+		AtRiskDetails:        args.AtRiskTests,
+		SuiteNamespace:       args.SuiteNamespace,
+		SchemaValidation:     args.SchemaValidation,
+		FailureContext:       args.FailureContextMessages,
+		FailureContextLevel:  failureContextLevel(args.FailureContextLevel),
+		ProgressInterval:     progressInterval(args),
+		FileWorkers:          args.FileWorkers,
+	}
+	if args.FailFast {
+		opts.FailFastLimit = &args.PassThreshold
+	}
+	// skipped rate limits are meaningless unless skipped tests are counted
+	if args.MaxSkippedRate != nil || args.SkippedRateWarn != nil || args.SkippedRateFail != nil {
+		opts.CountSkipped = true
+	}
+	if len(args.MaxTestDurationByTag) > 0 {
+		opts.MaxTestDurationByTag = map[string]float64{}
+		for tag, budget := range args.MaxTestDurationByTag {
+			opts.MaxTestDurationByTag[normalizeTag(tag)] = budget
+		}
+	}
+	tagFilter, err := newTagFilter(args.IncludeTags, args.ExcludeTags)
+	if err != nil {
+		return opts, err
+	}
+	opts.TagFilter = tagFilter
+
+	if args.AllowFailureTag != "" {
+		if opts.AllowFailure, err = compileTagPattern(args.AllowFailureTag); err != nil {
+			return opts, err
+		}
+	}
+
+	nameFilter, err := newNameFilter(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.NameFilter = nameFilter
+	opts.Cache = newStatsCache(args)
+	return opts, nil
+}
+
+// testResultSettings returns the enabled settings that need the per-test
+// results of the run.
+func testResultSettings(args Args) []string {
+	var settings []string
+	for _, setting := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"PLUGIN_COMPARE_TO", args.CompareTo != ""},
+		{"PLUGIN_PREVIOUS_STATS_URL", args.PreviousStatsURL != ""},
+		{"PLUGIN_HISTORY_DIR", args.HistoryDir != ""},
+		{"PLUGIN_TIMELINE_FILE", args.TimelineFile != ""},
+		{"PLUGIN_SUITE_TREE", args.SuiteTree},
+		{"suite_thresholds", len(args.SuiteThresholds) > 0},
+	} {
+		if setting.enabled {
+			settings = append(settings, setting.name)
+		}
+	}
+	return settings
+}
+
+// needsTestResults reports whether the run keeps the per-test results:
+// for the settings using them, and for the stats file, which later runs
+// compare against.
+func needsTestResults(args Args) bool {
+	return len(testResultSettings(args)) > 0 || args.StatsFile != ""
+}
+
 // computeStats calculates all test statistics from the parsed XML.
-func computeStats(robotOutput RobotOutput, onlyCritical, countSkipped bool) StatsResult {
-	stats := StatsResult{}
-	var mu sync.Mutex
+func computeStats(robotOutput RobotOutput, opts statsOptions) StatsResult {
+	stats := StatsResult{Tags: emptyTagStats(opts.OutputTags)}
+
+	// Select the parsing rules for the report format version
+	opts.profile = detectProfile(robotOutput)
+	opts.profile.setVersions(&stats)
+
+	// Root suite wall-clock time
+	if duration, ok := opts.profile.duration(robotOutput.Suite.Status); ok {
+		stats.WallClockTime = duration
+		if start, ok := opts.profile.start(robotOutput.Suite.Status); ok {
+			stats.StartTime = start
+			stats.EndTime = start.Add(time.Duration(duration * float64(time.Millisecond)))
+		}
+	}
+
+	stats.Incomplete = robotOutput.Incomplete
+	stats.RecoveredTests = robotOutput.RecoveredTests
+
+	// Walk the suite tree sequentially; the results are sorted by suite
+	// and test name below
+	if !opts.SkipTestResults {
+		stats.Tests = make([]TestResult, 0, countSuiteTests(&robotOutput.Suite))
+	}
+	processSuite(&robotOutput.Suite, "", &stats, opts)
+	if len(stats.Tests) == 0 {
+		stats.Tests = nil
+	}
+	checkStructure(&robotOutput.Suite, &stats)
+
+	// Robot Framework's counts of combined tags ignore the filters
+	if opts.OutputTags != nil && opts.TagFilter == nil && opts.NameFilter == nil && !opts.OnlyCritical {
+		countCombinedTags(robotOutput.Statistics, &stats, opts)
+	}
+	if opts.TagStatistics {
+		stats.TagStatistics = robotTagStatistics(robotOutput.Statistics, opts.TreatSkippedAsFailed)
+	}
+	if opts.SkipKeywords {
+		stats.TotalWarnings += reportedWarnings(robotOutput)
+	} else {
+		stats.TotalWarnings += executionWarnings(robotOutput)
+	}
+	if opts.Scoring != nil {
+		stats.QualityWeights = stats.QualityWeights.add(QualityWeights{
+			Penalty: opts.Scoring.FailedKeywordWeight * float64(stats.FailedKeywords),
+		})
+		score := stats.QualityWeights.Score()
+		stats.QualityScore = &score
+	}
 
-	// Call processSuite directly instead of launching a goroutine
-	processSuite(&robotOutput.Suite, &stats, &mu, onlyCritical, countSkipped)
+	// Sort suite breakdown by name and slow tests by duration for
+	// deterministic results
+	sort.Slice(stats.Suites, func(i, j int) bool {
+		return stats.Suites[i].Name < stats.Suites[j].Name
+	})
+	sortSlowTests(stats.SlowTests)
+	sortTestResults(stats.Tests)
+	stats.Metadata = mergeSuiteMetadata(stats.Suites)
 
 	// ✅ Compute failure & skipped rates safely (avoid division by zero)
 	if stats.TotalTests > 0 {
@@ -24,136 +297,442 @@ func computeStats(robotOutput RobotOutput, onlyCritical, countSkipped bool) Stat
 	return stats
 }
 
-// processSuite extracts statistics recursively.
-func processSuite(suite *Suite, stats *StatsResult, mu *sync.Mutex, onlyCritical, countSkipped bool) {
-	if len(suite.Tests) > 0 || len(suite.Suites) > 0 {
-		mu.Lock()
-		stats.TotalSuites++
-		mu.Unlock()
+// processSuite extracts statistics recursively, visiting the tests and
+// the child suites in document order.
+func processSuite(suite *Suite, parentName string, stats *StatsResult, opts statsOptions) {
+	longName := suite.Name
+	if parentName != "" {
+		longName = parentName + "." + suite.Name
+	}
+
+	// ✅ Apply suite name patterns
+	if opts.NameFilter.excludesSuite(suite.Name, longName) {
+		return
+	}
+	if opts.NameFilter.selectsSuite(suite.Name, longName) {
+		opts.suiteSelected = true
+	}
+	opts.suiteLongName = namespaced(opts.namespace, longName)
+	if suite.Source != "" {
+		opts.suiteSource = suite.Source
 	}
 
 	// ✅ Extract suite execution time
-	startTime, errStart := parseRobotTime(suite.Status.StartTime)
-	endTime, errEnd := parseRobotTime(suite.Status.EndTime)
-	if errStart == nil && errEnd == nil {
-		executionTime := int(endTime.Sub(startTime).Milliseconds()) // ✅ Convert int64 to int
-		mu.Lock()
-		stats.ExecutionTime += float64(executionTime)
-		mu.Unlock()
+	executionTime := 0.0
+	if duration, ok := opts.profile.duration(suite.Status); ok && opts.suiteSelected {
+		executionTime = duration
+		stats.ExecutionTime += executionTime
+	}
+
+	// ✅ Count suite setup and teardown and their warnings
+	if opts.suiteSelected && !opts.SkipKeywords {
+		stats.TotalWarnings += keywordWarnings(suite.Keywords)
+		countFixtures(suite.Keywords, stats)
 	}
 
-	var wg sync.WaitGroup
+	if opts.suiteSelected && (len(suite.Tests) > 0 || len(suite.Suites) > 0) {
+		stats.TotalSuites++
+		stats.Suites = append(stats.Suites, SuiteStats{
+			Name:          opts.suiteLongName,
+			ExecutionTime: executionTime,
+			Metadata:      metadataMap(suite.AllMetadata()),
+		})
+	}
 
 	for _, test := range suite.Tests {
-		if onlyCritical && test.Status.Critical != "yes" {
+		if opts.OnlyCritical && !opts.profile.isCritical(test) {
 			continue // ✅ Skip non-critical tests if onlyCritical flag is enabled
 		}
+		if !opts.TagFilter.allows(test.AllTags()) {
+			continue // ✅ Skip tests filtered out by tag patterns
+		}
+		if !opts.suiteSelected || !opts.NameFilter.allowsTest(test.Name, longName+"."+test.Name) {
+			continue // ✅ Skip tests filtered out by name patterns
+		}
+		if reservedAction(test.AllTags(), opts.ReservedTags) == reservedExclude {
+			continue // ✅ Skip tests excluded by reserved tags
+		}
 
-		wg.Add(1)
-		go func(test Test) {
-			defer wg.Done()
-			processTest(test, suite.Name, stats, mu, countSkipped)
-		}(test)
+		processTest(test, suite.Name, stats, opts)
 	}
 
-	for _, subSuite := range suite.Suites {
-		wg.Add(1)
-		go func(subSuite Suite) {
-			defer wg.Done()
-			processSuite(&subSuite, stats, mu, onlyCritical, countSkipped)
-		}(subSuite)
+	for i := range suite.Suites {
+		processSuite(&suite.Suites[i], longName, stats, opts)
 	}
-
-	wg.Wait()
 }
 
 // processTest processes a single test case and updates statistics.
-func processTest(test Test, suiteName string, stats *StatsResult, mu *sync.Mutex, countSkipped bool) {
-	mu.Lock()
+func processTest(test Test, suiteName string, stats *StatsResult, opts statsOptions) {
 	stats.TotalTests++
-	mu.Unlock()
 
 	// ✅ Extract execution time for individual tests
-	startTime, errStart := parseRobotTime(test.Status.StartTime)
-	endTime, errEnd := parseRobotTime(test.Status.EndTime)
-	if errStart == nil && errEnd == nil {
-		executionTime := int(endTime.Sub(startTime).Milliseconds()) // ✅ Convert int64 to int
-		mu.Lock()
-		stats.ExecutionTime += float64(executionTime)
-		mu.Unlock()
+	executionTime, ok := opts.profile.duration(test.Status)
+	if ok {
+		stats.ExecutionTime += executionTime
+		stats.CumulativeTestTime += executionTime
+
+		// ✅ Flag tests exceeding their duration budget
+		if budget := opts.testDurationBudget(test); budget > 0 && executionTime > budget {
+			stats.SlowTests = append(stats.SlowTests, SlowTestDetails{
+				Name:     test.Name,
+				Suite:    namespaced(opts.namespace, suiteName),
+				Status:   test.Status.Status,
+				Duration: executionTime,
+				Budget:   budget,
+			})
+		}
+	}
+
+	if opts.Verbose {
+		logrus.Infof("%s: %s.%s (%.2f ms)\n", test.Status.Status, opts.suiteLongName, test.Name, executionTime)
 	}
 
 	// ✅ Track critical tests
-	if test.Status.Critical == "yes" {
-		mu.Lock()
+	critical := opts.profile.isCritical(test)
+	if critical {
 		stats.TotalCritical++
-		mu.Unlock()
 	}
 
 	// ✅ Extract error messages
-	errorMsg := ""
-	for _, msg := range test.Status.Messages {
-		if msg.Level == "ERROR" {
-			errorMsg = msg.Text
-		}
+	errorMsg := opts.profile.errorMessage(test)
+
+	status := countedStatus(test.Status.Status, opts.StatusMapping, opts.NotRunAs, opts.TreatSkippedAsFailed)
+	action := reservedAction(test.AllTags(), opts.ReservedTags)
+	if action == reservedSkip {
+		status = countedStatus("SKIP", nil, opts.NotRunAs, opts.TreatSkippedAsFailed)
+	}
+	allowedFailure := status == "FAIL" && (action == reservedAllowFailure || opts.AllowFailure != nil && opts.AllowFailure.match(test.AllTags()))
+	details := FailedTestDetails{
+		Name:         test.Name,
+		Suite:        namespaced(opts.namespace, suiteName),
+		LongSuite:    opts.suiteLongName,
+		Status:       test.Status.Status,
+		Duration:     executionTime,
+		ErrorMessage: errorMsg,
+		Source:       opts.suiteSource,
+		Line:         test.Line,
+	}
+	if status == "FAIL" {
+		details.Context = failureContext(test, opts.FailureContext, opts.FailureContextLevel)
+		details.FailedIterations = failedIterations(test.Keywords)
 	}
 
 	// ✅ Count pass/fail/skip stats
-	mu.Lock()
-	switch test.Status.Status {
+	result := TestResult{
+		Name:           test.Name,
+		Suite:          opts.suiteLongName,
+		Status:         test.Status.Status,
+		Duration:       executionTime,
+		ErrorMessage:   errorMsg,
+		AllowedFailure: allowedFailure,
+	}
+	if start, ok := opts.profile.start(test.Status); ok {
+		result.Start = &start
+	}
+	if !opts.SkipTestResults {
+		stats.Tests = append(stats.Tests, result)
+	}
+	if test.Status.Status == "NOT RUN" {
+		stats.NotRunTests++
+	}
+	if unknownStatus(test.Status.Status, opts.StatusMapping) {
+		stats.UnknownStatusTests++
+		logrus.Debugf("Unknown status %q of test %s.%s", test.Status.Status, opts.suiteLongName, test.Name)
+	}
+
+	if opts.OutputTags != nil {
+		counted := status
+		if allowedFailure {
+			counted = ""
+		}
+		countTestTags(stats, test, counted, opts.OutputTags)
+	}
+
+	if opts.Scoring != nil && (status == "PASS" || status == "FAIL") && !allowedFailure {
+		weight := opts.Scoring.testWeight(test, critical)
+		passed := 0.0
+		if status == "PASS" {
+			passed = weight
+		}
+		stats.QualityWeights = stats.QualityWeights.add(QualityWeights{Passed: passed, Total: weight})
+	}
+
+	if allowedFailure {
+		// ✅ Report failures of allow-failure tests without counting them
+		stats.AllowedFailures++
+		if !opts.SkipTestResults {
+			stats.AllowedFailuresDetails = append(stats.AllowedFailuresDetails, details)
+		}
+		status = ""
+	}
+
+	switch status {
 	case "PASS":
 		stats.PassedTests++
-		if test.Status.Critical == "yes" {
+		if critical {
 			stats.CriticalPassed++
 		}
 	case "FAIL":
 		stats.FailedTests++
-		if test.Status.Critical == "yes" {
+		if critical {
 			stats.CriticalFailed++
 		}
-		stats.FailedTestsDetails = append(stats.FailedTestsDetails, FailedTestDetails{
-			Name:         test.Name,
-			Suite:        suiteName,
-			Status:       "FAIL",
-			ErrorMessage: errorMsg,
-		})
+		stats.FailedTestsDetails = append(stats.FailedTestsDetails, details)
 	case "SKIP":
-		if countSkipped {
+		if opts.CountSkipped {
 			stats.SkippedTests++
 		}
 	}
-	mu.Unlock()
+	if test.Status.Status == "SKIP" {
+		if stats.SkipReasons == nil {
+			stats.SkipReasons = map[string]int{}
+		}
+		stats.SkipReasons[skipReason(test)]++
+	}
 
 	// ✅ Process test-level keywords
+	if opts.SkipKeywords {
+		return
+	}
+	countFixtures(test.Keywords, stats)
+	failedKeywords := stats.FailedKeywords
 	for _, kw := range test.Keywords {
-		processKeyword(&kw, stats, mu)
+		processKeyword(&kw, stats)
 	}
-}
 
-// processKeyword processes a keyword inside a test case or suite.
-func processKeyword(kw *Keyword, stats *StatsResult, mu *sync.Mutex) {
-	mu.Lock()
-	stats.TotalKeywords++
+	// ✅ Flag passed tests with failed keywords, e.g. ignored errors
+	if failed := stats.FailedKeywords - failedKeywords; failed > 0 && test.Status.Status == "PASS" {
+		countAtRiskTest(test, suiteName, failed, stats, opts)
+	}
+}
 
-	switch kw.Status.Status {
-	case "PASS":
-		stats.PassedKeywords++
-	case "FAIL":
-		stats.FailedKeywords++
-	case "SKIP":
-		stats.SkippedKeywords++
+// testErrorMessage returns the error message of the test: the status
+// text, which holds the failure message, or the last ERROR status message
+// of reports without one.
+func testErrorMessage(test Test) string {
+	if message := strings.TrimSpace(test.Status.Text); message != "" {
+		return message
+	}
+	errorMsg := ""
+	for _, msg := range test.Status.Messages {
+		if msg.Level == "ERROR" {
+			errorMsg = msg.Text
+		}
 	}
+	return errorMsg
+}
 
-	mu.Unlock()
+// processKeyword processes a keyword inside a test case or suite.
+func processKeyword(kw *Keyword, stats *StatsResult) {
+	// ✅ Count loop iterations, control structures are not keywords
+	if kw.iteration() {
+		stats.TotalIterations++
+		if kw.Status.Status == "FAIL" {
+			stats.FailedIterations++
+		}
+	}
+	if !kw.control() {
+		stats.TotalKeywords++
+		switch kw.Status.Status {
+		case "PASS":
+			stats.PassedKeywords++
+		case "FAIL":
+			stats.FailedKeywords++
+		case "SKIP":
+			stats.SkippedKeywords++
+		case "NOT RUN":
+			stats.NotRunKeywords++
+		}
+		countLibraryFailure(kw, stats)
+	}
+	for _, msg := range kw.Messages {
+		if msg.Level == "WARN" {
+			stats.TotalWarnings++
+		}
+	}
 
 	// ✅ Recursively process nested keywords
 	for _, subKw := range kw.Keywords {
-		processKeyword(&subKw, stats, mu)
+		processKeyword(&subKw, stats)
+	}
+}
+
+// testDurationBudget returns the duration budget of the test. The
+// largest matching per-tag override takes precedence over the default.
+func (opts statsOptions) testDurationBudget(test Test) float64 {
+	budget, overridden := 0.0, false
+	for _, tag := range test.AllTags() {
+		if b, ok := opts.MaxTestDurationByTag[normalizeTag(tag)]; ok && (!overridden || b > budget) {
+			budget, overridden = b, true
+		}
+	}
+	if overridden {
+		return budget
 	}
+	return opts.MaxTestDuration
+}
+
+// sortTestResults orders test results by suite and test name.
+func sortTestResults(tests []TestResult) {
+	sort.SliceStable(tests, func(i, j int) bool {
+		if tests[i].Suite != tests[j].Suite {
+			return tests[i].Suite < tests[j].Suite
+		}
+		return tests[i].Name < tests[j].Name
+	})
+}
+
+// sortSlowTests sorts slow tests by descending duration.
+func sortSlowTests(tests []SlowTestDetails) {
+	sort.SliceStable(tests, func(i, j int) bool {
+		if tests[i].Duration != tests[j].Duration {
+			return tests[i].Duration > tests[j].Duration
+		}
+		return tests[i].Suite+"."+tests[i].Name < tests[j].Suite+"."+tests[j].Name
+	})
+}
+
+// normalizeTag normalizes a tag the way Robot Framework matches tags:
+// case, space and underscore insensitive.
+func normalizeTag(tag string) string {
+	return strings.NewReplacer(" ", "", "_", "").Replace(strings.ToLower(tag))
 }
 
+// robotTimeLayouts are the timestamp formats used by Robot Framework:
+// RF 6 and earlier, and the ISO-8601 format of RF 7.
+var robotTimeLayouts = []string{legacyTimeLayout, isoTimeLayout}
+
 // parseRobotTime converts Robot Framework timestamps to Go time.
 func parseRobotTime(timestamp string) (time.Time, error) {
-	layout := "20060102 15:04:05.000"
-	return time.Parse(layout, timestamp)
-}
\ No newline at end of file
+	var err error
+	for _, layout := range robotTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, timestamp); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// statusStart returns the start time of a status. RF 7 reports it in
+// the start attribute, earlier versions in the starttime attribute.
+func statusStart(status Status) (time.Time, bool) {
+	start := status.Start
+	if start == "" {
+		start = status.StartTime
+	}
+	t, err := parseRobotTime(start)
+	return t, err == nil
+}
+
+// statusDuration returns the execution time of a status in milliseconds.
+// RF 7 reports the elapsed seconds, earlier versions the start and end
+// times.
+func statusDuration(status Status) (float64, bool) {
+	if status.Elapsed != "" {
+		return elapsedDuration(status.Elapsed)
+	}
+	startTime, errStart := parseRobotTime(status.StartTime)
+	endTime, errEnd := parseRobotTime(status.EndTime)
+	if errStart != nil || errEnd != nil {
+		return 0, false
+	}
+	return float64(endTime.Sub(startTime).Milliseconds()), true
+}
+
+// elapsedDuration converts the elapsed seconds of RF 7 to milliseconds.
+func elapsedDuration(elapsed string) (float64, bool) {
+	seconds, err := strconv.ParseFloat(elapsed, 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Round(seconds*1e6) / 1e3, true
+}
+
+// intervalDuration returns the milliseconds between the start and end
+// times of the status in the timestamp layout.
+func intervalDuration(status Status, layout string) (float64, bool) {
+	startTime, errStart := time.Parse(layout, status.StartTime)
+	endTime, errEnd := time.Parse(layout, status.EndTime)
+	if errStart != nil || errEnd != nil {
+		return 0, false
+	}
+	return float64(endTime.Sub(startTime).Milliseconds()), true
+}
+
+// keywordFailureRate returns the percentage of failed keywords, zero
+// without keywords.
+func keywordFailureRate(stats StatsResult) float64 {
+	if stats.TotalKeywords == 0 {
+		return 0
+	}
+	return float64(stats.FailedKeywords) / float64(stats.TotalKeywords) * 100
+}
+
+// statsMetrics returns the named metric values of the statistics, as
+// referenced by gate expressions.
+func statsMetrics(stats StatsResult) map[string]float64 {
+	return map[string]float64{
+		"total_suites":         float64(stats.TotalSuites),
+		"total_tests":          float64(stats.TotalTests),
+		"passed_tests":         float64(stats.PassedTests),
+		"failed_tests":         float64(stats.FailedTests),
+		"skipped_tests":        float64(stats.SkippedTests),
+		"not_run_tests":        float64(stats.NotRunTests),
+		"unknown_status_tests": float64(stats.UnknownStatusTests),
+		"duplicate_test_ids":   float64(stats.DuplicateTestIDs),
+		"empty_test_names":     float64(stats.EmptyTestNames),
+		"at_risk_tests":        float64(stats.AtRiskTests),
+		"total_keywords":       float64(stats.TotalKeywords),
+		"passed_keywords":      float64(stats.PassedKeywords),
+		"failed_keywords":      float64(stats.FailedKeywords),
+		"skipped_keywords":     float64(stats.SkippedKeywords),
+		"not_run_keywords":     float64(stats.NotRunKeywords),
+		"total_iterations":     float64(stats.TotalIterations),
+		"failed_iterations":    float64(stats.FailedIterations),
+		"keyword_failure_rate": keywordFailureRate(stats),
+		"total_setups":         float64(stats.Setups.Total),
+		"failed_setups":        float64(stats.Setups.Failed),
+		"total_teardowns":      float64(stats.Teardowns.Total),
+		"failed_teardowns":     float64(stats.Teardowns.Failed),
+		"total_warnings":       float64(stats.TotalWarnings),
+		"allowed_failures":     float64(stats.AllowedFailures),
+		"total_critical":       float64(stats.TotalCritical),
+		"critical_passed":      float64(stats.CriticalPassed),
+		"critical_failed":      float64(stats.CriticalFailed),
+		"failure_rate":         stats.FailureRate,
+		"skipped_rate":         stats.SkippedRate,
+		"execution_time":       stats.ExecutionTime,
+		"wall_clock_time":      stats.WallClockTime,
+		"cumulative_test_time": stats.CumulativeTestTime,
+		"flaky_tests":          float64(stats.FlakyTests),
+		"quality_score":        qualityScore(stats),
+		"shard_imbalance":      stats.ShardImbalance,
+	}
+}
+
+// notRunStatus normalizes the PLUGIN_NOT_RUN_AS setting to the status NOT
+// RUN tests are counted as. It returns an empty status for "ignore".
+func notRunStatus(setting string) string {
+	switch strings.ToLower(strings.TrimSpace(setting)) {
+	case "skip":
+		return "SKIP"
+	case "fail":
+		return "FAIL"
+	}
+	return ""
+}
+
+// countedStatus returns the status a test counts as: custom statuses
+// count as mapped, NOT RUN tests count as notRunAs, and skipped tests
+// count as failed when treatSkippedAsFailed is set.
+func countedStatus(status string, mapping map[string]string, notRunAs string, treatSkippedAsFailed bool) string {
+	status = mapStatus(status, mapping)
+	if status == "NOT RUN" && notRunAs != "" {
+		status = notRunAs
+	}
+	if status == "SKIP" && treatSkippedAsFailed {
+		status = "FAIL"
+	}
+	return status
+}
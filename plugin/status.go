@@ -0,0 +1,65 @@
+package plugin
+
+import "strings"
+
+// standardStatuses are the test statuses written by Robot Framework.
+var standardStatuses = []string{"PASS", "FAIL", "SKIP", "NOT RUN"}
+
+// statusTargets maps the PLUGIN_STATUS_MAPPING targets to the status a
+// custom status is counted as. Ignored statuses count only toward the
+// total.
+var statusTargets = map[string]string{
+	"pass":   "PASS",
+	"fail":   "FAIL",
+	"skip":   "SKIP",
+	"ignore": "",
+}
+
+// validStatusMapping reports whether the custom status can be mapped to
+// the target.
+func validStatusMapping(status, target string) bool {
+	if containsString(standardStatuses, normalizeStatus(status)) {
+		return false
+	}
+	_, ok := statusTargets[strings.ToLower(strings.TrimSpace(target))]
+	return ok
+}
+
+// newStatusMapping returns the statuses custom statuses are counted as,
+// keyed by the normalized custom status. It returns nil without custom
+// statuses.
+func newStatusMapping(setting map[string]string) map[string]string {
+	if len(setting) == 0 {
+		return nil
+	}
+	mapping := map[string]string{}
+	for status, target := range setting {
+		mapping[normalizeStatus(status)] = statusTargets[strings.ToLower(strings.TrimSpace(target))]
+	}
+	return mapping
+}
+
+// unknownStatus reports whether the test status is neither a Robot
+// Framework status nor a mapped custom status, including empty statuses.
+func unknownStatus(status string, mapping map[string]string) bool {
+	if containsString(standardStatuses, status) {
+		return false
+	}
+	_, mapped := mapping[normalizeStatus(status)]
+	return !mapped
+}
+
+// normalizeStatus returns the status in upper case without surrounding
+// spaces, as Robot Framework writes statuses.
+func normalizeStatus(status string) string {
+	return strings.ToUpper(strings.TrimSpace(status))
+}
+
+// mapStatus returns the status a custom status is mapped to, or the
+// status itself when it is not mapped.
+func mapStatus(status string, mapping map[string]string) string {
+	if mapped, ok := mapping[normalizeStatus(status)]; ok {
+		return mapped
+	}
+	return status
+}
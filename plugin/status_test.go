@@ -0,0 +1,103 @@
+package plugin
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestStatusMapping validates that custom test statuses are counted as
+// mapped
+func TestStatusMapping(t *testing.T) {
+	robotOutput := RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{
+		{Name: "A", Status: Status{Status: "PASS"}},
+		{Name: "B", Status: Status{Status: "RETRIED"}},
+		{Name: "C", Status: Status{Status: "inconclusive"}},
+		{Name: "D", Status: Status{Status: "ERROR"}},
+		{Name: "E", Status: Status{Status: "FLAKY"}},
+	}}}
+
+	opts, err := newStatsOptions(Args{
+		CountSkippedTests: true,
+		StatusMapping:     map[string]string{"retried": "pass", "INCONCLUSIVE": "Skip", "ERROR": "fail", "FLAKY": "ignore"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(robotOutput, opts)
+	if stats.TotalTests != 5 || stats.PassedTests != 2 || stats.SkippedTests != 1 || stats.FailedTests != 1 {
+		t.Errorf("Expected 5 tests, 2 passed, 1 skipped and 1 failed, got %d, %d, %d and %d",
+			stats.TotalTests, stats.PassedTests, stats.SkippedTests, stats.FailedTests)
+	}
+	if len(stats.FailedTestsDetails) != 1 || stats.FailedTestsDetails[0].Name != "D" {
+		t.Errorf("Expected the failure details of D, got %+v", stats.FailedTestsDetails)
+	}
+
+	// Suite thresholds count the per-test records with the same mapping
+	suite := suiteTestStats(regexp.MustCompile("Root"), stats.Tests, Args{StatusMapping: map[string]string{"ERROR": "fail"}})
+	if suite.FailedTests != 1 {
+		t.Errorf("Expected 1 failed test in the suite, got %d", suite.FailedTests)
+	}
+}
+
+// TestValidStatusMapping validates the accepted mappings
+func TestValidStatusMapping(t *testing.T) {
+	tests := []struct {
+		status, target string
+		want           bool
+	}{
+		{"RETRIED", "pass", true},
+		{"retried", "IGNORE", true},
+		{"ERROR", "broken", false},
+		{"PASS", "fail", false},
+		{"not run", "skip", false},
+	}
+	for _, tt := range tests {
+		if got := validStatusMapping(tt.status, tt.target); got != tt.want {
+			t.Errorf("validStatusMapping(%q, %q) = %v, want %v", tt.status, tt.target, got, tt.want)
+		}
+	}
+}
+
+// TestUnknownStatusTests validates the counting and the gate of tests
+// with an empty or unmapped status
+func TestUnknownStatusTests(t *testing.T) {
+	robotOutput := RobotOutput{Suite: Suite{Name: "Root", Tests: []Test{
+		{Name: "A", Status: Status{Status: "PASS"}},
+		{Name: "B", Status: Status{Status: "RETRIED"}},
+		{Name: "C", Status: Status{Status: "BROKEN"}},
+		{Name: "D"},
+	}}}
+	args := Args{StatusMapping: map[string]string{"RETRIED": "ignore"}}
+	opts, err := newStatsOptions(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(robotOutput, opts)
+	if stats.TotalTests != 4 || stats.PassedTests != 1 || stats.UnknownStatusTests != 2 {
+		t.Errorf("Expected 4 tests, 1 passed and 2 with an unknown status, got %d, %d and %d",
+			stats.TotalTests, stats.PassedTests, stats.UnknownStatusTests)
+	}
+
+	gate, _ := findGate(evaluateThresholds(stats, args), "unknown_status_tests")
+	if gate.Outcome != gateWarn {
+		t.Errorf("Expected a warning by default, got %s", gate.Outcome)
+	}
+	args.FailOnUnknownStatus = true
+	gate, _ = findGate(evaluateThresholds(stats, args), "unknown_status_tests")
+	if gate.Outcome != gateFail || gate.Actual != 2 {
+		t.Errorf("Expected a failed gate with 2 tests, got %s with %v", gate.Outcome, gate.Actual)
+	}
+	if _, ok := findGate(evaluateThresholds(StatsResult{}, Args{}), "unknown_status_tests"); ok {
+		t.Errorf("Expected no unknown status gate without unknown statuses")
+	}
+}
+
+// findGate returns the gate with the name.
+func findGate(gates []GateResult, name string) (GateResult, bool) {
+	for _, gate := range gates {
+		if gate.Name == name {
+			return gate, true
+		}
+	}
+	return GateResult{}, false
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import "fmt"
+
+// annotateFailingStreaks sets the failing streak of each failed test: the
+// number of consecutive runs it failed, including the current run, and
+// the build of the first of those runs. The records are the runs on the
+// branch, oldest first.
+func annotateFailingStreaks(stats *StatsResult, records []HistoryRecord) {
+	if len(stats.FailedTestsDetails) == 0 {
+		return
+	}
+	current := ""
+	if stats.Build != nil {
+		current = stats.Build.Number
+	}
+	failed := make([]map[string]bool, len(records))
+	for i, record := range records {
+		failed[i] = failedTests(record.Stats)
+	}
+	for i := range stats.FailedTestsDetails {
+		details := &stats.FailedTestsDetails[i]
+		// the records key the tests by the suite long name
+		key := testKey(TestResult{Name: details.Name, Suite: firstNonEmpty(details.LongSuite, details.Suite)})
+		details.FailingStreak = 1
+		details.FirstFailedBuild = current
+		for j := len(records) - 1; j >= 0 && failed[j][key]; j-- {
+			details.FailingStreak++
+			details.FirstFailedBuild = records[j].number()
+		}
+	}
+}
+
+// failedTests returns the keys of the tests failed in the run.
+func failedTests(stats StatsResult) map[string]bool {
+	failed := map[string]bool{}
+	for _, test := range stats.Tests {
+		if test.Status == "FAIL" && !test.AllowedFailure {
+			failed[testKey(test)] = true
+		}
+	}
+	return failed
+}
+
+// failingStreak describes the failing streak of the failed test, or
+// returns an empty string when the history is disabled.
+func failingStreak(details FailedTestDetails) string {
+	switch {
+	case details.FailingStreak == 0:
+		return ""
+	case details.FailingStreak == 1:
+		return "new failure"
+	case details.FirstFailedBuild == "":
+		return fmt.Sprintf("%d runs", details.FailingStreak)
+	}
+	return fmt.Sprintf("%d runs, since build #%s", details.FailingStreak, details.FirstFailedBuild)
+}
@@ -0,0 +1,71 @@
+package plugin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestFailingStreaks validates the failing streaks of the failed tests in
+// nested suites, recorded as compact history records
+func TestFailingStreaks(t *testing.T) {
+	opts, err := newStatsOptions(Args{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(login, search string) StatsResult {
+		t.Helper()
+		robotOutput, err := parseOutput([]byte(fmt.Sprintf(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<suite id="s1-s1" name="Auth">
+<test id="s1-s1-t1" name="Login"><status status="%s">boom</status></test>
+<status status="%[1]s"/>
+</suite>
+<suite id="s1-s2" name="Shop">
+<test id="s1-s2-t1" name="Search"><status status="%s">boom</status></test>
+<status status="%[2]s"/>
+</suite>
+<status status="FAIL"/>
+</suite>
+</robot>`, login, search)), "output.xml", statsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return computeStats(*robotOutput, opts)
+	}
+	record := func(number string, stats StatsResult) HistoryRecord {
+		return HistoryRecord{Build: &BuildInfo{Number: number}, Stats: compactStats(stats), Compact: true}
+	}
+	records := []HistoryRecord{
+		record("38", run("FAIL", "FAIL")),
+		record("39", run("PASS", "FAIL")),
+		record("40", run("FAIL", "FAIL")),
+		record("41", run("FAIL", "PASS")),
+	}
+	stats := run("FAIL", "FAIL")
+	stats.Build = &BuildInfo{Number: "42"}
+	annotateFailingStreaks(&stats, records)
+
+	type streak struct {
+		Name, Suite, FirstFailedBuild string
+		FailingStreak                 int
+	}
+	var got []streak
+	for _, details := range stats.FailedTestsDetails {
+		got = append(got, streak{details.Name, details.Suite, details.FirstFailedBuild, details.FailingStreak})
+	}
+	want := []streak{
+		{Name: "Login", Suite: "Auth", FirstFailedBuild: "40", FailingStreak: 3},
+		{Name: "Search", Suite: "Shop", FirstFailedBuild: "42", FailingStreak: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected failing streaks (-want +got):\n%s", diff)
+	}
+	if got := failingStreak(stats.FailedTestsDetails[0]); got != "3 runs, since build #40" {
+		t.Errorf("Unexpected streak description %q", got)
+	}
+	if got := failingStreak(stats.FailedTestsDetails[1]); got != "new failure" {
+		t.Errorf("Unexpected streak description %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxLoggedAnomalies limits the structural anomalies logged per report.
+const maxLoggedAnomalies = 10
+
+// checkStructure counts the tests with duplicate IDs or empty names in
+// the suite tree, regardless of the test filters. Both usually indicate a
+// broken merge or a generator bug.
+func checkStructure(suite *Suite, stats *StatsResult) {
+	seen := map[string]bool{}
+	var anomalies []string
+
+	var walk func(suite *Suite)
+	walk = func(suite *Suite) {
+		for _, test := range suite.Tests {
+			if strings.TrimSpace(test.Name) == "" {
+				stats.EmptyTestNames++
+				anomalies = append(anomalies, fmt.Sprintf("test %s in suite %s has an empty name", test.ID, suite.Name))
+			}
+			if test.ID == "" {
+				continue
+			}
+			if seen[test.ID] {
+				stats.DuplicateTestIDs++
+				anomalies = append(anomalies, fmt.Sprintf("test ID %s of %s is not unique", test.ID, test.Name))
+			}
+			seen[test.ID] = true
+		}
+		for i := range suite.Suites {
+			walk(&suite.Suites[i])
+		}
+	}
+	walk(suite)
+
+	for i, anomaly := range anomalies {
+		if i == maxLoggedAnomalies {
+			logrus.Warnf("... and %d more structural anomalies", len(anomalies)-i)
+			break
+		}
+		logrus.Warnf("Structural anomaly: %s", anomaly)
+	}
+}
+
+// structureGate returns the gate of the structural anomalies, failing
+// when strict, and false when there is nothing to report.
+func structureGate(stats StatsResult, strict bool) (GateResult, bool) {
+	n := stats.DuplicateTestIDs + stats.EmptyTestNames
+	if n == 0 && !strict {
+		return GateResult{}, false
+	}
+	gate := GateResult{Name: "structure_anomalies", Metric: "structure_anomalies", Actual: float64(n), Outcome: gatePass}
+	if n > 0 {
+		gate.Message = fmt.Sprintf("%d duplicate test IDs and %d empty test names", stats.DuplicateTestIDs, stats.EmptyTestNames)
+		if strict {
+			gate.Outcome = gateFail
+		} else {
+			gate.Outcome = gateWarn
+		}
+	}
+	return gate, true
+}
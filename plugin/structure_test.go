@@ -0,0 +1,42 @@
+package plugin
+
+import "testing"
+
+// TestCheckStructure validates the detection of duplicate test IDs and
+// empty test names
+func TestCheckStructure(t *testing.T) {
+	robotOutput := RobotOutput{Suite: Suite{ID: "s1", Name: "Root", Suites: []Suite{
+		{ID: "s1-s1", Name: "A", Tests: []Test{
+			{ID: "s1-s1-t1", Name: "One", Status: Status{Status: "PASS"}},
+			{ID: "s1-s1-t2", Name: " ", Status: Status{Status: "PASS"}},
+		}},
+		{ID: "s1-s2", Name: "B", Tests: []Test{
+			{ID: "s1-s1-t1", Name: "One", Status: Status{Status: "PASS"}},
+			{ID: "s1-s1-t1", Name: "Two", Status: Status{Status: "FAIL"}},
+			{Name: "No ID", Status: Status{Status: "PASS"}},
+		}},
+	}}}
+
+	opts, err := newStatsOptions(Args{IncludeTests: []string{"One"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(robotOutput, opts)
+	if stats.DuplicateTestIDs != 2 || stats.EmptyTestNames != 1 {
+		t.Errorf("Expected 2 duplicate IDs and 1 empty name regardless of filters, got %d and %d", stats.DuplicateTestIDs, stats.EmptyTestNames)
+	}
+
+	gate, ok := structureGate(stats, false)
+	if !ok || gate.Outcome != gateWarn || gate.Actual != 3 {
+		t.Errorf("Expected a warning for 3 anomalies, got %+v", gate)
+	}
+	if gate, _ := structureGate(stats, true); gate.Outcome != gateFail {
+		t.Errorf("Expected a failure in strict mode, got %s", gate.Outcome)
+	}
+	if gate, ok := structureGate(StatsResult{}, true); !ok || gate.Outcome != gatePass {
+		t.Errorf("Expected a passed gate in strict mode without anomalies, got %+v", gate)
+	}
+	if _, ok := structureGate(StatsResult{}, false); ok {
+		t.Errorf("Expected no gate without anomalies")
+	}
+}
@@ -0,0 +1,112 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SuiteThreshold limits the failures of the tests in the suites matching
+// a suite name pattern, including their child suites.
+type SuiteThreshold struct {
+	Suite           string   `yaml:"suite"`
+	FailedTestsWarn *float64 `yaml:"failed_tests_warn"`
+	FailedTestsFail *float64 `yaml:"failed_tests_fail"`
+	FailureRateWarn *float64 `yaml:"failure_rate_warn"`
+	FailureRateFail *float64 `yaml:"failure_rate_fail"`
+}
+
+// rules returns the threshold rules of the suite threshold. The setting
+// prefix names the threshold in validation errors.
+func (t SuiteThreshold) rules(setting string) []thresholdRule {
+	return []thresholdRule{
+		{
+			Metric:      "failed_tests",
+			Label:       fmt.Sprintf("failed tests count of suite %s", t.Suite),
+			Warn:        t.FailedTestsWarn,
+			Fail:        t.FailedTestsFail,
+			WarnSetting: setting + ".failed_tests_warn",
+			FailSetting: setting + ".failed_tests_fail",
+		},
+		{
+			Metric:      "failure_rate",
+			Label:       fmt.Sprintf("failure rate of suite %s", t.Suite),
+			Warn:        t.FailureRateWarn,
+			Fail:        t.FailureRateFail,
+			WarnSetting: setting + ".failure_rate_warn",
+			FailSetting: setting + ".failure_rate_fail",
+		},
+	}
+}
+
+// validateSuiteThresholds checks the suite patterns and limits.
+func validateSuiteThresholds(thresholds []SuiteThreshold) error {
+	var errs []error
+	for i, t := range thresholds {
+		setting := fmt.Sprintf("suite_thresholds[%d]", i)
+		if strings.TrimSpace(t.Suite) == "" {
+			errs = append(errs, fmt.Errorf("%s.suite: suite thresholds require a suite pattern", setting))
+		} else if _, err := compileNamePattern(t.Suite); err != nil {
+			errs = append(errs, fmt.Errorf("%s.suite: %v", setting, err))
+		}
+		if err := validateThresholdRules(t.rules(setting)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// evaluateSuiteThresholds evaluates every suite threshold against the
+// tests of the matching suites.
+func evaluateSuiteThresholds(thresholds []SuiteThreshold, stats StatsResult, args Args) error {
+	return gateErrors(suiteThresholdGates(thresholds, stats, args))
+}
+
+// suiteThresholdGates returns the gate results of the suite thresholds,
+// named after the suite pattern and the metric.
+func suiteThresholdGates(thresholds []SuiteThreshold, stats StatsResult, args Args) []GateResult {
+	var gates []GateResult
+	for _, t := range thresholds {
+		re, err := compileNamePattern(t.Suite)
+		if err != nil {
+			gates = append(gates, GateResult{Name: "suite " + t.Suite, Outcome: gateFail, Message: err.Error()})
+			continue
+		}
+		gates = append(gates, thresholdGates("suite "+t.Suite+" ", t.rules(""), suiteTestStats(re, stats.Tests, args))...)
+	}
+	return gates
+}
+
+// suiteTestStats counts the tests in the suites matching the pattern.
+func suiteTestStats(re *regexp.Regexp, tests []TestResult, args Args) StatsResult {
+	stats := StatsResult{}
+	for _, test := range tests {
+		if !inMatchingSuite(re, test.Suite) || test.AllowedFailure {
+			continue
+		}
+		stats.TotalTests++
+		switch countedStatus(test.Status, newStatusMapping(args.StatusMapping), notRunStatus(args.NotRunAs), args.TreatSkippedAsFailed) {
+		case "FAIL":
+			stats.FailedTests++
+		case "PASS":
+			stats.PassedTests++
+		}
+	}
+	if stats.TotalTests > 0 {
+		stats.FailureRate = float64(stats.FailedTests) / float64(stats.TotalTests) * 100
+	}
+	return stats
+}
+
+// inMatchingSuite reports whether the suite or one of its parents
+// matches the pattern, by name or long name.
+func inMatchingSuite(re *regexp.Regexp, longName string) bool {
+	names := strings.Split(longName, ".")
+	for i := range names {
+		if re.MatchString(names[i]) || re.MatchString(strings.Join(names[:i+1], ".")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestEvaluateSuiteThresholds validates per-suite failure limits
+func TestEvaluateSuiteThresholds(t *testing.T) {
+	stats := StatsResult{Tests: []TestResult{
+		{Name: "Pay", Suite: "Root.Checkout.Payment", Status: "FAIL"},
+		{Name: "Cart", Suite: "Root.Checkout", Status: "PASS"},
+		{Name: "Login", Suite: "Root.Login", Status: "SKIP"},
+	}}
+
+	zero, fifty := 0.0, 50.0
+	err := evaluateSuiteThresholds([]SuiteThreshold{
+		{Suite: "Checkout", FailedTestsFail: &zero},
+		{Suite: "Root.Login", FailureRateFail: &fifty},
+	}, stats, Args{})
+	if err == nil || err.Error() != "failed tests count of suite Checkout (1) exceeds the fail threshold (0)" {
+		t.Errorf("Expected Checkout suite failure, got %v", err)
+	}
+
+	err = evaluateSuiteThresholds([]SuiteThreshold{{Suite: "Root.Login", FailureRateFail: &fifty}}, stats, Args{TreatSkippedAsFailed: true})
+	if err == nil || !strings.Contains(err.Error(), "failure rate of suite Root.Login (100)") {
+		t.Errorf("Expected Login suite failure with skipped tests as failed, got %v", err)
+	}
+}
+
+// TestValidateSuiteThresholds validates suite threshold configuration
+func TestValidateSuiteThresholds(t *testing.T) {
+	negative := -1.0
+	if err := validateSuiteThresholds([]SuiteThreshold{{FailedTestsFail: &negative}}); err == nil {
+		t.Error("Expected an error for a missing suite pattern")
+	}
+	if err := validateSuiteThresholds([]SuiteThreshold{{Suite: "Root", FailedTestsFail: &negative}}); err == nil {
+		t.Error("Expected an error for a negative limit")
+	}
+}
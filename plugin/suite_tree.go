@@ -0,0 +1,160 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// suiteNode is a suite of the suite tree with the test counts and
+// duration of the suite and all its children.
+type suiteNode struct {
+	Name     string // suite name, relative to the parent suite
+	LongName string
+	Passed   int
+	Failed   int
+	Skipped  int
+	Duration float64 // in milliseconds
+	Children []*suiteNode
+}
+
+// buildSuiteTree builds the suite tree of the statistics from the suite
+// long names. Suites merged from several report files are combined.
+func buildSuiteTree(stats StatsResult) []*suiteNode {
+	nodes := map[string]*suiteNode{}
+	node := func(longName string) *suiteNode {
+		if n, ok := nodes[longName]; ok {
+			return n
+		}
+		n := &suiteNode{LongName: longName}
+		nodes[longName] = n
+		return n
+	}
+	for _, suite := range stats.Suites {
+		node(suite.Name).Duration += suite.ExecutionTime
+	}
+	testTime := map[string]float64{}
+	for _, test := range stats.Tests {
+		node(test.Suite)
+		testTime[test.Suite] += test.Duration
+	}
+
+	// Link each suite to the longest known suite name prefixing it, so
+	// suite names containing dots stay intact.
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var roots []*suiteNode
+	parents := map[*suiteNode]*suiteNode{}
+	for _, name := range names {
+		n := nodes[name]
+		n.Name = name
+		for i := strings.LastIndex(name, "."); i > 0; i = strings.LastIndex(name[:i], ".") {
+			if parent, ok := nodes[name[:i]]; ok {
+				n.Name = name[i+1:]
+				parent.Children = append(parent.Children, n)
+				parents[n] = parent
+				break
+			}
+		}
+		if parents[n] == nil {
+			roots = append(roots, n)
+		}
+	}
+
+	for _, test := range stats.Tests {
+		for n := nodes[test.Suite]; n != nil; n = parents[n] {
+			switch {
+			case test.Status == "PASS":
+				n.Passed++
+			case test.Status == "FAIL" && !test.AllowedFailure:
+				n.Failed++
+			default:
+				n.Skipped++
+			}
+		}
+	}
+	for _, root := range roots {
+		fillSuiteDurations(root, testTime)
+	}
+	return roots
+}
+
+// fillSuiteDurations falls back to the test and child suite durations for
+// suites without an execution time, e.g. from fast summaries.
+func fillSuiteDurations(n *suiteNode, testTime map[string]float64) float64 {
+	sum := testTime[n.LongName]
+	for _, child := range n.Children {
+		sum += fillSuiteDurations(child, testTime)
+	}
+	if n.Duration == 0 {
+		n.Duration = sum
+	}
+	return n.Duration
+}
+
+// suiteTreeLines renders the suite tree, down to the depth when it is
+// positive. Deeper suites are counted in their ancestors.
+func suiteTreeLines(roots []*suiteNode, depth int, style, unit string) []string {
+	branch, last, pipe, blank := "├── ", "└── ", "│   ", "    "
+	if isPlainStyle(style) {
+		branch, last, pipe = "|-- ", "`-- ", "|   "
+	}
+
+	var lines []string
+	var walk func(n *suiteNode, prefix, connector string, level int)
+	walk = func(n *suiteNode, prefix, connector string, level int) {
+		lines = append(lines, prefix+connector+suiteNodeLabel(n, style, unit))
+		if depth > 0 && level >= depth {
+			return
+		}
+		switch connector {
+		case branch:
+			prefix += pipe
+		case last:
+			prefix += blank
+		}
+		for i, child := range n.Children {
+			next := branch
+			if i == len(n.Children)-1 {
+				next = last
+			}
+			walk(child, prefix, next, level+1)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "", 1)
+	}
+	return lines
+}
+
+func suiteNodeLabel(n *suiteNode, style, unit string) string {
+	status := "PASS"
+	icon := "✅"
+	if n.Failed > 0 {
+		status, icon = "FAIL", "❌"
+	}
+	counts := fmt.Sprintf("(%d passed, %d failed, %d skipped, %s)", n.Passed, n.Failed, n.Skipped, formatDuration(n.Duration, unit))
+	if isPlainStyle(style) {
+		return fmt.Sprintf("%s [%s] %s", n.Name, status, counts)
+	}
+	return fmt.Sprintf("%s %s %s", icon, n.Name, counts)
+}
+
+// logSuiteTree logs the suite tree of the statistics. It requires the
+// per-test results, which fast summaries do not provide.
+func logSuiteTree(stats StatsResult, depth int, style, unit string) {
+	if len(stats.Tests) == 0 {
+		return
+	}
+	logrus.Infof("Suite Tree:\n")
+	logrus.Infof("-----------------------------------------------\n")
+	for _, line := range suiteTreeLines(buildSuiteTree(stats), depth, style, unit) {
+		logrus.Infof("%s\n", line)
+	}
+	logrus.Infof("-----------------------------------------------\n")
+}
@@ -0,0 +1,72 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSuiteTree validates the suite tree counts, durations and rendering
+func TestSuiteTree(t *testing.T) {
+	stats := StatsResult{
+		Suites: []SuiteStats{
+			{Name: "Root", ExecutionTime: 900},
+			{Name: "Root.Login", ExecutionTime: 300},
+			{Name: "Root.Api v1.2"},
+		},
+		Tests: []TestResult{
+			{Name: "Valid", Suite: "Root.Login", Status: "PASS", Duration: 100},
+			{Name: "Invalid", Suite: "Root.Login", Status: "FAIL", Duration: 150},
+			{Name: "Get", Suite: "Root.Api v1.2", Status: "PASS", Duration: 200},
+			{Name: "Put", Suite: "Root.Api v1.2", Status: "SKIP"},
+			{Name: "Delete", Suite: "Root.Api v1.2", Status: "FAIL", AllowedFailure: true, Duration: 50},
+		},
+	}
+
+	got := suiteTreeLines(buildSuiteTree(stats), 0, "plain", "")
+	want := []string{
+		"Root [FAIL] (2 passed, 1 failed, 2 skipped, 900.00 ms)",
+		"|-- Api v1.2 [PASS] (1 passed, 0 failed, 2 skipped, 250.00 ms)",
+		"`-- Login [FAIL] (1 passed, 1 failed, 0 skipped, 300.00 ms)",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected plain tree (-want +got):\n%s", diff)
+	}
+
+	got = suiteTreeLines(buildSuiteTree(stats), 0, "rich", "")
+	want = []string{
+		"❌ Root (2 passed, 1 failed, 2 skipped, 900.00 ms)",
+		"├── ✅ Api v1.2 (1 passed, 0 failed, 2 skipped, 250.00 ms)",
+		"└── ❌ Login (1 passed, 1 failed, 0 skipped, 300.00 ms)",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected rich tree (-want +got):\n%s", diff)
+	}
+
+	got = suiteTreeLines(buildSuiteTree(stats), 1, "plain", "")
+	if len(got) != 1 {
+		t.Errorf("Expected only the root suite at depth 1, got %q", got)
+	}
+}
+
+// TestSuiteTreeNested validates the connectors of nested suites
+func TestSuiteTreeNested(t *testing.T) {
+	stats := StatsResult{
+		Tests: []TestResult{
+			{Name: "A", Suite: "Root.One.Deep", Status: "PASS"},
+			{Name: "B", Suite: "Root.Two", Status: "PASS"},
+			{Name: "C", Suite: "Root", Status: "PASS"},
+			{Name: "D", Suite: "Root.One", Status: "PASS"},
+		},
+	}
+	got := suiteTreeLines(buildSuiteTree(stats), 0, "plain", "")
+	want := []string{
+		"Root [PASS] (4 passed, 0 failed, 0 skipped, 0.00 ms)",
+		"|-- One [PASS] (2 passed, 0 failed, 0 skipped, 0.00 ms)",
+		"|   `-- Deep [PASS] (1 passed, 0 failed, 0 skipped, 0.00 ms)",
+		"`-- Two [PASS] (1 passed, 0 failed, 0 skipped, 0.00 ms)",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected nested tree (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,314 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// errNoStatistics is returned when a report has no <statistics> block.
+var errNoStatistics = errors.New("no statistics block found")
+
+// processFileSummary computes statistics from the <statistics> block of
+// the report without walking the suite tree. Keyword statistics, failure
+// details and execution time are not available in this mode.
+func processFileSummary(filename string, opts statsOptions) (StatsResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return StatsResult{}, fmt.Errorf("error opening file: %s. Error: %v", filename, err)
+	}
+	defer file.Close()
+
+	if info, err := file.Stat(); err == nil {
+		if err := opts.Limits.checkSize(info.Size(), filename); err != nil {
+			return StatsResult{}, err
+		}
+	}
+
+	robotOutput, err := decodeStatistics(file, filename, opts.Limits)
+	if err != nil {
+		return StatsResult{}, err
+	}
+	opts.profile = detectProfile(robotOutput)
+	stats := summaryStats(robotOutput.Statistics, opts)
+	opts.profile.setVersions(&stats)
+	return stats, nil
+}
+
+// decodeStatistics streams the report and decodes the generator and the
+// <statistics> block. The suite tree of UTF-8 reports is skipped by
+// scanning its bytes for the <statistics> tag without tokenizing it;
+// UTF-16 reports are tokenized within the parsing limits.
+func decodeStatistics(r io.Reader, filename string, limits xmlLimits) (RobotOutput, error) {
+	var robotOutput RobotOutput
+	br := getReader(r)
+	defer putReader(br)
+	src := utf8BufferedReader(br)
+	decoder := newUTF8Decoder(src)
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			return robotOutput, errNoStatistics
+		}
+		if err != nil {
+			return robotOutput, fmt.Errorf("failed to parse output.xml: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if depth == 1 && t.Name.Local == "statistics" {
+				if err := decoder.DecodeElement(&robotOutput.Statistics, &t); err != nil {
+					return robotOutput, fmt.Errorf("failed to parse statistics: %v", err)
+				}
+				return robotOutput, nil
+			}
+			if depth == 0 && t.Name.Local == "robot" {
+				for _, attr := range t.Attr {
+					switch attr.Name.Local {
+					case "generator":
+						robotOutput.Generator = attr.Value
+					case "schemaversion":
+						robotOutput.Schema = attr.Value
+					}
+				}
+				// the decoder reads byte by byte from a buffered reader,
+				// which is positioned right after the <robot> tag
+				if src == br {
+					err := scanStatistics(br, &robotOutput.Statistics, filename, limits)
+					return robotOutput, err
+				}
+			}
+			depth++
+			if err := limits.checkDepth(depth, filename); err != nil {
+				return robotOutput, err
+			}
+		case xml.EndElement:
+			depth--
+		case xml.Directive:
+			if err := checkDirective(t, filename); err != nil {
+				return robotOutput, err
+			}
+		}
+	}
+}
+
+// scanStatistics skips the suite tree at byte level to the <statistics>
+// block below the <robot> element and decodes the block.
+func scanStatistics(br *bufio.Reader, statistics *Statistics, filename string, limits xmlLimits) error {
+	scanner := &elementScanner{r: br, depth: 1, limits: limits, filename: filename}
+	found, err := scanner.skipTo("statistics", 1)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return errNoStatistics
+	}
+	decoder := xml.NewDecoder(io.MultiReader(strings.NewReader("<"), br))
+	if err := decoder.Decode(statistics); err != nil {
+		return fmt.Errorf("failed to parse statistics: %v", err)
+	}
+	return nil
+}
+
+// summaryStats converts Robot Framework statistics into a StatsResult.
+func summaryStats(statistics Statistics, opts statsOptions) StatsResult {
+	stats := StatsResult{}
+
+	var all, critical *Stat
+	for i, stat := range statistics.Total {
+		switch strings.TrimSpace(stat.Label) {
+		case "All Tests":
+			all = &statistics.Total[i]
+		case "Critical Tests":
+			critical = &statistics.Total[i]
+		}
+	}
+
+	// without criticality all tests are critical
+	if critical == nil && !opts.profile.Criticality {
+		critical = all
+	}
+
+	counted := all
+	if opts.OnlyCritical && critical != nil {
+		counted = critical
+	}
+	if counted != nil {
+		stats.PassedTests = counted.Pass
+		stats.FailedTests = counted.Fail
+		stats.TotalTests = counted.Pass + counted.Fail + counted.Skip
+		switch {
+		case opts.TreatSkippedAsFailed:
+			stats.FailedTests += counted.Skip
+		case opts.CountSkipped:
+			stats.SkippedTests = counted.Skip
+		}
+	}
+	if critical != nil {
+		stats.TotalCritical = critical.Pass + critical.Fail + critical.Skip
+		stats.CriticalPassed = critical.Pass
+		stats.CriticalFailed = critical.Fail
+	}
+
+	stats.Tags = summaryTagStats(statistics, opts)
+	if opts.TagStatistics {
+		stats.TagStatistics = robotTagStatistics(statistics, opts.TreatSkippedAsFailed)
+	}
+	for _, stat := range statistics.Suite {
+		stats.TotalSuites++
+		stats.Suites = append(stats.Suites, SuiteStats{Name: namespaced(opts.namespace, strings.TrimSpace(stat.Label))})
+	}
+
+	if stats.TotalTests > 0 {
+		stats.FailureRate = (float64(stats.FailedTests) / float64(stats.TotalTests)) * 100
+		stats.SkippedRate = (float64(stats.SkippedTests) / float64(stats.TotalTests)) * 100
+	}
+	return stats
+}
+
+// summaryArgs are the arguments known to work with the counts of the
+// <statistics> block alone: report discovery, count options and limits,
+// and outputs of the counts. Any other argument that is set requires
+// walking every test, so new options fall back to full parsing until
+// they are added here. The stats file is left out as its per-test
+// results are the baseline of later comparisons and run histories.
+var summaryArgs = map[string]bool{
+	"ReportDirectory":       true,
+	"ReportFileNamePattern": true,
+	"ReportFileRegex":       true,
+	"ReportFileIgnoreCase":  true,
+	"FollowSymlinks":        true,
+	"MaxSearchDepth":        true,
+	"SkipHiddenDirs":        true,
+	"SuiteNamespace":        true,
+	"PassThreshold":         true,
+	"UnstableThreshold":     true,
+	"ThresholdsScope":       true,
+	"CountSkippedTests":     true,
+	"TreatSkippedAsFailed":  true,
+	"OnlyCritical":          true,
+	"FastSummary":           true,
+	"SalvageTruncated":      true,
+	"MaxReportSize":         true,
+	"MaxXMLDepth":           true,
+	"FailFast":              true,
+	"FileWorkers":           true,
+	"GateExpression":        true, // metrics are checked by summaryMetrics
+	"SummaryStyle":          true,
+	"Quiet":                 true,
+	"SkipKeywordStats":      true,
+	"OutputFile":            true,
+	"OutputFormat":          true,
+	"OutputPrecision":       true,
+	"DurationUnit":          true,
+	"FailOnOutputError":     true,
+	"HarnessOutputs":        true,
+	"ConfigFile":            true,
+	"Level":                 true,
+	"WaitForReports":        true,
+	"WaitPollInterval":      true,
+	"WaitTimeout":           true,
+	"Progress":              true,
+	"ProgressInterval":      true,
+	"CacheDir":              true,
+	"CPUProfile":            true,
+	"HeapProfile":           true,
+	"OutputTags":            true,
+	"TagStatistics":         true,
+	"FailureRateWarn":       true,
+	"FailureRateFail":       true,
+	"SkippedRateWarn":       true,
+	"SkippedRateFail":       true,
+	"MaxSkippedRate":        true,
+	"CriticalFailedWarn":    true,
+	"CriticalFailedFail":    true,
+	"BuildLabels":           true,
+	"HostInfo":              true,
+	"HostEnv":               true,
+	"Reporters":             true,
+}
+
+// summaryMetrics are the gate expression metrics computed from the
+// counts of the <statistics> block.
+var summaryMetrics = map[string]bool{
+	"total_suites":    true,
+	"total_tests":     true,
+	"passed_tests":    true,
+	"failed_tests":    true,
+	"skipped_tests":   true,
+	"total_critical":  true,
+	"critical_passed": true,
+	"critical_failed": true,
+	"failure_rate":    true,
+	"skipped_rate":    true,
+}
+
+// fullParseReasons returns why the arguments require walking every test
+// instead of using the fast summary path: the settings outside of
+// summaryArgs, named like their environment variables, and the gate
+// expression metrics outside of summaryMetrics.
+func fullParseReasons(args Args) []string {
+	var reasons []string
+	v := reflect.ValueOf(args)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if summaryArgs[field.Name] || isEmptyValue(v.Field(i)) {
+			continue
+		}
+		name := field.Tag.Get("envconfig")
+		if name == "" {
+			name = field.Tag.Get("yaml")
+		}
+		reasons = append(reasons, name)
+	}
+	if args.GateExpression != "" {
+		if node, err := compileExpression(args.GateExpression); err == nil {
+			for _, name := range exprIdentifiers(node) {
+				if !summaryMetrics[name] {
+					reasons = append(reasons, "gate expression metric "+name)
+				}
+			}
+		}
+	}
+	return reasons
+}
+
+// isEmptyValue reports whether an argument is unset: the zero value, or
+// an empty slice or map.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// loggedFallbacks holds the full parsing reasons already logged, so the
+// fallback is logged once rather than on every newStatsOptions call.
+var loggedFallbacks sync.Map
+
+// useFastSummary reports whether the fast summary path can be used,
+// logging why it cannot when it was requested.
+func useFastSummary(args Args) bool {
+	if !args.FastSummary {
+		return false
+	}
+	if reasons := fullParseReasons(args); len(reasons) > 0 {
+		message := strings.Join(reasons, ", ")
+		if _, logged := loggedFallbacks.LoadOrStore(message, true); !logged {
+			logrus.Infof("Fast summary disabled, full parsing required for: %s\n", message)
+		}
+		return false
+	}
+	return true
+}
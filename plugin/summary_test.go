@@ -0,0 +1,90 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestProcessFileSummary validates statistics read from the <statistics> block
+func TestProcessFileSummary(t *testing.T) {
+	result, err := processFileSummary("../testdata/robot_report.xml", statsOptions{CountSkipped: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := StatsResult{
+		RobotVersion:   "6.0",
+		TotalSuites:    1,
+		TotalTests:     5,
+		PassedTests:    2,
+		FailedTests:    2,
+		SkippedTests:   1,
+		TotalCritical:  2,
+		CriticalPassed: 1,
+		CriticalFailed: 1,
+		FailureRate:    40,
+		SkippedRate:    20,
+		Suites:         []SuiteStats{{Name: "Advanced Test Suite"}},
+	}
+	if diff := cmp.Diff(expected, result); diff != "" {
+		t.Errorf("Results mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := processFileSummary("../testdata/empty.xml", statsOptions{}); err != errNoStatistics {
+		t.Errorf("Expected errNoStatistics for empty file, got %v", err)
+	}
+}
+
+// TestFullParseReasons validates the automatic fallback to full parsing
+func TestFullParseReasons(t *testing.T) {
+	if reasons := fullParseReasons(Args{GateExpression: "failure_rate < 5"}); len(reasons) != 0 {
+		t.Errorf("Expected no reasons, got %v", reasons)
+	}
+	reasons := fullParseReasons(Args{GateExpression: "failed_keywords == 0", IncludeTags: []string{"smoke"}})
+	if len(reasons) != 2 {
+		t.Errorf("Expected 2 reasons, got %v", reasons)
+	}
+
+	// settings only needing the counts keep the fast path
+	limit := 5.0
+	args := Args{
+		ReportDirectory:   "reports",
+		FastSummary:       true,
+		CountSkippedTests: true,
+		FailureRateFail:   &limit,
+		OutputTags:        []string{"smoke"},
+		GateExpression:    "failed_tests / total_tests < 0.1",
+		BuildLabels:       map[string]string{},
+	}
+	if reasons := fullParseReasons(args); len(reasons) != 0 {
+		t.Errorf("Expected no reasons, got %v", reasons)
+	}
+
+	// any other setting requires full parsing
+	args.NotRunAs = "fail"
+	args.StatsFile = "stats.json"
+	args.SuiteThresholds = []SuiteThreshold{{Suite: "Checkout"}}
+	args.GateExpression = "flaky_tests == 0"
+	want := []string{"PLUGIN_NOT_RUN_AS", "PLUGIN_STATS_FILE", "suite_thresholds", "gate expression metric flaky_tests"}
+	if diff := cmp.Diff(want, fullParseReasons(args)); diff != "" {
+		t.Errorf("Reasons mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestSummaryArgs validates that the fast summary settings name arguments
+func TestSummaryArgs(t *testing.T) {
+	fields := reflect.TypeOf(Args{})
+	for name := range summaryArgs {
+		if _, ok := fields.FieldByName(name); !ok {
+			t.Errorf("Unknown argument %s in summaryArgs", name)
+		}
+	}
+	metrics := statsMetrics(StatsResult{})
+	for name := range summaryMetrics {
+		if _, ok := metrics[name]; !ok {
+			t.Errorf("Unknown metric %s in summaryMetrics", name)
+		}
+	}
+}
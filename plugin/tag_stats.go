@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// newOutputTags returns the configured output tags by normalized tag, or
+// nil when none are configured.
+func newOutputTags(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	outputTags := map[string]string{}
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			outputTags[normalizeTag(tag)] = tag
+		}
+	}
+	return outputTags
+}
+
+// emptyTagStats returns zero counts for every output tag, so tags without
+// tests are reported too.
+func emptyTagStats(outputTags map[string]string) map[string]TagStats {
+	if len(outputTags) == 0 {
+		return nil
+	}
+	tags := map[string]TagStats{}
+	for _, name := range outputTags {
+		tags[name] = TagStats{}
+	}
+	return tags
+}
+
+// countTestTags counts the test under each of its output tags. Status is
+// the counted status, empty for allowed failures.
+func countTestTags(stats *StatsResult, test Test, status string, outputTags map[string]string) {
+	seen := map[string]bool{}
+	for _, tag := range test.AllTags() {
+		name, ok := outputTags[normalizeTag(tag)]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tagStats := stats.Tags[name]
+		tagStats.add(status)
+		stats.Tags[name] = tagStats
+	}
+}
+
+func (t *TagStats) add(status string) {
+	t.Total++
+	switch status {
+	case "PASS":
+		t.Passed++
+	case "FAIL":
+		t.Failed++
+	case "SKIP":
+		t.Skipped++
+	}
+}
+
+// summaryTagStats converts the tag statistics of the <statistics> block
+// for the output tags.
+func summaryTagStats(statistics Statistics, opts statsOptions) map[string]TagStats {
+	tags := emptyTagStats(opts.OutputTags)
+	for _, stat := range statistics.Tag {
+		name, ok := opts.OutputTags[normalizeTag(strings.TrimSpace(stat.Label))]
+		if !ok {
+			continue
+		}
+		tags[name] = statTagStats(stat, opts.TreatSkippedAsFailed)
+	}
+	return tags
+}
+
+// statTagStats returns the test counts of a tag statistics entry.
+func statTagStats(stat Stat, treatSkippedAsFailed bool) TagStats {
+	tagStats := TagStats{Total: stat.Pass + stat.Fail + stat.Skip, Passed: stat.Pass, Failed: stat.Fail, Skipped: stat.Skip}
+	if treatSkippedAsFailed {
+		tagStats.Failed += tagStats.Skipped
+		tagStats.Skipped = 0
+	}
+	return tagStats
+}
+
+// countCombinedTags sets the counts of the output tags that are combined
+// tags of the <statistics> block. Robot Framework computes them from the
+// --tagstatcombine patterns, so they cannot be counted from the test tags.
+func countCombinedTags(statistics Statistics, stats *StatsResult, opts statsOptions) {
+	for _, stat := range statistics.Tag {
+		if stat.Combined == "" {
+			continue
+		}
+		if name, ok := opts.OutputTags[normalizeTag(strings.TrimSpace(stat.Label))]; ok {
+			stats.Tags[name] = statTagStats(stat, opts.TreatSkippedAsFailed)
+		}
+	}
+}
+
+// robotTagStatistics returns the tag statistics entries of the
+// <statistics> block, including combined tags, sorted by name.
+func robotTagStatistics(statistics Statistics, treatSkippedAsFailed bool) []RobotTagStats {
+	var tags []RobotTagStats
+	for _, stat := range statistics.Tag {
+		tags = append(tags, RobotTagStats{
+			Name:     strings.TrimSpace(stat.Label),
+			Combined: stat.Combined,
+			Doc:      stat.Doc,
+			Links:    parseTagLinks(stat.Links),
+			TagStats: statTagStats(stat, treatSkippedAsFailed),
+		})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags
+}
+
+// parseTagLinks parses the links of a tag statistics entry, written as
+// title:url pairs separated by ":::".
+func parseTagLinks(links string) []TagLink {
+	var parsed []TagLink
+	for _, link := range strings.Split(links, ":::") {
+		title, url, ok := strings.Cut(link, ":")
+		if !ok || url == "" {
+			continue
+		}
+		parsed = append(parsed, TagLink{Title: title, URL: url})
+	}
+	return parsed
+}
+
+// mergeTagStatistics adds up the tag statistics of two reports by name.
+// The documentation and links of the first report with the tag are kept.
+func mergeTagStatistics(tags, other []RobotTagStats) []RobotTagStats {
+	if len(other) == 0 {
+		return tags
+	}
+	index := make(map[string]int, len(tags))
+	for i, tag := range tags {
+		index[tag.Name] = i
+	}
+	for _, tag := range other {
+		i, ok := index[tag.Name]
+		if !ok {
+			index[tag.Name] = len(tags)
+			tags = append(tags, tag)
+			continue
+		}
+		tags[i].Total += tag.Total
+		tags[i].Passed += tag.Passed
+		tags[i].Failed += tag.Failed
+		tags[i].Skipped += tag.Skipped
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags
+}
+
+// writeTagStats sets the counts of the output tags as output variables.
+func writeTagStats(out *outputVars, stats StatsResult) {
+	names := make([]string, 0, len(stats.Tags))
+	for name := range stats.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		tagStats := stats.Tags[name]
+		prefix := "TAG_" + outputName(name)
+		out.set(prefix+"_TOTAL", strconv.Itoa(tagStats.Total))
+		out.set(prefix+"_PASSED", strconv.Itoa(tagStats.Passed))
+		out.set(prefix+"_FAILED", strconv.Itoa(tagStats.Failed))
+	}
+}
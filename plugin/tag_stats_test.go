@@ -0,0 +1,166 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestTagStats validates the output tag counts of full parsing
+func TestTagStats(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Login"><tag>smoke</tag><tag>SMOKE</tag><status status="PASS"/></test>
+<test id="s1-t2" name="Logout"><tag>smoke</tag><tag>flaky</tag><status status="FAIL"/></test>
+<test id="s1-t3" name="Search"><tag>smoke</tag><status status="SKIP"/></test>
+<test id="s1-t4" name="Export"><tag>flaky</tag><status status="FAIL"/></test>
+</suite>
+</robot>`), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(Args{OutputTags: []string{"Smoke", "flaky", "unused"}, AllowFailureTag: "flaky"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	want := map[string]TagStats{
+		"Smoke":  {Total: 3, Passed: 1, Skipped: 1},
+		"flaky":  {Total: 2},
+		"unused": {},
+	}
+	if diff := cmp.Diff(want, stats.Tags); diff != "" {
+		t.Errorf("Unexpected tag stats (-want +got):\n%s", diff)
+	}
+}
+
+// TestSummaryTagStats validates the output tag counts of fast summaries
+func TestSummaryTagStats(t *testing.T) {
+	opts, err := newStatsOptions(Args{OutputTags: []string{"Smoke", "regression", "unused"}, FastSummary: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := processFile("../testdata/robot_report.xml", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]TagStats{
+		"Smoke":      {Total: 1, Passed: 1},
+		"regression": {Total: 1, Failed: 1},
+		"unused":     {},
+	}
+	if diff := cmp.Diff(want, stats.Tags); diff != "" {
+		t.Errorf("Unexpected tag stats (-want +got):\n%s", diff)
+	}
+}
+
+// TestWriteTagStats validates the per-tag output variables
+func TestWriteTagStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outputs.env")
+	out := &outputVars{}
+	writeTagStats(out, StatsResult{Tags: map[string]TagStats{"smoke tests": {Total: 3, Passed: 2, Failed: 1}}})
+	if err := out.writeTo(outputTarget{Path: path, Format: outputFormatDotenv}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "TAG_SMOKE_TESTS_TOTAL=3\nTAG_SMOKE_TESTS_PASSED=2\nTAG_SMOKE_TESTS_FAILED=1\n"
+	if got := string(data); !strings.Contains(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// combinedTagsReport has a combined tag with documentation and links in
+// its tag statistics.
+const combinedTagsReport = `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Login"><tag>smoke</tag><tag>web</tag><status status="PASS"/></test>
+<test id="s1-t2" name="Logout"><tag>web</tag><status status="FAIL"/></test>
+<test id="s1-t3" name="Search"><tag>smoke</tag><status status="SKIP"/></test>
+</suite>
+<statistics>
+<total><stat pass="1" fail="1" skip="1">All Tests</stat></total>
+<tag>
+<stat pass="1" fail="0" skip="0" info="combined" combined="smokeANDweb">Smoke web</stat>
+<stat pass="1" fail="0" skip="1" doc="Quick checks" links="Wiki:https://wiki.example.com/smoke:::Board:https://board.example.com">smoke</stat>
+<stat pass="1" fail="1" skip="0">web</stat>
+</tag>
+</statistics>
+</robot>`
+
+// TestCombinedTagStats validates that output tags matching combined tags
+// use Robot Framework's counts, unless filters are set
+func TestCombinedTagStats(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(combinedTagsReport), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(Args{OutputTags: []string{"Smoke web", "smoke"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	want := map[string]TagStats{
+		"Smoke web": {Total: 1, Passed: 1},
+		"smoke":     {Total: 2, Passed: 1, Skipped: 1},
+	}
+	if diff := cmp.Diff(want, stats.Tags); diff != "" {
+		t.Errorf("Unexpected tag stats (-want +got):\n%s", diff)
+	}
+
+	opts, err = newStatsOptions(Args{OutputTags: []string{"Smoke web"}, IncludeTags: []string{"web"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := computeStats(*robotOutput, opts).Tags["Smoke web"]; got != (TagStats{}) {
+		t.Errorf("Expected combined tags not to be counted with filters, got %+v", got)
+	}
+}
+
+// TestRobotTagStatistics validates the exported tag statistics and
+// their aggregation across reports
+func TestRobotTagStatistics(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(combinedTagsReport), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := newStatsOptions(Args{TagStatistics: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := computeStats(*robotOutput, opts)
+	want := []RobotTagStats{
+		{Name: "Smoke web", Combined: "smokeANDweb", TagStats: TagStats{Total: 1, Passed: 1}},
+		{Name: "smoke", Doc: "Quick checks", Links: []TagLink{
+			{Title: "Wiki", URL: "https://wiki.example.com/smoke"},
+			{Title: "Board", URL: "https://board.example.com"},
+		}, TagStats: TagStats{Total: 2, Passed: 1, Skipped: 1}},
+		{Name: "web", TagStats: TagStats{Total: 2, Passed: 1, Failed: 1}},
+	}
+	if diff := cmp.Diff(want, stats.TagStatistics); diff != "" {
+		t.Errorf("Unexpected tag statistics (-want +got):\n%s", diff)
+	}
+
+	merged := mergeTagStatistics(nil, stats.TagStatistics)
+	merged = mergeTagStatistics(merged, []RobotTagStats{
+		{Name: "api", TagStats: TagStats{Total: 1, Failed: 1}},
+		{Name: "web", TagStats: TagStats{Total: 1, Passed: 1}},
+	})
+	if len(merged) != 4 || merged[0].Name != "Smoke web" || merged[1].Name != "api" {
+		t.Fatalf("Unexpected merged tag statistics %+v", merged)
+	}
+	if got := merged[3].TagStats; got != (TagStats{Total: 3, Passed: 2, Failed: 1}) {
+		t.Errorf("Expected the web counts to add up, got %+v", got)
+	}
+
+	opts.FastSummary = true
+	summary := summaryStats(robotOutput.Statistics, opts)
+	if diff := cmp.Diff(want, summary.TagStatistics); diff != "" {
+		t.Errorf("Unexpected fast summary tag statistics (-want +got):\n%s", diff)
+	}
+}
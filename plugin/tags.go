@@ -0,0 +1,190 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tagPattern matches a set of test tags using Robot Framework tag
+// pattern syntax.
+type tagPattern interface {
+	match(tags []string) bool
+}
+
+// compileTagPattern compiles a Robot Framework tag expression such as
+// `smokeANDNOTwip` or `regression OR nightly`. Operators are NOT, OR and
+// AND (or &), in increasing order of precedence as in Robot Framework,
+// so `xORyNOTz` is `(x OR y) NOT z`, and tag names may contain the * and
+// ? wildcards.
+func compileTagPattern(pattern string) (tagPattern, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return nil, fmt.Errorf("empty tag pattern")
+	}
+
+	if parts := splitTagPattern(pattern, "NOT"); len(parts) > 1 {
+		p := notTagPattern{}
+		// xANDNOTy is the same as xNOTy
+		first := strings.TrimSpace(parts[0])
+		first = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(first, "AND"), "&"))
+		if first != "" {
+			sub, err := compileTagPattern(first)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+			}
+			p.include = sub
+		}
+		for _, part := range parts[1:] {
+			sub, err := compileTagPattern(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+			}
+			p.exclude = append(p.exclude, sub)
+		}
+		return p, nil
+	}
+
+	if parts := splitTagPattern(pattern, "OR"); len(parts) > 1 {
+		var p orTagPattern
+		for _, part := range parts {
+			sub, err := compileTagPattern(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+			}
+			p = append(p, sub)
+		}
+		return p, nil
+	}
+
+	if parts := splitTagPattern(strings.ReplaceAll(pattern, "&", "AND"), "AND"); len(parts) > 1 {
+		var p andTagPattern
+		for _, part := range parts {
+			sub, err := compileTagPattern(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tag pattern %q: %v", pattern, err)
+			}
+			p = append(p, sub)
+		}
+		return p, nil
+	}
+
+	return newSingleTagPattern(pattern), nil
+}
+
+// tagOperators match the operators of tag patterns with the surrounding
+// spaces.
+var tagOperators = map[string]*regexp.Regexp{
+	"NOT": regexp.MustCompile(`\s*NOT\s*`),
+	"OR":  regexp.MustCompile(`\s*OR\s*`),
+	"AND": regexp.MustCompile(`\s*AND\s*`),
+}
+
+// splitTagPattern splits the pattern on the operator. An empty leading
+// part is kept so that a leading NOT can be detected.
+func splitTagPattern(pattern, operator string) []string {
+	return tagOperators[operator].Split(pattern, -1)
+}
+
+type singleTagPattern struct {
+	re *regexp.Regexp
+}
+
+func newSingleTagPattern(pattern string) singleTagPattern {
+	expr := regexp.QuoteMeta(normalizeTag(pattern))
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	return singleTagPattern{re: regexp.MustCompile("^" + expr + "$")}
+}
+
+func (p singleTagPattern) match(tags []string) bool {
+	for _, tag := range tags {
+		if p.re.MatchString(normalizeTag(tag)) {
+			return true
+		}
+	}
+	return false
+}
+
+type andTagPattern []tagPattern
+
+func (p andTagPattern) match(tags []string) bool {
+	for _, sub := range p {
+		if !sub.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+type orTagPattern []tagPattern
+
+func (p orTagPattern) match(tags []string) bool {
+	for _, sub := range p {
+		if sub.match(tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// notTagPattern matches when the include pattern (if any) matches and
+// none of the exclude patterns match.
+type notTagPattern struct {
+	include tagPattern
+	exclude []tagPattern
+}
+
+func (p notTagPattern) match(tags []string) bool {
+	if p.include != nil && !p.include.match(tags) {
+		return false
+	}
+	for _, sub := range p.exclude {
+		if sub.match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagFilter selects tests by include and exclude tag patterns, with the
+// same semantics as robot's --include and --exclude options.
+type tagFilter struct {
+	include []tagPattern
+	exclude []tagPattern
+}
+
+// newTagFilter compiles the include and exclude tag patterns. It returns
+// nil when no patterns are configured.
+func newTagFilter(include, exclude []string) (*tagFilter, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+	f := &tagFilter{}
+	for _, pattern := range include {
+		p, err := compileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, p)
+	}
+	for _, pattern := range exclude {
+		p, err := compileTagPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, p)
+	}
+	return f, nil
+}
+
+// allows reports whether a test with the given tags passes the filter.
+func (f *tagFilter) allows(tags []string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !orTagPattern(f.include).match(tags) {
+		return false
+	}
+	return !orTagPattern(f.exclude).match(tags)
+}
@@ -0,0 +1,50 @@
+package plugin
+
+import "testing"
+
+// TestTagFilter validates Robot Framework tag pattern matching
+func TestTagFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		tags     []string
+		expected bool
+	}{
+		{name: "No Patterns", tags: []string{"smoke"}, expected: true},
+		{name: "Include Match Case Insensitive", include: []string{"Smoke"}, tags: []string{"smoke"}, expected: true},
+		{name: "Include No Match", include: []string{"smoke"}, tags: []string{"regression"}, expected: false},
+		{name: "AND NOT Without Spaces", include: []string{"smokeANDNOTwip"}, tags: []string{"smoke"}, expected: true},
+		{name: "AND NOT Excludes", include: []string{"smokeANDNOTwip"}, tags: []string{"smoke", "WIP"}, expected: false},
+		{name: "OR Pattern", include: []string{"regression OR nightly"}, tags: []string{"nightly"}, expected: true},
+		{name: "Ampersand", include: []string{"smoke&fast"}, tags: []string{"smoke"}, expected: false},
+		{name: "Leading NOT", include: []string{"NOT wip"}, tags: []string{"smoke"}, expected: true},
+		{name: "Wildcards", include: []string{"feat-?-*"}, tags: []string{"feat-a-login"}, expected: true},
+		{name: "Exclude Wins", include: []string{"smoke"}, exclude: []string{"flaky"}, tags: []string{"smoke", "flaky"}, expected: false},
+		{name: "Any Include Matches", include: []string{"smoke", "sanity"}, tags: []string{"sanity"}, expected: true},
+		{name: "AND Binds Tighter Than OR", include: []string{"xORyANDz"}, tags: []string{"x"}, expected: true},
+		{name: "AND Before OR", include: []string{"xANDyORz"}, tags: []string{"z"}, expected: true},
+		{name: "AND Before OR No Match", include: []string{"xANDyORz"}, tags: []string{"x"}, expected: false},
+		{name: "NOT Binds Loosest After OR", include: []string{"xORyNOTz"}, tags: []string{"x", "z"}, expected: false},
+		{name: "NOT Binds Loosest Before AND", include: []string{"xNOTyANDz"}, tags: []string{"x", "y"}, expected: true},
+		{name: "NOT Excludes AND", include: []string{"xNOTyANDz"}, tags: []string{"x", "y", "z"}, expected: false},
+		{name: "Several NOTs", include: []string{"x NOT y NOT z"}, tags: []string{"x", "z"}, expected: false},
+		{name: "Ampersand NOT", include: []string{"x&NOTy"}, tags: []string{"x"}, expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newTagFilter(tc.include, tc.exclude)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := f.allows(tc.tags); got != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if _, err := newTagFilter([]string{"smokeAND"}, nil); err == nil {
+		t.Errorf("Expected error for incomplete tag pattern")
+	}
+}
@@ -0,0 +1,158 @@
+package plugin
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// liveCounts holds the running test counts of an output.xml being
+// written.
+type liveCounts struct {
+	Total, Passed, Failed, Skipped int
+}
+
+// tailReports follows the report files while Robot Framework writes
+// them, logging running pass/fail counts, and returns once every file is
+// complete. The timeout limits how long a file may go without growing.
+func tailReports(ctx context.Context, directory string, query fileQuery, interval, timeout time.Duration) error {
+	if interval <= 0 {
+		interval = defaultWaitPollInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+
+	// Wait for the reports to be created
+	var files []string
+	if err := pollUntil(ctx, interval, timeout, func() bool {
+		sizes := reportFileSizes(directory, query)
+		files = files[:0]
+		for file := range sizes {
+			files = append(files, file)
+		}
+		return len(files) > 0
+	}); err != nil {
+		return waitError(err, timeout, query)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		counts, err := tailFile(ctx, file, interval, timeout)
+		if err != nil {
+			return fmt.Errorf("failed to follow %s: %v", file, err)
+		}
+		logrus.Infof("Completed %s: %d tests, %d passed, %d failed, %d skipped\n",
+			filepath.Base(file), counts.Total, counts.Passed, counts.Failed, counts.Skipped)
+	}
+	return nil
+}
+
+// tailFile incrementally parses a growing output.xml until its closing
+// robot tag, logging the running counts at most once per interval.
+func tailFile(ctx context.Context, filename string, interval, timeout time.Duration) (liveCounts, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return liveCounts{}, err
+	}
+	defer f.Close()
+
+	logrus.Infof("Following %s\n", filename)
+	decoder := newReportDecoder(&tailReader{ctx: ctx, file: f, interval: interval, timeout: timeout})
+
+	var counts liveCounts
+	var stack []string
+	status := ""
+	lastLog := time.Now()
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return counts, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "status" && len(stack) > 0 && stack[len(stack)-1] == "test" {
+				status = attrValue(t, "status")
+			}
+			stack = append(stack, t.Name.Local)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			switch t.Name.Local {
+			case "test":
+				counts.add(status)
+				status = ""
+				if time.Since(lastLog) >= interval {
+					lastLog = time.Now()
+					logrus.Infof("Running: %d tests, %d passed, %d failed, %d skipped\n",
+						counts.Total, counts.Passed, counts.Failed, counts.Skipped)
+				}
+			case "robot":
+				return counts, nil
+			}
+		}
+	}
+}
+
+func (c *liveCounts) add(status string) {
+	c.Total++
+	switch status {
+	case "PASS":
+		c.Passed++
+	case "FAIL":
+		c.Failed++
+	case "SKIP":
+		c.Skipped++
+	}
+}
+
+func attrValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// tailReader reads a file that is still being written, waiting for more
+// data at the end of the file instead of returning io.EOF.
+type tailReader struct {
+	ctx      context.Context
+	file     *os.File
+	interval time.Duration
+	timeout  time.Duration
+	lastData time.Time
+}
+
+func (r *tailReader) Read(p []byte) (int, error) {
+	if r.lastData.IsZero() {
+		r.lastData = time.Now()
+	}
+	for {
+		n, err := r.file.Read(p)
+		if n > 0 {
+			r.lastData = time.Now()
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if time.Since(r.lastData) > r.timeout {
+			return 0, fmt.Errorf("no new data for %s", r.timeout)
+		}
+
+		select {
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		case <-time.After(r.interval):
+		}
+	}
+}
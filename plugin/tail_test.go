@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTailFile validates incremental parsing of an output.xml being written
+func TestTailFile(t *testing.T) {
+	content, err := os.ReadFile("../testdata/rf7/output.xml")
+	if err != nil {
+		t.Fatalf("Failed to read test data: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "output.xml")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create report: %v", err)
+	}
+	defer f.Close()
+
+	// Write the report in chunks, as Robot Framework does while running
+	half := len(content) / 2
+	f.Write(content[:half])
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		f.Write(content[half:])
+	}()
+
+	counts, err := tailFile(context.Background(), path, 5*time.Millisecond, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts != (liveCounts{Total: 2, Passed: 1, Failed: 1}) {
+		t.Errorf("Expected 2 tests with 1 passed and 1 failed, got %+v", counts)
+	}
+}
+
+// TestTailFileTimeout validates the error for reports that stop growing before completion
+func TestTailFileTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.xml")
+	if err := os.WriteFile(path, []byte(`<robot><suite name="Root">`), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	_, err := tailFile(context.Background(), path, 5*time.Millisecond, 30*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "no new data for 30ms") {
+		t.Errorf("Expected idle timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// thresholdRule limits a single metric with an optional warning limit
+// and an optional failure limit. A limit is breached when the metric
+// value exceeds it, or falls below it for minimum limits.
+type thresholdRule struct {
+	Metric    string   // metric name, as returned by statsMetrics
+	Label     string   // human readable metric description
+	Warn      *float64 // warning limit, nil when unset
+	Fail      *float64 // failure limit, nil when unset
+	WarnLabel string
+	FailLabel string
+	Minimum   bool // the limits are lower bounds
+
+	// Setting names of the limits, reported by input validation.
+	WarnSetting string
+	FailSetting string
+}
+
+// thresholdRules returns the table of configured threshold rules.
+func thresholdRules(args Args) []thresholdRule {
+	passThreshold := float64(args.PassThreshold)
+	unstableThreshold := float64(args.UnstableThreshold)
+
+	return []thresholdRule{
+		{
+			Metric:      "failed_tests",
+			Label:       "failed tests count",
+			Warn:        &unstableThreshold,
+			Fail:        &passThreshold,
+			WarnLabel:   "unstable threshold",
+			FailLabel:   "pass threshold",
+			WarnSetting: "PLUGIN_UNSTABLE_THRESHOLD",
+			FailSetting: "PLUGIN_PASS_THRESHOLD",
+		},
+		{
+			Metric:      "failure_rate",
+			Label:       "failure rate",
+			Warn:        args.FailureRateWarn,
+			Fail:        args.FailureRateFail,
+			WarnSetting: "PLUGIN_FAILURE_RATE_WARN",
+			FailSetting: "PLUGIN_FAILURE_RATE_FAIL",
+		},
+		{
+			Metric:      "skipped_rate",
+			Label:       "skipped rate",
+			Warn:        args.SkippedRateWarn,
+			Fail:        firstLimit(args.SkippedRateFail, args.MaxSkippedRate),
+			WarnSetting: "PLUGIN_SKIPPED_RATE_WARN",
+			FailSetting: skippedRateFailSetting(args),
+		},
+		{
+			Metric:      "critical_failed",
+			Label:       "critical failed tests count",
+			Warn:        args.CriticalFailedWarn,
+			Fail:        args.CriticalFailedFail,
+			WarnSetting: "PLUGIN_CRITICAL_FAILED_WARN",
+			FailSetting: "PLUGIN_CRITICAL_FAILED_FAIL",
+		},
+		{
+			Metric:      "flaky_tests",
+			Label:       "flaky tests count",
+			Fail:        args.MaxFlakyTests,
+			FailSetting: "PLUGIN_MAX_FLAKY_TESTS",
+		},
+		{
+			Metric:      "failed_keywords",
+			Label:       "failed keywords count",
+			Fail:        args.MaxFailedKeywords,
+			FailSetting: "PLUGIN_MAX_FAILED_KEYWORDS",
+		},
+		{
+			Metric:      "keyword_failure_rate",
+			Label:       "keyword failure rate",
+			Fail:        args.MaxKeywordFailureRate,
+			FailSetting: "PLUGIN_MAX_KEYWORD_FAILURE_RATE",
+		},
+		{
+			Metric:      "execution_time",
+			Label:       "execution time (ms)",
+			Warn:        args.ExecutionTimeWarn,
+			Fail:        args.ExecutionTimeFail,
+			WarnSetting: "PLUGIN_EXECUTION_TIME_WARN",
+			FailSetting: "PLUGIN_EXECUTION_TIME_FAIL",
+		},
+		{
+			Metric:      "total_warnings",
+			Label:       "warnings count",
+			Warn:        args.WarningsWarn,
+			Fail:        args.WarningsFail,
+			WarnSetting: "PLUGIN_WARNINGS_WARN",
+			FailSetting: "PLUGIN_WARNINGS_FAIL",
+		},
+		{
+			Metric:      "shard_imbalance",
+			Label:       "shard imbalance",
+			Warn:        args.ShardImbalanceWarn,
+			WarnSetting: "PLUGIN_SHARD_IMBALANCE_WARN",
+		},
+		{
+			Metric:      "quality_score",
+			Label:       "quality score",
+			Fail:        args.MinQualityScore,
+			FailLabel:   "minimum quality score",
+			FailSetting: "PLUGIN_MIN_QUALITY_SCORE",
+			Minimum:     true,
+		},
+	}
+}
+
+func skippedRateFailSetting(args Args) string {
+	if args.SkippedRateFail == nil && args.MaxSkippedRate != nil {
+		return "PLUGIN_MAX_SKIPPED_RATE"
+	}
+	return "PLUGIN_SKIPPED_RATE_FAIL"
+}
+
+// validateThresholdRules checks that all configured limits are
+// non-negative and that warning limits do not exceed failure limits.
+func validateThresholdRules(rules []thresholdRule) error {
+	var errs []error
+	for _, rule := range rules {
+		if rule.Warn != nil && *rule.Warn < 0 {
+			errs = append(errs, fmt.Errorf("%s: %s threshold values must be non-negative", rule.WarnSetting, rule.Label))
+		}
+		if rule.Fail != nil && *rule.Fail < 0 {
+			errs = append(errs, fmt.Errorf("%s: %s threshold values must be non-negative", rule.FailSetting, rule.Label))
+		}
+		if rule.Warn != nil && rule.Fail != nil && rule.breaches(*rule.Warn, *rule.Fail) {
+			errs = append(errs, fmt.Errorf("%s: %s (%s) must not %s %s (%s)", rule.WarnSetting,
+				labelOr(rule.WarnLabel, "warn threshold"), formatMetric(*rule.Warn), rule.verb(), rule.FailSetting, formatMetric(*rule.Fail)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// thresholdGates evaluates the rules with at least one configured limit,
+// logging a warning for every breached warning limit. The prefix
+// qualifies the gate names, e.g. with the suite of suite thresholds.
+func thresholdGates(prefix string, rules []thresholdRule, stats StatsResult) []GateResult {
+	metrics := statsMetrics(stats)
+
+	var gates []GateResult
+	for _, rule := range rules {
+		if rule.Warn == nil && rule.Fail == nil {
+			continue
+		}
+		value := metrics[rule.Metric]
+		gate := GateResult{Name: prefix + rule.Metric, Metric: rule.Metric, Limit: firstLimit(rule.Fail, rule.Warn), Actual: value, Outcome: gatePass}
+		switch {
+		case rule.Fail != nil && rule.breaches(value, *rule.Fail):
+			gate.Outcome = gateFail
+			gate.Message = fmt.Sprintf("%s (%s) %ss the %s (%s)",
+				rule.Label, formatMetric(value), rule.verb(), labelOr(rule.FailLabel, "fail threshold"), formatMetric(*rule.Fail))
+		case rule.Warn != nil && rule.breaches(value, *rule.Warn):
+			gate.Outcome, gate.Limit = gateWarn, rule.Warn
+			gate.Message = fmt.Sprintf("%s (%s) %ss the %s (%s)",
+				rule.Label, formatMetric(value), rule.verb(), labelOr(rule.WarnLabel, "warn threshold"), formatMetric(*rule.Warn))
+			logrus.Warnf("Warning: %s", gate.Message)
+		}
+		gates = append(gates, gate)
+	}
+	return gates
+}
+
+// breaches reports whether the value breaches the limit.
+func (r thresholdRule) breaches(value, limit float64) bool {
+	if r.Minimum {
+		return value < limit
+	}
+	return value > limit
+}
+
+// verb describes a breach of the rule's limits.
+func (r thresholdRule) verb() string {
+	if r.Minimum {
+		return "undercut"
+	}
+	return "exceed"
+}
+
+// firstLimit returns the first configured limit.
+func firstLimit(limits ...*float64) *float64 {
+	for _, limit := range limits {
+		if limit != nil {
+			return limit
+		}
+	}
+	return nil
+}
+
+// formatMetric formats a metric value without trailing zeros.
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func labelOr(label, fallback string) string {
+	if label == "" {
+		return fallback
+	}
+	return label
+}
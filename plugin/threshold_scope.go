@@ -0,0 +1,72 @@
+package plugin
+
+import "strings"
+
+// Threshold scopes, selecting whether the threshold rules are evaluated
+// against the aggregated statistics, every report file or both.
+const (
+	thresholdsScopeAggregate = "aggregate"
+	thresholdsScopePerFile   = "per-file"
+	thresholdsScopeBoth      = "both"
+)
+
+// perFileMetrics are the metrics of the threshold rules available for
+// individual report files.
+var perFileMetrics = []string{"failed_tests", "failure_rate", "skipped_rate", "critical_failed", "execution_time", "total_warnings"}
+
+// validThresholdsScope reports whether the threshold scope is known.
+func validThresholdsScope(scope string) bool {
+	switch strings.ToLower(strings.TrimSpace(scope)) {
+	case "", thresholdsScopeAggregate, thresholdsScopePerFile, thresholdsScopeBoth:
+		return true
+	}
+	return false
+}
+
+// thresholdsScope returns the normalized threshold scope, aggregate by
+// default.
+func thresholdsScope(scope string) string {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	if scope == "" {
+		return thresholdsScopeAggregate
+	}
+	return scope
+}
+
+// fileThresholdGates evaluates the threshold rules against every report
+// file, so a shard that failed completely is caught even when the
+// aggregated statistics look fine. The gates are named after the file.
+func fileThresholdGates(stats StatsResult, args Args) []GateResult {
+	var gates []GateResult
+	for _, file := range stats.Files {
+		var rules []thresholdRule
+		for _, rule := range thresholdRules(args) {
+			if containsString(perFileMetrics, rule.Metric) {
+				rule.Label += " of file " + file.File
+				rules = append(rules, rule)
+			}
+		}
+		gates = append(gates, thresholdGates("file "+file.File+" ", rules, file.statsResult())...)
+	}
+	return gates
+}
+
+// statsResult returns the statistics of the report file the threshold
+// rules are evaluated against.
+func (f FileStats) statsResult() StatsResult {
+	stats := StatsResult{
+		TotalTests:     f.TotalTests,
+		PassedTests:    f.PassedTests,
+		FailedTests:    f.FailedTests,
+		SkippedTests:   f.SkippedTests,
+		CriticalFailed: f.CriticalFailed,
+		TotalWarnings:  f.TotalWarnings,
+		ExecutionTime:  f.ExecutionTime,
+		WallClockTime:  f.WallClockTime,
+	}
+	if stats.TotalTests > 0 {
+		stats.FailureRate = float64(stats.FailedTests) / float64(stats.TotalTests) * 100
+		stats.SkippedRate = float64(stats.SkippedTests) / float64(stats.TotalTests) * 100
+	}
+	return stats
+}
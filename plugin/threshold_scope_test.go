@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestThresholdsScope validates the threshold rules evaluated against the
+// aggregated statistics, every report file or both
+func TestThresholdsScope(t *testing.T) {
+	failRate := 50.0
+	stats := StatsResult{
+		TotalTests:  20,
+		PassedTests: 17,
+		FailedTests: 3,
+		FailureRate: 15,
+		Files: []FileStats{
+			{File: "shard-1.xml", TotalTests: 16, PassedTests: 16},
+			{File: "shard-2.xml", TotalTests: 4, PassedTests: 1, FailedTests: 3},
+		},
+	}
+
+	tests := []struct {
+		scope  string
+		gates  []string
+		failed []string
+	}{
+		{"", []string{"failed_tests", "failure_rate"}, nil},
+		{"aggregate", []string{"failed_tests", "failure_rate"}, nil},
+		{
+			"per-file",
+			[]string{"file shard-1.xml failed_tests", "file shard-1.xml failure_rate", "file shard-2.xml failed_tests", "file shard-2.xml failure_rate"},
+			[]string{"file shard-2.xml failure_rate"},
+		},
+		{
+			"both",
+			[]string{"failed_tests", "failure_rate", "file shard-1.xml failed_tests", "file shard-1.xml failure_rate", "file shard-2.xml failed_tests", "file shard-2.xml failure_rate"},
+			[]string{"file shard-2.xml failure_rate"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.scope, func(t *testing.T) {
+			args := Args{PassThreshold: 5, ThresholdsScope: tc.scope, FailureRateFail: &failRate}
+			gates := evaluateThresholds(stats, args)
+			var names, failed []string
+			for _, gate := range gates {
+				names = append(names, gate.Name)
+				if gate.Outcome == gateFail {
+					failed = append(failed, gate.Name)
+				}
+			}
+			if !cmp.Equal(names, tc.gates) {
+				t.Errorf("Expected gates %v, got %v", tc.gates, names)
+			}
+			if !cmp.Equal(failed, tc.failed) {
+				t.Errorf("Expected failed gates %v, got %v", tc.failed, failed)
+			}
+		})
+	}
+
+	for _, gate := range evaluateThresholds(stats, Args{PassThreshold: 5, ThresholdsScope: "per-file", FailureRateFail: &failRate}) {
+		if gate.Outcome == gateFail && gate.Message != "failure rate of file shard-2.xml (75) exceeds the fail threshold (50)" {
+			t.Errorf("Unexpected gate message %q", gate.Message)
+		}
+	}
+}
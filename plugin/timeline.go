@@ -0,0 +1,241 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// timelineMinGap is the shortest idle period, in milliseconds, reported
+// as a gap of the timeline.
+const timelineMinGap = 1000
+
+// Timeline is the execution timeline of the tests, by pabot worker or
+// report file. Durations and offsets are in milliseconds.
+type Timeline struct {
+	Start          time.Time      `json:"start"`
+	End            time.Time      `json:"end"`
+	Duration       float64        `json:"duration"`
+	MaxConcurrency int            `json:"max_concurrency"`
+	Lanes          []TimelineLane `json:"lanes"`
+	Gaps           []TimelineGap  `json:"gaps,omitempty"`
+}
+
+// TimelineLane holds the tests run by a pabot worker, or of a report file
+// without pabot. Busy is the time the lane ran tests.
+type TimelineLane struct {
+	Name  string          `json:"name"`
+	Busy  float64         `json:"busy"`
+	Tests []TimelineEntry `json:"tests"`
+}
+
+// TimelineEntry is a test of the timeline. Offset is the time from the
+// start of the timeline.
+type TimelineEntry struct {
+	Name     string    `json:"name"`
+	Suite    string    `json:"suite"`
+	Status   string    `json:"status"`
+	Start    time.Time `json:"start"`
+	Offset   float64   `json:"offset"`
+	Duration float64   `json:"duration"`
+}
+
+// TimelineGap is a period no test ran. Offset is the time from the start
+// of the timeline.
+type TimelineGap struct {
+	Start    time.Time `json:"start"`
+	Offset   float64   `json:"offset"`
+	Duration float64   `json:"duration"`
+}
+
+// timelineLane returns the lane name of a test: the pabot worker, the
+// report file, or "all" when the file is not known.
+func timelineLane(test TestResult) string {
+	if worker := pabotWorker(test.File); worker != "" {
+		return worker
+	}
+	if test.File != "" {
+		return test.File
+	}
+	return "all"
+}
+
+// milliseconds returns the duration in milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// buildTimeline returns the execution timeline of the tests with a start
+// time. Lanes are sorted by name and their tests by start time.
+func buildTimeline(tests []TestResult) Timeline {
+	var timeline Timeline
+	lanes := make(map[string]*TimelineLane)
+	type interval struct{ start, end time.Time }
+	var intervals []interval
+
+	for _, test := range tests {
+		if test.Start == nil {
+			continue
+		}
+		start := *test.Start
+		end := start.Add(time.Duration(test.Duration * float64(time.Millisecond)))
+		if timeline.Start.IsZero() || start.Before(timeline.Start) {
+			timeline.Start = start
+		}
+		if end.After(timeline.End) {
+			timeline.End = end
+		}
+		intervals = append(intervals, interval{start, end})
+
+		name := timelineLane(test)
+		lane, ok := lanes[name]
+		if !ok {
+			lane = &TimelineLane{Name: name}
+			lanes[name] = lane
+		}
+		lane.Busy += test.Duration
+		lane.Tests = append(lane.Tests, TimelineEntry{
+			Name:     test.Name,
+			Suite:    test.Suite,
+			Status:   test.Status,
+			Start:    start,
+			Duration: test.Duration,
+		})
+	}
+	if len(intervals) == 0 {
+		return timeline
+	}
+	timeline.Duration = milliseconds(timeline.End.Sub(timeline.Start))
+
+	for _, lane := range lanes {
+		sort.SliceStable(lane.Tests, func(i, j int) bool {
+			return lane.Tests[i].Start.Before(lane.Tests[j].Start)
+		})
+		for i := range lane.Tests {
+			lane.Tests[i].Offset = milliseconds(lane.Tests[i].Start.Sub(timeline.Start))
+		}
+		timeline.Lanes = append(timeline.Lanes, *lane)
+	}
+	sort.Slice(timeline.Lanes, func(i, j int) bool {
+		return timeline.Lanes[i].Name < timeline.Lanes[j].Name
+	})
+
+	// The concurrency changes at the start and end of each test. Ends
+	// sort before starts at the same time, so back to back tests do not
+	// overlap.
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	events := make([]event, 0, 2*len(intervals))
+	for _, iv := range intervals {
+		events = append(events, event{iv.start, 1}, event{iv.end, -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].at.Equal(events[j].at) {
+			return events[i].delta < events[j].delta
+		}
+		return events[i].at.Before(events[j].at)
+	})
+	running := 0
+	var idle time.Time
+	for _, e := range events {
+		if running == 0 && e.delta > 0 && !idle.IsZero() {
+			if gap := milliseconds(e.at.Sub(idle)); gap >= timelineMinGap {
+				timeline.Gaps = append(timeline.Gaps, TimelineGap{
+					Start:    idle,
+					Offset:   milliseconds(idle.Sub(timeline.Start)),
+					Duration: gap,
+				})
+			}
+		}
+		running += e.delta
+		if running > timeline.MaxConcurrency {
+			timeline.MaxConcurrency = running
+		}
+		if running == 0 {
+			idle = e.at
+		}
+	}
+	return timeline
+}
+
+// percent returns the share of part in total as a percentage, zero for an
+// empty total.
+func percent(part, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+var timelineTemplate = htmltemplate.Must(htmltemplate.New("timeline").Funcs(htmltemplate.FuncMap{
+	"percent":  percent,
+	"lower":    strings.ToLower,
+	"duration": formatDuration,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Robot Framework Execution Timeline</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #24292f; }
+.lane { display: flex; align-items: center; margin-bottom: 4px; }
+.name { width: 16em; overflow: hidden; text-overflow: ellipsis; white-space: nowrap; font-size: 0.9em; }
+.track { position: relative; flex: 1; height: 1.4em; background: #f6f8fa; }
+.bar { position: absolute; top: 0; bottom: 0; min-width: 1px; }
+.gap { position: absolute; top: 0; bottom: 0; background: repeating-linear-gradient(45deg, #fff8c5, #fff8c5 4px, #f6f8fa 4px, #f6f8fa 8px); }
+.pass { background: #2da44e; } .fail { background: #cf222e; } .skip, .not { background: #8c959f; }
+</style>
+</head>
+<body>
+<h1>Robot Framework Execution Timeline</h1>
+{{- if .Timeline.Lanes}}
+<p>{{len .Timeline.Lanes}} lanes · {{duration .Timeline.Duration .Unit}} · max concurrency {{.Timeline.MaxConcurrency}}{{with .Timeline.Gaps}} · {{len .}} gaps{{end}}</p>
+{{- range .Timeline.Lanes}}
+<div class="lane">
+<div class="name" title="{{.Name}}">{{.Name}} ({{printf "%.0f" (percent .Busy $.Timeline.Duration)}}% busy)</div>
+<div class="track">
+{{- range $.Timeline.Gaps}}
+<div class="gap" style="left: {{printf "%.3f" (percent .Offset $.Timeline.Duration)}}%; width: {{printf "%.3f" (percent .Duration $.Timeline.Duration)}}%" title="idle {{duration .Duration $.Unit}}"></div>
+{{- end}}
+{{- range .Tests}}
+<div class="bar {{lower .Status}}" style="left: {{printf "%.3f" (percent .Offset $.Timeline.Duration)}}%; width: {{printf "%.3f" (percent .Duration $.Timeline.Duration)}}%" title="{{.Suite}} / {{.Name}}: {{.Status}}, {{duration .Duration $.Unit}}"></div>
+{{- end}}
+</div>
+</div>
+{{- end}}
+{{- else}}
+<p>No test start times were reported.</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// newTimelineReporter writes the execution timeline of the tests: an HTML
+// Gantt chart when the extension is .html, and JSON otherwise.
+func newTimelineReporter(args Args) (Reporter, error) {
+	if args.TimelineFile == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		timeline := buildTimeline(stats.Tests)
+		if !strings.EqualFold(filepath.Ext(args.TimelineFile), ".html") {
+			return WriteJSON(args.TimelineFile, timeline)
+		}
+		var b bytes.Buffer
+		data := struct {
+			Timeline Timeline
+			Unit     string
+		}{timeline, args.DurationUnit}
+		if err := timelineTemplate.Execute(&b, data); err != nil {
+			return err
+		}
+		return os.WriteFile(args.TimelineFile, b.Bytes(), 0644)
+	}), nil
+}
@@ -0,0 +1,121 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestBuildTimeline validates the lanes, concurrency and gaps of the
+// execution timeline
+func TestBuildTimeline(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	at := func(ms int) *time.Time { return timePtr(base.Add(time.Duration(ms) * time.Millisecond)) }
+	worker := func(n string) string { return filepath.Join("pabot_results", n, "output.xml") }
+
+	tests := []TestResult{
+		{Name: "A", Suite: "S", Status: "PASS", Duration: 2000, Start: at(0), File: worker("0")},
+		{Name: "B", Suite: "S", Status: "FAIL", Duration: 1000, Start: at(2000), File: worker("0")},
+		{Name: "C", Suite: "S", Status: "PASS", Duration: 2500, Start: at(500), File: worker("1")},
+		{Name: "D", Suite: "S", Status: "PASS", Duration: 1000, Start: at(5000), File: worker("1")},
+		{Name: "E", Suite: "S", Status: "PASS", Duration: 100},
+	}
+
+	got := buildTimeline(tests)
+	want := Timeline{
+		Start:          base,
+		End:            base.Add(6 * time.Second),
+		Duration:       6000,
+		MaxConcurrency: 2,
+		Lanes: []TimelineLane{
+			{Name: "0", Busy: 3000, Tests: []TimelineEntry{
+				{Name: "A", Suite: "S", Status: "PASS", Start: base, Offset: 0, Duration: 2000},
+				{Name: "B", Suite: "S", Status: "FAIL", Start: *at(2000), Offset: 2000, Duration: 1000},
+			}},
+			{Name: "1", Busy: 3500, Tests: []TimelineEntry{
+				{Name: "C", Suite: "S", Status: "PASS", Start: *at(500), Offset: 500, Duration: 2500},
+				{Name: "D", Suite: "S", Status: "PASS", Start: *at(5000), Offset: 5000, Duration: 1000},
+			}},
+		},
+		Gaps: []TimelineGap{{Start: *at(3000), Offset: 3000, Duration: 2000}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("timeline mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := buildTimeline(tests[4:]); len(got.Lanes) != 0 || got.MaxConcurrency != 0 {
+		t.Errorf("expected an empty timeline without start times, got %+v", got)
+	}
+}
+
+// TestBuildTimelineBackToBack validates that back to back tests do not
+// overlap and short idle periods are not reported as gaps
+func TestBuildTimelineBackToBack(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tests := []TestResult{
+		{Name: "A", Status: "PASS", Duration: 1000, Start: timePtr(base), File: "output.xml"},
+		{Name: "B", Status: "PASS", Duration: 1000, Start: timePtr(base.Add(time.Second)), File: "output.xml"},
+		{Name: "C", Status: "PASS", Duration: 1000, Start: timePtr(base.Add(2500 * time.Millisecond)), File: "output.xml"},
+	}
+	got := buildTimeline(tests)
+	if got.MaxConcurrency != 1 {
+		t.Errorf("expected max concurrency 1, got %d", got.MaxConcurrency)
+	}
+	if len(got.Gaps) != 0 {
+		t.Errorf("expected no gaps, got %+v", got.Gaps)
+	}
+	if len(got.Lanes) != 1 || got.Lanes[0].Name != "output.xml" {
+		t.Errorf("expected a lane per report file, got %+v", got.Lanes)
+	}
+}
+
+// TestTimelineReporter validates the JSON and HTML timeline files
+func TestTimelineReporter(t *testing.T) {
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	stats := StatsResult{Tests: []TestResult{
+		{Name: "Login <admin>", Suite: "Web", Status: "FAIL", Duration: 1500, Start: timePtr(base), File: "output.xml"},
+	}}
+
+	if reporter, err := newTimelineReporter(Args{}); err != nil || reporter != nil {
+		t.Fatalf("expected no reporter without a timeline file, got %v, %v", reporter, err)
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"timeline.json", "timeline.HTML"} {
+		file := filepath.Join(dir, name)
+		reporter, err := newTimelineReporter(Args{TimelineFile: file, DurationUnit: "s"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := reporter.Report(context.Background(), stats); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if name == "timeline.json" {
+			var got Timeline
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Lanes) != 1 || len(got.Lanes[0].Tests) != 1 || got.Duration != 1500 {
+				t.Errorf("unexpected JSON timeline %+v", got)
+			}
+			continue
+		}
+		html := string(data)
+		for _, want := range []string{`class="bar fail"`, "width: 100.000%", "Login &lt;admin&gt;", "1.50 s"} {
+			if !strings.Contains(html, want) {
+				t.Errorf("expected HTML timeline to contain %q", want)
+			}
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// defaultTrendRuns is the number of runs shown in the trend chart,
+// including the current run.
+const defaultTrendRuns = 20
+
+// Trend chart geometry, in pixels.
+const (
+	trendWidth   = 480
+	trendHeight  = 160
+	trendPadding = 30
+)
+
+// Trend chart colors.
+var (
+	trendPassColor     = color.RGBA{0x2d, 0xa4, 0x4e, 0xff}
+	trendDurationColor = color.RGBA{0x09, 0x69, 0xda, 0xff}
+	trendAxisColor     = color.RGBA{0xd0, 0xd7, 0xde, 0xff}
+)
+
+// TrendPoint is a run of the trend chart. Duration is in milliseconds.
+type TrendPoint struct {
+	Build    string    `json:"build,omitempty"`
+	Recorded time.Time `json:"recorded"`
+	PassRate float64   `json:"pass_rate"`
+	Duration float64   `json:"duration"`
+}
+
+// newTrendPoint returns the trend point of the run statistics.
+func newTrendPoint(stats StatsResult, build *BuildInfo, recorded time.Time) TrendPoint {
+	point := TrendPoint{Recorded: recorded, Duration: stats.WallClockTime}
+	if build != nil {
+		point.Build = build.Number
+	}
+	if point.Duration == 0 {
+		point.Duration = stats.ExecutionTime
+	}
+	if stats.TotalTests > 0 {
+		point.PassRate = float64(stats.PassedTests) / float64(stats.TotalTests) * 100
+	}
+	return point
+}
+
+// trendRuns returns the number of runs of the trend chart.
+func trendRuns(args Args) int {
+	if args.TrendRuns > 0 {
+		return args.TrendRuns
+	}
+	return defaultTrendRuns
+}
+
+// trendEnabled reports whether an output renders the trend chart.
+func trendEnabled(args Args) bool {
+	return args.HistoryDir != "" && (args.TrendChart != "" || args.HTMLReport != "" || args.MarkdownSummary != "")
+}
+
+// trendPoints returns the trend of the last recorded runs followed by the
+// current run, at most runs points.
+func trendPoints(records []HistoryRecord, stats StatsResult, runs int) []TrendPoint {
+	var points []TrendPoint
+	for _, record := range records {
+		points = append(points, newTrendPoint(record.Stats, record.Build, record.Recorded))
+	}
+	points = append(points, newTrendPoint(stats, stats.Build, time.Now().UTC()))
+	if len(points) > runs {
+		points = points[len(points)-runs:]
+	}
+	return points
+}
+
+// trendCoordinates returns the chart coordinates of the pass rates,
+// scaled from 0 to 100%, and of the durations, scaled from 0 to the
+// longest duration.
+func trendCoordinates(points []TrendPoint) (pass, duration []image.Point, maxDuration float64) {
+	for _, point := range points {
+		if point.Duration > maxDuration {
+			maxDuration = point.Duration
+		}
+	}
+	plotWidth := float64(trendWidth - 2*trendPadding)
+	plotHeight := float64(trendHeight - 2*trendPadding)
+	for i, point := range points {
+		x := float64(trendPadding) + plotWidth/2
+		if len(points) > 1 {
+			x = float64(trendPadding) + plotWidth*float64(i)/float64(len(points)-1)
+		}
+		y := float64(trendHeight-trendPadding) - plotHeight*point.PassRate/100
+		pass = append(pass, image.Pt(int(x), int(y)))
+		y = float64(trendHeight - trendPadding)
+		if maxDuration > 0 {
+			y -= plotHeight * point.Duration / maxDuration
+		}
+		duration = append(duration, image.Pt(int(x), int(y)))
+	}
+	return pass, duration, maxDuration
+}
+
+// trendSVG renders the pass rate and duration trend as SVG.
+func trendSVG(points []TrendPoint) []byte {
+	pass, duration, maxDuration := trendCoordinates(points)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`+"\n", trendWidth, trendHeight, trendWidth, trendHeight)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", trendWidth, trendHeight)
+	fmt.Fprintf(&b, `<path d="M%d %d H%d M%d %d H%d" stroke="%s"/>`+"\n",
+		trendPadding, trendPadding, trendWidth-trendPadding,
+		trendPadding, trendHeight-trendPadding, trendWidth-trendPadding, hexColor(trendAxisColor))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" fill="%s">100%%</text>`+"\n", trendPadding-4, trendPadding+3, hexColor(trendPassColor))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" fill="%s">0%%</text>`+"\n", trendPadding-4, trendHeight-trendPadding+3, hexColor(trendPassColor))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", trendWidth-trendPadding+4, trendPadding+3, hexColor(trendDurationColor), formatSeconds(maxDuration))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">Pass rate</text>`+"\n", trendPadding, trendPadding-12, hexColor(trendPassColor))
+	fmt.Fprintf(&b, `<text x="%d" y="%d" fill="%s">Duration</text>`+"\n", trendPadding+70, trendPadding-12, hexColor(trendDurationColor))
+	for _, line := range []struct {
+		points []image.Point
+		color  color.RGBA
+	}{{duration, trendDurationColor}, {pass, trendPassColor}} {
+		coords := make([]string, len(line.points))
+		for i, p := range line.points {
+			coords[i] = fmt.Sprintf("%d,%d", p.X, p.Y)
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(coords, " "), hexColor(line.color))
+		if n := len(line.points); n > 0 {
+			fmt.Fprintf(&b, `<circle cx="%d" cy="%d" r="3" fill="%s"/>`+"\n", line.points[n-1].X, line.points[n-1].Y, hexColor(line.color))
+		}
+	}
+	for i, point := range points {
+		if point.Build != "" && (i == 0 || i == len(points)-1) {
+			fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" fill="#57606a">#%s</text>`+"\n", pass[i].X, trendHeight-trendPadding+14, xmlEscape(point.Build))
+		}
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// trendPNG renders the pass rate and duration trend as PNG. The standard
+// library has no font rendering, so the PNG chart has no labels.
+func trendPNG(points []TrendPoint) ([]byte, error) {
+	pass, duration, _ := trendCoordinates(points)
+	img := image.NewRGBA(image.Rect(0, 0, trendWidth, trendHeight))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	drawLine(img, image.Pt(trendPadding, trendPadding), image.Pt(trendWidth-trendPadding, trendPadding), trendAxisColor)
+	drawLine(img, image.Pt(trendPadding, trendHeight-trendPadding), image.Pt(trendWidth-trendPadding, trendHeight-trendPadding), trendAxisColor)
+	for _, line := range []struct {
+		points []image.Point
+		color  color.RGBA
+	}{{duration, trendDurationColor}, {pass, trendPassColor}} {
+		for i := range line.points {
+			from := line.points[i]
+			if i > 0 {
+				from = line.points[i-1]
+			}
+			// two pixels wide
+			drawLine(img, from, line.points[i], line.color)
+			drawLine(img, from.Add(image.Pt(0, 1)), line.points[i].Add(image.Pt(0, 1)), line.color)
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// drawLine draws a line with Bresenham's algorithm.
+func drawLine(img *image.RGBA, from, to image.Point, c color.RGBA) {
+	dx, dy := abs(to.X-from.X), -abs(to.Y-from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+	err := dx + dy
+	for p := from; ; {
+		img.SetRGBA(p.X, p.Y, c)
+		if p == to {
+			return
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 := 2 * err; e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// formatSeconds formats milliseconds as seconds.
+func formatSeconds(ms float64) string {
+	return formatMetric(ms/1000) + " s"
+}
+
+// xmlEscape escapes the text for XML content and attributes.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// newTrendChartReporter writes the trend chart, as PNG when the file
+// extension is .png and as SVG otherwise.
+func newTrendChartReporter(args Args) (Reporter, error) {
+	if args.TrendChart == "" || args.HistoryDir == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		data := trendSVG(stats.Trend)
+		if strings.EqualFold(filepath.Ext(args.TrendChart), ".png") {
+			var err error
+			if data, err = trendPNG(stats.Trend); err != nil {
+				return err
+			}
+		}
+		return os.WriteFile(args.TrendChart, data, 0644)
+	}), nil
+}
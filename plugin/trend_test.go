@@ -0,0 +1,85 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestHistoryTrend validates the trend of the runs on the current branch
+func TestHistoryTrend(t *testing.T) {
+	args := Args{HistoryDir: t.TempDir(), TrendRuns: 3, HTMLReport: "report.html"}
+	history, err := newHistoryStore(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	runs := []struct {
+		number, branch string
+		passed         int
+	}{
+		{"1", "main", 1},
+		{"2", "main", 2},
+		{"3", "feature", 0},
+		{"4", "main", 3},
+	}
+	for i, run := range runs {
+		record := HistoryRecord{
+			Recorded: start.Add(time.Duration(i) * time.Hour),
+			Build:    &BuildInfo{Number: run.number, Branch: run.branch},
+			Stats:    StatsResult{TotalTests: 4, PassedTests: run.passed, WallClockTime: float64(1000 * (i + 1))},
+		}
+		if err := history.add(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current := StatsResult{TotalTests: 4, PassedTests: 4, ExecutionTime: 500, Build: &BuildInfo{Number: "5", Branch: "main"}}
+	if err := attachHistory(context.Background(), &current, args); err != nil {
+		t.Fatal(err)
+	}
+	var got []TrendPoint
+	for _, point := range current.Trend {
+		point.Recorded = time.Time{}
+		got = append(got, point)
+	}
+	want := []TrendPoint{
+		{Build: "2", PassRate: 50, Duration: 2000},
+		{Build: "4", PassRate: 75, Duration: 4000},
+		{Build: "5", PassRate: 100, Duration: 500},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected trend (-want +got):\n%s", diff)
+	}
+}
+
+// TestTrendChart validates the SVG and PNG trend charts
+func TestTrendChart(t *testing.T) {
+	points := []TrendPoint{
+		{Build: "41", PassRate: 80, Duration: 2000},
+		{Build: "<42>", PassRate: 100, Duration: 1500},
+	}
+	svg := string(trendSVG(points))
+	for _, want := range []string{`<polyline points="30,50 450,30"`, `<polyline points="30,30 450,55"`, "#&lt;42&gt;", "2 s"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected SVG chart to contain %q:\n%s", want, svg)
+		}
+	}
+
+	data, err := trendPNG(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, g, b, _ := img.At(450, 30).RGBA(); r>>8 != uint32(trendPassColor.R) || g>>8 != uint32(trendPassColor.G) || b>>8 != uint32(trendPassColor.B) {
+		t.Errorf("Expected the pass rate line at the last point, got color %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// twilioAPIURL is the base URL of the Twilio REST API.
+var twilioAPIURL = "https://api.twilio.com"
+
+// maxSMSBody keeps alerts within two SMS segments.
+const maxSMSBody = 320
+
+// matchesBranch reports whether the branch matches one of the shell
+// patterns, e.g. main or release/*.
+func matchesBranch(patterns []string, branch string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(pattern), branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// smsAlert returns the text of the SMS alert of the failed critical
+// tests.
+func smsAlert(stats StatsResult) string {
+	text := fmt.Sprintf("Robot Framework: %d of %d critical tests failed", stats.CriticalFailed, stats.TotalCritical)
+	if build := stats.Build; build != nil {
+		text += " in " + build.Repo
+		if build.Branch != "" {
+			text += " " + build.Branch
+		}
+		if build.Number != "" {
+			text += " build #" + build.Number
+		}
+		if build.Link != "" {
+			text += " " + build.Link
+		}
+	}
+	return truncateMessage(text, maxSMSBody)
+}
+
+// sendSMS sends the text message through the Twilio Messages API.
+func sendSMS(ctx context.Context, sid, token, from, to, body string) error {
+	ctx, cancel := context.WithTimeout(ctx, notificationTimeout)
+	defer cancel()
+
+	form := url.Values{"From": {from}, "To": {to}, "Body": {body}}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimSuffix(twilioAPIURL, "/"), url.PathEscape(sid))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(sid, token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Twilio responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// newTwilioReporter sends an SMS alert to every recipient when critical
+// tests fail on one of the alerting branches, as a last-resort alert of
+// release gates.
+func newTwilioReporter(args Args) (Reporter, error) {
+	if args.TwilioAccountSID == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		if stats.CriticalFailed == 0 || stats.Build == nil || !matchesBranch(args.TwilioBranches, stats.Build.Branch) {
+			return nil
+		}
+		body := smsAlert(stats)
+		var failed []string
+		for _, to := range args.TwilioTo {
+			if err := sendSMS(ctx, args.TwilioAccountSID, args.TwilioAuthToken, args.TwilioFrom, to, body); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", to, err))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("failed to send SMS alerts to %s", strings.Join(failed, "; "))
+		}
+		logrus.Infof("Sent SMS alerts of %d failed critical tests to %d recipients\n", stats.CriticalFailed, len(args.TwilioTo))
+		return nil
+	}), nil
+}
@@ -0,0 +1,78 @@
+package plugin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestTwilioReporter validates the SMS alerts of failed critical tests
+// on the alerting branches
+func TestTwilioReporter(t *testing.T) {
+	var messages []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" || user != "AC123" || pass != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		messages = append(messages, r.PostForm)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	defer func(base string) { twilioAPIURL = base }(twilioAPIURL)
+	twilioAPIURL = server.URL
+
+	args := Args{TwilioAccountSID: "AC123", TwilioAuthToken: "secret", TwilioFrom: "+15005550006", TwilioTo: []string{"+14155550100", "+14155550101"}, TwilioBranches: []string{"main", "release/*"}}
+	reporter, err := newTwilioReporter(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := StatsResult{TotalCritical: 10, CriticalFailed: 2, Build: &BuildInfo{Repo: "octo/app", Branch: "release/1.2", Number: "42"}}
+	if err := reporter.Report(context.Background(), stats); err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || messages[1].Get("To") != "+14155550101" || messages[0].Get("From") != "+15005550006" {
+		t.Fatalf("Expected an SMS to every recipient, got %v", messages)
+	}
+	if want := "Robot Framework: 2 of 10 critical tests failed in octo/app release/1.2 build #42"; messages[0].Get("Body") != want {
+		t.Errorf("Expected body %q, got %q", want, messages[0].Get("Body"))
+	}
+
+	messages = nil
+	for _, stats := range []StatsResult{
+		{TotalCritical: 10, CriticalFailed: 2, Build: &BuildInfo{Branch: "feature/x"}},
+		{TotalCritical: 10, Build: &BuildInfo{Branch: "main"}},
+		{TotalCritical: 10, CriticalFailed: 2},
+	} {
+		if err := reporter.Report(context.Background(), stats); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected no SMS off the alerting branches or without failures, got %v", messages)
+	}
+
+	args.TwilioAuthToken = "wrong"
+	reporter, _ = newTwilioReporter(args)
+	stats.Build.Branch = "main"
+	if err := reporter.Report(context.Background(), stats); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected a 401 error, got %v", err)
+	}
+}
+
+// TestValidateTwilioSettings validates the required Twilio settings
+func TestValidateTwilioSettings(t *testing.T) {
+	err := ValidateInputs(Args{ReportDirectory: ".", TwilioAccountSID: "AC123", TwilioBranches: []string{"release/["}})
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, setting := range []string{"PLUGIN_TWILIO_AUTH_TOKEN", "PLUGIN_TWILIO_FROM", "PLUGIN_TWILIO_TO", "PLUGIN_TWILIO_BRANCHES"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("Expected an error of %s, got %v", setting, err)
+		}
+	}
+}
@@ -1,12 +1,46 @@
 package plugin
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"time"
+)
 
 // RobotOutput represents the structure of Robot Framework's output.xml
 type RobotOutput struct {
-	XMLName xml.Name `xml:"robot"`
-	Suite   Suite    `xml:"suite"`
-	Errors  []Error  `xml:"errors>msg"`
+	XMLName    xml.Name   `xml:"robot"`
+	Generator  string     `xml:"generator,attr"`
+	Schema     string     `xml:"schemaversion,attr"` // RF 4 and later
+	Suite      Suite      `xml:"suite"`
+	Statistics Statistics `xml:"statistics"`
+	Errors     []Error    `xml:"errors>msg"`
+
+	// Set when the report was salvaged from truncated or malformed XML.
+	Incomplete     bool `xml:"-"`
+	RecoveredTests int  `xml:"-"`
+}
+
+// Statistics represents the totals Robot Framework computes itself.
+type Statistics struct {
+	Total []Stat `xml:"total>stat"`
+	Tag   []Stat `xml:"tag>stat"`
+	Suite []Stat `xml:"suite>stat"`
+}
+
+// Stat represents a single statistics entry. Label is the entry text,
+// e.g. "All Tests", a tag name or a suite long name. Tag entries of
+// combined tags have the combining pattern, and tag entries may have the
+// documentation and links of the --tagdoc and --tagstatlink options.
+type Stat struct {
+	Pass     int    `xml:"pass,attr"`
+	Fail     int    `xml:"fail,attr"`
+	Skip     int    `xml:"skip,attr"`
+	ID       string `xml:"id,attr,omitempty"`
+	Name     string `xml:"name,attr,omitempty"`
+	Info     string `xml:"info,attr,omitempty"` // "combined", or the criticality before RF 4
+	Combined string `xml:"combined,attr,omitempty"`
+	Doc      string `xml:"doc,attr,omitempty"`
+	Links    string `xml:"links,attr,omitempty"` // title:url pairs separated by ":::"
+	Label    string `xml:",chardata"`
 }
 
 // Suite represents a test suite, which contains tests and sub-suites.
@@ -15,39 +49,79 @@ type Suite struct {
 	Name     string    `xml:"name,attr"`
 	Source   string    `xml:"source,attr,omitempty"`
 	Doc      string    `xml:"doc,omitempty"`
+	Metadata []Meta    `xml:"meta"`          // RF 4 and later
+	MetaList []Meta    `xml:"metadata>item"` // RF 3 and earlier
 	Tests    []Test    `xml:"test"`
 	Keywords []Keyword `xml:"kw"`
 	Status   Status    `xml:"status"`
 	Suites   []Suite   `xml:"suite"`
 }
 
+// AllMetadata returns the suite metadata regardless of the output format
+// version.
+func (s Suite) AllMetadata() []Meta {
+	if len(s.MetaList) == 0 {
+		return s.Metadata
+	}
+	return append(append([]Meta{}, s.MetaList...), s.Metadata...)
+}
+
+// Meta represents a suite metadata entry, e.g. Environment or Version.
+type Meta struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
 // Test represents a test case inside a suite.
 type Test struct {
-	ID       string    `xml:"id,attr"`
-	Name     string    `xml:"name,attr"`
-	Keywords []Keyword `xml:"kw"`
-	Status   Status    `xml:"status"`
+	ID       string      `xml:"id,attr"`
+	Name     string      `xml:"name,attr"`
+	Line     int         `xml:"line,attr,omitempty"` // RF 5 and later
+	Tags     []string    `xml:"tags>tag"`            // RF 3 and earlier
+	TagList  []string    `xml:"tag"`                 // RF 4 and later
+	Keywords keywordList `xml:",any"`                // keywords and control structures
+	Status   Status      `xml:"status"`
+}
+
+// AllTags returns the test tags regardless of the output format version.
+func (t Test) AllTags() []string {
+	if len(t.TagList) == 0 {
+		return t.Tags
+	}
+	return append(append([]string{}, t.Tags...), t.TagList...)
 }
 
 // Keyword represents a keyword inside a test case or suite.
 type Keyword struct {
-	Name      string    `xml:"name,attr"`
-	Type      string    `xml:"type,attr,omitempty"` // Can be "setup", "teardown", etc.
-	Library   string    `xml:"library,attr,omitempty"`
-	Arguments []Arg     `xml:"arguments>arg"`
-	Doc       string    `xml:"doc,omitempty"`
-	Status    Status    `xml:"status"`
-	Messages  []Msg     `xml:"msg"`
-	Keywords  []Keyword `xml:"kw"`
+	Name      string      `xml:"name,attr"`
+	Type      string      `xml:"type,attr,omitempty"` // Can be "setup", "teardown", etc.
+	Library   string      `xml:"library,attr,omitempty"`
+	Owner     string      `xml:"owner,attr,omitempty"` // library, RF 7 and later
+	Arguments []Arg       `xml:"arguments>arg"`
+	Doc       string      `xml:"doc,omitempty"`
+	Status    Status      `xml:"status"`
+	Messages  []Msg       `xml:"msg"`
+	Vars      []Var       `xml:"var"` // loop variables, RF 4 and later
+	Keywords  keywordList `xml:",any"`
+}
+
+// Var represents a loop variable of a FOR loop or of one of its
+// iterations, where it holds the value of the iteration.
+type Var struct {
+	Name  string `xml:"name,attr,omitempty"`
+	Value string `xml:",chardata"`
 }
 
 // Status represents the execution status of a test, keyword, or suite.
 type Status struct {
 	Status    string `xml:"status,attr"`
-	Critical  string `xml:"critical,attr,omitempty"` // Only present in test statuses
-	StartTime string `xml:"starttime,attr,omitempty"`
-	EndTime   string `xml:"endtime,attr,omitempty"`
+	Critical  string `xml:"critical,attr,omitempty"`  // Only present in test statuses
+	StartTime string `xml:"starttime,attr,omitempty"` // RF 6 and earlier
+	EndTime   string `xml:"endtime,attr,omitempty"`   // RF 6 and earlier
+	Start     string `xml:"start,attr,omitempty"`     // RF 7 and later, ISO-8601
+	Elapsed   string `xml:"elapsed,attr,omitempty"`   // RF 7 and later, in seconds
 	Messages  []Msg  `xml:"msg"`
+	Text      string `xml:",chardata"` // failure or skip message, RF 4 and later
 }
 
 // Arg represents arguments passed to a keyword.
@@ -57,40 +131,240 @@ type Arg struct {
 
 // Msg represents log messages inside a test or keyword.
 type Msg struct {
-	Timestamp string `xml:"timestamp,attr"`
+	Timestamp string `xml:"timestamp,attr"` // RF 6 and earlier
+	Time      string `xml:"time,attr"`      // RF 7 and later, ISO-8601
 	Level     string `xml:"level,attr"`
 	Text      string `xml:",chardata"`
 }
 
 // Error represents errors in the test execution.
 type Error struct {
-	Message string `xml:",chardata"`
+	Timestamp string `xml:"timestamp,attr"` // RF 6 and earlier
+	Time      string `xml:"time,attr"`      // RF 7 and later, ISO-8601
+	Level     string `xml:"level,attr"`
+	Message   string `xml:",chardata"`
 }
 
 // StatsResult stores computed test statistics.
 type StatsResult struct {
-	TotalSuites        int
-	TotalTests         int
-	PassedTests        int
-	FailedTests        int
-	SkippedTests       int
-	TotalKeywords      int
-	PassedKeywords     int
-	FailedKeywords     int
-	SkippedKeywords    int
-	TotalCritical      int
-	CriticalPassed     int
-	CriticalFailed     int
-	FailureRate        float64
-	SkippedRate        float64
-	ExecutionTime      float64
-	FailedTestsDetails []FailedTestDetails
+	TotalSuites            int                 `json:"total_suites"`
+	TotalTests             int                 `json:"total_tests"`
+	PassedTests            int                 `json:"passed_tests"`
+	FailedTests            int                 `json:"failed_tests"`
+	SkippedTests           int                 `json:"skipped_tests"`
+	NotRunTests            int                 `json:"not_run_tests"`        // RF 5 NOT RUN status
+	UnknownStatusTests     int                 `json:"unknown_status_tests"` // empty or unmapped custom status
+	DuplicateTestIDs       int                 `json:"duplicate_test_ids"`   // tests whose ID is not unique in their report
+	EmptyTestNames         int                 `json:"empty_test_names"`
+	TotalKeywords          int                 `json:"total_keywords"`
+	PassedKeywords         int                 `json:"passed_keywords"`
+	FailedKeywords         int                 `json:"failed_keywords"`
+	SkippedKeywords        int                 `json:"skipped_keywords"`
+	NotRunKeywords         int                 `json:"not_run_keywords"`
+	TotalIterations        int                 `json:"total_iterations"` // FOR and WHILE loop iterations
+	FailedIterations       int                 `json:"failed_iterations"`
+	Setups                 KeywordStats        `json:"setups"`         // suite and test setup keywords
+	Teardowns              KeywordStats        `json:"teardowns"`      // suite and test teardown keywords
+	TotalWarnings          int                 `json:"total_warnings"` // WARN messages
+	TotalCritical          int                 `json:"total_critical"`
+	CriticalPassed         int                 `json:"critical_passed"`
+	CriticalFailed         int                 `json:"critical_failed"`
+	FailureRate            float64             `json:"failure_rate"`
+	SkippedRate            float64             `json:"skipped_rate"`
+	ExecutionTime          float64             `json:"execution_time"`       // sum of all suite and test durations
+	WallClockTime          float64             `json:"wall_clock_time"`      // root suite wall-clock time
+	CumulativeTestTime     float64             `json:"cumulative_test_time"` // sum of all test durations
+	StartTime              time.Time           `json:"start_time"`
+	EndTime                time.Time           `json:"end_time"`
+	FailedTestsDetails     []FailedTestDetails `json:"failed_tests_details,omitempty"`
+	OmittedFailureDetails  int                 `json:"omitted_failure_details,omitempty"` // beyond PLUGIN_MAX_FAILURE_DETAILS
+	AllowedFailures        int                 `json:"allowed_failures"`                  // failures of allow-failure tests, not counted as failed
+	AllowedFailuresDetails []FailedTestDetails `json:"allowed_failures_details,omitempty"`
+	AtRiskTests            int                 `json:"at_risk_tests"` // passed tests with failed keywords
+	AtRiskTestsDetails     []AtRiskTestDetails `json:"at_risk_tests_details,omitempty"`
+	FailureLeaderboard     []TestHistory       `json:"failure_leaderboard,omitempty"` // tests failing most often in the history
+	QualityScore           *float64            `json:"quality_score,omitempty"`       // weighted quality score, when enabled
+	QualityWeights         *QualityWeights     `json:"quality_weights,omitempty"`     // inputs of the quality score
+	Gates                  []GateResult        `json:"gates,omitempty"`               // gate outcomes, set when the gates are evaluated
+	Unstable               bool                `json:"unstable,omitempty"`            // only the unstable threshold was breached
+	Suites                 []SuiteStats        `json:"suites,omitempty"`
+	SlowTests              []SlowTestDetails   `json:"slow_tests,omitempty"`
+	FlakyTests             int                 `json:"flaky_tests"` // tests that failed and passed on rerun
+	FlakyTestsDetails      []FlakyTestDetails  `json:"flaky_tests_details,omitempty"`
+	FailureCategories      map[string]int      `json:"failure_categories,omitempty"`
+	SkipReasons            map[string]int      `json:"skip_reasons,omitempty"`     // skipped tests by reason
+	LibraryFailures        map[string]int      `json:"library_failures,omitempty"` // failed library keywords by library
+	Tags                   map[string]TagStats `json:"tags,omitempty"`             // counts of the output tags
+	TagStatistics          []RobotTagStats     `json:"tag_statistics,omitempty"`   // tag statistics of the reports, when enabled
+	Metadata               map[string]string   `json:"metadata,omitempty"`         // suite metadata, outer suites first
+	RobotVersion           string              `json:"robot_version,omitempty"`    // generator versions, comma separated when mixed
+	SchemaVersion          string              `json:"schema_version,omitempty"`   // output schema versions, comma separated when mixed
+	Build                  *BuildInfo          `json:"build,omitempty"`            // CI build metadata
+	Host                   *HostInfo           `json:"host,omitempty"`             // runner details, when enabled
+	Trend                  []TrendPoint        `json:"-"`                          // rendered by the reports, not exported
+	SuiteDurations         map[string]float64  `json:"-"`                          // averaged over the history, for the split manifest
+	SuiteDurationRuns      int                 `json:"-"`                          // runs the suite durations are averaged over
+	History                []HistoryRecord     `json:"-"`                          // recorded runs, read once and shared by the reporters
+	Incomplete             bool                `json:"incomplete,omitempty"`       // salvaged from truncated reports
+	RecoveredTests         int                 `json:"recovered_tests,omitempty"`  // complete tests salvaged
+	SkippedFiles           int                 `json:"skipped_files,omitempty"`    // not processed in fail-fast mode
+	Tests                  []TestResult        `json:"tests,omitempty"`
+	Files                  []FileStats         `json:"files,omitempty"`
+	SplitSuites            []SplitSuite        `json:"split_suites,omitempty"`
+	Shards                 []ShardStats        `json:"shards,omitempty"`          // pabot workers, with two or more workers
+	ShardImbalance         float64             `json:"shard_imbalance,omitempty"` // slowest shard over the mean, in percent
+}
+
+// TestResult stores the result of a single test. Suite is the suite
+// long name and Duration is in milliseconds.
+type TestResult struct {
+	Name         string  `json:"name"`
+	Suite        string  `json:"suite"`
+	Status       string  `json:"status"`
+	Duration     float64 `json:"duration"`
+	ErrorMessage string  `json:"error_message,omitempty"`
+
+	// Start is the start time of the test, when reported, and File the
+	// report file of the test, for the execution timeline.
+	Start *time.Time `json:"start,omitempty"`
+	File  string     `json:"file,omitempty"`
+
+	// AllowedFailure is set for failures not counted because of the
+	// allow-failure tag.
+	AllowedFailure bool `json:"allowed_failure,omitempty"`
+}
+
+// QualityWeights holds the weighted test counts and the failed keyword
+// penalty the quality score is computed from. They add up across files.
+type QualityWeights struct {
+	Passed  float64 `json:"passed"`
+	Total   float64 `json:"total"`
+	Penalty float64 `json:"penalty"`
+}
+
+// KeywordStats stores the counts of setup or teardown keywords.
+type KeywordStats struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	NotRun  int `json:"not_run"`
+}
+
+// TagStats stores the test counts of a tag.
+type TagStats struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// RobotTagStats stores a tag statistics entry computed by Robot
+// Framework. Combined is the pattern of combined tags, e.g. "smokeANDweb".
+type RobotTagStats struct {
+	Name     string    `json:"name"`
+	Combined string    `json:"combined,omitempty"`
+	Doc      string    `json:"doc,omitempty"`
+	Links    []TagLink `json:"links,omitempty"`
+	TagStats
+}
+
+// TagLink is a link of a tag statistics entry.
+type TagLink struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// FileStats stores per-file statistics. Worker is the pabot worker
+// label for pabot worker outputs.
+type FileStats struct {
+	File          string  `json:"file"`
+	Worker        string  `json:"worker,omitempty"`
+	TotalTests    int     `json:"total_tests"`
+	PassedTests   int     `json:"passed_tests"`
+	FailedTests   int     `json:"failed_tests"`
+	SkippedTests  int     `json:"skipped_tests"`
+	WallClockTime float64 `json:"wall_clock_time"`
+	RobotVersion  string  `json:"robot_version,omitempty"`
+	SchemaVersion string  `json:"schema_version,omitempty"`
+
+	// Further metrics of the per-file threshold rules.
+	CriticalFailed int     `json:"critical_failed"`
+	TotalWarnings  int     `json:"total_warnings"`
+	ExecutionTime  float64 `json:"execution_time"`
+}
+
+// SplitSuite stores a suite whose tests were split across pabot workers.
+type SplitSuite struct {
+	Name    string   `json:"name"`
+	Workers []string `json:"workers,omitempty"`
+}
+
+// SuiteStats stores per-suite statistics.
+type SuiteStats struct {
+	Name          string            `json:"name"` // suite long name, e.g. "Root.Child"
+	ExecutionTime float64           `json:"execution_time"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+// FlakyTestDetails stores information about tests that failed and then
+// passed on rerun. Suite is the suite long name.
+type FlakyTestDetails struct {
+	Name  string `json:"name"`
+	Suite string `json:"suite"`
+}
+
+// AtRiskTestDetails stores a passed test with failed keywords, often
+// keywords run with Run Keyword And Ignore Error. Keyword and Message
+// are the name and failure message of the first failed keyword.
+type AtRiskTestDetails struct {
+	Name           string `json:"name"`
+	Suite          string `json:"suite"`
+	FailedKeywords int    `json:"failed_keywords"`
+	Keyword        string `json:"keyword,omitempty"`
+	Message        string `json:"message,omitempty"`
+}
+
+// SlowTestDetails stores information about tests exceeding their
+// duration budget.
+type SlowTestDetails struct {
+	Name     string  `json:"name"`
+	Suite    string  `json:"suite"`
+	Status   string  `json:"status"`
+	Duration float64 `json:"duration"`
+	Budget   float64 `json:"budget"`
 }
 
 // FailedTestDetails stores information about failed tests.
 type FailedTestDetails struct {
-	Name         string
-	Suite        string
-	Status       string
-	ErrorMessage string
+	Name         string  `json:"name"`
+	Suite        string  `json:"suite"`
+	Status       string  `json:"status"`
+	Duration     float64 `json:"duration"`             // in milliseconds
+	LongSuite    string  `json:"long_suite,omitempty"` // the suite of TestResult
+	ErrorMessage string  `json:"error_message,omitempty"`
+	Category     string  `json:"category,omitempty"` // set by classification rules
+	Source       string  `json:"source,omitempty"`   // suite file or directory
+	Line         int     `json:"line,omitempty"`     // RF 5 and later
+	URL          string  `json:"url,omitempty"`      // from PLUGIN_SOURCE_URL_TEMPLATE
+	File         string  `json:"file,omitempty"`     // report file, unless merged from reruns
+
+	// Consecutive failed runs including the current run, and the build of
+	// the first of them, set from the run history.
+	FailingStreak    int    `json:"failing_streak,omitempty"`
+	FirstFailedBuild string `json:"first_failed_build,omitempty"`
+
+	// Keyword messages logged before the failure.
+	Context []ContextMessage `json:"context,omitempty"`
+
+	// Loop variables of the failed loop iterations, e.g. "${user} = admin",
+	// for data-driven tests.
+	FailedIterations []string `json:"failed_iterations,omitempty"`
+}
+
+// ContextMessage is a keyword message leading up to a failure.
+type ContextMessage struct {
+	Level string `json:"level"`
+	Time  string `json:"time,omitempty"`
+	Text  string `json:"text"`
 }
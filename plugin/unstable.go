@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// unstableWebhookTimeout limits the unstable webhook call.
+const unstableWebhookTimeout = 10 * time.Second
+
+// isUnstable reports whether only the unstable threshold was breached:
+// the failed tests gate warned and no gate failed.
+func isUnstable(gates []GateResult) bool {
+	if gateOutcome(gates) == gateFail {
+		return false
+	}
+	for _, gate := range gates {
+		if gate.Name == "failed_tests" && gate.Outcome == gateWarn {
+			return true
+		}
+	}
+	return false
+}
+
+// unstableEvent is the JSON body posted to the unstable webhook.
+type unstableEvent struct {
+	Event             string     `json:"event"` // always unstable
+	Message           string     `json:"message"`
+	FailedTests       int        `json:"failed_tests"`
+	UnstableThreshold int        `json:"unstable_threshold"`
+	Build             *BuildInfo `json:"build,omitempty"` // build metadata and custom labels
+}
+
+// newUnstableWebhookReporter posts an event to the unstable webhook when
+// the run is unstable, for a receiver of the user's choice, e.g. to mark
+// the step or alert a channel. The payload is not tied to a CI API.
+func newUnstableWebhookReporter(args Args) (Reporter, error) {
+	if args.UnstableWebhook == "" {
+		return nil, nil
+	}
+	return ReporterFunc(func(ctx context.Context, stats StatsResult) error {
+		if !stats.Unstable {
+			return nil
+		}
+		build := stats.Build
+		if build == nil {
+			build = newBuildInfo(args.BuildLabels, os.Getenv)
+		}
+		event := unstableEvent{
+			Event:             "unstable",
+			Message:           fmt.Sprintf("%d failed tests exceed the unstable threshold (%d)", stats.FailedTests, args.UnstableThreshold),
+			FailedTests:       stats.FailedTests,
+			UnstableThreshold: args.UnstableThreshold,
+			Build:             build,
+		}
+		return postUnstableEvent(ctx, args.UnstableWebhook, args.UnstableWebhookToken, event)
+	}), nil
+}
+
+// postUnstableEvent posts the event as JSON, authenticated with the
+// bearer token when set.
+func postUnstableEvent(ctx context.Context, url, token string, event unstableEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, unstableWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unstable webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// writeUnstable sets whether the run is unstable as output variable.
+func writeUnstable(out *outputVars, unstable bool) {
+	out.set("UNSTABLE", strconv.FormatBool(unstable))
+}
@@ -0,0 +1,91 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestIsUnstable validates detection of runs breaching only the unstable
+// threshold
+func TestIsUnstable(t *testing.T) {
+	tests := []struct {
+		name  string
+		gates []GateResult
+		want  bool
+	}{
+		{"no gates", nil, false},
+		{"passed", []GateResult{{Name: "failed_tests", Outcome: gatePass}}, false},
+		{"unstable", []GateResult{{Name: "failed_tests", Outcome: gateWarn}, {Name: "failure_rate", Outcome: gatePass}}, true},
+		{"other warning", []GateResult{{Name: "failed_tests", Outcome: gatePass}, {Name: "failure_rate", Outcome: gateWarn}}, false},
+		{"failed", []GateResult{{Name: "failed_tests", Outcome: gateWarn}, {Name: "failure_rate", Outcome: gateFail}}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isUnstable(tc.gates); got != tc.want {
+				t.Errorf("Expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+// TestUnstableWebhookReporter validates the webhook call of unstable runs
+func TestUnstableWebhookReporter(t *testing.T) {
+	t.Setenv("DRONE_REPO", "octocat/hello-world")
+	t.Setenv("DRONE_BUILD_NUMBER", "42")
+	t.Setenv("DRONE_STAGE_NAME", "test")
+	t.Setenv("DRONE_STEP_NAME", "robot")
+
+	var got []unstableEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret-token" {
+			t.Errorf("Unexpected authorization header %q", auth)
+		}
+		var event unstableEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+		got = append(got, event)
+	}))
+	defer server.Close()
+
+	args := Args{UnstableThreshold: 2, UnstableWebhook: server.URL, UnstableWebhookToken: "secret-token"}
+	reporter, err := newUnstableWebhookReporter(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := reporter.Report(context.Background(), StatsResult{FailedTests: 1}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := reporter.Report(context.Background(), StatsResult{FailedTests: 3, Unstable: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []unstableEvent{{
+		Event:             "unstable",
+		Message:           "3 failed tests exceed the unstable threshold (2)",
+		FailedTests:       3,
+		UnstableThreshold: 2,
+		Build:             &BuildInfo{Repo: "octocat/hello-world", Number: "42", Stage: "test", Step: "robot"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unstable events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestUnstableWebhookError validates errors of the unstable webhook
+func TestUnstableWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := postUnstableEvent(context.Background(), server.URL, "", unstableEvent{Event: "unstable"})
+	if err == nil || err.Error() != "unstable webhook responded with 403 Forbidden" {
+		t.Errorf("Expected a webhook error, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// validator collects every invalid argument instead of stopping at the
+// first one. Errors are prefixed with the name of the offending setting.
+type validator struct {
+	errs []error
+}
+
+// check records an error for the setting unless ok holds.
+func (v *validator) check(ok bool, setting, format string, a ...interface{}) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", setting, fmt.Sprintf(format, a...)))
+	}
+}
+
+// add records an error for the setting unless err is nil.
+func (v *validator) add(setting string, err error) {
+	if err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s: %v", setting, err))
+	}
+}
+
+// merge records errors already prefixed with their setting names.
+func (v *validator) merge(err error) {
+	if err != nil {
+		v.errs = append(v.errs, err)
+	}
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
+
+// ValidateInputs ensures valid plugin arguments, reporting every invalid
+// argument at once.
+func ValidateInputs(args Args) error {
+	v := &validator{}
+
+	v.check(args.ReportDirectory != "" || args.Digest, "PLUGIN_REPORT_DIRECTORY", "report directory is required")
+
+	// Thresholds and limits
+	v.merge(validateThresholdRules(thresholdRules(args)))
+	v.check(args.SkippedRateFail == nil || args.MaxSkippedRate == nil, "PLUGIN_MAX_SKIPPED_RATE", "conflicts with PLUGIN_SKIPPED_RATE_FAIL, set only one of them")
+	v.check(args.MaxFlakyTests == nil || args.MergeReruns, "PLUGIN_MAX_FLAKY_TESTS", "requires PLUGIN_MERGE_RERUNS")
+	v.merge(validateSuiteThresholds(args.SuiteThresholds))
+
+	switch strings.ToLower(strings.TrimSpace(args.NotRunAs)) {
+	case "", "ignore", "skip", "fail":
+	default:
+		v.add("PLUGIN_NOT_RUN_AS", fmt.Errorf("unknown value %q, expected ignore, skip or fail", args.NotRunAs))
+	}
+	for status, target := range args.StatusMapping {
+		v.check(validStatusMapping(status, target), "PLUGIN_STATUS_MAPPING", "invalid mapping %s:%s, expected a custom status mapped to pass, fail, skip or ignore", status, target)
+	}
+	for tag, action := range args.ReservedTags {
+		v.check(validReservedAction(action), "PLUGIN_RESERVED_TAGS", "invalid action %s:%s, expected exclude, skip, allow-failure or count", tag, action)
+	}
+
+	if args.AllowFailureTag != "" {
+		_, err := compileTagPattern(args.AllowFailureTag)
+		v.add("PLUGIN_ALLOW_FAILURE_TAG", err)
+	}
+
+	// Quality scoring
+	v.check(args.MinQualityScore == nil || *args.MinQualityScore <= 100, "PLUGIN_MIN_QUALITY_SCORE", "minimum quality score must not exceed 100")
+	v.check(args.CriticalTestWeight >= 0, "PLUGIN_CRITICAL_TEST_WEIGHT", "critical test weight must be non-negative")
+	v.check(args.FailedKeywordWeight >= 0, "PLUGIN_FAILED_KEYWORD_WEIGHT", "failed keyword weight must be non-negative")
+	for tag, weight := range args.TagWeights {
+		v.check(weight >= 0, "PLUGIN_TAG_WEIGHTS", "weight for tag %s must be non-negative", tag)
+	}
+
+	// Duration budgets
+	v.check(args.MaxTestDuration >= 0, "PLUGIN_MAX_TEST_DURATION", "max test duration must be non-negative")
+	for tag, budget := range args.MaxTestDurationByTag {
+		v.check(budget >= 0, "PLUGIN_MAX_TEST_DURATION_BY_TAG", "max test duration for tag %s must be non-negative", tag)
+	}
+	v.check(!args.FailOnSlowTests || args.MaxTestDuration > 0 || len(args.MaxTestDurationByTag) > 0,
+		"PLUGIN_FAIL_ON_SLOW_TESTS", "requires PLUGIN_MAX_TEST_DURATION or PLUGIN_MAX_TEST_DURATION_BY_TAG")
+
+	// Parsing limits
+	v.check(args.MaxReportSize >= 0, "PLUGIN_MAX_REPORT_SIZE", "report size limit must be non-negative")
+	v.check(args.MaxXMLDepth >= 0, "PLUGIN_MAX_XML_DEPTH", "depth limit must be non-negative")
+
+	// Waiting for reports
+	v.check(args.WaitPollInterval >= 0, "PLUGIN_WAIT_POLL_INTERVAL", "poll interval must be non-negative")
+	v.check(args.WaitTimeout >= 0, "PLUGIN_WAIT_TIMEOUT", "timeout must be non-negative")
+
+	// Filters
+	for _, p := range []struct {
+		setting  string
+		patterns []string
+	}{
+		{"PLUGIN_INCLUDE_TAGS", args.IncludeTags},
+		{"PLUGIN_EXCLUDE_TAGS", args.ExcludeTags},
+	} {
+		for _, pattern := range p.patterns {
+			_, err := compileTagPattern(pattern)
+			v.add(p.setting, err)
+		}
+	}
+	for _, p := range []struct {
+		setting  string
+		patterns []string
+	}{
+		{"PLUGIN_INCLUDE_SUITES", args.IncludeSuites},
+		{"PLUGIN_EXCLUDE_SUITES", args.ExcludeSuites},
+		{"PLUGIN_INCLUDE_TESTS", args.IncludeTests},
+		{"PLUGIN_EXCLUDE_TESTS", args.ExcludeTests},
+	} {
+		_, err := compileNamePatterns(p.patterns)
+		v.add(p.setting, err)
+	}
+	if args.ReportFileRegex != "" {
+		_, err := compileFileRegex(args.ReportFileRegex, args.ReportFileIgnoreCase)
+		v.add("PLUGIN_REPORT_FILE_REGEX", err)
+	}
+	for i, rule := range args.ClassificationRules {
+		_, err := compileClassificationRules([]ClassificationRule{rule})
+		v.add(fmt.Sprintf("classification_rules[%d]", i), err)
+	}
+
+	// Baseline and comparison
+	v.check((args.ExecutionTimeRegressionWarn == nil && args.ExecutionTimeRegressionFail == nil) || args.BaselineFile != "",
+		"PLUGIN_EXECUTION_TIME_REGRESSION_WARN/PLUGIN_EXECUTION_TIME_REGRESSION_FAIL", "execution time regression limits require a baseline file")
+	v.merge(validateThresholdRules([]thresholdRule{{
+		Label:       "execution time regression",
+		Warn:        args.ExecutionTimeRegressionWarn,
+		Fail:        args.ExecutionTimeRegressionFail,
+		WarnSetting: "PLUGIN_EXECUTION_TIME_REGRESSION_WARN",
+		FailSetting: "PLUGIN_EXECUTION_TIME_REGRESSION_FAIL",
+	}}))
+	v.check(args.MaxSearchDepth >= 0, "PLUGIN_MAX_SEARCH_DEPTH", "must be non-negative")
+	v.check(args.MaxFailureDetails >= 0, "PLUGIN_MAX_FAILURE_DETAILS", "must be non-negative")
+	v.check(args.OutputPrecision == nil || (*args.OutputPrecision >= 0 && *args.OutputPrecision <= 10), "PLUGIN_OUTPUT_PRECISION", "must be between 0 and 10")
+	v.check(validDurationUnit(args.DurationUnit), "PLUGIN_DURATION_UNIT", "unknown value %q, expected ms, s or human", args.DurationUnit)
+	v.check(args.MaxErrorMessageLength >= 0, "PLUGIN_MAX_ERROR_MESSAGE_LENGTH", "must be non-negative")
+	if invalid := invalidEnvPatterns(args.HostEnv); len(invalid) > 0 {
+		v.check(false, "PLUGIN_HOST_ENV", "invalid patterns %s", strings.Join(invalid, ", "))
+	}
+	v.check(args.FailureContextMessages >= 0, "PLUGIN_FAILURE_CONTEXT_MESSAGES", "must be non-negative")
+	v.check(validFailureContextLevel(args.FailureContextLevel), "PLUGIN_FAILURE_CONTEXT_LEVEL", "unknown value %q, expected TRACE, DEBUG, INFO or WARN", args.FailureContextLevel)
+	v.check(validSummaryStyle(args.SummaryStyle), "PLUGIN_SUMMARY_STYLE", "unknown value %q, expected rich or plain", args.SummaryStyle)
+	v.check(args.ProgressInterval >= 0, "PLUGIN_PROGRESS_INTERVAL", "progress interval must be non-negative")
+	if args.SkipKeywordStats {
+		settings := keywordStatsSettings(args)
+		v.check(len(settings) == 0, "PLUGIN_SKIP_KEYWORD_STATS", "conflicts with %s, which require keyword statistics", strings.Join(settings, ", "))
+	}
+	v.check(!args.Quiet || !args.Verbose, "PLUGIN_QUIET", "conflicts with PLUGIN_VERBOSE, set only one of them")
+	v.check(args.SuiteTreeDepth >= 0, "PLUGIN_SUITE_TREE_DEPTH", "must be non-negative")
+	v.check(validSchemaValidation(args.SchemaValidation), "PLUGIN_SCHEMA_VALIDATION", "unknown value %q, expected warn or fail", args.SchemaValidation)
+	v.check(validSuiteNamespace(args.SuiteNamespace), "PLUGIN_SUITE_NAMESPACE", "unknown value %q, expected file or directory", args.SuiteNamespace)
+	v.check(validThresholdsScope(args.ThresholdsScope), "PLUGIN_THRESHOLDS_SCOPE", "unknown value %q, expected aggregate, per-file or both", args.ThresholdsScope)
+	v.check(thresholdsScope(args.ThresholdsScope) == thresholdsScopeAggregate || !args.MergeReruns, "PLUGIN_THRESHOLDS_SCOPE", "per-file thresholds conflict with PLUGIN_MERGE_RERUNS, which merges the report files")
+	v.check(args.FileWorkers >= 0, "PLUGIN_FILE_WORKERS", "must be non-negative")
+	v.check(!args.FailFast || !args.MergeReruns, "PLUGIN_FAIL_FAST", "conflicts with PLUGIN_MERGE_RERUNS, where reruns can turn failures into passes")
+	v.check(args.SuiteNamespace == "" || !args.MergeReruns, "PLUGIN_SUITE_NAMESPACE", "conflicts with PLUGIN_MERGE_RERUNS, which merges suites across files")
+	v.check(validFailureSort(args.FailureSort), "PLUGIN_FAILURE_SORT", "unknown value %q, expected suite, duration or file", args.FailureSort)
+	v.check(validOutputFormat(args.OutputFormat), "PLUGIN_OUTPUT_FORMAT", "unknown value %q, expected dotenv or export", args.OutputFormat)
+	if args.UnstableWebhook != "" {
+		u, err := url.Parse(args.UnstableWebhook)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"PLUGIN_UNSTABLE_WEBHOOK", "must be an http or https URL")
+	}
+	v.check(args.CompareReport == "" || args.CompareTo != "" || args.PreviousStatsURL != "" || args.HistoryDir != "", "PLUGIN_COMPARE_REPORT", "requires PLUGIN_COMPARE_TO, PLUGIN_PREVIOUS_STATS_URL or PLUGIN_HISTORY_DIR")
+	v.check(args.PreviousStatsURL == "" || args.CompareTo == "", "PLUGIN_PREVIOUS_STATS_URL", "conflicts with PLUGIN_COMPARE_TO, set only one of them")
+	v.check(args.BaselineBuild == "" || args.HistoryDir != "", "PLUGIN_BASELINE_BUILD", "requires PLUGIN_HISTORY_DIR")
+	v.check(args.TrendChart == "" || args.HistoryDir != "", "PLUGIN_TREND_CHART", "requires PLUGIN_HISTORY_DIR")
+	v.check(args.TrendRuns >= 0, "PLUGIN_TREND_RUNS", "must be non-negative")
+	v.check(args.FailureLeaderboard >= 0, "PLUGIN_FAILURE_LEADERBOARD", "must be non-negative")
+	v.check(args.FailureLeaderboard == 0 || args.HistoryDir != "", "PLUGIN_FAILURE_LEADERBOARD", "requires PLUGIN_HISTORY_DIR")
+	v.check(args.FailureLeaderboardRuns >= 0, "PLUGIN_FAILURE_LEADERBOARD_RUNS", "must be non-negative")
+	if args.ConfluenceURL != "" {
+		u, err := url.Parse(args.ConfluenceURL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"PLUGIN_CONFLUENCE_URL", "must be an http or https URL")
+	}
+	v.check(args.ConfluenceURL == "" || args.ConfluenceSpace != "", "PLUGIN_CONFLUENCE_SPACE", "is required with PLUGIN_CONFLUENCE_URL")
+	v.check(args.ConfluenceURL == "" || args.ConfluenceToken != "", "PLUGIN_CONFLUENCE_TOKEN", "is required with PLUGIN_CONFLUENCE_URL")
+	for _, webhook := range []struct{ setting, url string }{
+		{"PLUGIN_DISCORD_WEBHOOK", args.DiscordWebhook},
+		{"PLUGIN_MATTERMOST_WEBHOOK", args.MattermostWebhook},
+	} {
+		if webhook.url != "" {
+			u, err := url.Parse(webhook.url)
+			v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+				webhook.setting, "must be an http or https URL")
+		}
+	}
+	if args.NotificationTemplate != "" {
+		_, err := newNotificationTemplate(args)
+		v.check(err == nil, "PLUGIN_NOTIFICATION_TEMPLATE", "is not a valid template: %v", err)
+	}
+	v.check(validNotifyOn(args.NotifyOn), "PLUGIN_NOTIFY_ON", "must be always or failure")
+	if args.TwilioAccountSID != "" {
+		v.check(args.TwilioAuthToken != "", "PLUGIN_TWILIO_AUTH_TOKEN", "is required with PLUGIN_TWILIO_ACCOUNT_SID")
+		v.check(args.TwilioFrom != "", "PLUGIN_TWILIO_FROM", "is required with PLUGIN_TWILIO_ACCOUNT_SID")
+		v.check(len(args.TwilioTo) > 0, "PLUGIN_TWILIO_TO", "is required with PLUGIN_TWILIO_ACCOUNT_SID")
+		v.check(len(args.TwilioBranches) > 0, "PLUGIN_TWILIO_BRANCHES", "is required with PLUGIN_TWILIO_ACCOUNT_SID")
+	}
+	for _, pattern := range args.TwilioBranches {
+		_, err := path.Match(strings.TrimSpace(pattern), "")
+		v.check(err == nil, "PLUGIN_TWILIO_BRANCHES", "has an invalid pattern %q", pattern)
+	}
+	if args.FailureDetailsFile != "" {
+		settings := testResultSettings(args)
+		v.check(len(settings) == 0, "PLUGIN_FAILURE_DETAILS_FILE", "conflicts with %s, which need the per-test results", strings.Join(settings, ", "))
+	}
+	v.check(args.PublishDir == "" || filepath.Clean(args.PublishDir) != filepath.Clean(args.ReportDirectory), "PLUGIN_PUBLISH_DIR", "must differ from PLUGIN_REPORT_DIRECTORY")
+	v.check(args.ArtifactFile == "" || args.ArtifactBaseURL != "", "PLUGIN_ARTIFACT_BASE_URL", "is required with PLUGIN_ARTIFACT_FILE")
+	if args.ArtifactBaseURL != "" {
+		u, err := url.Parse(args.ArtifactBaseURL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"PLUGIN_ARTIFACT_BASE_URL", "must be an http or https URL")
+	}
+	v.check(args.SplitGroups >= 0, "PLUGIN_SPLIT_GROUPS", "must be non-negative")
+	v.check(args.HistoryMaxRuns >= 0, "PLUGIN_HISTORY_MAX_RUNS", "must be non-negative")
+	v.check(args.HistoryMaxAge >= 0, "PLUGIN_HISTORY_MAX_AGE", "must be non-negative")
+	v.check(!args.Digest || len(args.DigestFiles) > 0 || args.HistoryDir != "", "PLUGIN_DIGEST", "requires PLUGIN_DIGEST_FILES or PLUGIN_HISTORY_DIR")
+	v.check(args.DigestWindow >= 0, "PLUGIN_DIGEST_WINDOW", "must be non-negative")
+	v.check(args.BaselineBuild == "" || args.CompareTo == "", "PLUGIN_BASELINE_BUILD", "conflicts with PLUGIN_COMPARE_TO, set only one of them")
+	v.check(args.BaselineBuild == "" || args.PreviousStatsURL == "", "PLUGIN_BASELINE_BUILD", "conflicts with PLUGIN_PREVIOUS_STATS_URL, set only one of them")
+
+	if args.GateExpression != "" {
+		_, err := checkGateExpression(args.GateExpression, statsMetrics(StatsResult{}))
+		v.check(err == nil, "PLUGIN_GATE_EXPRESSION", "invalid gate expression: %v", err)
+	}
+	return classifyError(ErrorClassValidation, v.err())
+}
+
+// keywordStatsSettings returns the settings using keyword counts.
+func keywordStatsSettings(args Args) []string {
+	var settings []string
+	if args.FailedKeywordWeight > 0 {
+		settings = append(settings, "PLUGIN_FAILED_KEYWORD_WEIGHT")
+	}
+	if args.AtRiskTests {
+		settings = append(settings, "PLUGIN_AT_RISK_TESTS")
+	}
+	if args.MaxFailedKeywords != nil {
+		settings = append(settings, "PLUGIN_MAX_FAILED_KEYWORDS")
+	}
+	if args.MaxKeywordFailureRate != nil {
+		settings = append(settings, "PLUGIN_MAX_KEYWORD_FAILURE_RATE")
+	}
+	if node, err := compileExpression(args.GateExpression); args.GateExpression != "" && err == nil {
+		for _, name := range exprIdentifiers(node) {
+			if strings.Contains(name, "keyword") || strings.Contains(name, "setups") || strings.Contains(name, "teardowns") || name == "at_risk_tests" || strings.HasSuffix(name, "_iterations") {
+				settings = append(settings, "PLUGIN_GATE_EXPRESSION")
+				break
+			}
+		}
+	}
+	return settings
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateInputsReportsAllErrors validates that every invalid argument is reported at once
+func TestValidateInputsReportsAllErrors(t *testing.T) {
+	negative, five, ten := -1.0, 5.0, 10.0
+	err := ValidateInputs(Args{
+		FailureRateWarn: &ten,
+		FailureRateFail: &five,
+		MaxSkippedRate:  &negative,
+		IncludeTags:     []string{"smoke OR"},
+		ExcludeSuites:   []string{"regex:("},
+		CompareReport:   "diff.json",
+		GateExpression:  "failed_tests >",
+		NotRunAs:        "pass",
+	})
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+
+	for _, msg := range []string{
+		"PLUGIN_REPORT_DIRECTORY: report directory is required",
+		"PLUGIN_FAILURE_RATE_WARN: warn threshold (10) must not exceed PLUGIN_FAILURE_RATE_FAIL (5)",
+		"PLUGIN_MAX_SKIPPED_RATE: skipped rate threshold values must be non-negative",
+		"PLUGIN_INCLUDE_TAGS: invalid tag pattern",
+		"PLUGIN_EXCLUDE_SUITES: invalid name pattern",
+		"PLUGIN_COMPARE_REPORT: requires PLUGIN_COMPARE_TO",
+		"PLUGIN_GATE_EXPRESSION: invalid gate expression",
+		`PLUGIN_NOT_RUN_AS: unknown value "pass"`,
+	} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected error '%s' in:\n%v", msg, err)
+		}
+	}
+}
+
+// TestValidateInputsConfigSettings validates configuration file settings
+func TestValidateInputsConfigSettings(t *testing.T) {
+	err := ValidateInputs(Args{
+		ReportDirectory:     "./testdata",
+		SuiteThresholds:     []SuiteThreshold{{Suite: "regex:("}},
+		ClassificationRules: []ClassificationRule{{Message: "*timeout*"}},
+	})
+	for _, msg := range []string{"suite_thresholds[0].suite:", "classification_rules[0]: classification rules require a category"} {
+		if err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("Expected error '%s', but got %v", msg, err)
+		}
+	}
+}
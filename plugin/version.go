@@ -0,0 +1,188 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Supported Robot Framework major versions.
+const (
+	minSupportedMajor = 3
+	maxSupportedMajor = 7
+)
+
+// generatorPattern matches the generator attribute of the <robot>
+// element, e.g. "Robot 6.0 (Python 3.10.0 on win32)".
+var generatorPattern = regexp.MustCompile(`^(Robot|Rebot)\s+(\d+)\.(\d+)`)
+
+// robotVersion is the Robot Framework version that generated a report.
+type robotVersion struct {
+	Tool  string // Robot or Rebot
+	Major int
+	Minor int
+}
+
+func (v robotVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// parseGenerator extracts the Robot Framework version from the generator
+// attribute.
+func parseGenerator(generator string) (robotVersion, bool) {
+	m := generatorPattern.FindStringSubmatch(generator)
+	if m == nil {
+		return robotVersion{}, false
+	}
+	major, _ := strconv.Atoi(m[2])
+	minor, _ := strconv.Atoi(m[3])
+	return robotVersion{Tool: m[1], Major: major, Minor: minor}, true
+}
+
+// Timestamp layouts of Robot Framework: the starttime and endtime
+// attributes of RF 6 and earlier, and the ISO-8601 start attribute of
+// RF 7, which reports the elapsed seconds instead of the end time.
+const (
+	legacyTimeLayout = "20060102 15:04:05.000"
+	isoTimeLayout    = "2006-01-02T15:04:05.999999"
+)
+
+// formatProfile holds the parsing rules for a Robot Framework output
+// format version.
+type formatProfile struct {
+	Version robotVersion
+	Known   bool   // whether the version was detected
+	Schema  string // output schema version, RF 4 and later
+
+	// Criticality reports whether tests carry a critical attribute.
+	// Robot Framework 4 removed criticality; all tests are critical.
+	Criticality bool
+
+	// TimeLayout is the timestamp layout of the version, empty when it
+	// is unknown and both layouts are tried.
+	TimeLayout string
+
+	// MessageInText reports whether the failure message is only found in
+	// the status text: the status element of RF 7 holds no messages,
+	// earlier and unknown versions fall back to its last ERROR message.
+	MessageInText bool
+}
+
+// detectProfile selects the parsing rules for the report, warning when
+// the generator version is not supported.
+func detectProfile(robotOutput RobotOutput) formatProfile {
+	version, ok := parseGenerator(robotOutput.Generator)
+	profile := formatProfile{Version: version, Known: ok, Schema: robotOutput.Schema}
+
+	switch {
+	case !ok:
+		// unknown generator, keep the legacy rules
+		profile.Criticality = true
+		return profile
+	case version.Major < minSupportedMajor || version.Major > maxSupportedMajor:
+		logrus.Warnf("Unsupported Robot Framework version %s, results may be inaccurate", version)
+	}
+	profile.Criticality = version.Major < 4
+	profile.TimeLayout = legacyTimeLayout
+	if version.Major >= 7 {
+		profile.TimeLayout = isoTimeLayout
+		profile.MessageInText = true
+	}
+
+	// outputs post-processed by other tools may still carry criticality
+	if !profile.Criticality && hasCriticalAttributes(&robotOutput.Suite) {
+		profile.Criticality = true
+	}
+	return profile
+}
+
+// start returns the start time of the status in the timestamp layout of
+// the version.
+func (p formatProfile) start(status Status) (time.Time, bool) {
+	switch p.TimeLayout {
+	case "":
+		return statusStart(status)
+	case isoTimeLayout:
+		t, err := time.Parse(isoTimeLayout, status.Start)
+		return t, err == nil
+	}
+	t, err := time.Parse(p.TimeLayout, status.StartTime)
+	return t, err == nil
+}
+
+// duration returns the execution time of the status in milliseconds:
+// the elapsed seconds from RF 7, the time between the start and end
+// times before.
+func (p formatProfile) duration(status Status) (float64, bool) {
+	switch p.TimeLayout {
+	case "":
+		return statusDuration(status)
+	case isoTimeLayout:
+		return elapsedDuration(status.Elapsed)
+	}
+	return intervalDuration(status, p.TimeLayout)
+}
+
+// errorMessage returns the failure message of the test.
+func (p formatProfile) errorMessage(test Test) string {
+	if p.MessageInText {
+		return strings.TrimSpace(test.Status.Text)
+	}
+	return testErrorMessage(test)
+}
+
+// setVersions records the generator and schema versions in the
+// statistics.
+func (p formatProfile) setVersions(stats *StatsResult) {
+	if p.Known {
+		stats.RobotVersion = p.Version.String()
+	}
+	stats.SchemaVersion = p.Schema
+}
+
+// mergeVersions returns the distinct versions of both comma separated
+// lists, sorted.
+func mergeVersions(a, b string) string {
+	seen := map[string]bool{}
+	var versions []string
+	for _, list := range []string{a, b} {
+		for _, version := range strings.Split(list, ",") {
+			version = strings.TrimSpace(version)
+			if version != "" && !seen[version] {
+				seen[version] = true
+				versions = append(versions, version)
+			}
+		}
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ", ")
+}
+
+// isCritical reports whether the test is critical under the profile.
+func (p formatProfile) isCritical(test Test) bool {
+	if !p.Criticality {
+		return true
+	}
+	return test.Status.Critical == "yes"
+}
+
+// hasCriticalAttributes reports whether any test in the suite tree has a
+// critical attribute.
+func hasCriticalAttributes(suite *Suite) bool {
+	for _, test := range suite.Tests {
+		if test.Status.Critical != "" {
+			return true
+		}
+	}
+	for i := range suite.Suites {
+		if hasCriticalAttributes(&suite.Suites[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDetectProfile validates Robot Framework version detection and parsing rules
+func TestDetectProfile(t *testing.T) {
+	withCritical := Suite{Tests: []Test{{Name: "T", Status: Status{Status: "PASS", Critical: "yes"}}}}
+	withoutCritical := Suite{Tests: []Test{{Name: "T", Status: Status{Status: "PASS"}}}}
+
+	tests := []struct {
+		name                string
+		robotOutput         RobotOutput
+		expectedKnown       bool
+		expectedMajor       int
+		expectedCriticality bool
+	}{
+		{
+			name:                "Robot 3 Uses Criticality",
+			robotOutput:         RobotOutput{Generator: "Robot 3.2.2 (Python 3.8.5 on linux)", Suite: withCritical},
+			expectedKnown:       true,
+			expectedMajor:       3,
+			expectedCriticality: true,
+		},
+		{
+			name:                "Robot 7 Without Criticality",
+			robotOutput:         RobotOutput{Generator: "Robot 7.0.1 (Python 3.12.1 on linux)", Suite: withoutCritical},
+			expectedKnown:       true,
+			expectedMajor:       7,
+			expectedCriticality: false,
+		},
+		{
+			name:                "Rebot 6 With Critical Attributes",
+			robotOutput:         RobotOutput{Generator: "Rebot 6.1 (Python 3.11.4 on linux)", Suite: withCritical},
+			expectedKnown:       true,
+			expectedMajor:       6,
+			expectedCriticality: true,
+		},
+		{
+			name:                "Unknown Generator",
+			robotOutput:         RobotOutput{Suite: withoutCritical},
+			expectedCriticality: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			profile := detectProfile(tc.robotOutput)
+			if profile.Known != tc.expectedKnown || profile.Version.Major != tc.expectedMajor {
+				t.Errorf("Expected known=%v major=%d, got known=%v major=%d",
+					tc.expectedKnown, tc.expectedMajor, profile.Known, profile.Version.Major)
+			}
+			if profile.Criticality != tc.expectedCriticality {
+				t.Errorf("Expected criticality %v, got %v", tc.expectedCriticality, profile.Criticality)
+			}
+		})
+	}
+}
+
+// TestReportVersions validates the generator and schema versions of
+// single and mixed-version runs
+func TestReportVersions(t *testing.T) {
+	stats, err := processFile("../testdata/rf7/output.xml", statsOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.RobotVersion != "7.0" || stats.SchemaVersion != "5" {
+		t.Errorf("Expected Robot Framework 7.0 and schema 5, got %q and %q", stats.RobotVersion, stats.SchemaVersion)
+	}
+	if got := versionSummary(stats); got != "7.0 (schema 5)" {
+		t.Errorf("Unexpected version summary %q", got)
+	}
+
+	total := StatsResult{}
+	aggregateStats(&total, stats)
+	aggregateStats(&total, StatsResult{RobotVersion: "6.1", SchemaVersion: "4"})
+	aggregateStats(&total, StatsResult{RobotVersion: "7.0", SchemaVersion: "5"})
+	if total.RobotVersion != "6.1, 7.0" || total.SchemaVersion != "4, 5" {
+		t.Errorf("Expected mixed versions, got %q and %q", total.RobotVersion, total.SchemaVersion)
+	}
+
+	out := &outputVars{}
+	writeTestStats(out, total)
+	if got := out.values["ROBOT_VERSION"]; got != "6.1, 7.0" {
+		t.Errorf("Expected ROBOT_VERSION output, got %q", got)
+	}
+}
+
+// TestProfileParsingRules validates the timestamp layout and failure
+// message source of each version
+func TestProfileParsingRules(t *testing.T) {
+	legacy := Status{Status: "FAIL", StartTime: "20240501 10:00:00.000", EndTime: "20240501 10:00:01.500",
+		Messages: []Msg{{Level: "ERROR", Text: "logged failure"}}}
+	iso := Status{Status: "FAIL", Start: "2024-05-01T10:00:00.000000", Elapsed: "1.5", Text: "failure"}
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		generator string
+		status    Status
+		message   string
+		other     Status // the timestamps of the other layout
+	}{
+		{"Robot 3.2.2 (Python 3.8.5 on linux)", legacy, "logged failure", iso},
+		{"Rebot 6.1 (Python 3.11.4 on linux)", legacy, "logged failure", iso},
+		{"Robot 7.0.1 (Python 3.12.1 on linux)", iso, "failure", legacy},
+		{"", legacy, "logged failure", Status{}},
+		{"", iso, "failure", Status{}},
+	}
+	for _, tc := range tests {
+		profile := detectProfile(RobotOutput{Generator: tc.generator})
+		got, ok := profile.start(tc.status)
+		if !ok || !got.Equal(start) {
+			t.Errorf("%q: expected start %v, got %v (%v)", tc.generator, start, got, ok)
+		}
+		if duration, ok := profile.duration(tc.status); !ok || duration != 1500 {
+			t.Errorf("%q: expected a duration of 1500 ms, got %v (%v)", tc.generator, duration, ok)
+		}
+		if message := profile.errorMessage(Test{Status: tc.status}); message != tc.message {
+			t.Errorf("%q: expected message %q, got %q", tc.generator, tc.message, message)
+		}
+		if _, ok := profile.start(tc.other); ok {
+			t.Errorf("%q: expected no start time in the layout of other versions", tc.generator)
+		}
+	}
+
+	// RF 7 statuses hold no messages
+	status := Status{Status: "FAIL", Messages: []Msg{{Level: "ERROR", Text: "logged failure"}}}
+	profile := detectProfile(RobotOutput{Generator: "Robot 7.0"})
+	if message := profile.errorMessage(Test{Status: status}); message != "" {
+		t.Errorf("Expected no RF 7 message outside the status text, got %q", message)
+	}
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults for waiting on report files.
+const (
+	defaultWaitPollInterval = 5 * time.Second
+	defaultWaitTimeout      = 10 * time.Minute
+)
+
+// waitForReports polls the report directory until files matching the
+// query exist and their sizes did not change since the previous poll,
+// so reports still being written to a shared volume are not processed.
+func waitForReports(ctx context.Context, directory string, query fileQuery, interval, timeout time.Duration) error {
+	if interval <= 0 {
+		interval = defaultWaitPollInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultWaitTimeout
+	}
+	logrus.Infof("Waiting up to %s for report files matching %s in %s", timeout, query, directory)
+
+	var previous map[string]int64
+	err := pollUntil(ctx, interval, timeout, func() bool {
+		sizes := reportFileSizes(directory, query)
+		done := len(sizes) > 0 && maps.Equal(sizes, previous)
+		previous = sizes
+		return done
+	})
+	if err != nil {
+		return waitError(err, timeout, query)
+	}
+	logrus.Infof("Found %d report files after waiting", len(previous))
+	return nil
+}
+
+// waitError describes a failed wait for report files.
+func waitError(err error, timeout time.Duration, query fileQuery) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s waiting for report files matching %s", timeout, query)
+	}
+	return err
+}
+
+// pollUntil calls done every interval until it returns true, the timeout
+// expires or the context is cancelled.
+func pollUntil(ctx context.Context, interval, timeout time.Duration, done func() bool) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// reportFileSizes returns the sizes of the non-empty files matching the
+// query, including pabot worker outputs.
+func reportFileSizes(directory string, query fileQuery) map[string]int64 {
+	matches, _ := query.match(directory)
+
+	sizes := map[string]int64{}
+	for _, file := range matches {
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			sizes[file] = info.Size()
+		}
+	}
+	return sizes
+}
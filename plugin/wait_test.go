@@ -0,0 +1,34 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWaitForReports validates waiting for report files written after the step starts
+func TestWaitForReports(t *testing.T) {
+	dir := t.TempDir()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		os.WriteFile(filepath.Join(dir, "output.xml"), []byte("<robot>"), 0644)
+	}()
+
+	if err := waitForReports(context.Background(), dir, fileQuery{Pattern: "output.xml"}, 5*time.Millisecond, 5*time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "output.xml")); err != nil {
+		t.Errorf("Expected the report file to exist: %v", err)
+	}
+}
+
+// TestWaitForReportsTimeout validates the timeout when no reports appear
+func TestWaitForReportsTimeout(t *testing.T) {
+	err := waitForReports(context.Background(), t.TempDir(), fileQuery{Pattern: "output.xml"}, 5*time.Millisecond, 30*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 30ms waiting for report files") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package plugin
+
+// keywordWarnings counts the WARN messages of the keywords and their
+// nested keywords.
+func keywordWarnings(keywords []Keyword) int {
+	count := 0
+	for _, kw := range keywords {
+		for _, msg := range kw.Messages {
+			if msg.Level == "WARN" {
+				count++
+			}
+		}
+		count += keywordWarnings(kw.Keywords)
+	}
+	return count
+}
+
+// executionWarnings counts the WARN messages of the <errors> block not
+// logged by a keyword, such as import and parsing warnings. Robot
+// Framework repeats keyword warnings in the <errors> block; those are
+// counted with their keywords.
+func executionWarnings(robotOutput RobotOutput) int {
+	logged := map[string]bool{}
+	collectWarnings(&robotOutput.Suite, logged)
+
+	count := 0
+	for _, e := range robotOutput.Errors {
+		if e.Level == "WARN" && !logged[warningKey(e.Timestamp+e.Time, e.Message)] {
+			count++
+		}
+	}
+	return count
+}
+
+// reportedWarnings counts all WARN messages of the <errors> block,
+// including the ones repeated from keywords, without walking the
+// keywords.
+func reportedWarnings(robotOutput RobotOutput) int {
+	count := 0
+	for _, e := range robotOutput.Errors {
+		if e.Level == "WARN" {
+			count++
+		}
+	}
+	return count
+}
+
+func collectWarnings(suite *Suite, logged map[string]bool) {
+	var walk func(keywords []Keyword)
+	walk = func(keywords []Keyword) {
+		for _, kw := range keywords {
+			for _, msg := range kw.Messages {
+				if msg.Level == "WARN" {
+					logged[warningKey(msg.Timestamp+msg.Time, msg.Text)] = true
+				}
+			}
+			walk(kw.Keywords)
+		}
+	}
+
+	walk(suite.Keywords)
+	for _, test := range suite.Tests {
+		walk(test.Keywords)
+	}
+	for i := range suite.Suites {
+		collectWarnings(&suite.Suites[i], logged)
+	}
+}
+
+func warningKey(timestamp, text string) string {
+	return timestamp + "\x00" + text
+}
@@ -0,0 +1,85 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWarningCount validates counting of keyword and execution warnings
+func TestWarningCount(t *testing.T) {
+	report := `<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<kw name="Setup" type="SETUP">
+<msg time="2025-02-09T15:30:00.100000" level="WARN">Slow environment</msg>
+<status status="PASS"/>
+</kw>
+<test id="s1-t1" name="Test">
+<kw name="Outer">
+<kw name="Inner">
+<msg time="2025-02-09T15:30:01.000000" level="WARN">Deprecated keyword</msg>
+<msg time="2025-02-09T15:30:01.100000" level="INFO">Done</msg>
+<status status="PASS"/>
+</kw>
+<status status="PASS"/>
+</kw>
+<status status="PASS"/>
+</test>
+</suite>
+<errors>
+<msg time="2025-02-09T15:29:59.000000" level="WARN">Importing library 'Missing' failed</msg>
+<msg time="2025-02-09T15:30:00.100000" level="WARN">Slow environment</msg>
+<msg time="2025-02-09T15:30:01.000000" level="WARN">Deprecated keyword</msg>
+<msg time="2025-02-09T15:30:02.000000" level="ERROR">Broken</msg>
+</errors>
+</robot>`
+	robotOutput, err := parseOutput([]byte(report), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	stats := computeStats(*robotOutput, statsOptions{})
+	if stats.TotalWarnings != 3 {
+		t.Errorf("Expected 3 warnings, got %d", stats.TotalWarnings)
+	}
+
+	fail := 2.0
+	err = validateThresholds(stats, Args{PassThreshold: 10, UnstableThreshold: 5, WarningsFail: &fail})
+	if err == nil || !strings.Contains(err.Error(), "warnings count") {
+		t.Errorf("Expected the warnings threshold to fail, got %v", err)
+	}
+}
+
+// TestSkipKeywordStats validates that skipping keywords keeps test and warning counts
+func TestSkipKeywordStats(t *testing.T) {
+	robotOutput, err := parseOutput([]byte(`<robot generator="Robot 7.0">
+<suite id="s1" name="Root">
+<test id="s1-t1" name="Test">
+<kw name="Outer">
+<msg time="2025-02-09T15:30:01.000000" level="WARN">Deprecated keyword</msg>
+<status status="FAIL"/>
+</kw>
+<status status="FAIL"/>
+</test>
+</suite>
+<errors>
+<msg time="2025-02-09T15:29:59.000000" level="WARN">Importing library 'Missing' failed</msg>
+<msg time="2025-02-09T15:30:01.000000" level="WARN">Deprecated keyword</msg>
+</errors>
+</robot>`), "output.xml", statsOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	full := computeStats(*robotOutput, statsOptions{})
+	fast := computeStats(*robotOutput, statsOptions{SkipKeywords: true})
+	if fast.TotalKeywords != 0 || full.TotalKeywords != 1 {
+		t.Errorf("Expected keywords only without skipping, got %d and %d", full.TotalKeywords, fast.TotalKeywords)
+	}
+	if fast.FailedTests != 1 || fast.TotalWarnings != full.TotalWarnings || fast.TotalWarnings != 2 {
+		t.Errorf("Expected 1 failed test and 2 warnings, got %d and %d", fast.FailedTests, fast.TotalWarnings)
+	}
+
+	err = ValidateInputs(Args{ReportDirectory: ".", SkipKeywordStats: true, FailedKeywordWeight: 1, GateExpression: "failed_keywords == 0"})
+	if err == nil || !strings.Contains(err.Error(), "PLUGIN_FAILED_KEYWORD_WEIGHT, PLUGIN_GATE_EXPRESSION") {
+		t.Errorf("Expected keyword settings to conflict, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2020 the Drone Authors. All rights reserved.
+// Use of this source code is governed by the Blue Oak Model License
+// that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"github.com/drone/drone-robot/plugin"
+	"github.com/sirupsen/logrus"
+)
+
+// startProfiling starts writing a CPU profile and returns a function
+// stopping it and writing the heap profile. The profiling settings are
+// hidden diagnostics for performance bug reports. Profiling errors are
+// logged and never fail the run.
+func startProfiling(args plugin.Args) (stop func()) {
+	var cpu *os.File
+	if args.CPUProfile != "" {
+		f, err := os.Create(args.CPUProfile)
+		if err == nil {
+			err = pprof.StartCPUProfile(f)
+		}
+		if err != nil {
+			logrus.Warnf("Failed to start CPU profile: %v", err)
+		} else {
+			cpu = f
+		}
+	}
+
+	return func() {
+		if cpu != nil {
+			pprof.StopCPUProfile()
+			cpu.Close()
+			cpu = nil
+		}
+		if args.HeapProfile != "" {
+			if err := writeHeapProfile(args.HeapProfile); err != nil {
+				logrus.Warnf("Failed to write heap profile: %v", err)
+			}
+		}
+	}
+}
+
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	runtime.GC() // up-to-date statistics
+	return pprof.WriteHeapProfile(f)
+}
@@ -0,0 +1,123 @@
+package robottest
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-robot/plugin"
+	"github.com/google/go-cmp/cmp"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite
+// the golden files with the actual statistics instead of comparing them.
+const UpdateEnv = "ROBOTTEST_UPDATE_GOLDEN"
+
+// Tolerance bounds the differences accepted between statistics. Counts
+// and names always have to match exactly.
+type Tolerance struct {
+	// Duration is the accepted difference of durations and execution
+	// times, in milliseconds.
+	Duration float64
+
+	// Rate is the accepted difference of rates and scores, in
+	// percentage points.
+	Rate float64
+}
+
+// Diff returns a human-readable report of the differences between the
+// statistics, empty when they match within the tolerance. Both are
+// compared as they are written to JSON, so fields not exported to JSON
+// are ignored.
+func Diff(want, got *plugin.StatsResult, tol Tolerance) string {
+	w, err := normalize(want)
+	if err != nil {
+		return "robottest: " + err.Error()
+	}
+	g, err := normalize(got)
+	if err != nil {
+		return "robottest: " + err.Error()
+	}
+	return cmp.Diff(w, g,
+		cmp.FilterPath(isDuration, cmp.Comparer(within(tol.Duration))),
+		cmp.FilterPath(isRate, cmp.Comparer(within(tol.Rate))),
+	)
+}
+
+// AssertStats fails the test when the statistics differ beyond the
+// tolerance.
+func AssertStats(tb testing.TB, want, got *plugin.StatsResult, tol Tolerance) {
+	tb.Helper()
+	if diff := Diff(want, got, tol); diff != "" {
+		tb.Errorf("Statistics mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// AssertGolden compares the statistics with the golden JSON file at the
+// path. With the UpdateEnv environment variable set, the golden file is
+// written instead.
+func AssertGolden(tb testing.TB, path string, got *plugin.StatsResult, tol Tolerance) {
+	tb.Helper()
+	if os.Getenv(UpdateEnv) != "" {
+		if err := plugin.WriteJSON(path, got); err != nil {
+			tb.Fatalf("robottest: failed to update %s: %v", path, err)
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("robottest: failed to read %s: %v", path, err)
+	}
+	var want plugin.StatsResult
+	if err := json.Unmarshal(data, &want); err != nil {
+		tb.Fatalf("robottest: invalid golden file %s: %v", path, err)
+	}
+	AssertStats(tb, &want, got, tol)
+}
+
+// normalize round-trips the statistics through JSON.
+func normalize(stats *plugin.StatsResult) (*plugin.StatsResult, error) {
+	if stats == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return nil, err
+	}
+	var normalized plugin.StatsResult
+	err = json.Unmarshal(data, &normalized)
+	return &normalized, err
+}
+
+// within returns a comparer accepting values differing by at most tol.
+func within(tol float64) func(a, b float64) bool {
+	return func(a, b float64) bool {
+		return math.Abs(a-b) <= tol
+	}
+}
+
+// isDuration reports whether the path ends at a duration field, like
+// Duration, ExecutionTime or WallClockTime.
+func isDuration(p cmp.Path) bool {
+	name := fieldName(p)
+	return strings.HasSuffix(name, "Duration") || strings.HasSuffix(name, "Time")
+}
+
+// isRate reports whether the path ends at a rate or score field, like
+// FailureRate or QualityScore.
+func isRate(p cmp.Path) bool {
+	name := fieldName(p)
+	return strings.HasSuffix(name, "Rate") || strings.HasSuffix(name, "Score")
+}
+
+// fieldName returns the name of the last struct field of the path.
+func fieldName(p cmp.Path) string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if field, ok := p[i].(cmp.StructField); ok {
+			return field.Name()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+// Package robottest provides helpers for regression-testing tools that
+// embed the plugin package: a corpus of Robot Framework 3 to 7 sample
+// outputs with their expected statistics, and a comparer of statistics
+// with tolerances for timing-dependent values.
+package robottest
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/drone/drone-robot/plugin"
+)
+
+// corpus holds a directory per Robot Framework major version with the
+// sample output.xml and the golden statistics.json computed from it with
+// the default analyzer options.
+//
+//go:embed corpus
+var corpus embed.FS
+
+// Versions returns the versions of the corpus, "rf3" through "rf7".
+func Versions() []string {
+	entries, _ := fs.ReadDir(corpus, "corpus")
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			versions = append(versions, entry.Name())
+		}
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// Fixture returns the sample output of the version, failing the test
+// when the version is not in the corpus.
+func Fixture(tb testing.TB, version string) []byte {
+	tb.Helper()
+	data, err := corpus.ReadFile("corpus/" + version + "/output.xml")
+	if err != nil {
+		tb.Fatalf("robottest: unknown fixture %q: %v", version, err)
+	}
+	return data
+}
+
+// FixtureFile writes the sample output of the version as output.xml to
+// a temporary directory and returns its path, for APIs reading files.
+func FixtureFile(tb testing.TB, version string) string {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "output.xml")
+	if err := os.WriteFile(path, Fixture(tb, version), 0644); err != nil {
+		tb.Fatalf("robottest: failed to write fixture %q: %v", version, err)
+	}
+	return path
+}
+
+// Golden returns the expected statistics of the sample output of the
+// version, as computed by an Analyzer with the default options.
+func Golden(tb testing.TB, version string) *plugin.StatsResult {
+	tb.Helper()
+	data, err := corpus.ReadFile("corpus/" + version + "/statistics.json")
+	if err != nil {
+		tb.Fatalf("robottest: unknown fixture %q: %v", version, err)
+	}
+	var stats plugin.StatsResult
+	if err := json.Unmarshal(data, &stats); err != nil {
+		tb.Fatalf("robottest: invalid golden statistics of %q: %v", version, err)
+	}
+	return &stats
+}
@@ -0,0 +1,63 @@
+package robottest
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drone/drone-robot/plugin"
+)
+
+// TestCorpus validates the statistics of every sample output against its
+// golden file. Set ROBOTTEST_UPDATE_GOLDEN=1 to rewrite the golden files.
+func TestCorpus(t *testing.T) {
+	versions := Versions()
+	if want := []string{"rf3", "rf4", "rf5", "rf6", "rf7"}; strings.Join(versions, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected the versions %v, got %v", want, versions)
+	}
+
+	for _, version := range versions {
+		t.Run(version, func(t *testing.T) {
+			analyzer := plugin.NewAnalyzer(plugin.AnalyzerOptions{})
+			stats, err := analyzer.AnalyzeReader(context.Background(), version, bytes.NewReader(Fixture(t, version)))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stats.TotalTests == 0 {
+				t.Errorf("Expected tests in the %s fixture", version)
+			}
+			AssertGolden(t, filepath.Join("corpus", version, "statistics.json"), stats, Tolerance{})
+
+			files, err := analyzer.AnalyzeFiles(context.Background(), FixtureFile(t, version))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if files.TotalTests != Golden(t, version).TotalTests {
+				t.Errorf("Expected %d tests from the fixture file, got %d", Golden(t, version).TotalTests, files.TotalTests)
+			}
+		})
+	}
+}
+
+// TestDiffTolerance validates the tolerances of timing-dependent values
+func TestDiffTolerance(t *testing.T) {
+	want := &plugin.StatsResult{TotalTests: 4, ExecutionTime: 1000, FailureRate: 25}
+	got := &plugin.StatsResult{TotalTests: 4, ExecutionTime: 1040, FailureRate: 25.5}
+
+	if diff := Diff(want, got, Tolerance{Duration: 50, Rate: 1}); diff != "" {
+		t.Errorf("Expected a match within the tolerance, got:\n%s", diff)
+	}
+	if diff := Diff(want, got, Tolerance{Duration: 10, Rate: 1}); !strings.Contains(diff, "ExecutionTime") {
+		t.Errorf("Expected an execution time difference, got:\n%s", diff)
+	}
+	if diff := Diff(want, got, Tolerance{Duration: 50}); !strings.Contains(diff, "FailureRate") {
+		t.Errorf("Expected a failure rate difference, got:\n%s", diff)
+	}
+
+	got.TotalTests = 5
+	if diff := Diff(want, got, Tolerance{Duration: 50, Rate: 1}); !strings.Contains(diff, "TotalTests") {
+		t.Errorf("Expected counts to match exactly, got:\n%s", diff)
+	}
+}